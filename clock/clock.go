@@ -5,67 +5,154 @@ import (
 	"time"
 )
 
-func Freeze(t time.Time, block func()) { Default.Freeze(t, block) }
-
 func In(loc *time.Location) time.Time { return Default.In(loc) }
 func UTC() time.Time                  { return Default.UTC() }
 
 func After(d time.Duration) <-chan time.Time { return Default.After(d) }
 func Tick(d time.Duration) <-chan time.Time  { return Default.Tick(d) }
+func NewTimer(d time.Duration) *Timer        { return Default.NewTimer(d) }
 func Sleep(d time.Duration)                  { Default.Sleep(d) }
 
+// A Source tells the time, which is either the current time or a frozen
+// time for tests.  When it is frozen, timers and tickers only fire when the
+// clock is moved forward with Advance.
 type Source struct {
 	Now    time.Time
 	Frozen bool
 	sync.Mutex
+
+	offset time.Duration // added to the current time, while not frozen
+	timers []*Timer      // the pending timers, while frozen
 }
 
 var Default Source
 
-func (s Source) Freeze(t time.Time, block func()) {
+// NewFake returns a frozen Source, for tests which use timers or tickers
+//
+//     c := clock.NewFake(time.Date(2016, time.July, 4, 12, 0, 0, 0, time.UTC))
+//     timeout := c.After(5 * time.Second)
+//     c.Advance(5 * time.Second) // the timeout fires
+//
+func NewFake(now time.Time) *Source {
+	return &Source{Now: now, Frozen: true}
+}
+
+// Freeze sets the time while the block is running, then restores the source.
+// In tests, the Freeze function is usually simpler.
+func (s *Source) Freeze(t time.Time, block func()) {
 	s.Lock()
+	now, frozen := s.Now, s.Frozen
+	s.Now, s.Frozen = t, true
+	s.Unlock()
+
 	defer func() {
-		s.Frozen = false
+		s.Lock()
+		s.Now, s.Frozen = now, frozen
 		s.Unlock()
 	}()
-
-	s.Now = t
-	s.Frozen = true
 	block()
 }
 
-func (s Source) In(loc *time.Location) time.Time {
+func (s *Source) In(loc *time.Location) time.Time {
+	s.Lock()
+	defer s.Unlock()
+
 	if s.Frozen {
-		return s.Now
+		return s.Now.In(loc)
 	} else {
-		return time.Now().In(loc)
+		return time.Now().Add(s.offset).In(loc)
 	}
 }
 
-func (s Source) UTC() time.Time {
+func (s *Source) UTC() time.Time {
 	return s.In(time.UTC)
 }
 
-func (s Source) After(d time.Duration) <-chan time.Time {
-	if s.Frozen {
-		panic("vanilla/clock: clock.After() has not been implemented")
-	} else {
-		return time.After(d)
-	}
+func (s *Source) After(d time.Duration) <-chan time.Time {
+	return s.NewTimer(d).C
 }
 
-func (s Source) Tick(d time.Duration) <-chan time.Time {
-	if s.Frozen {
-		panic("vanilla/clock: clock.Tick() has not been implemented")
-	} else {
+// Tick is like time.Tick, and returns nil if d <= 0
+func (s *Source) Tick(d time.Duration) <-chan time.Time {
+	if d <= 0 {
+		return nil
+	}
+
+	s.Lock()
+	defer s.Unlock()
+	if !s.Frozen {
 		return time.Tick(d)
 	}
+
+	timer := newFakeTimer(s, d)
+	timer.period = d
+	s.timers = append(s.timers, timer)
+	return timer.C
 }
 
-func (s Source) Sleep(d time.Duration) {
-	if s.Frozen && d > 0 {
-		panic("vanilla/clock: clock.Sleep() has not been implemented")
-	} else {
-		time.Sleep(d)
+// NewTimer is like time.NewTimer, but the timer is fired by Advance if the
+// source is frozen
+func (s *Source) NewTimer(d time.Duration) *Timer {
+	s.Lock()
+	defer s.Unlock()
+	if !s.Frozen {
+		timer := time.NewTimer(d)
+		return &Timer{C: timer.C, timer: timer}
+	}
+
+	timer := newFakeTimer(s, d)
+	s.timers = append(s.timers, timer)
+	return timer
+}
+
+// Sleep is like time.Sleep, but if the source is frozen it waits until the
+// clock has been advanced by d
+func (s *Source) Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	<-s.After(d)
+}
+
+// Advance moves a frozen clock forward by d, firing each timer and ticker
+// which is due in the order they are due.  The Now time is set to the time
+// each timer was due when it fires.
+func (s *Source) Advance(d time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+	if !s.Frozen {
+		panic("vanilla/clock: clock.Advance() requires a frozen clock")
+	}
+
+	end := s.Now.Add(d)
+	for {
+		next := -1
+		for i, timer := range s.timers {
+			if !timer.when.After(end) && (next < 0 || timer.when.Before(s.timers[next].when)) {
+				next = i
+			}
+		}
+		if next < 0 {
+			break
+		}
+
+		timer := s.timers[next]
+		if timer.when.After(s.Now) {
+			s.Now = timer.when
+		}
+		timer.fire(s.Now)
+		if timer.period > 0 {
+			timer.when = timer.when.Add(timer.period)
+		} else {
+			s.removeTimer(next)
+		}
 	}
+	s.Now = end
+}
+
+// removeTimer removes a pending timer, keeping the order that timers were created
+func (s *Source) removeTimer(i int) {
+	copy(s.timers[i:], s.timers[i+1:])
+	s.timers[len(s.timers)-1] = nil
+	s.timers = s.timers[:len(s.timers)-1]
 }
@@ -0,0 +1,130 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+var start = time.Date(2016, time.July, 4, 12, 0, 0, 0, time.UTC)
+
+func received(c <-chan time.Time) (time.Time, bool) {
+	select {
+	case t := <-c:
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestSourceFreeze(t *testing.T) {
+	Default.Freeze(start, func() {
+		expect.Equal(t, UTC(), start)
+	})
+	expect.False(t, Default.Frozen)
+	expect.NotEqual(t, UTC(), start)
+}
+
+func TestAfter(t *testing.T) {
+	c := NewFake(start)
+	later := c.After(5 * time.Second)
+	sooner := c.After(2 * time.Second)
+
+	c.Advance(time.Second)
+	_, ok := received(sooner)
+	expect.False(t, ok)
+
+	c.Advance(time.Second)
+	at, ok := received(sooner)
+	expect.True(t, ok)
+	expect.Equal(t, at, start.Add(2*time.Second))
+	_, ok = received(later)
+	expect.False(t, ok)
+
+	c.Advance(time.Minute)
+	at, ok = received(later)
+	expect.True(t, ok)
+	expect.Equal(t, at, start.Add(5*time.Second))
+	expect.Equal(t, c.UTC(), start.Add(62*time.Second))
+}
+
+func TestTick(t *testing.T) {
+	c := NewFake(start)
+	expect.Nil(t, c.Tick(0))
+
+	ticks := c.Tick(time.Second)
+	var times []time.Time
+	for i := 0; i < 3; i++ {
+		c.Advance(time.Second)
+		at, _ := received(ticks)
+		times = append(times, at)
+	}
+	expect.Equal(t, times, []time.Time{start.Add(time.Second), start.Add(2 * time.Second), start.Add(3 * time.Second)})
+
+	// like time.Ticker, ticks are dropped if they aren't received
+	c.Advance(10 * time.Second)
+	at, ok := received(ticks)
+	expect.True(t, ok)
+	expect.Equal(t, at, start.Add(4*time.Second))
+	_, ok = received(ticks)
+	expect.False(t, ok)
+}
+
+func TestTimer(t *testing.T) {
+	c := NewFake(start)
+	timer := c.NewTimer(time.Minute)
+	expect.True(t, timer.Stop())
+	expect.False(t, timer.Stop())
+	c.Advance(time.Hour)
+	_, ok := received(timer.C)
+	expect.False(t, ok)
+
+	expect.False(t, timer.Reset(time.Minute))
+	expect.True(t, timer.Reset(2*time.Minute))
+	c.Advance(time.Minute)
+	_, ok = received(timer.C)
+	expect.False(t, ok)
+	c.Advance(time.Minute)
+	at, ok := received(timer.C)
+	expect.True(t, ok)
+	expect.Equal(t, at, start.Add(time.Hour+2*time.Minute))
+	expect.False(t, timer.Stop())
+}
+
+func TestSleep(t *testing.T) {
+	c := NewFake(start)
+	done := make(chan time.Time)
+	go func() {
+		c.Sleep(time.Minute)
+		done <- c.UTC()
+	}()
+
+	// wait for the sleep to start before advancing the clock
+	for {
+		c.Lock()
+		sleeping := len(c.timers) > 0
+		c.Unlock()
+		if sleeping {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	c.Advance(30 * time.Second)
+	select {
+	case <-done:
+		t.Fatal("expected Sleep to wait until the clock was advanced")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	c.Advance(30 * time.Second)
+	expect.Equal(t, <-done, start.Add(time.Minute))
+}
+
+func TestAdvanceWithoutFreezing(t *testing.T) {
+	var c Source
+	expect.PanicsWithValue(t, "vanilla/clock: clock.Advance() requires a frozen clock", func() {
+		c.Advance(time.Second)
+	})
+}
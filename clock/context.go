@@ -0,0 +1,20 @@
+package clock
+
+import "context"
+
+type contextKey struct{}
+
+// WithClock returns a copy of the context which carries a clock source, so
+// request-scoped code can read the time from FromContext(ctx).
+func WithClock(ctx context.Context, src *Source) context.Context {
+	return context.WithValue(ctx, contextKey{}, src)
+}
+
+// FromContext returns the clock source carried by the context, or the Default
+// source if the context doesn't have one
+func FromContext(ctx context.Context) *Source {
+	if src, ok := ctx.Value(contextKey{}).(*Source); ok && src != nil {
+		return src
+	}
+	return &Default
+}
@@ -0,0 +1,65 @@
+package clock
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// FallbackLocation is the location returned by Location when a timezone
+// can't be loaded, eg. if the tz database is missing an entry.  If it is nil,
+// Location panics instead.
+var FallbackLocation = time.UTC
+
+type loadResult struct {
+	loc *time.Location
+	err error
+}
+
+var (
+	locationMutex sync.Mutex
+	locations     = map[string]loadResult{}
+)
+
+// LoadLocation is like time.LoadLocation, but caches each location (and
+// error) so it is only read from the tz database once.
+func LoadLocation(name string) (*time.Location, error) {
+	locationMutex.Lock()
+	defer locationMutex.Unlock()
+
+	result, ok := locations[name]
+	if !ok {
+		result.loc, result.err = time.LoadLocation(name)
+		locations[name] = result
+	}
+	return result.loc, result.err
+}
+
+// MustLoadLocation is like LoadLocation but panics if the location can't be
+// loaded, for locations which are known when the program is written
+//
+//     var Pacific = clock.MustLoadLocation("America/Los_Angeles")
+//
+func MustLoadLocation(name string) *time.Location {
+	loc, err := LoadLocation(name)
+	if err != nil {
+		panic(fmt.Sprintf("vanilla/clock: cannot load location %q: %v", name, err))
+	}
+	return loc
+}
+
+// Location returns the named location, or FallbackLocation if it can't be loaded
+func Location(name string) *time.Location {
+	loc, err := LoadLocation(name)
+	if err != nil {
+		if FallbackLocation == nil {
+			panic(fmt.Sprintf("vanilla/clock: cannot load location %q: %v", name, err))
+		}
+		return FallbackLocation
+	}
+	return loc
+}
+
+// NowIn returns the time from the Default clock in the named location
+// (see Location for timezones which can't be loaded)
+func NowIn(name string) time.Time { return Default.In(Location(name)) }
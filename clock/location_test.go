@@ -0,0 +1,43 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+func TestLoadLocation(t *testing.T) {
+	loc, err := LoadLocation("America/Los_Angeles")
+	expect.Nil(t, err)
+	expect.Equal(t, loc.String(), "America/Los_Angeles")
+
+	cached, err := LoadLocation("America/Los_Angeles")
+	expect.Nil(t, err)
+	expect.True(t, cached == loc)
+
+	_, err = LoadLocation("America/Springfield")
+	expect.NotNil(t, err)
+}
+
+func TestMustLoadLocation(t *testing.T) {
+	expect.Equal(t, MustLoadLocation("UTC"), time.UTC)
+	expect.Panics(t, func() { MustLoadLocation("America/Springfield") })
+}
+
+func TestLocationFallback(t *testing.T) {
+	expect.Equal(t, Location("America/Springfield"), time.UTC)
+	expect.Equal(t, Location("Europe/Paris").String(), "Europe/Paris")
+
+	defer func(fallback *time.Location) { FallbackLocation = fallback }(FallbackLocation)
+	FallbackLocation = nil
+	expect.Panics(t, func() { Location("America/Springfield") })
+}
+
+func TestNowIn(t *testing.T) {
+	Freeze(t, start)
+	now := NowIn("America/Los_Angeles")
+	expect.True(t, now.Equal(start))
+	expect.Equal(t, now.Location().String(), "America/Los_Angeles")
+	expect.Equal(t, now.Hour(), 5)
+}
@@ -0,0 +1,62 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+// Freeze sets the Default clock to a frozen time until the end of the test
+//
+//     clock.Freeze(t, time.Date(2016, time.July, 4, 12, 0, 0, 0, time.UTC))
+//     date.TodayUTC() // July 4th, 2016
+//
+// Tests which call Freeze or Travel must not run in parallel with other tests
+// which read the Default clock.  To freeze the clock while running a function
+// instead, use Default.Freeze.
+func Freeze(tb testing.TB, at time.Time) {
+	Default.Lock()
+	defer Default.Unlock()
+	restoreAfterTest(tb)
+	Default.Now, Default.Frozen = at, true
+}
+
+// Travel moves the Default clock forward by d (or backward if d is negative)
+// until the end of the test.  A clock which isn't frozen keeps running from
+// the new time, and a frozen clock is advanced (firing its pending timers).
+func Travel(tb testing.TB, d time.Duration) {
+	Default.Lock()
+	restoreAfterTest(tb)
+	if !Default.Frozen {
+		Default.offset += d
+		Default.Unlock()
+		return
+	}
+
+	Default.Unlock()
+	Default.Advance(d)
+}
+
+// restoreAfterTest restores the Default clock's current state after the test,
+// and must be called while holding the lock.  Of the timers which were
+// pending, only those which are still pending (haven't fired or been stopped
+// during the test) are restored, and timers made during the test are dropped.
+func restoreAfterTest(tb testing.TB) {
+	now, frozen, offset := Default.Now, Default.Frozen, Default.offset
+	timers := append([]*Timer(nil), Default.timers...)
+	tb.Cleanup(func() {
+		Default.Lock()
+		defer Default.Unlock()
+		Default.Now, Default.Frozen, Default.offset = now, frozen, offset
+
+		pending := make(map[*Timer]bool, len(Default.timers))
+		for _, timer := range Default.timers {
+			pending[timer] = true
+		}
+		Default.timers = Default.timers[:0]
+		for _, timer := range timers {
+			if pending[timer] {
+				Default.timers = append(Default.timers, timer)
+			}
+		}
+	})
+}
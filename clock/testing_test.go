@@ -0,0 +1,69 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+func TestFreezeForTest(t *testing.T) {
+	t.Run("frozen", func(t *testing.T) {
+		Freeze(t, start)
+		expect.Equal(t, UTC(), start)
+		Freeze(t, start.Add(time.Hour))
+		expect.Equal(t, UTC(), start.Add(time.Hour))
+	})
+	expect.False(t, Default.Frozen)
+	expect.NotEqual(t, UTC(), start)
+}
+
+func TestTravel(t *testing.T) {
+	t.Run("frozen", func(t *testing.T) {
+		Freeze(t, start)
+		timeout := After(time.Minute)
+		Travel(t, time.Hour)
+		expect.Equal(t, UTC(), start.Add(time.Hour))
+		expect.Equal(t, <-timeout, start.Add(time.Minute))
+	})
+	expect.False(t, Default.Frozen)
+	expect.Equal(t, len(Default.timers), 0)
+
+	t.Run("running", func(t *testing.T) {
+		Travel(t, -24*time.Hour)
+		yesterday := time.Now().Add(-24 * time.Hour)
+		expect.True(t, UTC().Sub(yesterday) < time.Minute)
+		expect.True(t, UTC().Sub(yesterday) >= 0)
+	})
+	expect.True(t, time.Now().Sub(UTC()) < time.Minute)
+}
+
+func TestTravelRestoresPendingTimers(t *testing.T) {
+	t.Run("frozen", func(t *testing.T) {
+		Freeze(t, start)
+		soon, later := NewTimer(time.Minute), NewTimer(time.Hour)
+
+		t.Run("travel", func(t *testing.T) {
+			Travel(t, time.Minute) // fires soon
+			NewTimer(time.Second)
+		})
+		expect.Equal(t, UTC(), start)
+		expect.Equal(t, Default.timers, []*Timer{later})
+		expect.Equal(t, <-soon.C, start.Add(time.Minute))
+
+		// the fired timer isn't fired again
+		Default.Advance(2 * time.Hour)
+		expect.Equal(t, <-later.C, start.Add(time.Hour))
+		expect.Equal(t, len(soon.C), 0)
+	})
+}
+
+func TestFromContext(t *testing.T) {
+	expect.Equal(t, FromContext(context.Background()), &Default)
+
+	c := NewFake(start)
+	ctx := WithClock(context.Background(), c)
+	expect.Equal(t, FromContext(ctx), c)
+	expect.Equal(t, FromContext(ctx).UTC(), start)
+}
@@ -0,0 +1,67 @@
+package clock
+
+import "time"
+
+// A Timer is like a time.Timer, except that timers from a frozen Source
+// fire when the Source is advanced instead of after a real delay.
+type Timer struct {
+	C <-chan time.Time
+
+	timer *time.Timer // the real timer, if the source wasn't frozen
+
+	source *Source
+	c      chan time.Time
+	when   time.Time
+	period time.Duration // for tickers
+}
+
+func newFakeTimer(s *Source, d time.Duration) *Timer {
+	c := make(chan time.Time, 1)
+	return &Timer{C: c, source: s, c: c, when: s.Now.Add(d)}
+}
+
+// fire sends the time without blocking, dropping the tick (like time.Ticker)
+// if the previous one hasn't been received
+func (t *Timer) fire(now time.Time) {
+	select {
+	case t.c <- now:
+	default:
+	}
+}
+
+// Stop prevents the timer from firing, returning false if the timer has
+// already fired or been stopped
+func (t *Timer) Stop() bool {
+	if t.timer != nil {
+		return t.timer.Stop()
+	}
+
+	t.source.Lock()
+	defer t.source.Unlock()
+	return t.stop()
+}
+
+func (t *Timer) stop() bool {
+	for i, timer := range t.source.timers {
+		if timer == t {
+			t.source.removeTimer(i)
+			return true
+		}
+	}
+	return false
+}
+
+// Reset changes the timer to fire after d, returning true if the timer was
+// still pending
+func (t *Timer) Reset(d time.Duration) bool {
+	if t.timer != nil {
+		return t.timer.Reset(d)
+	}
+
+	t.source.Lock()
+	defer t.source.Unlock()
+	pending := t.stop()
+	t.when = t.source.Now.Add(d)
+	t.source.timers = append(t.source.timers, t)
+	return pending
+}
@@ -0,0 +1,90 @@
+package date
+
+import "time"
+
+// A Calendar decides which dates are holidays, which aren't business days
+type Calendar interface {
+	IsHoliday(d Date) bool
+}
+
+// Holidays is a Calendar with a list of holidays
+type Holidays []Date
+
+func (h Holidays) IsHoliday(d Date) bool {
+	for _, holiday := range h {
+		if holiday.Equal(d) {
+			return true
+		}
+	}
+	return false
+}
+
+// ISOWeek returns the ISO 8601 year and week number of the date, like time.Time.ISOWeek
+func (d Date) ISOWeek() (year, week int) {
+	return d.BeginningOfDayIn(time.UTC).ISOWeek()
+}
+
+// IsWeekend returns true if the date is a Saturday or Sunday
+func (d Date) IsWeekend() bool {
+	weekday := d.BeginningOfDayIn(time.UTC).Weekday()
+	return weekday == time.Saturday || weekday == time.Sunday
+}
+
+// IsBusinessDay returns true if the date is a weekday, and isn't a holiday
+// in any of the calendars
+func (d Date) IsBusinessDay(calendars ...Calendar) bool {
+	if d.IsWeekend() {
+		return false
+	}
+	for _, calendar := range calendars {
+		if calendar.IsHoliday(d) {
+			return false
+		}
+	}
+	return true
+}
+
+// AddBusinessDays returns the date which is a number of business days after
+// the date (or before if num is negative), skipping weekends and the holidays
+// in any of the calendars.
+//
+//    friday.AddBusinessDays(1)                  // the next monday
+//    friday.AddBusinessDays(1, date.Holidays{}) // with a list of holidays
+//
+func (d Date) AddBusinessDays(num int, calendars ...Calendar) Date {
+	for num > 0 {
+		d = d.NextDay()
+		if d.IsBusinessDay(calendars...) {
+			num -= 1
+		}
+	}
+	for num < 0 {
+		d = d.PrevDay()
+		if d.IsBusinessDay(calendars...) {
+			num += 1
+		}
+	}
+	return d
+}
+
+// BusinessDaysBetween returns the number of business days after the date
+// until the other date, including the other date.  If the other date is
+// before the date, it is the negative number of business days from the other
+// date until the date, so that AddBusinessDays(BusinessDaysBetween(other))
+// returns other if other is a business day.
+func (d Date) BusinessDaysBetween(other Date, calendars ...Calendar) int {
+	count := 0
+	for day := d; day.Before(other); {
+		day = day.NextDay()
+		if day.IsBusinessDay(calendars...) {
+			count += 1
+		}
+	}
+	for day := d; day.After(other); {
+		day = day.PrevDay()
+		if day.IsBusinessDay(calendars...) {
+			count -= 1
+		}
+	}
+	return count
+}
@@ -0,0 +1,79 @@
+package date
+
+import (
+	"testing"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+func TestISOWeek(t *testing.T) {
+	examples := []struct {
+		Date       Date
+		Year, Week int
+	}{
+		{At(2016, time.January, 1, nil), 2015, 53},
+		{At(2016, time.January, 4, nil), 2016, 1},
+		{At(2016, time.July, 4, nil), 2016, 27},
+		{At(2014, time.December, 29, nil), 2015, 1},
+	}
+	for _, example := range examples {
+		year, week := example.Date.ISOWeek()
+		expect.Equal(t, year, example.Year, "%v", example.Date)
+		expect.Equal(t, week, example.Week, "%v", example.Date)
+	}
+}
+
+func TestIsWeekend(t *testing.T) {
+	expect.False(t, At(2016, time.July, 1, nil).IsWeekend()) // friday
+	expect.True(t, At(2016, time.July, 2, nil).IsWeekend())
+	expect.True(t, At(2016, time.July, 3, nil).IsWeekend())
+	expect.False(t, At(2016, time.July, 4, nil).IsWeekend())
+}
+
+func TestAddBusinessDays(t *testing.T) {
+	holidays := Holidays{At(2016, time.July, 4, nil), At(2016, time.December, 26, nil)}
+	examples := []struct {
+		Date     Date
+		Days     int
+		Holidays Calendar
+		Result   Date
+	}{
+		{Date: At(2016, time.June, 29, nil), Days: 1, Result: At(2016, time.June, 30, nil)},
+		{Date: At(2016, time.July, 1, nil), Days: 1, Result: At(2016, time.July, 4, nil)},
+		{Date: At(2016, time.July, 1, nil), Days: 1, Holidays: holidays, Result: At(2016, time.July, 5, nil)},
+		{Date: At(2016, time.July, 2, nil), Days: 1, Result: At(2016, time.July, 4, nil)},
+		{Date: At(2016, time.July, 1, nil), Days: 10, Holidays: holidays, Result: At(2016, time.July, 18, nil)},
+		{Date: At(2016, time.December, 23, nil), Days: 5, Holidays: holidays, Result: At(2017, time.January, 2, nil)},
+		{Date: At(2016, time.July, 5, nil), Days: -1, Result: At(2016, time.July, 4, nil)},
+		{Date: At(2016, time.July, 5, nil), Days: -1, Holidays: holidays, Result: At(2016, time.July, 1, nil)},
+		{Date: At(2016, time.July, 3, nil), Days: -1, Result: At(2016, time.July, 1, nil)},
+		{Date: At(2016, time.July, 3, nil), Days: 0, Result: At(2016, time.July, 3, nil)},
+	}
+
+	for _, example := range examples {
+		var result, roundTrip Date
+		if example.Holidays != nil {
+			result = example.Date.AddBusinessDays(example.Days, example.Holidays)
+			expect.Equal(t, example.Date.BusinessDaysBetween(result, example.Holidays), example.Days, "%v + %d", example.Date, example.Days)
+			roundTrip = result.AddBusinessDays(-example.Days, example.Holidays)
+		} else {
+			result = example.Date.AddBusinessDays(example.Days)
+			expect.Equal(t, example.Date.BusinessDaysBetween(result), example.Days, "%v + %d", example.Date, example.Days)
+			roundTrip = result.AddBusinessDays(-example.Days)
+		}
+		expect.Equal(t, result, example.Result, "%v + %d", example.Date, example.Days)
+		if example.Date.IsBusinessDay(holidays) {
+			expect.Equal(t, roundTrip, example.Date, "%v + %d", example.Date, example.Days)
+		}
+	}
+}
+
+func TestBusinessDaysBetween(t *testing.T) {
+	friday := At(2016, time.July, 1, nil)
+	expect.Equal(t, friday.BusinessDaysBetween(friday), 0)
+	expect.Equal(t, friday.BusinessDaysBetween(At(2016, time.July, 3, nil)), 0)
+	expect.Equal(t, friday.BusinessDaysBetween(At(2016, time.July, 31, nil)), 20)
+	expect.Equal(t, friday.BusinessDaysBetween(At(2016, time.July, 31, nil), Holidays{At(2016, time.July, 4, nil)}), 19)
+	expect.Equal(t, friday.BusinessDaysBetween(At(2016, time.June, 27, nil)), -4)
+}
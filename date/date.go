@@ -3,6 +3,7 @@ package date
 import (
 	"database/sql/driver"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/reflexionhealth/vanilla/clock"
@@ -89,6 +90,75 @@ func (d Date) NextDay() Date {
 	}
 }
 
+// AddMonths adds a number of months to the date (or subtracts if num is negative).
+// If the day doesn't exist in the resulting month, it is clamped to the last
+// day of the month, so January 31st plus one month is February 28th or 29th.
+func (d Date) AddMonths(num int) Date {
+	months := int(d.Month) - 1 + num
+	year, index := d.Year+months/12, months%12
+	if index < 0 {
+		year, index = year-1, index+12
+	}
+	month := time.Month(index + 1)
+
+	day := d.Day
+	if last := DaysInMonth(month, year); day > last {
+		day = last
+	}
+	return Date{year, month, day, d.location}
+}
+
+// AddYears adds a number of years to the date, where February 29th becomes
+// February 28th if the resulting year is not a leap year
+func (d Date) AddYears(num int) Date {
+	return d.AddMonths(12 * num)
+}
+
+// Diff returns the number of years, months, and days from the date until
+// the other date, such that d.AddMonths(12*years + months).AddDays(days) is
+// the other date.  If the other date is earlier, each number is negative.
+//
+// Months are added the same way as AddMonths, so from January 31st until
+// March 1st is 1 month and 1 day (or 1 month and 2 days in a leap year).
+func (d Date) Diff(other Date) (years, months, days int) {
+	if other.Before(d) {
+		years, months, days = other.Diff(d)
+		return -years, -months, -days
+	}
+
+	total := (other.Year-d.Year)*12 + int(other.Month-d.Month)
+	start := d.AddMonths(total)
+	if start.After(other) {
+		total -= 1
+		start = d.AddMonths(total)
+	}
+	return total / 12, total % 12, other.DaysAfter(start)
+}
+
+// Age returns the number of whole years from the date (eg. a birthdate)
+// until a day, such as today.  Someone born on February 29th is one year
+// older on February 28th in years which aren't leap years.
+func (d Date) Age(on Date) int {
+	years, _, _ := d.Diff(on)
+	return years
+}
+
+func (d Date) BeginningOfMonth() Date {
+	return Date{d.Year, d.Month, 1, d.location}
+}
+
+func (d Date) EndOfMonth() Date {
+	return Date{d.Year, d.Month, DaysInMonth(d.Month, d.Year), d.location}
+}
+
+func (d Date) BeginningOfYear() Date {
+	return Date{d.Year, time.January, 1, d.location}
+}
+
+func (d Date) EndOfYear() Date {
+	return Date{d.Year, time.December, 31, d.location}
+}
+
 // MostRecent returns today if today is the given weekday, otherwise it returns
 // the date of the last day for that weekday.
 //
@@ -176,10 +246,31 @@ func (d Date) Format(format string) string {
 }
 
 // Implements sql.Scanner interface
+//
+// Dates can be scanned from a time.Time, or a string or []byte like
+// "2006-01-02" (which some drivers return for DATE columns).
 func (d *Date) Scan(src interface{}) error {
-	t, ok := src.(time.Time)
-	if !ok {
-		return errors.New("date: scan value was not a Time")
+	switch v := src.(type) {
+	case time.Time:
+		*d = From(v)
+		return nil
+	case string:
+		return d.scanString(v)
+	case []byte:
+		return d.scanString(string(v))
+	default:
+		return errors.New("date: scan value was not a Time, []byte, or string")
+	}
+}
+
+func (d *Date) scanString(src string) error {
+	if len(src) > len(RFC3339) && (src[len(RFC3339)] == 'T' || src[len(RFC3339)] == ' ') {
+		return fmt.Errorf("date: scan value %q has a time, but expected a date like \"2006-01-02\"", src)
+	}
+
+	t, err := time.Parse(RFC3339, src)
+	if err != nil {
+		return fmt.Errorf("date: scan value %q is not a date like \"2006-01-02\"", src)
 	}
 
 	*d = From(t)
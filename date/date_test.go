@@ -0,0 +1,173 @@
+package date
+
+import (
+	"testing"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+func TestAddMonths(t *testing.T) {
+	examples := []struct {
+		Date   Date
+		Months int
+		Result Date
+	}{
+		{At(2016, time.January, 15, nil), 1, At(2016, time.February, 15, nil)},
+		{At(2016, time.January, 31, nil), 1, At(2016, time.February, 29, nil)},
+		{At(2015, time.January, 31, nil), 1, At(2015, time.February, 28, nil)},
+		{At(2016, time.March, 31, nil), -1, At(2016, time.February, 29, nil)},
+		{At(2016, time.May, 31, nil), 1, At(2016, time.June, 30, nil)},
+		{At(2016, time.December, 31, nil), 1, At(2017, time.January, 31, nil)},
+		{At(2016, time.January, 31, nil), -1, At(2015, time.December, 31, nil)},
+		{At(2016, time.January, 1, nil), -12, At(2015, time.January, 1, nil)},
+		{At(2016, time.January, 1, nil), -13, At(2014, time.December, 1, nil)},
+		{At(2016, time.August, 31, nil), 18, At(2018, time.February, 28, nil)},
+		{At(2016, time.August, 31, nil), -30, At(2014, time.February, 28, nil)},
+		{At(2016, time.August, 31, nil), 0, At(2016, time.August, 31, nil)},
+	}
+
+	for _, example := range examples {
+		expect.Equal(t, example.Date.AddMonths(example.Months), example.Result, example.Date, example.Months)
+	}
+}
+
+func TestAddMonthsClampsEveryMonth(t *testing.T) {
+	for year := 1896; year <= 2104; year++ {
+		for month := time.January; month <= time.December; month++ {
+			for day := 28; day <= DaysInMonth(month, year); day++ {
+				d := At(year, month, day, nil)
+				next := d.AddMonths(1)
+
+				expectedMonth := month%12 + 1
+				expect.Equal(t, next.Month, expectedMonth, d)
+				if day <= DaysInMonth(expectedMonth, next.Year) {
+					expect.Equal(t, next.Day, day, d)
+				} else {
+					expect.Equal(t, next, next.EndOfMonth(), d)
+				}
+			}
+		}
+	}
+}
+
+func TestAddYears(t *testing.T) {
+	leapDay := At(2016, time.February, 29, nil)
+	expect.Equal(t, leapDay.AddYears(1), At(2017, time.February, 28, nil))
+	expect.Equal(t, leapDay.AddYears(4), At(2020, time.February, 29, nil))
+	expect.Equal(t, leapDay.AddYears(-4), At(2012, time.February, 29, nil))
+	expect.Equal(t, leapDay.AddYears(84), At(2100, time.February, 28, nil)) // not a leap year
+	expect.Equal(t, leapDay.AddYears(-16), At(2000, time.February, 29, nil))
+	expect.Equal(t, At(2015, time.March, 1, nil).AddYears(1), At(2016, time.March, 1, nil))
+
+	for year := 1896; year <= 2104; year++ {
+		d := At(year, time.February, 28, nil).AddYears(1)
+		expect.Equal(t, d, At(year+1, time.February, 28, nil))
+		if IsLeapYear(year) {
+			expect.Equal(t, At(year, time.February, 29, nil).AddYears(1), At(year+1, time.February, 28, nil))
+		}
+	}
+}
+
+func TestIsLeapYear(t *testing.T) {
+	leapYears := map[int]bool{1600: true, 1896: true, 1904: true, 2000: true, 2012: true, 2016: true, 2400: true}
+	for _, year := range []int{1600, 1700, 1800, 1896, 1900, 1904, 2000, 2012, 2015, 2016, 2100, 2400} {
+		expect.Equal(t, IsLeapYear(year), leapYears[year], year)
+		expect.Equal(t, DaysInMonth(time.February, year) == 29, leapYears[year], year)
+	}
+}
+
+func TestBeginningAndEndOfPeriods(t *testing.T) {
+	d := At(2016, time.February, 10, time.UTC)
+	expect.Equal(t, d.BeginningOfMonth(), At(2016, time.February, 1, time.UTC))
+	expect.Equal(t, d.EndOfMonth(), At(2016, time.February, 29, time.UTC))
+	expect.Equal(t, At(2015, time.February, 10, nil).EndOfMonth(), At(2015, time.February, 28, nil))
+	expect.Equal(t, At(2015, time.April, 10, nil).EndOfMonth(), At(2015, time.April, 30, nil))
+	expect.Equal(t, d.BeginningOfYear(), At(2016, time.January, 1, time.UTC))
+	expect.Equal(t, d.EndOfYear(), At(2016, time.December, 31, time.UTC))
+}
+
+func TestScan(t *testing.T) {
+	examples := []interface{}{
+		time.Date(2016, time.July, 4, 0, 0, 0, 0, time.UTC),
+		"2016-07-04",
+		[]byte("2016-07-04"),
+	}
+	for _, example := range examples {
+		var d Date
+		err := d.Scan(example)
+		expect.Nil(t, err, "%#v", example)
+		expect.Equal(t, d, At(2016, time.July, 4, time.UTC), "%#v", example)
+	}
+
+	var d Date
+	err := d.Scan("2016-07-04 12:30:00")
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.Error(), `date: scan value "2016-07-04 12:30:00" has a time, but expected a date like "2006-01-02"`)
+	}
+	err = d.Scan([]byte("2016-07-04T12:30:00Z"))
+	expect.NotNil(t, err)
+	err = d.Scan("07/04/2016")
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.Error(), `date: scan value "07/04/2016" is not a date like "2006-01-02"`)
+	}
+	expect.NotNil(t, d.Scan(20160704))
+	expect.NotNil(t, d.Scan(nil))
+	expect.Equal(t, d, Date{})
+}
+
+func TestDiff(t *testing.T) {
+	examples := []struct {
+		From, To            Date
+		Years, Months, Days int
+	}{
+		{At(2016, time.July, 4, nil), At(2016, time.July, 4, nil), 0, 0, 0},
+		{At(2016, time.July, 4, nil), At(2016, time.July, 5, nil), 0, 0, 1},
+		{At(2016, time.July, 4, nil), At(2016, time.August, 3, nil), 0, 0, 30},
+		{At(2016, time.July, 4, nil), At(2016, time.August, 4, nil), 0, 1, 0},
+		{At(2016, time.July, 4, nil), At(2017, time.July, 3, nil), 0, 11, 29},
+		{At(2016, time.July, 4, nil), At(2019, time.September, 10, nil), 3, 2, 6},
+		{At(2015, time.January, 31, nil), At(2015, time.February, 28, nil), 0, 1, 0},
+		{At(2015, time.January, 31, nil), At(2015, time.March, 1, nil), 0, 1, 1},
+		{At(2016, time.January, 31, nil), At(2016, time.March, 1, nil), 0, 1, 1},
+		{At(2016, time.January, 31, nil), At(2016, time.February, 29, nil), 0, 1, 0},
+		{At(2015, time.December, 31, nil), At(2016, time.January, 1, nil), 0, 0, 1},
+		{At(2016, time.February, 29, nil), At(2017, time.February, 28, nil), 1, 0, 0},
+		{At(2016, time.February, 29, nil), At(2020, time.February, 29, nil), 4, 0, 0},
+		{At(2016, time.July, 5, nil), At(2016, time.July, 4, nil), 0, 0, -1},
+		{At(2019, time.September, 10, nil), At(2016, time.July, 4, nil), -3, -2, -6},
+	}
+	for _, example := range examples {
+		years, months, days := example.From.Diff(example.To)
+		expect.Equal(t, []int{years, months, days}, []int{example.Years, example.Months, example.Days}, "%v until %v", example.From, example.To)
+		if !example.To.Before(example.From) {
+			result := example.From.AddMonths(12*years + months)
+			for i := 0; i < days; i++ {
+				result = result.NextDay()
+			}
+			expect.True(t, result.Equal(example.To), "%v plus the diff is %v", example.From, result)
+		}
+	}
+}
+
+func TestAge(t *testing.T) {
+	examples := []struct {
+		Birthdate, On Date
+		Age           int
+	}{
+		{At(2000, time.July, 4, nil), At(2000, time.July, 4, nil), 0},
+		{At(2000, time.July, 4, nil), At(2016, time.July, 3, nil), 15},
+		{At(2000, time.July, 4, nil), At(2016, time.July, 4, nil), 16},
+		{At(2000, time.July, 4, nil), At(2016, time.December, 31, nil), 16},
+		{At(1999, time.December, 31, nil), At(2016, time.January, 1, nil), 16},
+		{At(1999, time.December, 31, nil), At(2016, time.December, 30, nil), 16},
+		{At(2000, time.February, 29, nil), At(2017, time.February, 27, nil), 16},
+		{At(2000, time.February, 29, nil), At(2017, time.February, 28, nil), 17},
+		{At(2000, time.February, 29, nil), At(2016, time.February, 28, nil), 15},
+		{At(2000, time.February, 29, nil), At(2016, time.February, 29, nil), 16},
+		{At(2000, time.March, 1, nil), At(2016, time.February, 29, nil), 15},
+	}
+	for _, example := range examples {
+		expect.Equal(t, example.Birthdate.Age(example.On), example.Age, "born %v on %v", example.Birthdate, example.On)
+	}
+}
@@ -0,0 +1,47 @@
+package date
+
+import (
+	"fmt"
+	"time"
+)
+
+// Layouts are the formats accepted by ParseAny, in the order they are tried
+var Layouts = []string{
+	RFC3339,      // 2016-07-04
+	"2006/1/2",   // 2016/07/04 or 2016/7/4
+	"1/2/2006",   // 07/04/2016 or 7/4/2016 (US order)
+	"20060102",   // 20160704
+	time.RFC3339, // 2016-07-04T00:00:00Z, using the date in its own timezone
+}
+
+// ParseStrict is like Parse, but also returns an error if the year is not
+// between 1 and 9999.  Like Parse, it returns an error if the month or day
+// are out of range (eg. "2016-02-30").
+func ParseStrict(format string, source string) (Date, error) {
+	t, err := time.Parse(format, source)
+	if err != nil {
+		return Date{}, err
+	}
+	if t.Year() < 1 || t.Year() > 9999 {
+		return Date{}, &time.ParseError{Layout: format, Value: source, Message: ": year out of range"}
+	}
+	return From(t), nil
+}
+
+// ParseAny parses a date using the first of the Layouts which matches the
+// source, so dates from clients using different formats can be normalized.
+//
+//    date.ParseAny("2016-07-04") // July 4th, 2016
+//    date.ParseAny("7/4/2016")   // July 4th, 2016
+//    date.ParseAny("20160704")   // July 4th, 2016
+//
+// Each layout is parsed with ParseStrict, so dates like "2016-02-30" are
+// rejected instead of being normalized.
+func ParseAny(source string) (Date, error) {
+	for _, layout := range Layouts {
+		if d, err := ParseStrict(layout, source); err == nil {
+			return d, nil
+		}
+	}
+	return Date{}, fmt.Errorf("date: could not parse %q as a date", source)
+}
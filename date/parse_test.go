@@ -0,0 +1,62 @@
+package date
+
+import (
+	"testing"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+func TestParseAny(t *testing.T) {
+	examples := []string{
+		"2016-07-04",
+		"2016/07/04",
+		"2016/7/4",
+		"07/04/2016",
+		"7/4/2016",
+		"20160704",
+		"2016-07-04T00:00:00Z",
+		"2016-07-04T23:30:00-07:00",
+	}
+	for _, example := range examples {
+		d, err := ParseAny(example)
+		expect.Nil(t, err, example)
+		expect.True(t, d.Equal(At(2016, time.July, 4, nil)), "%s parsed as %v", example, d)
+	}
+}
+
+func TestParseAnyErrors(t *testing.T) {
+	examples := []string{
+		"",
+		"July 4th",
+		"2016-07-04 12:30",
+		"2016-7-4",
+		"2016-02-30",
+		"13/1/2016",
+		"20161301",
+		"0000-01-01",
+	}
+	for _, example := range examples {
+		_, err := ParseAny(example)
+		if expect.NotNil(t, err, example) {
+			expect.Equal(t, err.Error(), `date: could not parse "`+example+`" as a date`)
+		}
+	}
+}
+
+func TestParseStrict(t *testing.T) {
+	d, err := ParseStrict(RFC3339, "2016-02-29")
+	expect.Nil(t, err)
+	expect.Equal(t, d, At(2016, time.February, 29, time.UTC))
+
+	_, err = ParseStrict(RFC3339, "2015-02-29")
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.Error(), `parsing time "2015-02-29": day out of range`)
+	}
+	_, err = ParseStrict(RFC3339, "0000-02-01")
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.Error(), `parsing time "0000-02-01": year out of range`)
+	}
+	_, err = Parse(RFC3339, "0000-02-01")
+	expect.Nil(t, err)
+}
@@ -0,0 +1,178 @@
+package date
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Range is the dates from Start to End, including both the Start and End.
+// A range where End is before Start is empty.
+//
+//    july := date.Range{date.At(2016, 7, 1, nil), date.At(2016, 7, 31, nil)}
+//    july.Days() // 31
+//
+type Range struct {
+	Start Date
+	End   Date
+}
+
+// IsEmpty returns true if the range doesn't contain any dates
+func (r Range) IsEmpty() bool {
+	return r.End.Before(r.Start)
+}
+
+// Days returns the number of dates in the range
+func (r Range) Days() int {
+	if r.IsEmpty() {
+		return 0
+	}
+	return r.End.DaysAfter(r.Start) + 1
+}
+
+// Contains returns true if the date is in the range
+func (r Range) Contains(d Date) bool {
+	return d.AtLeast(r.Start) && d.AtMost(r.End)
+}
+
+// Overlaps returns true if the ranges have any dates in common
+func (r Range) Overlaps(other Range) bool {
+	return !r.Intersect(other).IsEmpty()
+}
+
+// Intersect returns the dates which are in both ranges, which is empty if the
+// ranges don't overlap
+func (r Range) Intersect(other Range) Range {
+	result := r
+	if other.Start.After(result.Start) {
+		result.Start = other.Start
+	}
+	if other.End.Before(result.End) {
+		result.End = other.End
+	}
+	return result
+}
+
+// Union returns the range covering both ranges, if they overlap or are
+// adjacent (so that the union doesn't include any dates not in either range).
+// An empty range can be joined with any range.
+func (r Range) Union(other Range) (Range, bool) {
+	if r.IsEmpty() {
+		return other, true
+	} else if other.IsEmpty() {
+		return r, true
+	} else if r.End.NextDay().Before(other.Start) || other.End.NextDay().Before(r.Start) {
+		return Range{}, false
+	}
+
+	result := r
+	if other.Start.Before(result.Start) {
+		result.Start = other.Start
+	}
+	if other.End.After(result.End) {
+		result.End = other.End
+	}
+	return result, true
+}
+
+// Each calls fn with each date in the range in order, until fn returns false
+func (r Range) Each(fn func(d Date) bool) {
+	for d := r.Start; d.AtMost(r.End); d = d.NextDay() {
+		if !fn(d) {
+			return
+		}
+	}
+}
+
+// Dates returns each of the dates in the range
+func (r Range) Dates() []Date {
+	dates := make([]Date, 0, r.Days())
+	r.Each(func(d Date) bool {
+		dates = append(dates, d)
+		return true
+	})
+	return dates
+}
+
+// String formats the range like an ISO 8601 interval, "2006-01-02/2006-01-31"
+func (r Range) String() string {
+	return r.Start.String() + "/" + r.End.String()
+}
+
+type rangeJSON struct {
+	Start Date `json:"start"`
+	End   Date `json:"end"`
+}
+
+// Implements json.Marshaler interface
+func (r Range) MarshalJSON() ([]byte, error) {
+	return json.Marshal(rangeJSON{r.Start, r.End})
+}
+
+// Implements json.Unmarshaler interface
+func (r *Range) UnmarshalJSON(bytes []byte) error {
+	var decoded rangeJSON
+	if err := json.Unmarshal(bytes, &decoded); err != nil {
+		return err
+	}
+
+	*r = Range{decoded.Start, decoded.End}
+	return nil
+}
+
+// Implements sql.Scanner interface, for range types like a postgres daterange
+// (eg. "[2006-01-02,2006-02-01)").  Ranges without a start or end aren't supported.
+func (r *Range) Scan(src interface{}) error {
+	var text string
+	switch t := src.(type) {
+	case string:
+		text = t
+	case []byte:
+		text = string(t)
+	default:
+		return errors.New("date: scan value was not a string or []byte")
+	}
+
+	if text == "empty" {
+		*r = Range{At(1, 1, 2, nil), At(1, 1, 1, nil)} // any End before Start
+		return nil
+	}
+
+	invalid := fmt.Errorf("date: scan value %q is not a bounded date range", text)
+	if len(text) < 2 {
+		return invalid
+	}
+	lower, upper := text[0], text[len(text)-1]
+	bounds := strings.Split(text[1:len(text)-1], ",")
+	if (lower != '[' && lower != '(') || (upper != ']' && upper != ')') || len(bounds) != 2 {
+		return invalid
+	}
+
+	start, err := Parse(RFC3339, strings.Trim(bounds[0], `" `))
+	if err != nil {
+		return invalid
+	}
+	end, err := Parse(RFC3339, strings.Trim(bounds[1], `" `))
+	if err != nil {
+		return invalid
+	}
+
+	if lower == '(' {
+		start = start.NextDay()
+	}
+	if upper == ')' {
+		end = end.PrevDay()
+	}
+	*r = Range{start, end}
+	return nil
+}
+
+// Implements sql.driver.Valuer interface, writing an inclusive range like "[2006-01-02,2006-01-31]"
+func (r Range) Value() (driver.Value, error) {
+	if r.IsEmpty() {
+		return "empty", nil
+	}
+	return "[" + r.Start.String() + "," + r.End.String() + "]", nil
+}
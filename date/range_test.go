@@ -0,0 +1,132 @@
+package date
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+func TestRangeDays(t *testing.T) {
+	july := Range{At(2016, time.July, 1, nil), At(2016, time.July, 31, nil)}
+	expect.Equal(t, july.Days(), 31)
+	expect.False(t, july.IsEmpty())
+	expect.Equal(t, july.String(), "2016-07-01/2016-07-31")
+
+	leap := Range{At(2016, time.February, 28, nil), At(2016, time.March, 1, nil)}
+	expect.Equal(t, leap.Days(), 3)
+
+	oneDay := Range{At(2016, time.July, 4, nil), At(2016, time.July, 4, nil)}
+	expect.Equal(t, oneDay.Days(), 1)
+
+	empty := Range{At(2016, time.July, 5, nil), At(2016, time.July, 4, nil)}
+	expect.Equal(t, empty.Days(), 0)
+	expect.True(t, empty.IsEmpty())
+	expect.Equal(t, len(empty.Dates()), 0)
+}
+
+func TestRangeContains(t *testing.T) {
+	july := Range{At(2016, time.July, 1, nil), At(2016, time.July, 31, nil)}
+	expect.True(t, july.Contains(At(2016, time.July, 1, nil)))
+	expect.True(t, july.Contains(At(2016, time.July, 15, nil)))
+	expect.True(t, july.Contains(At(2016, time.July, 31, nil)))
+	expect.False(t, july.Contains(At(2016, time.June, 30, nil)))
+	expect.False(t, july.Contains(At(2016, time.August, 1, nil)))
+	expect.False(t, july.Contains(At(2015, time.July, 15, nil)))
+}
+
+func TestRangeOverlaps(t *testing.T) {
+	july := Range{At(2016, time.July, 1, nil), At(2016, time.July, 31, nil)}
+	august := Range{At(2016, time.August, 1, nil), At(2016, time.August, 31, nil)}
+	midsummer := Range{At(2016, time.July, 15, nil), At(2016, time.August, 15, nil)}
+	lastDay := Range{At(2016, time.July, 31, nil), At(2016, time.August, 5, nil)}
+	empty := Range{At(2016, time.July, 10, nil), At(2016, time.July, 9, nil)}
+
+	expect.False(t, july.Overlaps(august))
+	expect.True(t, july.Overlaps(midsummer))
+	expect.True(t, midsummer.Overlaps(august))
+	expect.True(t, july.Overlaps(lastDay))
+	expect.False(t, july.Overlaps(empty))
+
+	expect.Equal(t, july.Intersect(midsummer), Range{At(2016, time.July, 15, nil), At(2016, time.July, 31, nil)})
+	expect.Equal(t, july.Intersect(lastDay).Days(), 1)
+	expect.True(t, july.Intersect(august).IsEmpty())
+
+	union, ok := july.Union(august) // adjacent
+	expect.True(t, ok)
+	expect.Equal(t, union, Range{At(2016, time.July, 1, nil), At(2016, time.August, 31, nil)})
+
+	union, ok = august.Union(midsummer)
+	expect.True(t, ok)
+	expect.Equal(t, union, Range{At(2016, time.July, 15, nil), At(2016, time.August, 31, nil)})
+
+	union, ok = empty.Union(august)
+	expect.True(t, ok)
+	expect.Equal(t, union, august)
+
+	september := Range{At(2016, time.September, 2, nil), At(2016, time.September, 30, nil)}
+	_, ok = july.Union(september)
+	expect.False(t, ok)
+}
+
+func TestRangeEach(t *testing.T) {
+	newYear := Range{At(2015, time.December, 30, nil), At(2016, time.January, 2, nil)}
+	expect.Equal(t, newYear.Dates(), []Date{
+		At(2015, time.December, 30, nil),
+		At(2015, time.December, 31, nil),
+		At(2016, time.January, 1, nil),
+		At(2016, time.January, 2, nil),
+	})
+
+	count := 0
+	newYear.Each(func(d Date) bool {
+		count += 1
+		return d.Year < 2016
+	})
+	expect.Equal(t, count, 3)
+}
+
+func TestRangeJSON(t *testing.T) {
+	july := Range{At(2016, time.July, 1, time.UTC), At(2016, time.July, 31, time.UTC)}
+	bytes, err := json.Marshal(july)
+	expect.Nil(t, err)
+	expect.Equal(t, string(bytes), `{"start":"2016-07-01","end":"2016-07-31"}`)
+
+	var decoded Range
+	err = json.Unmarshal(bytes, &decoded)
+	expect.Nil(t, err)
+	expect.Equal(t, decoded, july)
+
+	err = json.Unmarshal([]byte(`{"start":"2016-07-01","end":"July 31"}`), &decoded)
+	expect.NotNil(t, err)
+}
+
+func TestRangeSql(t *testing.T) {
+	july := Range{At(2016, time.July, 1, time.UTC), At(2016, time.July, 31, time.UTC)}
+	value, err := july.Value()
+	expect.Nil(t, err)
+	expect.Equal(t, value, "[2016-07-01,2016-07-31]")
+
+	examples := []string{"[2016-07-01,2016-08-01)", "(2016-06-30,2016-07-31]", "[2016-07-01,2016-07-31]", `["2016-07-01","2016-08-01")`}
+	for _, example := range examples {
+		var r Range
+		err := r.Scan(example)
+		expect.Nil(t, err, example)
+		expect.Equal(t, r, july, example)
+	}
+
+	var r Range
+	expect.Nil(t, r.Scan([]byte("empty")))
+	expect.True(t, r.IsEmpty())
+	value, err = r.Value()
+	expect.Nil(t, err)
+	expect.Equal(t, value, "empty")
+
+	err = r.Scan("[2016-07-01,)")
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.Error(), `date: scan value "[2016-07-01,)" is not a bounded date range`)
+	}
+	expect.NotNil(t, r.Scan("2016-07-01"))
+	expect.NotNil(t, r.Scan(3))
+}
@@ -0,0 +1,411 @@
+package expect
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Assertions has each expectation as a method, for tests which check many
+// expectations with the same testing.TB.  The Must methods stop the test with
+// t.FailNow if the expectation is false.
+//
+//    expect := expect.New(t)
+//    user, err := db.FindUser(3)
+//    expect.MustNoError(err)
+//    expect.Equal(user.Name, "Kermit")
+//
+type Assertions struct {
+	t testing.TB
+}
+
+// New returns the Assertions for a test
+func New(t testing.TB) *Assertions {
+	return &Assertions{t}
+}
+
+// That returns the Assertions for a test, like New
+//
+//    expect.That(t).Equal(greeting, "Hello world!")
+//
+func That(t testing.TB) *Assertions {
+	return &Assertions{t}
+}
+
+// True is the same as expect.True
+func (a *Assertions) True(val interface{}, msg ...interface{}) bool {
+	a.t.Helper()
+	return True(a.t, val, msg...)
+}
+
+// MustTrue is like True, but stops the test if the expectation is false
+func (a *Assertions) MustTrue(val interface{}, msg ...interface{}) {
+	a.t.Helper()
+	if !True(a.t, val, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// False is the same as expect.False
+func (a *Assertions) False(val interface{}, msg ...interface{}) bool {
+	a.t.Helper()
+	return False(a.t, val, msg...)
+}
+
+// MustFalse is like False, but stops the test if the expectation is false
+func (a *Assertions) MustFalse(val interface{}, msg ...interface{}) {
+	a.t.Helper()
+	if !False(a.t, val, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// Equal is the same as expect.Equal
+func (a *Assertions) Equal(actual, expected interface{}, msg ...interface{}) bool {
+	a.t.Helper()
+	return Equal(a.t, actual, expected, msg...)
+}
+
+// MustEqual is like Equal, but stops the test if the expectation is false
+func (a *Assertions) MustEqual(actual, expected interface{}, msg ...interface{}) {
+	a.t.Helper()
+	if !Equal(a.t, actual, expected, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// NotEqual is the same as expect.NotEqual
+func (a *Assertions) NotEqual(actual, expected interface{}, msg ...interface{}) bool {
+	a.t.Helper()
+	return NotEqual(a.t, actual, expected, msg...)
+}
+
+// MustNotEqual is like NotEqual, but stops the test if the expectation is false
+func (a *Assertions) MustNotEqual(actual, expected interface{}, msg ...interface{}) {
+	a.t.Helper()
+	if !NotEqual(a.t, actual, expected, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// Nil is the same as expect.Nil
+func (a *Assertions) Nil(val interface{}, msg ...interface{}) bool {
+	a.t.Helper()
+	return Nil(a.t, val, msg...)
+}
+
+// MustNil is like Nil, but stops the test if the expectation is false
+func (a *Assertions) MustNil(val interface{}, msg ...interface{}) {
+	a.t.Helper()
+	if !Nil(a.t, val, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// NotNil is the same as expect.NotNil
+func (a *Assertions) NotNil(val interface{}, msg ...interface{}) bool {
+	a.t.Helper()
+	return NotNil(a.t, val, msg...)
+}
+
+// MustNotNil is like NotNil, but stops the test if the expectation is false
+func (a *Assertions) MustNotNil(val interface{}, msg ...interface{}) {
+	a.t.Helper()
+	if !NotNil(a.t, val, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// Empty is the same as expect.Empty
+func (a *Assertions) Empty(val interface{}, msg ...interface{}) bool {
+	a.t.Helper()
+	return Empty(a.t, val, msg...)
+}
+
+// MustEmpty is like Empty, but stops the test if the expectation is false
+func (a *Assertions) MustEmpty(val interface{}, msg ...interface{}) {
+	a.t.Helper()
+	if !Empty(a.t, val, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// NotEmpty is the same as expect.NotEmpty
+func (a *Assertions) NotEmpty(val interface{}, msg ...interface{}) bool {
+	a.t.Helper()
+	return NotEmpty(a.t, val, msg...)
+}
+
+// MustNotEmpty is like NotEmpty, but stops the test if the expectation is false
+func (a *Assertions) MustNotEmpty(val interface{}, msg ...interface{}) {
+	a.t.Helper()
+	if !NotEmpty(a.t, val, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// Contains is the same as expect.Contains
+func (a *Assertions) Contains(set, elem interface{}, msg ...interface{}) bool {
+	a.t.Helper()
+	return Contains(a.t, set, elem, msg...)
+}
+
+// MustContains is like Contains, but stops the test if the expectation is false
+func (a *Assertions) MustContains(set, elem interface{}, msg ...interface{}) {
+	a.t.Helper()
+	if !Contains(a.t, set, elem, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// NotContains is the same as expect.NotContains
+func (a *Assertions) NotContains(set, elem interface{}, msg ...interface{}) bool {
+	a.t.Helper()
+	return NotContains(a.t, set, elem, msg...)
+}
+
+// MustNotContains is like NotContains, but stops the test if the expectation is false
+func (a *Assertions) MustNotContains(set, elem interface{}, msg ...interface{}) {
+	a.t.Helper()
+	if !NotContains(a.t, set, elem, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// AlmostEqual is the same as expect.AlmostEqual
+func (a *Assertions) AlmostEqual(actual, expected interface{}, deltaOrMsg ...interface{}) bool {
+	a.t.Helper()
+	return AlmostEqual(a.t, actual, expected, deltaOrMsg...)
+}
+
+// MustAlmostEqual is like AlmostEqual, but stops the test if the expectation is false
+func (a *Assertions) MustAlmostEqual(actual, expected interface{}, deltaOrMsg ...interface{}) {
+	a.t.Helper()
+	if !AlmostEqual(a.t, actual, expected, deltaOrMsg...) {
+		a.t.FailNow()
+	}
+}
+
+// Regexp is the same as expect.Regexp
+func (a *Assertions) Regexp(str interface{}, exp interface{}, msg ...interface{}) bool {
+	a.t.Helper()
+	return Regexp(a.t, str, exp, msg...)
+}
+
+// MustRegexp is like Regexp, but stops the test if the expectation is false
+func (a *Assertions) MustRegexp(str interface{}, exp interface{}, msg ...interface{}) {
+	a.t.Helper()
+	if !Regexp(a.t, str, exp, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// NotRegexp is the same as expect.NotRegexp
+func (a *Assertions) NotRegexp(str interface{}, exp interface{}, msg ...interface{}) bool {
+	a.t.Helper()
+	return NotRegexp(a.t, str, exp, msg...)
+}
+
+// MustNotRegexp is like NotRegexp, but stops the test if the expectation is false
+func (a *Assertions) MustNotRegexp(str interface{}, exp interface{}, msg ...interface{}) {
+	a.t.Helper()
+	if !NotRegexp(a.t, str, exp, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// NoError is the same as expect.NoError
+func (a *Assertions) NoError(err error, msg ...interface{}) bool {
+	a.t.Helper()
+	return NoError(a.t, err, msg...)
+}
+
+// MustNoError is like NoError, but stops the test if the expectation is false
+func (a *Assertions) MustNoError(err error, msg ...interface{}) {
+	a.t.Helper()
+	if !NoError(a.t, err, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// Error is the same as expect.Error
+func (a *Assertions) Error(err error, msg ...interface{}) bool {
+	a.t.Helper()
+	return Error(a.t, err, msg...)
+}
+
+// MustError is like Error, but stops the test if the expectation is false
+func (a *Assertions) MustError(err error, msg ...interface{}) {
+	a.t.Helper()
+	if !Error(a.t, err, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// ErrorIs is the same as expect.ErrorIs
+func (a *Assertions) ErrorIs(err, target error, msg ...interface{}) bool {
+	a.t.Helper()
+	return ErrorIs(a.t, err, target, msg...)
+}
+
+// MustErrorIs is like ErrorIs, but stops the test if the expectation is false
+func (a *Assertions) MustErrorIs(err, target error, msg ...interface{}) {
+	a.t.Helper()
+	if !ErrorIs(a.t, err, target, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// ErrorAs is the same as expect.ErrorAs
+func (a *Assertions) ErrorAs(err error, target interface{}, msg ...interface{}) bool {
+	a.t.Helper()
+	return ErrorAs(a.t, err, target, msg...)
+}
+
+// MustErrorAs is like ErrorAs, but stops the test if the expectation is false
+func (a *Assertions) MustErrorAs(err error, target interface{}, msg ...interface{}) {
+	a.t.Helper()
+	if !ErrorAs(a.t, err, target, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// ErrorContains is the same as expect.ErrorContains
+func (a *Assertions) ErrorContains(err error, substr string, msg ...interface{}) bool {
+	a.t.Helper()
+	return ErrorContains(a.t, err, substr, msg...)
+}
+
+// MustErrorContains is like ErrorContains, but stops the test if the expectation is false
+func (a *Assertions) MustErrorContains(err error, substr string, msg ...interface{}) {
+	a.t.Helper()
+	if !ErrorContains(a.t, err, substr, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// Panics is the same as expect.Panics
+func (a *Assertions) Panics(fn func(), msg ...interface{}) bool {
+	a.t.Helper()
+	return Panics(a.t, fn, msg...)
+}
+
+// MustPanics is like Panics, but stops the test if the expectation is false
+func (a *Assertions) MustPanics(fn func(), msg ...interface{}) {
+	a.t.Helper()
+	if !Panics(a.t, fn, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// NotPanics is the same as expect.NotPanics
+func (a *Assertions) NotPanics(fn func(), msg ...interface{}) bool {
+	a.t.Helper()
+	return NotPanics(a.t, fn, msg...)
+}
+
+// MustNotPanics is like NotPanics, but stops the test if the expectation is false
+func (a *Assertions) MustNotPanics(fn func(), msg ...interface{}) {
+	a.t.Helper()
+	if !NotPanics(a.t, fn, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// PanicsWithValue is the same as expect.PanicsWithValue
+func (a *Assertions) PanicsWithValue(expected interface{}, fn func(), msg ...interface{}) bool {
+	a.t.Helper()
+	return PanicsWithValue(a.t, expected, fn, msg...)
+}
+
+// MustPanicsWithValue is like PanicsWithValue, but stops the test if the expectation is false
+func (a *Assertions) MustPanicsWithValue(expected interface{}, fn func(), msg ...interface{}) {
+	a.t.Helper()
+	if !PanicsWithValue(a.t, expected, fn, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// PanicsWithError is the same as expect.PanicsWithError
+func (a *Assertions) PanicsWithError(message string, fn func(), msg ...interface{}) bool {
+	a.t.Helper()
+	return PanicsWithError(a.t, message, fn, msg...)
+}
+
+// MustPanicsWithError is like PanicsWithError, but stops the test if the expectation is false
+func (a *Assertions) MustPanicsWithError(message string, fn func(), msg ...interface{}) {
+	a.t.Helper()
+	if !PanicsWithError(a.t, message, fn, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// Eventually is the same as expect.Eventually
+func (a *Assertions) Eventually(cond func() bool, timeout, interval time.Duration, msg ...interface{}) bool {
+	a.t.Helper()
+	return Eventually(a.t, cond, timeout, interval, msg...)
+}
+
+// MustEventually is like Eventually, but stops the test if the expectation is false
+func (a *Assertions) MustEventually(cond func() bool, timeout, interval time.Duration, msg ...interface{}) {
+	a.t.Helper()
+	if !Eventually(a.t, cond, timeout, interval, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// Never is the same as expect.Never
+func (a *Assertions) Never(cond func() bool, duration, interval time.Duration, msg ...interface{}) bool {
+	a.t.Helper()
+	return Never(a.t, cond, duration, interval, msg...)
+}
+
+// MustNever is like Never, but stops the test if the expectation is false
+func (a *Assertions) MustNever(cond func() bool, duration, interval time.Duration, msg ...interface{}) {
+	a.t.Helper()
+	if !Never(a.t, cond, duration, interval, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// IsType is the same as expect.IsType
+func (a *Assertions) IsType(val, example interface{}, msg ...interface{}) bool {
+	a.t.Helper()
+	return IsType(a.t, val, example, msg...)
+}
+
+// MustIsType is like IsType, but stops the test if the expectation is false
+func (a *Assertions) MustIsType(val, example interface{}, msg ...interface{}) {
+	a.t.Helper()
+	if !IsType(a.t, val, example, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// Implements is the same as expect.Implements
+func (a *Assertions) Implements(iface, val interface{}, msg ...interface{}) bool {
+	a.t.Helper()
+	return Implements(a.t, iface, val, msg...)
+}
+
+// MustImplements is like Implements, but stops the test if the expectation is false
+func (a *Assertions) MustImplements(iface, val interface{}, msg ...interface{}) {
+	a.t.Helper()
+	if !Implements(a.t, iface, val, msg...) {
+		a.t.FailNow()
+	}
+}
+
+// Kind is the same as expect.Kind
+func (a *Assertions) Kind(val interface{}, kind reflect.Kind, msg ...interface{}) bool {
+	a.t.Helper()
+	return Kind(a.t, val, kind, msg...)
+}
+
+// MustKind is like Kind, but stops the test if the expectation is false
+func (a *Assertions) MustKind(val interface{}, kind reflect.Kind, msg ...interface{}) {
+	a.t.Helper()
+	if !Kind(a.t, val, kind, msg...) {
+		a.t.FailNow()
+	}
+}
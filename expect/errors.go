@@ -0,0 +1,111 @@
+package expect
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// NoError returns true only if the error is nil.
+// An error is reported with t.Errorf if the expectation is false.
+//
+//    expect.NoError(t, err)
+//    expect.NoError(t, err, "should have saved %v", user)
+//
+func NoError(t testing.TB, err error, msg ...interface{}) bool {
+	t.Helper()
+	if err != nil {
+		return errorf(t, fmt.Sprintf("Expected no error, but got: %v", err), msg...)
+	}
+	return true
+}
+
+// Error returns true only if the error is not nil.
+// An error is reported with t.Errorf if the expectation is false.
+//
+//    expect.Error(t, err)
+//    expect.Error(t, err, "should not parse %q", input)
+//
+func Error(t testing.TB, err error, msg ...interface{}) bool {
+	t.Helper()
+	if err == nil {
+		return errorf(t, "Expected an error, but got nil.", msg...)
+	}
+	return true
+}
+
+// ErrorIs returns true only if the error or an error that it wraps is the
+// target, as determined by errors.Is.
+// An error is reported with t.Errorf if the expectation is false.
+//
+//    expect.ErrorIs(t, err, sql.ErrNoRows)
+//    expect.ErrorIs(t, err, context.DeadlineExceeded, "should time out")
+//
+func ErrorIs(t testing.TB, err, target error, msg ...interface{}) bool {
+	t.Helper()
+	if !errors.Is(err, target) {
+		return errorf(t, fmt.Sprintf("Expected error to be %s, but got: %s", describeError(target), describeChain(err)), msg...)
+	}
+	return true
+}
+
+// ErrorAs returns true only if the error or an error that it wraps can be
+// assigned to target, which must be a pointer to an error type or interface.
+// If it is, target is set to that error (as with errors.As).
+// An error is reported with t.Errorf if the expectation is false.
+//
+//    var parseErr *parser.ParseError
+//    if expect.ErrorAs(t, err, &parseErr) {
+//        expect.Equal(t, parseErr.Pos.Line, 3)
+//    }
+//
+func ErrorAs(t testing.TB, err error, target interface{}, msg ...interface{}) bool {
+	t.Helper()
+	if !errors.As(err, target) {
+		typ := reflect.TypeOf(target).Elem()
+		return errorf(t, fmt.Sprintf("Expected error to be a %v, but got: %s", typ, describeChain(err)), msg...)
+	}
+	return true
+}
+
+// ErrorContains returns true only if the error is not nil and its message
+// contains the substring.
+// An error is reported with t.Errorf if the expectation is false.
+//
+//    expect.ErrorContains(t, err, "unknown column")
+//
+func ErrorContains(t testing.TB, err error, substr string, msg ...interface{}) bool {
+	t.Helper()
+	if err == nil {
+		return errorf(t, fmt.Sprintf("Expected an error containing %q, but got nil.", substr), msg...)
+	}
+	if !strings.Contains(err.Error(), substr) {
+		return errorf(t, fmt.Sprintf("Expected error %q to contain %q", err.Error(), substr), msg...)
+	}
+	return true
+}
+
+// describeChain formats an error and each error that it wraps, like
+// `"sql: no rows in result set" (*errors.errorString)`, so that failures show
+// the wrap chain.
+func describeChain(err error) string {
+	if err == nil {
+		return "nil"
+	}
+
+	var chain []string
+	for err != nil {
+		chain = append(chain, describeError(err))
+		err = errors.Unwrap(err)
+	}
+	return strings.Join(chain, " wrapping ")
+}
+
+func describeError(err error) string {
+	if err == nil {
+		return "nil"
+	}
+	return fmt.Sprintf("%q (%T)", err.Error(), err)
+}
@@ -0,0 +1,74 @@
+package expect
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// A Clock is the source of time used by Eventually and Never to wait between
+// checks.  A *clock.Source from "github.com/reflexionhealth/vanilla/clock"
+// is a Clock.
+type Clock interface {
+	UTC() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// PollingClock is the Clock used by Eventually and Never.  To test code
+// which uses them without waiting, set it to a frozen clock source and
+// advance the source from another goroutine.
+//
+// By default it reads the system time, like an unfrozen clock.Default.  It
+// can't be clock.Default itself, because the clock package's tests use
+// expect, so expect importing clock would be an import cycle.  To poll with
+// the Default clock (such as when a test freezes it with clock.Freeze), set
+// PollingClock to &clock.Default.
+var PollingClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) UTC() time.Time                         { return time.Now().UTC() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Eventually returns true only if the condition becomes true before the
+// timeout, checking it immediately and then after every interval.
+// An error is reported with t.Errorf if the expectation is false.
+//
+//    expect.Eventually(t, server.IsAvailable, time.Second, 10*time.Millisecond)
+//    expect.Eventually(t, func() bool { return len(queue.Done()) == 3 }, time.Second, 10*time.Millisecond)
+//
+func Eventually(t testing.TB, cond func() bool, timeout, interval time.Duration, msg ...interface{}) bool {
+	t.Helper()
+	deadline := PollingClock.UTC().Add(timeout)
+	for {
+		if cond() {
+			return true
+		}
+		if !PollingClock.UTC().Before(deadline) {
+			return errorf(t, fmt.Sprintf("Expected condition to become true within %v.", timeout), msg...)
+		}
+		<-PollingClock.After(interval)
+	}
+}
+
+// Never returns true only if the condition stays false for the duration,
+// checking it immediately and then after every interval.
+// An error is reported with t.Errorf if the expectation is false.
+//
+//    expect.Never(t, func() bool { return worker.Failed() }, time.Second, 10*time.Millisecond)
+//
+func Never(t testing.TB, cond func() bool, duration, interval time.Duration, msg ...interface{}) bool {
+	t.Helper()
+	start := PollingClock.UTC()
+	deadline := start.Add(duration)
+	for {
+		if cond() {
+			elapsed := PollingClock.UTC().Sub(start)
+			return errorf(t, fmt.Sprintf("Expected condition to stay false for %v, but it was true after %v.", duration, elapsed), msg...)
+		}
+		if !PollingClock.UTC().Before(deadline) {
+			return true
+		}
+		<-PollingClock.After(interval)
+	}
+}
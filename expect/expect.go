@@ -5,11 +5,8 @@ import (
 	"math"
 	"reflect"
 	"regexp"
-	"runtime"
 	"strings"
 	"testing"
-	"unicode"
-	"unicode/utf8"
 )
 
 // True returns true only if the value is true.
@@ -18,7 +15,8 @@ import (
 //    expect.True(t, isSomething)
 //    expect.True(t, isSomethingElse, "should be something else")
 //
-func True(t *testing.T, val interface{}, msg ...interface{}) bool {
+func True(t testing.TB, val interface{}, msg ...interface{}) bool {
+	t.Helper()
 	if val != true {
 		return errorf(t, "Expected value to be true.", msg...)
 	}
@@ -31,7 +29,8 @@ func True(t *testing.T, val interface{}, msg ...interface{}) bool {
 //    expect.False(t, isSomething)
 //    expect.False(t, isSomethingElse, "should not be something else")
 //
-func False(t *testing.T, val interface{}, msg ...interface{}) bool {
+func False(t testing.TB, val interface{}, msg ...interface{}) bool {
+	t.Helper()
 	if val != false {
 		return errorf(t, "Expected value to be false.", msg...)
 	}
@@ -45,7 +44,8 @@ func False(t *testing.T, val interface{}, msg ...interface{}) bool {
 //    expect.Equal(t, greeting, "Hello world!")
 //    expect.Equal(t, 3, 3.0, "value of 3 is not 3")
 //
-func Equal(t *testing.T, actual, expected interface{}, msg ...interface{}) bool {
+func Equal(t testing.TB, actual, expected interface{}, msg ...interface{}) bool {
+	t.Helper()
 	if !areEqual(actual, expected) {
 		return errorf(t, fmt.Sprintf("Expected %#v, but got: %#v", expected, actual), msg...)
 	}
@@ -58,9 +58,10 @@ func Equal(t *testing.T, actual, expected interface{}, msg ...interface{}) bool
 //    expect.NotEqual(t, greeting, "Goodbye planet!")
 //    expect.NotEqual(t, 3.0, 3.14, "value of pie is not 3.0")
 //
-func NotEqual(t *testing.T, actual, expected interface{}, msg ...interface{}) bool {
+func NotEqual(t testing.TB, actual, expected interface{}, msg ...interface{}) bool {
+	t.Helper()
 	if areEqual(actual, expected) {
-		return errorf(t, fmt.Sprintf("Expected value not to equal: %#v", expected, actual), msg...)
+		return errorf(t, fmt.Sprintf("Expected value not to equal: %#v", expected), msg...)
 	}
 	return true
 }
@@ -88,9 +89,10 @@ func areEqual(actual, expected interface{}) bool {
 //    expect.Nil(t, err)
 //    expect.Nil(t, err, "err should be noting")
 //
-func Nil(t *testing.T, val interface{}, msg ...interface{}) bool {
+func Nil(t testing.TB, val interface{}, msg ...interface{}) bool {
+	t.Helper()
 	if !isNil(val) {
-		return errorf(t, fmt.Sprintf("Expected nil, but got: %#v", val))
+		return errorf(t, fmt.Sprintf("Expected nil, but got: %#v", val), msg...)
 	}
 	return true
 }
@@ -101,7 +103,8 @@ func Nil(t *testing.T, val interface{}, msg ...interface{}) bool {
 //    expect.NotNil(t, err)
 //    expect.NotNil(t, err, "err should be something")
 //
-func NotNil(t *testing.T, val interface{}, msg ...interface{}) bool {
+func NotNil(t testing.TB, val interface{}, msg ...interface{}) bool {
+	t.Helper()
 	if isNil(val) {
 		return errorf(t, "Expected value not to be nil.", msg...)
 	}
@@ -132,12 +135,13 @@ func isNil(val interface{}) bool {
 //   expect.Empty(t, nil)      // false
 //   expect.Empty(t, 0)        // false
 //
-func Empty(t *testing.T, val interface{}, msg ...interface{}) bool {
+func Empty(t testing.TB, val interface{}, msg ...interface{}) bool {
+	t.Helper()
 	isEmpty, canBeEmpty := isEmpty(val)
 	if !canBeEmpty {
 		return errorf(t, fmt.Sprintf("Expected value to be empty, but cannot check emptiness of: %v", val), msg...)
 	}
-	if isEmpty {
+	if !isEmpty {
 		return errorf(t, fmt.Sprintf("Expected value to be empty, but got: %v", val), msg...)
 	}
 	return true
@@ -153,7 +157,8 @@ func Empty(t *testing.T, val interface{}, msg ...interface{}) bool {
 //   expect.NotEmpty(t, "")       // false
 //   expect.NotEmpty(t, 13)       // false
 //
-func NotEmpty(t *testing.T, val interface{}, msg ...interface{}) bool {
+func NotEmpty(t testing.TB, val interface{}, msg ...interface{}) bool {
+	t.Helper()
 	isEmpty, canBeEmpty := isEmpty(val)
 	if !canBeEmpty {
 		return errorf(t, fmt.Sprintf("Expected value not to be empty, but cannot check emptiness of: %v", val), msg...)
@@ -208,7 +213,8 @@ func isEmpty(val interface{}) (isEmpty bool, canBeEmpty bool) {
 //    expect.Contains(t, "Hello world", "world")
 //    expect.Contains(t, {"key": "value"}, "key")
 //
-func Contains(t *testing.T, set, elem interface{}, msg ...interface{}) bool {
+func Contains(t testing.TB, set, elem interface{}, msg ...interface{}) bool {
+	t.Helper()
 	hasElement, isContainer := containsElement(set, elem)
 	if !isContainer {
 		return errorf(t, fmt.Sprintf("Expected value to be a container, but got: %v", set), msg...)
@@ -227,7 +233,8 @@ func Contains(t *testing.T, set, elem interface{}, msg ...interface{}) bool {
 //    expect.NotContains(t, "Hello world", "earth")
 //    expect.NotContains(t, {"a": "apple"}, "b")
 //
-func NotContains(t *testing.T, set, elem interface{}, msg ...interface{}) bool {
+func NotContains(t testing.TB, set, elem interface{}, msg ...interface{}) bool {
+	t.Helper()
 	hasElement, isContainer := containsElement(set, elem)
 	if !isContainer {
 		return errorf(t, fmt.Sprintf("Expected value to be a container, but got: %v", set), msg...)
@@ -277,7 +284,8 @@ func containsElement(set interface{}, elem interface{}) (hasElement, isContainer
 // 	 expect.AlmostEqual(t, math.Pi, (22 / 7.0))
 // 	 expect.AlmostEqual(t, math.Pi, (22 / 7.0), 0.05)
 //
-func AlmostEqual(t *testing.T, actual, expected interface{}, deltaOrMsg ...interface{}) bool {
+func AlmostEqual(t testing.TB, actual, expected interface{}, deltaOrMsg ...interface{}) bool {
+	t.Helper()
 	var msg []interface{}
 	var delta float64
 	if len(deltaOrMsg) > 0 {
@@ -322,7 +330,8 @@ func toFloat(val interface{}) (float64, bool) {
 //  expect.Regexp(t, "it's starting", regexp.MustCompile("start"))
 //  expect.Regexp(t, "it's not starting", "start...$")
 //
-func Regexp(t *testing.T, str interface{}, exp interface{}, msg ...interface{}) bool {
+func Regexp(t testing.TB, str interface{}, exp interface{}, msg ...interface{}) bool {
+	t.Helper()
 	if !matchRegexp(exp, str) {
 		return errorf(t, fmt.Sprintf("Expected \"%v\" to match \"%v\"", str, exp), msg...)
 	}
@@ -335,7 +344,8 @@ func Regexp(t *testing.T, str interface{}, exp interface{}, msg ...interface{})
 //  expect.NotRegexp(t, "it's starting", regexp.MustCompile("starts"))
 //  expect.NotRegexp(t, "it's not starting", "start$")
 //
-func NotRegexp(t *testing.T, str interface{}, exp interface{}, msg ...interface{}) bool {
+func NotRegexp(t testing.TB, str interface{}, exp interface{}, msg ...interface{}) bool {
+	t.Helper()
 	if matchRegexp(exp, str) {
 		return errorf(t, fmt.Sprintf("Expected \"%v\" to NOT match \"%v\"", str, exp), msg...)
 	}
@@ -355,92 +365,21 @@ func matchRegexp(exp interface{}, str interface{}) bool {
 }
 
 // errorf emits an error message for a failed assertion and always returns false.
-func errorf(t *testing.T, expectation string, msg ...interface{}) bool {
-	stacktrace := strings.Join(getStacktrace(), "\n\r\t\t ")
+func errorf(t testing.TB, expectation string, msg ...interface{}) bool {
+	t.Helper()
 	if len(msg) > 0 {
-		t.Errorf("\r%s\r\tMessage: %s\n\r\t  Error: %s\n\r\t  Trace: %s\n\r",
-			getWhitespaceString(),
-			fmt.Sprintf(msg[0].(string), msg[1:]...),
-			expectation,
-			stacktrace)
+		t.Errorf("\n\tMessage: %s\n\t  Error: %s", formatMessage(msg), expectation)
 	} else {
-		t.Errorf("\r%s\r\t  Error: %s\n\r\t  Trace: %s\n\r",
-			getWhitespaceString(),
-			expectation,
-			stacktrace)
+		t.Errorf("\n\t  Error: %s", expectation)
 	}
 	return false
 }
 
-// NOTE: Mostly stolen from "github.com/stretchr/testify".
-// getStacktrace return the current stacktrace, ignoring frames in this package.
-func getStacktrace() []string {
-	pc := uintptr(0)
-	file := ""
-	line := 0
-	ok := false
-	name := ""
-
-	callers := []string{}
-	for i := 0; ; i++ {
-		pc, file, line, ok = runtime.Caller(i)
-		if !ok {
-			return nil
-		}
-
-		if file == "<autogenerated>" {
-			break
-		}
-
-		if strings.HasSuffix(file, "/expect/expect.go") {
-			continue
-		}
-
-		parts := strings.Split(file, "/")
-		file = parts[len(parts)-1]
-		callers = append(callers, fmt.Sprintf("%s:%d", file, line))
-		f := runtime.FuncForPC(pc)
-		if f == nil {
-			break
-		}
-
-		name = f.Name()
-		segments := strings.Split(name, ".")
-		name = segments[len(segments)-1]
-		if isTest(name, "Test") ||
-			isTest(name, "Benchmark") ||
-			isTest(name, "Example") {
-			break
-		}
-	}
-
-	return callers
-}
-
-// NOTE: Stolen from the `go test` tool.
-// isTest tells whether name looks like a test (or benchmark, according to prefix).
-// It is a Test (say) if there is a character after Test that is not a lower-case letter.
-// We don't want TesticularCancer.
-func isTest(name, prefix string) bool {
-	if !strings.HasPrefix(name, prefix) {
-		return false
-	}
-	if len(name) == len(prefix) { // "Test" is ok
-		return true
-	}
-	rune, _ := utf8.DecodeRuneInString(name[len(prefix):])
-	return !unicode.IsLower(rune)
-}
-
-// NOTE: Mysticism stolen from "github.com/stretchr/testify".
-// getWhitespaceString returns a string that is long enough to overwrite the
-// default output from the go testing framework.
-func getWhitespaceString() string {
-	_, file, line, ok := runtime.Caller(1)
-	if !ok {
-		return ""
+// formatMessage formats the optional message of an assertion, which is
+// usually a format string and its arguments.
+func formatMessage(msg []interface{}) string {
+	if format, ok := msg[0].(string); ok {
+		return fmt.Sprintf(format, msg[1:]...)
 	}
-	parts := strings.Split(file, "/")
-	file = parts[len(parts)-1]
-	return strings.Repeat(" ", len(fmt.Sprintf("%s:%d:        ", file, line)))
+	return fmt.Sprint(msg...)
 }
@@ -0,0 +1,389 @@
+package expect
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// recorder is a testing.TB which records the errors reported to it
+type recorder struct {
+	testing.TB
+	errors  []string
+	stopped bool
+}
+
+func (r *recorder) Helper() {}
+
+// FailNow stops the goroutine like testing.T.FailNow, so assertions which
+// call it must be run with r.run
+func (r *recorder) FailNow() {
+	r.stopped = true
+	runtime.Goexit()
+}
+
+func (r *recorder) run(fn func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+	<-done
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// an example is an expectation, and the error it should report, or an empty
+// string if the expectation should be true
+type example struct {
+	assert func(t testing.TB) bool
+	error  string
+}
+
+func checkExamples(t *testing.T, examples []example) {
+	t.Helper()
+	for i, example := range examples {
+		rec := &recorder{TB: t}
+		ok := example.assert(rec)
+		if len(example.error) == 0 {
+			if !ok || len(rec.errors) > 0 {
+				t.Errorf("example %d: expected the expectation to be true, but got %v with errors %q", i, ok, rec.errors)
+			}
+			continue
+		}
+		if ok {
+			t.Errorf("example %d: expected the expectation to be false", i)
+		}
+		if want := []string{"\n\t  Error: " + example.error}; !reflect.DeepEqual(rec.errors, want) {
+			t.Errorf("example %d: wrong errors reported\n\tGot:  %q\n\tWant: %q", i, rec.errors, want)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	boom := errors.New("boom")
+	wrapped := fmt.Errorf("read: %w", io.ErrUnexpectedEOF)
+	pathErr := fmt.Errorf("open: %w", &fs.PathError{Op: "open", Path: "a.txt", Err: fs.ErrNotExist})
+
+	checkExamples(t, []example{
+		{func(t testing.TB) bool { return NoError(t, nil) }, ""},
+		{func(t testing.TB) bool { return NoError(t, boom) }, "Expected no error, but got: boom"},
+
+		{func(t testing.TB) bool { return Error(t, boom) }, ""},
+		{func(t testing.TB) bool { return Error(t, nil) }, "Expected an error, but got nil."},
+
+		{func(t testing.TB) bool { return ErrorIs(t, wrapped, io.ErrUnexpectedEOF) }, ""},
+		{func(t testing.TB) bool { return ErrorIs(t, boom, io.EOF) },
+			`Expected error to be "EOF" (*errors.errorString), but got: "boom" (*errors.errorString)`},
+		{func(t testing.TB) bool { return ErrorIs(t, wrapped, io.EOF) },
+			`Expected error to be "EOF" (*errors.errorString), but got: "read: unexpected EOF" (*fmt.wrapError)` +
+				` wrapping "unexpected EOF" (*errors.errorString)`},
+		{func(t testing.TB) bool { return ErrorIs(t, nil, io.EOF) },
+			`Expected error to be "EOF" (*errors.errorString), but got: nil`},
+
+		{func(t testing.TB) bool { var target *fs.PathError; return ErrorAs(t, pathErr, &target) }, ""},
+		{func(t testing.TB) bool { var target *fs.PathError; return ErrorAs(t, boom, &target) },
+			`Expected error to be a *fs.PathError, but got: "boom" (*errors.errorString)`},
+
+		{func(t testing.TB) bool { return ErrorContains(t, wrapped, "unexpected") }, ""},
+		{func(t testing.TB) bool { return ErrorContains(t, wrapped, "timeout") },
+			`Expected error "read: unexpected EOF" to contain "timeout"`},
+		{func(t testing.TB) bool { return ErrorContains(t, nil, "timeout") },
+			`Expected an error containing "timeout", but got nil.`},
+	})
+
+	// ErrorAs sets the target, like errors.As
+	var target *fs.PathError
+	if ErrorAs(&recorder{TB: t}, pathErr, &target) && (target == nil || target.Path != "a.txt") {
+		t.Errorf("expected ErrorAs to set the target, but got %#v", target)
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	rec := &recorder{TB: t}
+	NoError(rec, errors.New("boom"), "should save user %d", 3)
+	Error(rec, nil, "should not parse")
+
+	want := []string{
+		"\n\tMessage: should save user 3\n\t  Error: Expected no error, but got: boom",
+		"\n\tMessage: should not parse\n\t  Error: Expected an error, but got nil.",
+	}
+	if !reflect.DeepEqual(rec.errors, want) {
+		t.Errorf("wrong errors reported\n\tGot:  %q\n\tWant: %q", rec.errors, want)
+	}
+}
+
+func TestPanics(t *testing.T) {
+	panics := func() { panic("wocka") }
+	panicsWithError := func() { panic(errors.New("wocka wocka")) }
+	returns := func() {}
+
+	checkExamples(t, []example{
+		{func(t testing.TB) bool { return Panics(t, panics) }, ""},
+		{func(t testing.TB) bool { return Panics(t, func() { panic(nil) }) }, ""},
+		{func(t testing.TB) bool { return Panics(t, returns) }, "Expected function to panic."},
+
+		{func(t testing.TB) bool { return NotPanics(t, returns) }, ""},
+		{func(t testing.TB) bool { return NotPanics(t, panics) },
+			`Expected function not to panic, but it panicked with: "wocka"`},
+
+		{func(t testing.TB) bool { return PanicsWithValue(t, "wocka", panics) }, ""},
+		{func(t testing.TB) bool { return PanicsWithValue(t, "wocka", returns) },
+			`Expected function to panic with: "wocka"`},
+		{func(t testing.TB) bool { return PanicsWithValue(t, "fozzie", panics) },
+			`Expected function to panic with "fozzie", but it panicked with: "wocka"`},
+
+		{func(t testing.TB) bool { return PanicsWithError(t, "wocka wocka", panicsWithError) }, ""},
+		{func(t testing.TB) bool { return PanicsWithError(t, "wocka wocka", returns) },
+			`Expected function to panic with error: "wocka wocka"`},
+		{func(t testing.TB) bool { return PanicsWithError(t, "wocka wocka", panics) },
+			`Expected function to panic with an error, but it panicked with: "wocka"`},
+		{func(t testing.TB) bool { return PanicsWithError(t, "wocka", panicsWithError) },
+			`Expected function to panic with error "wocka", but it panicked with: "wocka wocka"`},
+	})
+}
+
+// fakeClock is a Clock which advances by the duration of each wait, so that
+// Eventually and Never return without waiting
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) UTC() time.Time { return c.now }
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func TestEventually(t *testing.T) {
+	defer func(clock Clock) { PollingClock = clock }(PollingClock)
+	PollingClock = &fakeClock{now: time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	// trueAfter returns a condition which is true once it is checked n times
+	trueAfter := func(n int) func() bool {
+		calls := 0
+		return func() bool {
+			calls += 1
+			return calls >= n
+		}
+	}
+
+	checkExamples(t, []example{
+		{func(t testing.TB) bool { return Eventually(t, trueAfter(1), time.Second, 100*time.Millisecond) }, ""},
+		{func(t testing.TB) bool { return Eventually(t, trueAfter(3), time.Second, 100*time.Millisecond) }, ""},
+		{func(t testing.TB) bool { return Eventually(t, trueAfter(20), time.Second, 100*time.Millisecond) },
+			"Expected condition to become true within 1s."},
+
+		{func(t testing.TB) bool { return Never(t, trueAfter(20), time.Second, 100*time.Millisecond) }, ""},
+		{func(t testing.TB) bool { return Never(t, trueAfter(1), time.Second, 100*time.Millisecond) },
+			"Expected condition to stay false for 1s, but it was true after 0s."},
+		{func(t testing.TB) bool { return Never(t, trueAfter(3), time.Second, 100*time.Millisecond) },
+			"Expected condition to stay false for 1s, but it was true after 200ms."},
+	})
+}
+
+func TestEventuallyWithSystemClock(t *testing.T) {
+	start := time.Now()
+	done := make(chan struct{})
+	time.AfterFunc(20*time.Millisecond, func() { close(done) })
+
+	isDone := func() bool {
+		select {
+		case <-done:
+			return true
+		default:
+			return false
+		}
+	}
+	if !Eventually(t, isDone, time.Second, time.Millisecond) {
+		return
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("expected Eventually to wait for the condition, but it returned after %v", elapsed)
+	}
+}
+
+// an assertion is a method of Assertions and its Must variant, each called
+// with arguments which make the expectation true and false
+type assertion struct {
+	name           string
+	pass, fail     func(a *Assertions) bool
+	mustPass, must func(a *Assertions)
+}
+
+var assertions = []assertion{
+	{"True",
+		func(a *Assertions) bool { return a.True(true) }, func(a *Assertions) bool { return a.True(false) },
+		func(a *Assertions) { a.MustTrue(true) }, func(a *Assertions) { a.MustTrue(false) }},
+	{"False",
+		func(a *Assertions) bool { return a.False(false) }, func(a *Assertions) bool { return a.False(true) },
+		func(a *Assertions) { a.MustFalse(false) }, func(a *Assertions) { a.MustFalse(true) }},
+	{"Equal",
+		func(a *Assertions) bool { return a.Equal(3, 3.0) }, func(a *Assertions) bool { return a.Equal(3, 4) },
+		func(a *Assertions) { a.MustEqual("a", "a") }, func(a *Assertions) { a.MustEqual("a", "b") }},
+	{"NotEqual",
+		func(a *Assertions) bool { return a.NotEqual(3, 4) }, func(a *Assertions) bool { return a.NotEqual(3, 3.0) },
+		func(a *Assertions) { a.MustNotEqual("a", "b") }, func(a *Assertions) { a.MustNotEqual("a", "a") }},
+	{"Nil",
+		func(a *Assertions) bool { return a.Nil([]int(nil)) }, func(a *Assertions) bool { return a.Nil(3) },
+		func(a *Assertions) { a.MustNil(nil) }, func(a *Assertions) { a.MustNil(3) }},
+	{"NotNil",
+		func(a *Assertions) bool { return a.NotNil(3) }, func(a *Assertions) bool { return a.NotNil((*int)(nil)) },
+		func(a *Assertions) { a.MustNotNil(3) }, func(a *Assertions) { a.MustNotNil(nil) }},
+	{"Empty",
+		func(a *Assertions) bool { return a.Empty("") }, func(a *Assertions) bool { return a.Empty("a") },
+		func(a *Assertions) { a.MustEmpty([]int{}) }, func(a *Assertions) { a.MustEmpty(3) }},
+	{"NotEmpty",
+		func(a *Assertions) bool { return a.NotEmpty("a") }, func(a *Assertions) bool { return a.NotEmpty("") },
+		func(a *Assertions) { a.MustNotEmpty([]int{1}) }, func(a *Assertions) { a.MustNotEmpty(3) }},
+	{"Contains",
+		func(a *Assertions) bool { return a.Contains("Hello world", "world") }, func(a *Assertions) bool { return a.Contains([]int{3, 62}, 11) },
+		func(a *Assertions) { a.MustContains([]int{3, 62}, 62) }, func(a *Assertions) { a.MustContains(3, 3) }},
+	{"NotContains",
+		func(a *Assertions) bool { return a.NotContains("Hello world", "earth") }, func(a *Assertions) bool { return a.NotContains([]int{3, 62}, 3) },
+		func(a *Assertions) { a.MustNotContains([]int{3, 62}, 11) }, func(a *Assertions) { a.MustNotContains("Hello world", "world") }},
+	{"AlmostEqual",
+		func(a *Assertions) bool { return a.AlmostEqual(3.14, 22/7.0, 0.01) }, func(a *Assertions) bool { return a.AlmostEqual(3.14, 22/7.0) },
+		func(a *Assertions) { a.MustAlmostEqual(3, 3.0) }, func(a *Assertions) { a.MustAlmostEqual("3", 3) }},
+	{"Regexp",
+		func(a *Assertions) bool { return a.Regexp("it's starting", "start") }, func(a *Assertions) bool { return a.Regexp("it's starting", "start$") },
+		func(a *Assertions) { a.MustRegexp(42, "^4") }, func(a *Assertions) { a.MustRegexp(42, "^2") }},
+	{"NotRegexp",
+		func(a *Assertions) bool { return a.NotRegexp("it's starting", "start$") }, func(a *Assertions) bool { return a.NotRegexp("it's starting", "start") },
+		func(a *Assertions) { a.MustNotRegexp(42, "^2") }, func(a *Assertions) { a.MustNotRegexp(42, "^4") }},
+	{"NoError",
+		func(a *Assertions) bool { return a.NoError(nil) }, func(a *Assertions) bool { return a.NoError(io.EOF) },
+		func(a *Assertions) { a.MustNoError(nil) }, func(a *Assertions) { a.MustNoError(io.EOF) }},
+	{"Error",
+		func(a *Assertions) bool { return a.Error(io.EOF) }, func(a *Assertions) bool { return a.Error(nil) },
+		func(a *Assertions) { a.MustError(io.EOF) }, func(a *Assertions) { a.MustError(nil) }},
+	{"ErrorIs",
+		func(a *Assertions) bool { return a.ErrorIs(fmt.Errorf("read: %w", io.EOF), io.EOF) }, func(a *Assertions) bool { return a.ErrorIs(io.EOF, io.ErrClosedPipe) },
+		func(a *Assertions) { a.MustErrorIs(io.EOF, io.EOF) }, func(a *Assertions) { a.MustErrorIs(nil, io.EOF) }},
+	{"ErrorAs",
+		func(a *Assertions) bool { var target *fs.PathError; return a.ErrorAs(&fs.PathError{}, &target) },
+		func(a *Assertions) bool { var target *fs.PathError; return a.ErrorAs(io.EOF, &target) },
+		func(a *Assertions) { var target *fs.PathError; a.MustErrorAs(&fs.PathError{}, &target) },
+		func(a *Assertions) { var target *fs.PathError; a.MustErrorAs(io.EOF, &target) }},
+	{"ErrorContains",
+		func(a *Assertions) bool { return a.ErrorContains(io.EOF, "EOF") }, func(a *Assertions) bool { return a.ErrorContains(io.EOF, "pipe") },
+		func(a *Assertions) { a.MustErrorContains(io.EOF, "EOF") }, func(a *Assertions) { a.MustErrorContains(nil, "EOF") }},
+	{"Panics",
+		func(a *Assertions) bool { return a.Panics(func() { panic(1) }) }, func(a *Assertions) bool { return a.Panics(func() {}) },
+		func(a *Assertions) { a.MustPanics(func() { panic(1) }) }, func(a *Assertions) { a.MustPanics(func() {}) }},
+	{"NotPanics",
+		func(a *Assertions) bool { return a.NotPanics(func() {}) }, func(a *Assertions) bool { return a.NotPanics(func() { panic(1) }) },
+		func(a *Assertions) { a.MustNotPanics(func() {}) }, func(a *Assertions) { a.MustNotPanics(func() { panic(1) }) }},
+	{"PanicsWithValue",
+		func(a *Assertions) bool { return a.PanicsWithValue(1, func() { panic(1) }) }, func(a *Assertions) bool { return a.PanicsWithValue(2, func() { panic(1) }) },
+		func(a *Assertions) { a.MustPanicsWithValue(1, func() { panic(1) }) }, func(a *Assertions) { a.MustPanicsWithValue(1, func() {}) }},
+	{"PanicsWithError",
+		func(a *Assertions) bool { return a.PanicsWithError("EOF", func() { panic(io.EOF) }) },
+		func(a *Assertions) bool { return a.PanicsWithError("EOF", func() { panic("EOF") }) },
+		func(a *Assertions) { a.MustPanicsWithError("EOF", func() { panic(io.EOF) }) },
+		func(a *Assertions) { a.MustPanicsWithError("EOF", func() {}) }},
+	{"Eventually",
+		func(a *Assertions) bool {
+			return a.Eventually(func() bool { return true }, time.Second, time.Millisecond)
+		},
+		func(a *Assertions) bool { return a.Eventually(func() bool { return false }, 0, time.Millisecond) },
+		func(a *Assertions) { a.MustEventually(func() bool { return true }, time.Second, time.Millisecond) },
+		func(a *Assertions) { a.MustEventually(func() bool { return false }, 0, time.Millisecond) }},
+	{"Never",
+		func(a *Assertions) bool { return a.Never(func() bool { return false }, 0, time.Millisecond) },
+		func(a *Assertions) bool { return a.Never(func() bool { return true }, time.Second, time.Millisecond) },
+		func(a *Assertions) { a.MustNever(func() bool { return false }, 0, time.Millisecond) },
+		func(a *Assertions) { a.MustNever(func() bool { return true }, time.Second, time.Millisecond) }},
+	{"IsType",
+		func(a *Assertions) bool { return a.IsType(io.EOF, io.ErrClosedPipe) }, func(a *Assertions) bool { return a.IsType(3, int64(0)) },
+		func(a *Assertions) { a.MustIsType(3, 0) }, func(a *Assertions) { a.MustIsType(io.EOF, (*fs.PathError)(nil)) }},
+	{"Implements",
+		func(a *Assertions) bool { return a.Implements((*error)(nil), io.EOF) }, func(a *Assertions) bool { return a.Implements((*error)(nil), 3) },
+		func(a *Assertions) { a.MustImplements((*testing.TB)(nil), &recorder{}) }, func(a *Assertions) { a.MustImplements(io.EOF, io.EOF) }},
+	{"Kind",
+		func(a *Assertions) bool { return a.Kind(int64(3), reflect.Int64) }, func(a *Assertions) bool { return a.Kind(3, reflect.Int64) },
+		func(a *Assertions) { a.MustKind([]int{}, reflect.Slice) }, func(a *Assertions) { a.MustKind(nil, reflect.Slice) }},
+}
+
+func TestAssertions(t *testing.T) {
+	covered := map[string]bool{"New": true, "That": true}
+	for _, example := range assertions {
+		covered[example.name] = true
+		covered["Must"+example.name] = true
+
+		rec := &recorder{TB: t}
+		if !example.pass(New(rec)) || len(rec.errors) > 0 {
+			t.Errorf("%s: expected the expectation to be true, but got errors %q", example.name, rec.errors)
+		}
+		rec = &recorder{TB: t}
+		if example.fail(That(rec)) || len(rec.errors) != 1 {
+			t.Errorf("%s: expected the expectation to be false with one error, but got errors %q", example.name, rec.errors)
+		}
+
+		rec = &recorder{TB: t}
+		rec.run(func() { example.mustPass(New(rec)) })
+		if rec.stopped || len(rec.errors) > 0 {
+			t.Errorf("Must%s: expected the test to continue, but got errors %q", example.name, rec.errors)
+		}
+		rec = &recorder{TB: t}
+		rec.run(func() { example.must(New(rec)) })
+		if !rec.stopped || len(rec.errors) != 1 {
+			t.Errorf("Must%s: expected the test to stop with one error, but got errors %q", example.name, rec.errors)
+		}
+	}
+
+	// every method is tested
+	methods := reflect.TypeOf(&Assertions{})
+	for i := 0; i < methods.NumMethod(); i++ {
+		if name := methods.Method(i).Name; !covered[name] {
+			t.Errorf("Assertions.%s is not tested", name)
+		}
+	}
+}
+
+func TestAssertionsMessage(t *testing.T) {
+	rec := &recorder{TB: t}
+	New(rec).Equal(3, 4, "should be %d", 4)
+	New(rec).True(false, 3, 4)
+	New(rec).Nil(3, errors.New("boom"))
+
+	want := []string{
+		"\n\tMessage: should be 4\n\t  Error: Expected 4, but got: 3",
+		"\n\tMessage: 3 4\n\t  Error: Expected value to be true.",
+		"\n\tMessage: boom\n\t  Error: Expected nil, but got: 3",
+	}
+	if !reflect.DeepEqual(rec.errors, want) {
+		t.Errorf("wrong errors reported\n\tGot:  %q\n\tWant: %q", rec.errors, want)
+	}
+}
+
+func TestTypes(t *testing.T) {
+	checkExamples(t, []example{
+		{func(t testing.TB) bool { return IsType(t, io.EOF, io.ErrClosedPipe) }, ""},
+		{func(t testing.TB) bool { return IsType(t, (*fs.PathError)(nil), (*fs.PathError)(nil)) }, ""},
+		{func(t testing.TB) bool { return IsType(t, 3, int64(0)) }, "Expected value of type int64, but got: int"},
+		{func(t testing.TB) bool { return IsType(t, nil, 0) }, "Expected value of type int, but got: <nil>"},
+
+		{func(t testing.TB) bool { return Implements(t, (*error)(nil), io.EOF) }, ""},
+		{func(t testing.TB) bool { return Implements(t, (*fmt.Stringer)(nil), time.Second) }, ""},
+		{func(t testing.TB) bool { return Implements(t, (*error)(nil), 3) }, "Expected int to implement error"},
+		{func(t testing.TB) bool { return Implements(t, (*error)(nil), nil) }, "Expected <nil> to implement error"},
+		{func(t testing.TB) bool { return Implements(t, io.EOF, io.EOF) },
+			"Expected a pointer to an interface, but got: *errors.errorString"},
+
+		{func(t testing.TB) bool { return Kind(t, int64(3), reflect.Int64) }, ""},
+		{func(t testing.TB) bool { return Kind(t, map[string]int{}, reflect.Map) }, ""},
+		{func(t testing.TB) bool { return Kind(t, 3, reflect.Int64) }, "Expected value of kind int64, but got: int"},
+		{func(t testing.TB) bool { return Kind(t, nil, reflect.Ptr) }, "Expected value of kind ptr, but got: invalid"},
+	})
+}
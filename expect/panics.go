@@ -0,0 +1,90 @@
+package expect
+
+import (
+	"fmt"
+	"testing"
+)
+
+// didPanic runs fn and returns whether it panicked, and the recovered value
+func didPanic(fn func()) (panicked bool, value interface{}) {
+	panicked = true
+	defer func() {
+		if panicked {
+			value = recover()
+		}
+	}()
+
+	fn()
+	panicked = false
+	return
+}
+
+// Panics returns true only if the function panics.
+// An error is reported with t.Errorf if the expectation is false.
+//
+//    expect.Panics(t, func() { stmt.Values(1, 2, 3) })
+//
+func Panics(t testing.TB, fn func(), msg ...interface{}) bool {
+	t.Helper()
+	if panicked, _ := didPanic(fn); !panicked {
+		return errorf(t, "Expected function to panic.", msg...)
+	}
+	return true
+}
+
+// NotPanics returns true only if the function doesn't panic.
+// An error is reported with t.Errorf if the expectation is false.
+//
+//    expect.NotPanics(t, func() { router.GET("/users/:id", handler) })
+//
+func NotPanics(t testing.TB, fn func(), msg ...interface{}) bool {
+	t.Helper()
+	if panicked, value := didPanic(fn); panicked {
+		return errorf(t, fmt.Sprintf("Expected function not to panic, but it panicked with: %#v", value), msg...)
+	}
+	return true
+}
+
+// PanicsWithValue returns true only if the function panics with a value
+// equal to the expected value.  See Equal.
+// An error is reported with t.Errorf if the expectation is false.
+//
+//    expect.PanicsWithValue(t, "vanilla/clock: clock.Advance() requires a frozen clock", func() {
+//        src.Advance(time.Second)
+//    })
+//
+func PanicsWithValue(t testing.TB, expected interface{}, fn func(), msg ...interface{}) bool {
+	t.Helper()
+	panicked, value := didPanic(fn)
+	if !panicked {
+		return errorf(t, fmt.Sprintf("Expected function to panic with: %#v", expected), msg...)
+	}
+	if !areEqual(value, expected) {
+		return errorf(t, fmt.Sprintf("Expected function to panic with %#v, but it panicked with: %#v", expected, value), msg...)
+	}
+	return true
+}
+
+// PanicsWithError returns true only if the function panics with an error
+// which has the expected message.
+// An error is reported with t.Errorf if the expectation is false.
+//
+//    expect.PanicsWithError(t, "in InsertStmt.Values(...) expected 2 values but received 3", func() {
+//        sql.Insert("name, age").Into("users").Values("Ana", 31, "extra")
+//    })
+//
+func PanicsWithError(t testing.TB, message string, fn func(), msg ...interface{}) bool {
+	t.Helper()
+	panicked, value := didPanic(fn)
+	if !panicked {
+		return errorf(t, fmt.Sprintf("Expected function to panic with error: %q", message), msg...)
+	}
+	err, ok := value.(error)
+	if !ok {
+		return errorf(t, fmt.Sprintf("Expected function to panic with an error, but it panicked with: %#v", value), msg...)
+	}
+	if err.Error() != message {
+		return errorf(t, fmt.Sprintf("Expected function to panic with error %q, but it panicked with: %q", message, err.Error()), msg...)
+	}
+	return true
+}
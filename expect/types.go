@@ -0,0 +1,57 @@
+package expect
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// IsType returns true only if the value has the same type as the example
+// value, which is usually a typed nil.
+// An error is reported with t.Errorf if the expectation is false.
+//
+//    expect.IsType(t, err, (*parser.ParseError)(nil))
+//    expect.IsType(t, value, int64(0))
+//
+func IsType(t testing.TB, val, example interface{}, msg ...interface{}) bool {
+	t.Helper()
+	if actual, expected := reflect.TypeOf(val), reflect.TypeOf(example); actual != expected {
+		return errorf(t, fmt.Sprintf("Expected value of type %v, but got: %v", expected, actual), msg...)
+	}
+	return true
+}
+
+// Implements returns true only if the value implements an interface, which
+// is given as a nil pointer to the interface type.
+// An error is reported with t.Errorf if the expectation is false.
+//
+//    expect.Implements(t, (*json.Marshaler)(nil), null.Date{})
+//    expect.Implements(t, (*sql.Scanner)(nil), &null.Date{})
+//
+func Implements(t testing.TB, iface, val interface{}, msg ...interface{}) bool {
+	t.Helper()
+	ifaceType := reflect.TypeOf(iface)
+	if ifaceType == nil || ifaceType.Kind() != reflect.Ptr || ifaceType.Elem().Kind() != reflect.Interface {
+		return errorf(t, fmt.Sprintf("Expected a pointer to an interface, but got: %v", ifaceType), msg...)
+	}
+
+	ifaceType = ifaceType.Elem()
+	if valType := reflect.TypeOf(val); valType == nil || !valType.Implements(ifaceType) {
+		return errorf(t, fmt.Sprintf("Expected %v to implement %v", valType, ifaceType), msg...)
+	}
+	return true
+}
+
+// Kind returns true only if the value has the reflect.Kind.
+// An error is reported with t.Errorf if the expectation is false.
+//
+//    expect.Kind(t, row["id"], reflect.Int64)
+//    expect.Kind(t, columns, reflect.Slice)
+//
+func Kind(t testing.TB, val interface{}, kind reflect.Kind, msg ...interface{}) bool {
+	t.Helper()
+	if actual := reflect.ValueOf(val).Kind(); actual != kind {
+		return errorf(t, fmt.Sprintf("Expected value of kind %v, but got: %v", kind, actual), msg...)
+	}
+	return true
+}
@@ -0,0 +1,191 @@
+/*
+Package auth is a net/http middleware which authenticates requests with a
+bearer token (such as a JWT) or an API key, and puts the authenticated
+Principal in the request context.
+
+Create an Authenticator with a TokenValidator for each kind of credential:
+
+    authn := &auth.Authenticator{
+        Tokens:  &auth.JWT{PublicKey: crypto.MustLoadPublicKey("jwt.pem")},
+        APIKeys: auth.APIKeyFunc(lookupAPIKey),
+    }
+
+Then insert the handler in the chain, and require scopes for some routes:
+
+    mux.Use(authn.Handler)
+    mux.DELETE("/users/:id", deleteUser, auth.Require("admin"))
+
+Handlers read the principal with auth.GetPrincipal(req.Context()).  Requests
+without valid credentials receive a 401 Unauthorized, and requests without a
+required scope receive a 403 Forbidden, both with a JSON httpx/errors.Error.
+*/
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/reflexionhealth/vanilla/httpx"
+	"github.com/reflexionhealth/vanilla/httpx/errors"
+)
+
+// A Principal is the identity of an authenticated client.
+type Principal struct {
+	Subject string                 // the user or service which was authenticated
+	Scopes  []string               // the scopes the credentials were granted
+	Claims  map[string]interface{} // the other claims of a token, if any
+}
+
+// HasScope returns true if the principal was granted the scope.
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+var principals = httpx.NewLocal[*Principal]("principal")
+
+// GetPrincipal returns the principal authenticated for the request with the
+// context, or nil if the request wasn't authenticated.
+func GetPrincipal(ctx context.Context) *Principal {
+	return principals.Get(ctx)
+}
+
+// WithPrincipal returns a new Context carrying the principal, eg. for tests
+// of handlers which require authentication.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return principals.Put(ctx, p)
+}
+
+// A TokenValidator checks a credential, returning the principal it identifies
+// or an error if it isn't valid.
+type TokenValidator interface {
+	ValidateToken(ctx context.Context, token string) (*Principal, error)
+}
+
+// APIKeyFunc is a TokenValidator which looks up an API key, eg. in a database.
+// It should return a nil Principal if the key doesn't exist.
+type APIKeyFunc func(ctx context.Context, key string) (*Principal, error)
+
+func (fn APIKeyFunc) ValidateToken(ctx context.Context, key string) (*Principal, error) {
+	p, err := fn(ctx, key)
+	if err == nil && p == nil {
+		return nil, &TokenError{"unknown API key"}
+	}
+	return p, err
+}
+
+// A TokenError explains why a credential isn't valid.
+type TokenError struct {
+	Reason string
+}
+
+func (err *TokenError) Error() string {
+	return "auth: " + err.Reason
+}
+
+// DefaultAPIKeyHeader is the request header read by an Authenticator which
+// doesn't set an APIKeyHeader.
+const DefaultAPIKeyHeader = "X-Api-Key"
+
+// An Authenticator is middleware which authenticates each request.
+type Authenticator struct {
+	// Tokens validates tokens from an "Authorization: Bearer <token>" header
+	Tokens TokenValidator
+
+	// APIKeys validates keys from the APIKeyHeader
+	APIKeys      TokenValidator
+	APIKeyHeader string
+
+	// Optional lets requests without any credentials through, without a
+	// principal.  Requests with invalid credentials are still rejected.
+	Optional bool
+}
+
+// Handler returns a Handler which authenticates the requests to h.
+func (a *Authenticator) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		validator, token, bearer := a.credentials(req)
+		if len(token) == 0 {
+			if a.Optional {
+				h.ServeHTTP(w, req)
+			} else {
+				unauthorized(w, bearer, errors.Unauthorized("missing_credentials", "Authentication is required"))
+			}
+			return
+		}
+
+		var p *Principal
+		var err error
+		if validator != nil {
+			p, err = validator.ValidateToken(req.Context(), token)
+		}
+		if err != nil || p == nil {
+			invalid := errors.Unauthorized("invalid_credentials", "The credentials are invalid")
+			invalid.Meta.Error = err
+			unauthorized(w, bearer, invalid)
+			return
+		}
+
+		h.ServeHTTP(w, req.WithContext(WithPrincipal(req.Context(), p)))
+	})
+}
+
+// credentials returns the token in the request and the validator for it
+func (a *Authenticator) credentials(req *http.Request) (validator TokenValidator, token string, bearer bool) {
+	header := a.APIKeyHeader
+	if len(header) == 0 {
+		header = DefaultAPIKeyHeader
+	}
+	if key := req.Header.Get(header); len(key) > 0 {
+		return a.APIKeys, key, false
+	}
+
+	scheme, token, _ := strings.Cut(req.Header.Get("Authorization"), " ")
+	if strings.EqualFold(scheme, "Bearer") {
+		return a.Tokens, strings.TrimSpace(token), a.Tokens != nil
+	}
+	return nil, "", a.Tokens != nil
+}
+
+// Require returns middleware which only allows requests from principals
+// with all of the scopes.  It must be used after an Authenticator.
+func Require(scopes ...string) httpx.Middleware {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			p := GetPrincipal(req.Context())
+			if p == nil {
+				unauthorized(w, false, errors.Unauthorized("missing_credentials", "Authentication is required"))
+				return
+			}
+
+			for _, scope := range scopes {
+				if !p.HasScope(scope) {
+					forbidden := errors.Forbidden("missing_scope", "You don't have permission to do that")
+					forbidden.DebugMessage = "requires the scope '" + scope + "'"
+					writeError(w, forbidden)
+					return
+				}
+			}
+			h.ServeHTTP(w, req)
+		})
+	}
+}
+
+func unauthorized(w http.ResponseWriter, bearer bool, err *errors.Error) {
+	if bearer {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+	writeError(w, err)
+}
+
+func writeError(w http.ResponseWriter, err *errors.Error) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(err.HTTPStatus)
+	json.NewEncoder(w).Encode(err)
+}
@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/reflexionhealth/vanilla/expect"
+	"github.com/reflexionhealth/vanilla/httpx"
+)
+
+func TestAuthenticator(t *testing.T) {
+	authn := &Authenticator{
+		Tokens: &JWT{Secret: testSecret},
+		APIKeys: APIKeyFunc(func(ctx context.Context, key string) (*Principal, error) {
+			if key == "gonzo-key" {
+				return &Principal{Subject: "gonzo", Scopes: []string{"admin"}}, nil
+			}
+			return nil, nil
+		}),
+	}
+
+	var subject string
+	mux := httpx.NewMux()
+	mux.Use(authn.Handler)
+	mux.GET("/users", func(w http.ResponseWriter, r *http.Request) {
+		subject = GetPrincipal(r.Context()).Subject
+	})
+	mux.DELETE("/users", func(w http.ResponseWriter, r *http.Request) {
+		subject = GetPrincipal(r.Context()).Subject
+	}, Require("admin"))
+
+	token := makeToken("HS256", map[string]interface{}{"sub": "kermit", "scope": "read"})
+	examples := []struct {
+		method  string
+		header  string
+		value   string
+		code    int
+		subject string
+		body    string
+	}{
+		{"GET", "Authorization", "Bearer " + token, 200, "kermit", ``},
+		{"GET", "Authorization", "bearer " + token, 200, "kermit", ``},
+		{"GET", "X-Api-Key", "gonzo-key", 200, "gonzo", ``},
+		{"DELETE", "X-Api-Key", "gonzo-key", 200, "gonzo", ``},
+		{"DELETE", "Authorization", "Bearer " + token, 403, "",
+			`{"user_message":"You don't have permission to do that","debug_message":"requires the scope 'admin'"}`},
+		{"GET", "", "", 401, "", `{"user_message":"Authentication is required"}`},
+		{"GET", "Authorization", "Basic a2VybWl0", 401, "", `{"user_message":"Authentication is required"}`},
+		{"GET", "Authorization", "Bearer " + token + "x", 401, "", `{"user_message":"The credentials are invalid"}`},
+		{"GET", "X-Api-Key", "fozzie-key", 401, "", `{"user_message":"The credentials are invalid"}`},
+	}
+	for _, example := range examples {
+		subject = ""
+		r, _ := http.NewRequest(example.method, "/users", nil)
+		if len(example.header) > 0 {
+			r.Header.Set(example.header, example.value)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+
+		msg := "%s with %s: %s"
+		expect.Equal(t, w.Code, example.code, msg, example.method, example.header, example.value)
+		expect.Equal(t, subject, example.subject, msg, example.method, example.header, example.value)
+		if len(example.body) > 0 {
+			expect.Equal(t, w.Body.String(), example.body+"\n", msg, example.method, example.header, example.value)
+			expect.Equal(t, w.Header().Get("Content-Type"), "application/json; charset=utf-8")
+		}
+		if example.code == 401 && example.header != "X-Api-Key" {
+			expect.Equal(t, w.Header().Get("WWW-Authenticate"), "Bearer", msg, example.method, example.header, example.value)
+		}
+	}
+}
+
+func TestAuthenticatorOptional(t *testing.T) {
+	authn := &Authenticator{Tokens: &JWT{Secret: testSecret}, Optional: true}
+	var principal *Principal
+	handler := authn.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal = GetPrincipal(r.Context())
+	}))
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	expect.Equal(t, w.Code, 200)
+	expect.Nil(t, principal)
+
+	r.Header.Set("Authorization", "Bearer invalid")
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	expect.Equal(t, w.Code, 401)
+}
+
+func TestRequireWithoutAuthenticator(t *testing.T) {
+	handler := Require("admin")(http.NotFoundHandler())
+	r, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	expect.Equal(t, w.Code, 401)
+
+	r = r.WithContext(WithPrincipal(r.Context(), &Principal{Subject: "gonzo", Scopes: []string{"admin"}}))
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	expect.Equal(t, w.Code, 404)
+}
@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/clock"
+	"github.com/reflexionhealth/vanilla/crypto"
+)
+
+// JWT is a TokenValidator for JSON Web Tokens signed with HS256 (using the
+// Secret) or RS256 (using the PublicKey, eg. from crypto.LoadPublicKey).
+// Tokens signed with any other algorithm are rejected.
+//
+// The "sub" claim is the principal's Subject, and the "scope" claim (a space
+// separated string) is its Scopes.  The "exp" and "nbf" claims are checked
+// if they are present, and the "iss" and "aud" claims are checked if the JWT
+// sets an Issuer or Audience.
+type JWT struct {
+	Secret    []byte           // the key for HS256 tokens
+	PublicKey crypto.PublicKey // an *rsa.PublicKey for RS256 tokens
+
+	Issuer   string // the required issuer, if not empty
+	Audience string // the required audience, if not empty
+
+	// Leeway allows for clock skew when checking "exp" and "nbf"
+	Leeway time.Duration
+
+	// Clock is the clock used to check "exp" and "nbf", or the clock in the
+	// request context (see clock.FromContext) if it is nil
+	Clock *clock.Source
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+}
+
+type jwtClaims struct {
+	Subject   string          `json:"sub"`
+	Issuer    string          `json:"iss"`
+	Audience  json.RawMessage `json:"aud"`
+	Expires   *float64        `json:"exp"`
+	NotBefore *float64        `json:"nbf"`
+	Scope     string          `json:"scope"`
+}
+
+// ValidateToken checks the token's signature and claims.
+func (j *JWT) ValidateToken(ctx context.Context, token string) (*Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, &TokenError{"malformed token"}
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, &TokenError{"malformed token header"}
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, &TokenError{"malformed token signature"}
+	}
+	if !j.verify(header.Alg, []byte(parts[0]+"."+parts[1]), sig) {
+		return nil, &TokenError{"invalid token signature"}
+	}
+
+	var claims jwtClaims
+	var all map[string]interface{}
+	if decodeSegment(parts[1], &claims) != nil || decodeSegment(parts[1], &all) != nil {
+		return nil, &TokenError{"malformed token claims"}
+	}
+	if err := j.checkClaims(ctx, claims); err != nil {
+		return nil, err
+	}
+
+	return &Principal{
+		Subject: claims.Subject,
+		Scopes:  strings.Fields(claims.Scope),
+		Claims:  all,
+	}, nil
+}
+
+func (j *JWT) verify(alg string, msg, sig []byte) bool {
+	switch alg {
+	case "HS256":
+		if len(j.Secret) == 0 {
+			return false
+		}
+		mac := hmac.New(sha256.New, j.Secret)
+		mac.Write(msg)
+		return hmac.Equal(sig, mac.Sum(nil))
+	case "RS256":
+		if _, isRsa := j.PublicKey.(*rsa.PublicKey); !isRsa {
+			return false
+		}
+		return crypto.VerifySha256(j.PublicKey, msg, sig)
+	default:
+		return false
+	}
+}
+
+func (j *JWT) checkClaims(ctx context.Context, claims jwtClaims) error {
+	src := j.Clock
+	if src == nil {
+		src = clock.FromContext(ctx)
+	}
+	now := src.UTC()
+
+	if claims.Expires != nil && !now.Before(numericDate(*claims.Expires).Add(j.Leeway)) {
+		return &TokenError{"token is expired"}
+	}
+	if claims.NotBefore != nil && now.Add(j.Leeway).Before(numericDate(*claims.NotBefore)) {
+		return &TokenError{"token is not valid yet"}
+	}
+	if len(j.Issuer) > 0 && claims.Issuer != j.Issuer {
+		return &TokenError{"token has the wrong issuer"}
+	}
+	if len(j.Audience) > 0 && !hasAudience(claims.Audience, j.Audience) {
+		return &TokenError{"token has the wrong audience"}
+	}
+	return nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// numericDate converts seconds since the epoch to a time
+func numericDate(seconds float64) time.Time {
+	return time.Unix(0, int64(seconds*float64(time.Second))).UTC()
+}
+
+// hasAudience checks an "aud" claim, which may be a string or an array
+func hasAudience(claim json.RawMessage, audience string) bool {
+	var one string
+	if json.Unmarshal(claim, &one) == nil {
+		return one == audience
+	}
+	var many []string
+	if json.Unmarshal(claim, &many) == nil {
+		for _, aud := range many {
+			if aud == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/clock"
+	"github.com/reflexionhealth/vanilla/crypto"
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+var (
+	testSecret = []byte("kermit's secret")
+	testRsaKey = crypto.MustGenerateRsaKey(1024)
+	testNow    = time.Date(2016, time.July, 4, 12, 0, 0, 0, time.UTC)
+)
+
+func makeToken(alg string, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	msg := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	var sig []byte
+	switch alg {
+	case "HS256":
+		mac := hmac.New(sha256.New, testSecret)
+		mac.Write([]byte(msg))
+		sig = mac.Sum(nil)
+	case "RS256":
+		sig, _ = crypto.SignSha256(testRsaKey, []byte(msg))
+	}
+	return msg + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestJWT(t *testing.T) {
+	validator := &JWT{
+		Secret:    testSecret,
+		PublicKey: &testRsaKey.PublicKey,
+		Issuer:    "muppets",
+		Audience:  "theater",
+		Leeway:    time.Minute,
+		Clock:     clock.NewFake(testNow),
+	}
+	claims := map[string]interface{}{
+		"sub":   "kermit",
+		"iss":   "muppets",
+		"aud":   []string{"studio", "theater"},
+		"exp":   testNow.Add(time.Hour).Unix(),
+		"nbf":   testNow.Unix(),
+		"scope": "read write",
+		"team":  "frogs",
+	}
+
+	for _, alg := range []string{"HS256", "RS256"} {
+		p, err := validator.ValidateToken(context.Background(), makeToken(alg, claims))
+		if expect.Nil(t, err, "%s token", alg) {
+			expect.Equal(t, p.Subject, "kermit")
+			expect.Equal(t, p.Scopes, []string{"read", "write"})
+			expect.Equal(t, p.Claims["team"], "frogs")
+		}
+	}
+}
+
+func TestJWTErrors(t *testing.T) {
+	validator := &JWT{
+		Secret:   testSecret,
+		Issuer:   "muppets",
+		Audience: "theater",
+		Leeway:   time.Minute,
+	}
+	valid := func(changes map[string]interface{}) map[string]interface{} {
+		claims := map[string]interface{}{"sub": "kermit", "iss": "muppets", "aud": "theater"}
+		for key, value := range changes {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	examples := []struct {
+		token string
+		error string
+	}{
+		{"not a token", "auth: malformed token"},
+		{"a.b.c", "auth: malformed token header"},
+		{makeToken("HS256", valid(nil)) + "x", "auth: invalid token signature"},
+		{makeToken("RS256", valid(nil)), "auth: invalid token signature"}, // no public key
+		{makeToken("none", valid(nil)), "auth: invalid token signature"},
+		{makeToken("HS256", valid(map[string]interface{}{"exp": testNow.Add(-time.Minute).Unix()})), "auth: token is expired"},
+		{makeToken("HS256", valid(map[string]interface{}{"nbf": testNow.Add(2 * time.Minute).Unix()})), "auth: token is not valid yet"},
+		{makeToken("HS256", valid(map[string]interface{}{"iss": "sesame street"})), "auth: token has the wrong issuer"},
+		{makeToken("HS256", valid(map[string]interface{}{"aud": []string{"studio"}})), "auth: token has the wrong audience"},
+		{makeToken("HS256", valid(map[string]interface{}{"aud": 3})), "auth: token has the wrong audience"},
+	}
+
+	ctx := clock.WithClock(context.Background(), clock.NewFake(testNow))
+	for _, example := range examples {
+		_, err := validator.ValidateToken(ctx, example.token)
+		if expect.NotNil(t, err, "token %s", example.token) {
+			expect.Equal(t, err.Error(), example.error, "token %s", example.token)
+		}
+	}
+
+	// the leeway allows for a little clock skew
+	token := makeToken("HS256", valid(map[string]interface{}{"exp": testNow.Add(-30 * time.Second).Unix()}))
+	_, err := validator.ValidateToken(ctx, token)
+	expect.Nil(t, err)
+}
@@ -0,0 +1,407 @@
+package httpx
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/reflexionhealth/vanilla/httpx/errors"
+)
+
+// DefaultMaxBodySize is the largest request body read by a Binder which
+// doesn't set a MaxBodySize.
+const DefaultMaxBodySize = 1 << 20 // 1 MiB
+
+// A Validator can check its own fields after being bound from a request.
+// If Validate returns an *errors.Error it is returned as-is by the Binder,
+// otherwise it is returned as an errors.InvalidRequest.
+type Validator interface {
+	Validate() error
+}
+
+// A Binder decodes the body or query of a request into a struct, and then
+// validates the struct if it is a Validator.  The zero value is ready to use.
+//
+// The errors returned by a Binder are *errors.Error with the HTTPStatus to
+// respond with, such as 400 if the body is malformed, 413 if it is too large,
+// 415 if it has the wrong Content-Type, and 422 if it isn't valid.  When
+// form values or params can't be decoded, the 422 error lists the problem
+// with each field in its Fields.
+//
+//     var params struct {
+//         Name  string `json:"name" form:"name"`
+//         Teams []int  `json:"teams" form:"team"`
+//     }
+//     binder := httpx.Binder{DisallowUnknownFields: true}
+//     if err := binder.JSON(req, &params); err != nil {
+//         ...
+//     }
+//
+type Binder struct {
+	// MaxBodySize is the largest request body which will be read, in bytes.
+	// If it is zero, DefaultMaxBodySize is used.
+	MaxBodySize int64
+
+	// DisallowUnknownFields causes an error if the request has a field which
+	// doesn't match any field of the struct.
+	DisallowUnknownFields bool
+
+	// FieldNames converts the name of a struct field without a "form" tag to
+	// the name of its form value, such as inflect.Snakecase.  If it is nil,
+	// the field's name is used as-is.
+	FieldNames func(string) string
+}
+
+// BindJSON decodes a JSON request body into dst, using the default Binder.
+func BindJSON(req *http.Request, dst interface{}) error {
+	return Binder{}.JSON(req, dst)
+}
+
+// BindQuery decodes the query string of a request into dst, using the
+// default Binder.
+func BindQuery(req *http.Request, dst interface{}) error {
+	return Binder{}.Query(req, dst)
+}
+
+// BindForm decodes a form-encoded request body into dst, using the
+// default Binder.
+func BindForm(req *http.Request, dst interface{}) error {
+	return Binder{}.Form(req, dst)
+}
+
+// BindParams decodes the path params of a request into dst, using the
+// default Binder.
+func BindParams(req *http.Request, dst interface{}) error {
+	return Binder{}.Params(req, dst)
+}
+
+// JSON decodes the request body into dst with encoding/json.  The request
+// must have a Content-Type of "application/json".
+func (b Binder) JSON(req *http.Request, dst interface{}) error {
+	if err := requireContentType(req, "application/json"); err != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, b.maxBodySize()+1))
+	if err != nil {
+		return errors.BadRequest("could not read the request body")
+	} else if int64(len(body)) > b.maxBodySize() {
+		return bodyTooLarge(b.maxBodySize())
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if b.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(dst); err != nil {
+		return errors.BadRequest("invalid JSON in the request body: " + err.Error())
+	} else if decoder.More() {
+		return errors.BadRequest("the request body must contain a single JSON value")
+	}
+	return validate(dst)
+}
+
+// Query decodes the query string of the request into dst (see Form for how
+// the values are decoded).
+func (b Binder) Query(req *http.Request, dst interface{}) error {
+	if err := b.decodeValues(req.URL.Query(), dst); err != nil {
+		return err
+	}
+	return validate(dst)
+}
+
+// Form decodes the request body into dst.  The request must have a
+// Content-Type of "application/x-www-form-urlencoded" or "multipart/form-data",
+// and values in the query string are ignored.
+//
+// Values are assigned to the struct field with a matching "form" tag, or
+// with the same name if the field isn't tagged (see FieldNames), and fields
+// tagged with "-" are skipped.  Fields can be strings, bools, numbers, or
+// implement encoding.TextUnmarshaler, or be pointers or slices of those types.
+// If the struct has a field of any other type, nothing is bound and a 500
+// error is returned.
+func (b Binder) Form(req *http.Request, dst interface{}) error {
+	err := requireContentType(req, "application/x-www-form-urlencoded", "multipart/form-data")
+	if err != nil {
+		return err
+	}
+
+	req.Body = http.MaxBytesReader(nil, req.Body, b.maxBodySize())
+	if isFormEncoded(req) {
+		err = req.ParseForm()
+	} else {
+		err = req.ParseMultipartForm(b.maxBodySize())
+	}
+	if _, tooLarge := err.(*http.MaxBytesError); tooLarge {
+		return bodyTooLarge(b.maxBodySize())
+	} else if err != nil {
+		return errors.BadRequest("invalid form in the request body: " + err.Error())
+	}
+
+	if err := b.decodeValues(req.PostForm, dst); err != nil {
+		return err
+	}
+	return validate(dst)
+}
+
+// Params decodes the path params of the request (see GetParams) into dst,
+// in the same way as the values of a form.
+func (b Binder) Params(req *http.Request, dst interface{}) error {
+	params := GetParams(req.Context())
+	values := make(url.Values, len(params))
+	for _, param := range params {
+		values.Add(param.Key, param.Value)
+	}
+	if err := b.decodeValues(values, dst); err != nil {
+		return err
+	}
+	return validate(dst)
+}
+
+func (b Binder) maxBodySize() int64 {
+	if b.MaxBodySize > 0 {
+		return b.MaxBodySize
+	}
+	return DefaultMaxBodySize
+}
+
+func requireContentType(req *http.Request, allowed ...string) error {
+	contentType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	for _, typ := range allowed {
+		if contentType == typ {
+			return nil
+		}
+	}
+	return &errors.Error{
+		HTTPStatus:   http.StatusUnsupportedMediaType,
+		DebugMessage: fmt.Sprintf("expected a Content-Type of %q, but received %q", allowed[0], contentType),
+	}
+}
+
+func bodyTooLarge(limit int64) error {
+	return &errors.Error{
+		HTTPStatus:   http.StatusRequestEntityTooLarge,
+		DebugMessage: fmt.Sprintf("the request body must be at most %d bytes", limit),
+	}
+}
+
+func validate(dst interface{}) error {
+	validator, ok := dst.(Validator)
+	if !ok {
+		return nil
+	}
+
+	err := validator.Validate()
+	if err == nil {
+		return nil
+	} else if httpErr, ok := err.(*errors.Error); ok {
+		return httpErr
+	}
+	invalid := errors.InvalidRequest(err.Error())
+	invalid.Meta.Error = err
+	return invalid
+}
+
+func (b Binder) decodeValues(values url.Values, dst interface{}) error {
+	val := reflect.ValueOf(dst)
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("httpx: values must be bound to a pointer to a struct, not %T", dst))
+	}
+	if err := checkFormFields(val.Elem().Type()); err != nil {
+		return errors.InternalError(err)
+	}
+
+	var problems []errors.FieldError
+	known := make(map[string]bool)
+	walkFormFields(val.Elem(), b.FieldNames, func(name string, field reflect.Value) {
+		known[name] = true
+		if vals, ok := values[name]; ok {
+			if problem := setFormField(field, vals); len(problem) > 0 {
+				problems = append(problems, errors.FieldError{Field: name, Message: problem})
+			}
+		}
+	})
+
+	if b.DisallowUnknownFields {
+		var unknown []string
+		for name := range values {
+			if !known[name] {
+				unknown = append(unknown, name)
+			}
+		}
+		sort.Strings(unknown)
+		for _, name := range unknown {
+			problems = append(problems, errors.FieldError{Field: name, Message: "is not a known field"})
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	messages := make([]string, len(problems))
+	for i, problem := range problems {
+		messages[i] = fmt.Sprintf("field '%s' %s", problem.Field, problem.Message)
+	}
+	invalid := errors.InvalidRequest(strings.Join(messages, "; "))
+	invalid.Fields = problems
+	return invalid
+}
+
+// formFieldErrors caches the result of checkFormFields for each struct type
+var formFieldErrors sync.Map // map[reflect.Type]error
+
+// checkFormFields returns an error if the struct has a field which values
+// can't be bound to, so that it is reported before anything is bound
+func checkFormFields(typ reflect.Type) error {
+	if cached, ok := formFieldErrors.Load(typ); ok {
+		err, _ := cached.(error)
+		return err
+	}
+
+	var err error
+	walkFormFields(reflect.New(typ).Elem(), nil, func(name string, field reflect.Value) {
+		if err == nil && !canBindFormField(field.Type()) {
+			err = fmt.Errorf("httpx: can't bind the field '%s' with type %s", name, field.Type())
+		}
+	})
+	formFieldErrors.Store(typ, err)
+	return err
+}
+
+// walkFormFields calls fn with the name and value of each exported field in
+// the struct, descending into anonymous struct fields.  Untagged fields are
+// renamed with names, if it isn't nil.
+func walkFormFields(val reflect.Value, names func(string) string, fn func(string, reflect.Value)) {
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		fld := typ.Field(i)
+		tag := fld.Tag.Get("form")
+		if tag == "-" {
+			continue
+		}
+
+		if fld.Anonymous && len(tag) == 0 && fld.Type.Kind() == reflect.Struct {
+			walkFormFields(val.Field(i), names, fn)
+			continue
+		}
+		if len(fld.PkgPath) > 0 {
+			continue
+		}
+
+		name := tag
+		if len(name) == 0 && names != nil {
+			name = names(fld.Name)
+		} else if len(name) == 0 {
+			name = fld.Name
+		}
+		fn(name, val.Field(i))
+	}
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// canBindFormField reports whether setFormField can assign to a field of the type
+func canBindFormField(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Slice && !reflect.PtrTo(typ).Implements(textUnmarshalerType) {
+		return canBindFormValue(typ.Elem())
+	}
+	return canBindFormValue(typ)
+}
+
+// canBindFormValue reports whether setFormValue can assign to a value of the type
+func canBindFormValue(typ reflect.Type) bool {
+	if typ.Kind() == reflect.Ptr {
+		return canBindFormValue(typ.Elem())
+	} else if reflect.PtrTo(typ).Implements(textUnmarshalerType) {
+		return true
+	}
+
+	switch typ.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// setFormField assigns the values to the field, returning a description of
+// the problem if they aren't valid for the field's type.  The field's type
+// must have been checked by canBindFormField.
+func setFormField(field reflect.Value, vals []string) string {
+	if field.Kind() == reflect.Slice && !field.Addr().Type().Implements(textUnmarshalerType) {
+		slice := reflect.MakeSlice(field.Type(), len(vals), len(vals))
+		for i, value := range vals {
+			if problem := setFormValue(slice.Index(i), value); len(problem) > 0 {
+				return problem
+			}
+		}
+		field.Set(slice)
+		return ""
+	}
+	return setFormValue(field, vals[len(vals)-1])
+}
+
+func setFormValue(field reflect.Value, value string) string {
+	if field.Kind() == reflect.Ptr {
+		ptr := reflect.New(field.Type().Elem())
+		if problem := setFormValue(ptr.Elem(), value); len(problem) > 0 {
+			return problem
+		}
+		field.Set(ptr)
+		return ""
+	}
+
+	if unmarshaler, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		if err := unmarshaler.UnmarshalText([]byte(value)); err != nil {
+			return invalidFormValue(field, value)
+		}
+		return ""
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return invalidFormValue(field, value)
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return invalidFormValue(field, value)
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return invalidFormValue(field, value)
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return invalidFormValue(field, value)
+		}
+		field.SetFloat(n)
+	}
+	return ""
+}
+
+func invalidFormValue(field reflect.Value, value string) string {
+	return fmt.Sprintf("must be a valid %s, but was %q", field.Type(), value)
+}
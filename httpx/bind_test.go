@@ -0,0 +1,240 @@
+package httpx
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/reflexionhealth/vanilla/httpx/errors"
+	"github.com/reflexionhealth/vanilla/inflect"
+	"github.com/reflexionhealth/vanilla/uuid"
+)
+
+type bindTimestamps struct {
+	Created string `json:"created" form:"created"`
+}
+
+type bindParams struct {
+	bindTimestamps
+	Name    string     `json:"name" form:"name"`
+	Teams   []int      `json:"teams" form:"team"`
+	Admin   bool       `json:"admin" form:"admin"`
+	Score   *float64   `json:"score" form:"score"`
+	Owner   uuid.UUID  `json:"owner" form:"owner"`
+	Secret  string     `json:"-" form:"-"`
+	Default string     `json:"Default"`
+	ignored string     // unexported fields aren't bound
+	Invite  *uuid.UUID `json:"invite" form:"invite"`
+}
+
+func (p *bindParams) Validate() error {
+	if p.Name == "animal" {
+		return fmt.Errorf("name can't be %q", p.Name)
+	} else if p.Name == "gonzo" {
+		return errors.Forbidden("weirdo", "Gonzo isn't allowed")
+	}
+	return nil
+}
+
+func bindRequest(method, target, contentType, body string) *http.Request {
+	req, _ := http.NewRequest(method, target, strings.NewReader(body))
+	if len(contentType) > 0 {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req
+}
+
+func TestBindJSON(t *testing.T) {
+	var params bindParams
+	req := bindRequest("POST", "/", "application/json; charset=utf-8",
+		`{"name": "kermit", "teams": [1, 2], "admin": true, "score": 9.5, "created": "today", "Default": "yes"}`)
+	if err := BindJSON(req, &params); err != nil {
+		t.Fatalf("BindJSON failed: %v", err)
+	}
+	score := 9.5
+	want := bindParams{bindTimestamps{"today"}, "kermit", []int{1, 2}, true, &score, uuid.Nil, "", "yes", "", nil}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("BindJSON decoded %+v; Want %+v", params, want)
+	}
+}
+
+func TestBindQuery(t *testing.T) {
+	var params bindParams
+	req := bindRequest("GET", "/?name=kermit&team=1&team=2&admin=true&score=9.5&created=today&Default=yes&Secret=shh&ignored=x&owner=6ba7b810-9dad-11d1-80b4-00c04fd430c8&invite=6ba7b810-9dad-11d1-80b4-00c04fd430c8", "", "")
+	if err := BindQuery(req, &params); err != nil {
+		t.Fatalf("BindQuery failed: %v", err)
+	}
+	score := 9.5
+	owner := uuid.Must(uuid.FromString("6ba7b810-9dad-11d1-80b4-00c04fd430c8"))
+	want := bindParams{bindTimestamps{"today"}, "kermit", []int{1, 2}, true, &score, owner, "", "yes", "", &owner}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("BindQuery decoded %+v; Want %+v", params, want)
+	}
+}
+
+func TestBindForm(t *testing.T) {
+	var params bindParams
+	req := bindRequest("POST", "/?name=gonzo", "application/x-www-form-urlencoded", "name=kermit&team=3&admin=1")
+	if err := BindForm(req, &params); err != nil {
+		t.Fatalf("BindForm failed: %v", err)
+	}
+	if params.Name != "kermit" || !reflect.DeepEqual(params.Teams, []int{3}) || !params.Admin {
+		t.Errorf("BindForm decoded %+v; Want name=kermit, teams=[3], and admin=true", params)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("name", "fozzie")
+	writer.WriteField("team", "4")
+	writer.Close()
+
+	params = bindParams{}
+	req = bindRequest("POST", "/", writer.FormDataContentType(), body.String())
+	if err := BindForm(req, &params); err != nil {
+		t.Fatalf("BindForm failed for a multipart form: %v", err)
+	}
+	if params.Name != "fozzie" || !reflect.DeepEqual(params.Teams, []int{4}) {
+		t.Errorf("BindForm decoded %+v; Want name=fozzie and teams=[4]", params)
+	}
+}
+
+func TestBindParams(t *testing.T) {
+	var params struct {
+		ID    int       `form:"id"`
+		Owner uuid.UUID `form:"owner"`
+	}
+	req := bindRequest("GET", "/teams/3/6ba7b810-9dad-11d1-80b4-00c04fd430c8", "", "")
+	req = req.WithContext(Params{{"id", "3"}, {"owner", "6ba7b810-9dad-11d1-80b4-00c04fd430c8"}}.Put(req.Context()))
+	if err := BindParams(req, &params); err != nil {
+		t.Fatalf("BindParams failed: %v", err)
+	}
+	if params.ID != 3 || params.Owner.String() != "6ba7b810-9dad-11d1-80b4-00c04fd430c8" {
+		t.Errorf("BindParams decoded %+v; Want id=3 and owner=6ba7b810-9dad-11d1-80b4-00c04fd430c8", params)
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	strict := Binder{MaxBodySize: 32, DisallowUnknownFields: true}
+	testErrors := []struct {
+		bind    func(*http.Request, interface{}) error
+		req     *http.Request
+		status  int
+		message string
+	}{
+		{BindJSON, bindRequest("POST", "/", "text/plain", `{}`), 415,
+			`expected a Content-Type of "application/json", but received "text/plain"`},
+		{BindJSON, bindRequest("POST", "/", "application/json", `{"name": 3}`), 400,
+			`invalid JSON in the request body: json: cannot unmarshal number into Go struct field bindParams.name of type string`},
+		{BindJSON, bindRequest("POST", "/", "application/json", `{} {}`), 400,
+			`the request body must contain a single JSON value`},
+		{BindJSON, bindRequest("POST", "/", "application/json", `{"name": "animal"}`), 422,
+			`name can't be "animal"`},
+		{BindJSON, bindRequest("POST", "/", "application/json", `{"name": "gonzo"}`), 403, ``},
+		{strict.JSON, bindRequest("POST", "/", "application/json", `{"size": 3}`), 400,
+			`invalid JSON in the request body: json: unknown field "size"`},
+		{strict.JSON, bindRequest("POST", "/", "application/json", `{"name": "`+strings.Repeat("a", 32)+`"}`), 413,
+			`the request body must be at most 32 bytes`},
+		{BindQuery, bindRequest("GET", "/?team=1&team=two", "", ""), 422,
+			`field 'team' must be a valid int, but was "two"`},
+		{BindQuery, bindRequest("GET", "/?owner=kermit", "", ""), 422,
+			`field 'owner' must be a valid uuid.UUID, but was "kermit"`},
+		{BindQuery, bindRequest("GET", "/?name=animal", "", ""), 422,
+			`name can't be "animal"`},
+		{strict.Query, bindRequest("GET", "/?name=kermit&Secret=shh", "", ""), 422,
+			`field 'Secret' is not a known field`},
+		{BindForm, bindRequest("POST", "/", "application/json", `{}`), 415,
+			`expected a Content-Type of "application/x-www-form-urlencoded", but received "application/json"`},
+		{BindForm, bindRequest("POST", "/", "application/x-www-form-urlencoded", "admin=maybe"), 422,
+			`field 'admin' must be a valid bool, but was "maybe"`},
+		{strict.Form, bindRequest("POST", "/", "application/x-www-form-urlencoded", "name="+strings.Repeat("a", 32)), 413,
+			`the request body must be at most 32 bytes`},
+	}
+	for _, te := range testErrors {
+		var params bindParams
+		err := te.bind(te.req, &params)
+		httpErr, ok := err.(*errors.Error)
+		if !ok || httpErr.HTTPStatus != te.status || httpErr.DebugMessage != te.message {
+			t.Errorf("Binding %s %s failed: Error=%#v; Want %d, %q", te.req.Method, te.req.URL, err, te.status, te.message)
+		}
+	}
+
+	// each invalid field is listed in the error
+	err := strict.Query(bindRequest("GET", "/?admin=maybe&name=kermit&score=high&size=3&age=2", "", ""), &bindParams{})
+	want := []errors.FieldError{
+		{"admin", `must be a valid bool, but was "maybe"`},
+		{"score", `must be a valid float64, but was "high"`},
+		{"age", "is not a known field"},
+		{"size", "is not a known field"},
+	}
+	if httpErr, ok := err.(*errors.Error); !ok || httpErr.HTTPStatus != 422 || !reflect.DeepEqual(httpErr.Fields, want) {
+		t.Errorf("Binding invalid fields failed: Error=%#v; Want 422 with %v", err, want)
+	}
+
+	// the validation error from the struct is kept in the metadata
+	var params bindParams
+	err = BindQuery(bindRequest("GET", "/?name=animal", "", ""), &params)
+	if httpErr, ok := err.(*errors.Error); !ok || httpErr.Meta.Error == nil {
+		t.Errorf("Validation error wasn't kept in the metadata: Error=%#v", err)
+	}
+
+	// fields which can't be bound are an error even when there isn't a value for them
+	unsupported := []struct {
+		dst     interface{}
+		message string
+	}{
+		{&struct{ Values url.Values }{}, "httpx: can't bind the field 'Values' with type url.Values"},
+		{&struct {
+			Name  string
+			Teams []map[string]int `form:"team"`
+		}{}, "httpx: can't bind the field 'team' with type []map[string]int"},
+		{&struct{ Owner *struct{ ID int } }{}, "httpx: can't bind the field 'Owner' with type *struct { ID int }"},
+	}
+	for _, u := range unsupported {
+		err := BindQuery(bindRequest("GET", "/?Name=kermit", "", ""), u.dst)
+		httpErr, ok := err.(*errors.Error)
+		if !ok || httpErr.HTTPStatus != 500 || httpErr.Meta.Error == nil || httpErr.Meta.Error.Error() != u.message {
+			t.Errorf("Binding %T failed: Error=%#v; Want 500 with %q", u.dst, err, u.message)
+		}
+	}
+}
+
+func TestBindFieldNames(t *testing.T) {
+	var params struct {
+		TeamName string
+		HostURL  string
+		Owner    string `form:"OwnerName"`
+	}
+	binder := Binder{FieldNames: inflect.Snakecase, DisallowUnknownFields: true}
+	req := bindRequest("GET", "/?team_name=muppets&host_url=example.com&OwnerName=kermit", "", "")
+	if err := binder.Query(req, &params); err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if params.TeamName != "muppets" || params.HostURL != "example.com" || params.Owner != "kermit" {
+		t.Errorf("Query decoded %+v; Want team_name=muppets, host_url=example.com, and OwnerName=kermit", params)
+	}
+}
+
+func TestBindPanics(t *testing.T) {
+	testPanics := []struct {
+		dst   interface{}
+		panic string
+	}{
+		{map[string]string{}, "httpx: values must be bound to a pointer to a struct, not map[string]string"},
+		{bindParams{}, "httpx: values must be bound to a pointer to a struct, not httpx.bindParams"},
+	}
+	for _, tp := range testPanics {
+		func() {
+			defer func() {
+				if recovered := recover(); recovered != tp.panic {
+					t.Errorf("Binding %T panicked with %v; Want %q", tp.dst, recovered, tp.panic)
+				}
+			}()
+			BindQuery(bindRequest("GET", "/?Values=x", "", ""), tp.dst)
+		}()
+	}
+}
@@ -0,0 +1,269 @@
+/*
+Package cache is a net/http middleware which caches rendered responses
+(status, headers, and body) for safe requests, so that expensive endpoints
+like reports are only computed once per TTL.
+
+Create a Cache with a Store, a TTL, and optionally a KeyFunc:
+
+    reports := cache.New(cache.NewMemoryStore(), 5*time.Minute, nil)
+
+Then insert the handler in the chain:
+
+    handler = reports.Handler(handler)
+
+Only GET and HEAD requests are cached, and only responses with status 200 OK.
+The request method is always part of the cache key, so GET and HEAD responses
+are stored separately.  Responses which set a cookie, or which include
+"Cache-Control: no-store", "no-cache", or "private", are never stored.
+If a response has a Vary header, the values of the named request headers
+become part of the cache key.
+
+Only the headers set by the handler that the Cache wraps are stored, so
+headers set by outer middleware before the Cache is reached (such as a
+request id) aren't replayed from the first request.
+
+Responses to requests with an Authorization or Cookie header are only stored
+if the response includes "Cache-Control: public", because they are likely to
+be specific to the user.  Endpoints which render per-user responses should
+instead use a KeyFunc which includes the user's identity.
+
+Cached entries can be removed before they expire with Invalidate.
+*/
+package cache
+
+import (
+	"bytes"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// A KeyFunc returns the cache key for a request.
+type KeyFunc func(*http.Request) string
+
+// DefaultKey is the KeyFunc used when none is provided to New.
+// It returns the request URI (path and query).
+func DefaultKey(r *http.Request) string {
+	return r.URL.RequestURI()
+}
+
+// cachedMethods are the safe methods which Cache will store responses for
+var cachedMethods = []string{"GET", "HEAD"}
+
+// Cache is a middleware which caches responses in a Store.
+type Cache struct {
+	store Store
+	ttl   time.Duration
+	key   KeyFunc
+}
+
+// New creates a new Cache.  If key is nil, DefaultKey is used.
+func New(store Store, ttl time.Duration, key KeyFunc) *Cache {
+	if key == nil {
+		key = DefaultKey
+	}
+	return &Cache{store: store, ttl: ttl, key: key}
+}
+
+// Invalidate removes the cached responses (for all methods and variants) for
+// each key, where a key is a value returned by the Cache's KeyFunc.
+func (c *Cache) Invalidate(keys ...string) {
+	for _, key := range keys {
+		for _, method := range cachedMethods {
+			c.store.Delete(method + " " + key)
+		}
+	}
+}
+
+// InvalidateRequest removes the cached responses that would be used for a request.
+func (c *Cache) InvalidateRequest(r *http.Request) {
+	c.Invalidate(c.key(r))
+}
+
+// Handler serves cached responses when they are available, otherwise it calls
+// the next handler and caches the response if it is cacheable.
+func (c *Cache) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" && r.Method != "HEAD" {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		key := r.Method + " " + c.key(r)
+		entry, hit := c.store.Get(key)
+		if hit {
+			if resp, ok := entry.Responses[variant(entry.Vary, r)]; ok {
+				resp.write(w)
+				return
+			}
+		}
+
+		before := cloneHeader(w.Header())
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(rec, r)
+		if !rec.cacheable(r) {
+			return
+		}
+
+		// NOTE: Stored entries may be read concurrently, so build a new entry
+		// instead of adding the variant to the existing one.
+		vary := varyHeaders(rec.Header())
+		update := &Entry{Vary: vary, Responses: make(map[string]*Response)}
+		if hit && sameHeaders(entry.Vary, vary) {
+			for k, resp := range entry.Responses {
+				update.Responses[k] = resp
+			}
+		}
+		update.Responses[variant(vary, r)] = &Response{
+			Status: rec.status,
+			Header: changedHeader(before, rec.Header()),
+			Body:   rec.body.Bytes(),
+		}
+		c.store.Set(key, update, c.ttl)
+	})
+}
+
+// Entry is the value stored for a single cache key.
+// It holds one Response for each variant of the request named by Vary.
+type Entry struct {
+	Vary      []string
+	Responses map[string]*Response
+}
+
+// Response is a rendered response.
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+func (resp *Response) write(w http.ResponseWriter) {
+	// NOTE: Copy the values so that outer handlers can't modify the stored response
+	for name, values := range resp.Header {
+		w.Header()[name] = append([]string(nil), values...)
+	}
+	w.WriteHeader(resp.Status)
+	w.Write(resp.Body)
+}
+
+// recorder passes a response through to the client while keeping a copy
+type recorder struct {
+	http.ResponseWriter
+	status  int
+	body    bytes.Buffer
+	written bool
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	if !rec.written {
+		rec.status = status
+		rec.written = true
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *recorder) Write(b []byte) (int, error) {
+	rec.written = true
+	rec.body.Write(b)
+	return rec.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying ResponseWriter
+func (rec *recorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+func (rec *recorder) cacheable(r *http.Request) bool {
+	if rec.status != http.StatusOK {
+		return false
+	}
+
+	header := rec.Header()
+	if len(header["Set-Cookie"]) > 0 {
+		return false
+	}
+
+	public := false
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		switch strings.ToLower(strings.TrimSpace(directive)) {
+		case "no-store", "no-cache", "private":
+			return false
+		case "public":
+			public = true
+		}
+	}
+
+	// See RFC 7234, Section 3.2 (Storing Responses to Authenticated Requests)
+	if !public && (len(r.Header["Authorization"]) > 0 || len(r.Header["Cookie"]) > 0) {
+		return false
+	}
+
+	for _, name := range varyHeaders(header) {
+		if name == "*" {
+			return false
+		}
+	}
+	return true
+}
+
+// varyHeaders returns the sorted, canonical names listed in the Vary header(s)
+func varyHeaders(header http.Header) []string {
+	var names []string
+	for _, value := range header["Vary"] {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "*" {
+				return []string{"*"}
+			} else if len(name) > 0 {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// variant returns the part of the key which depends on the Vary'd request headers
+func variant(vary []string, r *http.Request) string {
+	buf := bytes.Buffer{}
+	for _, name := range vary {
+		buf.WriteString(name)
+		buf.WriteString(": ")
+		buf.WriteString(strings.Join(r.Header[name], ", "))
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+func sameHeaders(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func cloneHeader(header http.Header) http.Header {
+	clone := make(http.Header, len(header))
+	for name, values := range header {
+		clone[name] = append([]string(nil), values...)
+	}
+	return clone
+}
+
+// changedHeader returns a copy of the headers which are different from the
+// headers before the handler was called
+func changedHeader(before, after http.Header) http.Header {
+	changed := make(http.Header, len(after))
+	for name, values := range after {
+		if !sameHeaders(before[name], values) {
+			changed[name] = append([]string(nil), values...)
+		}
+	}
+	return changed
+}
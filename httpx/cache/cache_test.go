@@ -0,0 +1,279 @@
+package cache
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+func countingHandler(calls *int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls += 1
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Vary", "Accept-Language")
+		fmt.Fprintf(w, "%s call %d", r.Header.Get("Accept-Language"), *calls)
+	})
+}
+
+func serve(h http.Handler, method, url string, header map[string]string) *httptest.ResponseRecorder {
+	res := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, url, nil)
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+	h.ServeHTTP(res, req)
+	return res
+}
+
+func TestCachesResponses(t *testing.T) {
+	calls := 0
+	c := New(NewMemoryStore(), time.Minute, nil)
+	h := c.Handler(countingHandler(&calls))
+
+	res := serve(h, "GET", "/report", nil)
+	expect.Equal(t, res.Code, 200)
+	expect.Equal(t, res.Body.String(), " call 1")
+
+	res = serve(h, "GET", "/report", nil)
+	expect.Equal(t, res.Code, 200)
+	expect.Equal(t, res.Body.String(), " call 1")
+	expect.Equal(t, res.Header().Get("Content-Type"), "text/plain")
+	expect.Equal(t, calls, 1)
+
+	res = serve(h, "GET", "/report?page=2", nil)
+	expect.Equal(t, res.Body.String(), " call 2")
+	expect.Equal(t, calls, 2)
+}
+
+func TestSkipsUnsafeMethods(t *testing.T) {
+	calls := 0
+	c := New(NewMemoryStore(), time.Minute, nil)
+	h := c.Handler(countingHandler(&calls))
+
+	serve(h, "POST", "/report", nil)
+	res := serve(h, "POST", "/report", nil)
+	expect.Equal(t, res.Body.String(), " call 2")
+	expect.Equal(t, calls, 2)
+}
+
+func TestVaryAwareKeys(t *testing.T) {
+	calls := 0
+	c := New(NewMemoryStore(), time.Minute, nil)
+	h := c.Handler(countingHandler(&calls))
+
+	res := serve(h, "GET", "/report", map[string]string{"Accept-Language": "en"})
+	expect.Equal(t, res.Body.String(), "en call 1")
+	res = serve(h, "GET", "/report", map[string]string{"Accept-Language": "fr"})
+	expect.Equal(t, res.Body.String(), "fr call 2")
+	res = serve(h, "GET", "/report", map[string]string{"Accept-Language": "en"})
+	expect.Equal(t, res.Body.String(), "en call 1")
+	res = serve(h, "GET", "/report", map[string]string{"Accept-Language": "fr"})
+	expect.Equal(t, res.Body.String(), "fr call 2")
+	expect.Equal(t, calls, 2)
+}
+
+func TestSkipsUncacheableResponses(t *testing.T) {
+	examples := []http.HandlerFunc{
+		func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNotFound) },
+		func(w http.ResponseWriter, r *http.Request) { w.Header().Set("Cache-Control", "private, max-age=0") },
+		func(w http.ResponseWriter, r *http.Request) { w.Header().Set("Cache-Control", "no-store") },
+		func(w http.ResponseWriter, r *http.Request) { w.Header().Set("Cache-Control", "no-cache") },
+		func(w http.ResponseWriter, r *http.Request) { w.Header().Set("Set-Cookie", "session=abc") },
+		func(w http.ResponseWriter, r *http.Request) { w.Header().Set("Vary", "*") },
+	}
+
+	for i, example := range examples {
+		calls := 0
+		c := New(NewMemoryStore(), time.Minute, nil)
+		h := c.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls += 1
+			example(w, r)
+		}))
+
+		serve(h, "GET", "/report", nil)
+		serve(h, "GET", "/report", nil)
+		expect.Equal(t, calls, 2, "example %d", i)
+	}
+}
+
+func TestSkipsOuterHeaders(t *testing.T) {
+	calls := 0
+	c := New(NewMemoryStore(), time.Minute, nil)
+	h := c.Handler(countingHandler(&calls))
+
+	// like a request id middleware wrapping the cache
+	requestID := 0
+	outer := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID += 1
+		w.Header().Set("X-Request-Id", fmt.Sprint(requestID))
+		h.ServeHTTP(w, r)
+	})
+
+	serve(outer, "GET", "/report", nil)
+	res := serve(outer, "GET", "/report", nil)
+	expect.Equal(t, res.Body.String(), " call 1")
+	expect.Equal(t, res.Header().Get("X-Request-Id"), "2")
+	expect.Equal(t, res.Header().Get("Content-Type"), "text/plain")
+	expect.Equal(t, calls, 1)
+}
+
+func TestInvalidate(t *testing.T) {
+	calls := 0
+	c := New(NewMemoryStore(), time.Minute, nil)
+	h := c.Handler(countingHandler(&calls))
+
+	serve(h, "GET", "/report", nil)
+	c.Invalidate("/report")
+	res := serve(h, "GET", "/report", nil)
+	expect.Equal(t, res.Body.String(), " call 2")
+
+	req, _ := http.NewRequest("GET", "/report", nil)
+	c.InvalidateRequest(req)
+	res = serve(h, "GET", "/report", nil)
+	expect.Equal(t, res.Body.String(), " call 3")
+}
+
+func TestExpiration(t *testing.T) {
+	now := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	calls := 0
+	c := New(store, time.Minute, nil)
+	h := c.Handler(countingHandler(&calls))
+
+	serve(h, "GET", "/report", nil)
+	now = now.Add(time.Minute)
+	res := serve(h, "GET", "/report", nil)
+	expect.Equal(t, res.Body.String(), " call 1")
+
+	now = now.Add(time.Second)
+	res = serve(h, "GET", "/report", nil)
+	expect.Equal(t, res.Body.String(), " call 2")
+}
+
+func TestMaxEntries(t *testing.T) {
+	now := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.MaxEntries = 2
+	store.now = func() time.Time { return now }
+
+	entry := &Entry{}
+	store.Set("a", entry, time.Second)
+	store.Set("b", entry, time.Minute)
+	store.Set("c", entry, time.Minute)
+	_, ok := store.Get("a")
+	expect.False(t, ok, "expected the soonest expiring entry to be evicted")
+	_, ok = store.Get("b")
+	expect.True(t, ok)
+	_, ok = store.Get("c")
+	expect.True(t, ok)
+
+	// replacing an entry does not evict
+	store.Set("c", entry, time.Hour)
+	_, ok = store.Get("b")
+	expect.True(t, ok)
+
+	// expired entries are swept before evicting live ones
+	now = now.Add(2 * time.Minute)
+	store.Set("d", entry, time.Minute)
+	expect.Equal(t, len(store.entries), 2)
+	_, ok = store.Get("c")
+	expect.True(t, ok)
+	_, ok = store.Get("d")
+	expect.True(t, ok)
+}
+
+func TestSkipsAuthorizedRequests(t *testing.T) {
+	credentials := []map[string]string{
+		{"Authorization": "Bearer abc"},
+		{"Cookie": "session=abc"},
+	}
+
+	for i, header := range credentials {
+		calls := 0
+		c := New(NewMemoryStore(), time.Minute, nil)
+		h := c.Handler(countingHandler(&calls))
+
+		serve(h, "GET", "/report", header)
+		res := serve(h, "GET", "/report", nil)
+		expect.Equal(t, res.Body.String(), " call 2", "example %d", i)
+	}
+
+	calls := 0
+	c := New(NewMemoryStore(), time.Minute, nil)
+	h := c.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls += 1
+		w.Header().Set("Cache-Control", "public, max-age=60")
+		fmt.Fprintf(w, "call %d", calls)
+	}))
+
+	serve(h, "GET", "/report", map[string]string{"Authorization": "Bearer abc"})
+	res := serve(h, "GET", "/report", map[string]string{"Authorization": "Bearer abc"})
+	expect.Equal(t, res.Body.String(), "call 1")
+}
+
+func TestSeparatesMethods(t *testing.T) {
+	calls := 0
+	byPath := func(r *http.Request) string { return r.URL.Path }
+	c := New(NewMemoryStore(), time.Minute, byPath)
+	h := c.Handler(countingHandler(&calls))
+
+	serve(h, "HEAD", "/report", nil)
+	expect.Equal(t, calls, 1)
+	res := serve(h, "GET", "/report", nil)
+	expect.Equal(t, res.Body.String(), " call 2")
+	serve(h, "HEAD", "/report", nil)
+	expect.Equal(t, calls, 2)
+
+	c.Invalidate("/report")
+	serve(h, "HEAD", "/report", nil)
+	serve(h, "GET", "/report", nil)
+	expect.Equal(t, calls, 4)
+}
+
+func TestConcurrentHits(t *testing.T) {
+	var mutex sync.Mutex
+	calls := 0
+	c := New(NewMemoryStore(), time.Minute, nil)
+	h := c.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		calls += 1
+		mutex.Unlock()
+		w.Header().Set("Vary", "Accept-Language")
+		fmt.Fprintf(w, "report %s", r.Header.Get("Accept-Language"))
+	}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			lang := []string{"en", "fr"}[i%2]
+			res := serve(h, "GET", "/report", map[string]string{"Accept-Language": lang})
+			res.Header()["Vary"][0] = "Modified"
+			expect.Equal(t, res.Body.String(), "report "+lang)
+		}(i)
+	}
+	wg.Wait()
+
+	res := serve(h, "GET", "/report", map[string]string{"Accept-Language": "en"})
+	expect.Equal(t, res.Header().Get("Vary"), "Accept-Language")
+	expect.True(t, calls <= 50)
+}
+
+func TestCustomKey(t *testing.T) {
+	calls := 0
+	byPath := func(r *http.Request) string { return r.URL.Path }
+	c := New(NewMemoryStore(), time.Minute, byPath)
+	h := c.Handler(countingHandler(&calls))
+
+	serve(h, "GET", "/report?page=1", nil)
+	res := serve(h, "GET", "/report?page=2", nil)
+	expect.Equal(t, res.Body.String(), " call 1")
+}
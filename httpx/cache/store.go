@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// Store is the interface for the backing storage of a Cache.
+//
+// Implementations must be safe for concurrent use.  Stores shared between
+// processes (eg. Redis or memcached) can serialize an Entry with encoding/gob
+// and should use ttl as the expiration of the stored value.
+type Store interface {
+	// Get returns the entry for the key, if it exists and has not expired
+	Get(key string) (*Entry, bool)
+
+	// Set stores the entry for the key, replacing any existing entry
+	Set(key string, entry *Entry, ttl time.Duration)
+
+	// Delete removes the entry for the key, if it exists
+	Delete(key string)
+}
+
+// DefaultMaxEntries is the MaxEntries of a store created with NewMemoryStore
+const DefaultMaxEntries = 10000
+
+// MemoryStore is a Store which keeps entries in memory.
+//
+// Expired entries are removed when they are next accessed, or when the store
+// is full.  If the store is still full after removing expired entries, the
+// entry which expires soonest is evicted.
+type MemoryStore struct {
+	// MaxEntries is the number of entries the store holds before evicting,
+	// or zero for no limit.
+	MaxEntries int
+
+	mutex   sync.RWMutex
+	entries map[string]memoryEntry
+	now     func() time.Time
+}
+
+type memoryEntry struct {
+	entry   *Entry
+	expires time.Time
+}
+
+// Make sure the MemoryStore conforms with the Store interface
+var _ Store = NewMemoryStore()
+
+// NewMemoryStore returns a new empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		MaxEntries: DefaultMaxEntries,
+		entries:    make(map[string]memoryEntry),
+		now:        time.Now,
+	}
+}
+
+func (s *MemoryStore) Get(key string) (*Entry, bool) {
+	s.mutex.RLock()
+	stored, exists := s.entries[key]
+	s.mutex.RUnlock()
+
+	if !exists {
+		return nil, false
+	} else if s.now().After(stored.expires) {
+		// NOTE: The entry may have been replaced since we released the read lock,
+		// so check that it is still expired before deleting it.
+		s.mutex.Lock()
+		if current, ok := s.entries[key]; ok && s.now().After(current.expires) {
+			delete(s.entries, key)
+		}
+		s.mutex.Unlock()
+		return nil, false
+	}
+	return stored.entry, true
+}
+
+func (s *MemoryStore) Set(key string, entry *Entry, ttl time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	if _, exists := s.entries[key]; !exists && s.MaxEntries > 0 && len(s.entries) >= s.MaxEntries {
+		s.evict(now)
+	}
+	s.entries[key] = memoryEntry{entry, now.Add(ttl)}
+}
+
+func (s *MemoryStore) Delete(key string) {
+	s.mutex.Lock()
+	delete(s.entries, key)
+	s.mutex.Unlock()
+}
+
+// evict removes all expired entries, or the soonest expiring entry if none
+// have expired.  The caller must hold the write lock.
+func (s *MemoryStore) evict(now time.Time) {
+	var soonest string
+	var soonestExpires time.Time
+	found := false
+	for key, stored := range s.entries {
+		if now.After(stored.expires) {
+			delete(s.entries, key)
+		} else if !found || stored.expires.Before(soonestExpires) {
+			soonest, soonestExpires, found = key, stored.expires, true
+		}
+	}
+	if len(s.entries) >= s.MaxEntries {
+		delete(s.entries, soonest)
+	}
+}
@@ -2,17 +2,21 @@ package httpx
 
 import "net/http"
 
+// Middleware wraps a handler with another handler, which usually does some
+// work before or after calling the wrapped handler.
+type Middleware func(http.Handler) http.Handler
+
 // Chain is a helper for chaining middleware handlers together for easier
 // management.
-type Chain []func(http.Handler) http.Handler
+type Chain []Middleware
 
 // Use appends a handler to the middleware chain.
-func (c *Chain) Use(handler func(http.Handler) http.Handler) {
+func (c *Chain) Use(handler Middleware) {
 	*c = append(*c, handler)
 }
 
 // Add appends multiple middleware handlers to the middleware chain.
-func (c *Chain) Add(handlers ...func(http.Handler) http.Handler) {
+func (c *Chain) Add(handlers ...Middleware) {
 	for _, handler := range handlers {
 		c.Use(handler)
 	}
@@ -20,7 +24,7 @@ func (c *Chain) Add(handlers ...func(http.Handler) http.Handler) {
 
 // With creates a new middleware chain from an existing chain, extending it with
 // additional middleware.
-func (c *Chain) With(handlers ...func(http.Handler) http.Handler) *Chain {
+func (c *Chain) With(handlers ...Middleware) *Chain {
 	chain := make(Chain, len(*c))
 	copy(chain, *c)
 	chain.Add(handlers...)
@@ -0,0 +1,285 @@
+package httpx
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultMinCompressSize is the smallest response compressed by a Compress
+// which doesn't set a MinSize.
+const DefaultMinCompressSize = 1024
+
+// A Compress is middleware which compresses responses with gzip or deflate,
+// as accepted by the client's Accept-Encoding header.  As in RFC 9110, the
+// "deflate" coding is the zlib format (RFC 1950), not a raw deflate stream.
+//
+//     mux.Use((&httpx.Compress{}).Handler)
+//
+// Responses aren't compressed if they are small, if they already have a
+// Content-Encoding, or if their Content-Type is already compressed (such as
+// images, video, and archives).  When a response is compressed its
+// Content-Length is removed, since the length changes.
+//
+// Flushing the response (see http.ResponseController) flushes the compressed
+// data written so far, so compression can be used with Stream.
+type Compress struct {
+	// Level is the compression level, from flate.BestSpeed to
+	// flate.BestCompression.  If it is zero, flate.DefaultCompression is used.
+	Level int
+
+	// MinSize is the smallest response body to compress, in bytes.  If it is
+	// zero, DefaultMinCompressSize is used.
+	MinSize int
+
+	gzipWriters sync.Pool
+	zlibWriters sync.Pool
+}
+
+// Handler returns a Handler which compresses the responses of h.
+func (c *Compress) Handler(h http.Handler) http.Handler {
+	if _, err := flate.NewWriter(io.Discard, c.level()); err != nil {
+		panic(fmt.Sprintf("httpx: invalid Compress level %d", c.Level))
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := acceptedEncoding(req.Header.Get("Accept-Encoding"))
+		if len(encoding) == 0 || req.Method == "HEAD" {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, compress: c, encoding: encoding}
+		defer cw.Close()
+		h.ServeHTTP(cw, req)
+	})
+}
+
+func (c *Compress) level() int {
+	if c.Level == 0 {
+		return flate.DefaultCompression
+	}
+	return c.Level
+}
+
+func (c *Compress) minSize() int {
+	if c.MinSize > 0 {
+		return c.MinSize
+	}
+	return DefaultMinCompressSize
+}
+
+func (c *Compress) newEncoder(encoding string, w io.Writer) io.WriteCloser {
+	if encoding == "gzip" {
+		if gz, ok := c.gzipWriters.Get().(*gzip.Writer); ok {
+			gz.Reset(w)
+			return gz
+		}
+		gz, _ := gzip.NewWriterLevel(w, c.level())
+		return gz
+	}
+
+	if zw, ok := c.zlibWriters.Get().(*zlib.Writer); ok {
+		zw.Reset(w)
+		return zw
+	}
+	zw, _ := zlib.NewWriterLevel(w, c.level())
+	return zw
+}
+
+func (c *Compress) releaseEncoder(enc io.WriteCloser) {
+	switch enc := enc.(type) {
+	case *gzip.Writer:
+		c.gzipWriters.Put(enc)
+	case *zlib.Writer:
+		c.zlibWriters.Put(enc)
+	}
+}
+
+// acceptedEncoding returns the preferred encoding in an Accept-Encoding
+// header which can be used by Compress, or an empty string if none can be
+func acceptedEncoding(header string) string {
+	qualities := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		qualities[strings.ToLower(strings.TrimSpace(coding))] = quality
+	}
+
+	best, bestQuality := "", 0.0
+	for _, coding := range []string{"gzip", "deflate"} {
+		quality, listed := qualities[coding]
+		if !listed {
+			quality = qualities["*"]
+		}
+		if quality > bestQuality {
+			best, bestQuality = coding, quality
+		}
+	}
+	return best
+}
+
+// compressedTypes are the types which are already compressed, in addition
+// to image, audio, and video types
+var compressedTypes = map[string]bool{
+	"application/gzip":             true,
+	"application/x-gzip":           true,
+	"application/zip":              true,
+	"application/x-bzip2":          true,
+	"application/x-xz":             true,
+	"application/x-7z-compressed":  true,
+	"application/x-rar-compressed": true,
+	"application/zstd":             true,
+	"application/octet-stream":     true,
+	"font/woff":                    true,
+	"font/woff2":                   true,
+}
+
+func isCompressible(contentType string) bool {
+	typ, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	if typ == "image/svg+xml" {
+		return true
+	}
+	media, _, _ := strings.Cut(typ, "/")
+	return media != "image" && media != "audio" && media != "video" && !compressedTypes[typ]
+}
+
+// compressWriter buffers the start of a response until it can decide
+// whether to compress it
+type compressWriter struct {
+	http.ResponseWriter
+	compress *Compress
+	encoding string
+
+	status  int
+	buf     []byte
+	decided bool
+	enc     io.WriteCloser // nil unless the response is being compressed
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if cw.status != 0 || cw.decided {
+		return
+	}
+	cw.status = status
+
+	// responses without a body can be sent immediately
+	if status < 200 || status == http.StatusNoContent || status == http.StatusNotModified {
+		cw.decide(false)
+	}
+}
+
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+	if !cw.decided {
+		cw.buf = append(cw.buf, b...)
+		if len(cw.buf) < cw.compress.minSize() {
+			return len(b), nil
+		}
+		if err := cw.decideAndFlush(true); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+
+	if cw.enc != nil {
+		return cw.enc.Write(b)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+// Flush writes any buffered data to the client, compressing the response
+// if it will be compressed.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		if cw.status == 0 {
+			cw.status = http.StatusOK
+		}
+		cw.decideAndFlush(true)
+	}
+	if flusher, ok := cw.enc.(interface{ Flush() error }); ok {
+		flusher.Flush()
+	}
+	http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying ResponseWriter
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// Close writes the rest of the response after the handler has returned
+func (cw *compressWriter) Close() error {
+	if !cw.decided {
+		if cw.status == 0 && len(cw.buf) == 0 {
+			return nil // the handler didn't write anything
+		}
+		if cw.status == 0 {
+			cw.status = http.StatusOK
+		}
+		if err := cw.decideAndFlush(len(cw.buf) >= cw.compress.minSize()); err != nil {
+			return err
+		}
+	}
+
+	if cw.enc == nil {
+		return nil
+	}
+	err := cw.enc.Close()
+	cw.compress.releaseEncoder(cw.enc)
+	cw.enc = nil
+	return err
+}
+
+func (cw *compressWriter) decideAndFlush(largeEnough bool) error {
+	header := cw.Header()
+	if len(cw.buf) > 0 && len(header.Get("Content-Type")) == 0 {
+		// NOTE: net/http would sniff the type of the compressed data instead
+		header.Set("Content-Type", http.DetectContentType(cw.buf))
+	}
+
+	cw.decide(largeEnough && len(header.Get("Content-Encoding")) == 0 && isCompressible(header.Get("Content-Type")))
+	if len(cw.buf) == 0 {
+		return nil
+	}
+
+	buf := cw.buf
+	cw.buf = nil
+	var err error
+	if cw.enc != nil {
+		_, err = cw.enc.Write(buf)
+	} else {
+		_, err = cw.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+func (cw *compressWriter) decide(compress bool) {
+	cw.decided = true
+	if compress {
+		header := cw.Header()
+		header.Set("Content-Encoding", cw.encoding)
+		header.Del("Content-Length")
+		cw.enc = cw.compress.newEncoder(cw.encoding, cw.ResponseWriter)
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+}
@@ -0,0 +1,166 @@
+package httpx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func decompress(t *testing.T, encoding string, body []byte) string {
+	var r io.Reader = bytes.NewReader(body)
+	switch encoding {
+	case "gzip":
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			t.Fatalf("Invalid gzip response: %v", err)
+		}
+		r = gz
+	case "deflate":
+		zr, err := zlib.NewReader(r)
+		if err != nil {
+			t.Fatalf("Invalid deflate (zlib) response: %v", err)
+		}
+		r = zr
+	}
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Invalid %s response: %v", encoding, err)
+	}
+	return string(decoded)
+}
+
+func TestAcceptedEncoding(t *testing.T) {
+	testHeaders := []struct {
+		header   string
+		encoding string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"deflate, gzip", "gzip"},
+		{"deflate", "deflate"},
+		{"GZIP;q=0.5, deflate", "deflate"},
+		{"gzip;q=0, deflate;q=0", ""},
+		{"br, *;q=0.1", "gzip"},
+		{"*, gzip;q=0", "deflate"},
+		{"identity", ""},
+		{"gzip;q=high, deflate", "deflate"},
+	}
+	for _, th := range testHeaders {
+		if encoding := acceptedEncoding(th.header); encoding != th.encoding {
+			t.Errorf("Accept-Encoding %q chose %q; Want %q", th.header, encoding, th.encoding)
+		}
+	}
+}
+
+func TestCompress(t *testing.T) {
+	large := strings.Repeat("kermit the frog ", 100)
+	compress := &Compress{}
+
+	testResponses := []struct {
+		name     string
+		accept   string
+		method   string
+		handler  http.HandlerFunc
+		encoding string
+		body     string
+	}{
+		{"large text", "gzip", "GET", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Length", "1600")
+			io.WriteString(w, large[:800])
+			io.WriteString(w, large[800:])
+		}, "gzip", large},
+		{"deflate", "deflate", "GET", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			io.WriteString(w, large)
+		}, "deflate", large},
+		{"not accepted", "", "GET", func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, large)
+		}, "", large},
+		{"small", "gzip", "GET", func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, "hello")
+		}, "", "hello"},
+		{"image", "gzip", "GET", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "image/png")
+			io.WriteString(w, large)
+		}, "", large},
+		{"svg", "gzip", "GET", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "image/svg+xml")
+			io.WriteString(w, large)
+		}, "gzip", large},
+		{"already encoded", "gzip", "GET", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Encoding", "br")
+			io.WriteString(w, large)
+		}, "br", large},
+		{"head", "gzip", "HEAD", func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, large)
+		}, "", large},
+		{"no content", "gzip", "GET", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}, "", ""},
+	}
+	for _, tr := range testResponses {
+		r, _ := http.NewRequest(tr.method, "/", nil)
+		r.Header.Set("Accept-Encoding", tr.accept)
+		w := httptest.NewRecorder()
+		compress.Handler(tr.handler).ServeHTTP(w, r)
+
+		encoding := w.Header().Get("Content-Encoding")
+		if encoding != tr.encoding || w.Header().Get("Vary") != "Accept-Encoding" {
+			t.Errorf("Response for %s failed: Content-Encoding=%q, Vary=%q; Want %q", tr.name, encoding, w.Header().Get("Vary"), tr.encoding)
+			continue
+		}
+		if encoding == "gzip" || encoding == "deflate" {
+			if body := decompress(t, encoding, w.Body.Bytes()); body != tr.body || w.Header().Get("Content-Length") != "" {
+				t.Errorf("Response for %s failed: Body=%q, Content-Length=%q", tr.name, body, w.Header().Get("Content-Length"))
+			}
+		} else if w.Body.String() != tr.body {
+			t.Errorf("Response for %s failed: Body=%q; Want %q", tr.name, w.Body.String(), tr.body)
+		}
+	}
+
+	// the type is detected before compressing, so it isn't sniffed from the compressed data
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	compress.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "<html>"+large)
+	})).ServeHTTP(w, r)
+	if contentType := w.Header().Get("Content-Type"); contentType != "text/html; charset=utf-8" {
+		t.Errorf("Compressed response has Content-Type %q; Want %q", contentType, "text/html; charset=utf-8")
+	}
+}
+
+func TestCompressFlush(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+
+	var flushed string
+	handler := (&Compress{}).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		Stream(w, r, func(w io.Writer) bool {
+			io.WriteString(w, "hello")
+			return false
+		})
+		flushed = w.Header().Get("Content-Encoding")
+	}))
+	handler.ServeHTTP(w, r)
+	if !w.Flushed || flushed != "gzip" || decompress(t, "gzip", w.Body.Bytes()) != "hello" {
+		t.Errorf("Flushing a compressed response failed: Flushed=%v, Content-Encoding=%q", w.Flushed, flushed)
+	}
+}
+
+func TestCompressInvalidLevel(t *testing.T) {
+	defer func() {
+		if recovered := recover(); recovered != "httpx: invalid Compress level 12" {
+			t.Errorf("Compress with an invalid level panicked with %v", recovered)
+		}
+	}()
+	(&Compress{Level: 12}).Handler(http.NotFoundHandler())
+}
@@ -0,0 +1,26 @@
+package httpx
+
+import "github.com/reflexionhealth/vanilla/httpx/cors"
+
+// CORS returns middleware which handles cross-origin requests for the routes
+// of a Mux, as configured by the options (see cors.Options).
+//
+// If the options don't set AllowedMethodsFunc, the methods allowed for a
+// request are the methods routed for its path, rather than a fixed list:
+//
+//     mux := httpx.NewMux()
+//     mux.Use(httpx.CORS(mux, cors.Options{
+//         AllowedOrigins:   []string{"https://*.example.com"},
+//         AllowedHeaders:   []string{"Authorization", "Content-Type"},
+//         AllowCredentials: true,
+//         MaxAge:           600,
+//     }))
+//
+// The middleware answers preflight requests itself (unless the options set
+// OptionsPassthrough), so it should be added with Use rather than to routes.
+func CORS(mux *Mux, options cors.Options) Middleware {
+	if options.AllowedMethodsFunc == nil && len(options.AllowedMethods) == 0 {
+		options.AllowedMethodsFunc = mux.AllowedMethods
+	}
+	return cors.New(options).Handler
+}
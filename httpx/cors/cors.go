@@ -47,6 +47,11 @@ type Options struct {
 	// AllowedMethods is a list of methods the client is allowed to use with
 	// cross-domain requests. Default value is simple methods (GET and POST)
 	AllowedMethods []string
+	// AllowedMethodsFunc is a custom function which returns the methods the client
+	// is allowed to use with a cross-domain request, such as the methods which are
+	// routed for the request's path. If this option is set, the content of
+	// AllowedMethods is ignored.
+	AllowedMethodsFunc func(r *http.Request) []string
 	// AllowedHeaders is list of non simple headers the client is allowed to use with
 	// cross-domain requests.
 	// If the special "*" value is present in the list, all headers will be allowed.
@@ -82,6 +87,8 @@ type Cors struct {
 	allowedHeaders []string
 	// Normalized list of allowed methods
 	allowedMethods []string
+	// Optional function returning the allowed methods for a request
+	allowedMethodsFunc func(r *http.Request) []string
 	// Normalized list of exposed headers
 	exposedHeaders    []string
 	allowCredentials  bool
@@ -92,11 +99,12 @@ type Cors struct {
 // New creates a new Cors handler with the provided options.
 func New(options Options) *Cors {
 	c := &Cors{
-		exposedHeaders:    convert(options.ExposedHeaders, http.CanonicalHeaderKey),
-		allowOriginFunc:   options.AllowOriginFunc,
-		allowCredentials:  options.AllowCredentials,
-		maxAge:            options.MaxAge,
-		optionPassthrough: options.OptionsPassthrough,
+		exposedHeaders:     convert(options.ExposedHeaders, http.CanonicalHeaderKey),
+		allowOriginFunc:    options.AllowOriginFunc,
+		allowedMethodsFunc: options.AllowedMethodsFunc,
+		allowCredentials:   options.AllowCredentials,
+		maxAge:             options.MaxAge,
+		optionPassthrough:  options.OptionsPassthrough,
 	}
 
 	// Normalize options
@@ -234,7 +242,7 @@ func (c *Cors) handlePreflight(w http.ResponseWriter, r *http.Request) {
 	}
 
 	reqMethod := r.Header.Get("Access-Control-Request-Method")
-	if !c.isMethodAllowed(reqMethod) {
+	if !c.isMethodAllowed(r, reqMethod) {
 		return
 	}
 	reqHeaders := parseHeaderList(r.Header.Get("Access-Control-Request-Headers"))
@@ -280,7 +288,7 @@ func (c *Cors) handleActualRequest(w http.ResponseWriter, r *http.Request) {
 	// POST. Access-Control-Allow-Methods is only used for pre-flight requests and the
 	// spec doesn't instruct to check the allowed methods for simple cross-origin requests.
 	// We think it's a nice feature to be able to have control on those methods though.
-	if !c.isMethodAllowed(r.Method) {
+	if !c.isMethodAllowed(r, r.Method) {
 		return
 	}
 	headers.Set("Access-Control-Allow-Origin", origin)
@@ -317,8 +325,12 @@ func (c *Cors) isOriginAllowed(origin string) bool {
 
 // isMethodAllowed checks if a given method can be used as part of a cross-domain request
 // on the endpoing
-func (c *Cors) isMethodAllowed(method string) bool {
-	if len(c.allowedMethods) == 0 {
+func (c *Cors) isMethodAllowed(r *http.Request, method string) bool {
+	allowedMethods := c.allowedMethods
+	if c.allowedMethodsFunc != nil {
+		allowedMethods = c.allowedMethodsFunc(r)
+	}
+	if len(allowedMethods) == 0 {
 		// If no method allowed, always return false, even for preflight request
 		return false
 	}
@@ -327,8 +339,8 @@ func (c *Cors) isMethodAllowed(method string) bool {
 		// Always allow preflight requests
 		return true
 	}
-	for _, m := range c.allowedMethods {
-		if m == method {
+	for _, m := range allowedMethods {
+		if strings.ToUpper(m) == method {
 			return true
 		}
 	}
@@ -259,6 +259,40 @@ func TestDisallowedMethod(t *testing.T) {
 	})
 }
 
+func TestAllowedMethodsFunc(t *testing.T) {
+	s := New(Options{
+		AllowedOrigins: []string{"http://foobar.com"},
+		AllowedMethods: []string{"PUT"},
+		AllowedMethodsFunc: func(r *http.Request) []string {
+			if r.URL.Path == "/foo" {
+				return []string{"get", "DELETE"}
+			}
+			return nil
+		},
+	})
+
+	examples := []struct {
+		path, method, allowed string
+	}{
+		{"/foo", "DELETE", "DELETE"},
+		{"/foo", "GET", "GET"},
+		{"/foo", "PUT", ""},
+		{"/bar", "DELETE", ""},
+	}
+	for _, example := range examples {
+		res := httptest.NewRecorder()
+		req, _ := http.NewRequest("OPTIONS", "http://example.com"+example.path, nil)
+		req.Header.Add("Origin", "http://foobar.com")
+		req.Header.Add("Access-Control-Request-Method", example.method)
+
+		s.Handler(testHandler).ServeHTTP(res, req)
+
+		assertHeaders(t, res.Header(), map[string]string{
+			"Access-Control-Allow-Methods": example.allowed,
+		})
+	}
+}
+
 func TestAllowedHeader(t *testing.T) {
 	s := New(Options{
 		AllowedOrigins: []string{"http://foobar.com"},
@@ -682,7 +716,7 @@ func TestIsMethodAllowedReturnsFalseWithNoMethods(t *testing.T) {
 	// Intentionally left blank.
 	})
 	s.allowedMethods = []string{}
-	if s.isMethodAllowed("") {
+	if s.isMethodAllowed(nil, "") {
 		t.Error("IsMethodAllowed should return false when c.allowedMethods is nil.")
 	}
 }
@@ -691,7 +725,7 @@ func TestIsMethodAllowedReturnsTrueWithOptions(t *testing.T) {
 	s := New(Options{
 	// Intentionally left blank.
 	})
-	if !s.isMethodAllowed("OPTIONS") {
+	if !s.isMethodAllowed(nil, "OPTIONS") {
 		t.Error("IsMethodAllowed should return true when c.allowedMethods is nil.")
 	}
 }
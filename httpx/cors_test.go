@@ -0,0 +1,53 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/reflexionhealth/vanilla/httpx/cors"
+)
+
+func TestCORS(t *testing.T) {
+	routed := false
+	handler := func(w http.ResponseWriter, r *http.Request) { routed = true }
+
+	router := NewMux()
+	router.Use(CORS(router, cors.Options{AllowedOrigins: []string{"http://example.com"}}))
+	router.GET("/users/:id", handler)
+	router.PATCH("/users/:id", handler)
+	router.Host("api.*").DELETE("/users/:id", handler)
+
+	testPreflights := []struct {
+		host    string
+		route   string
+		method  string
+		allowed string
+	}{
+		{"example.com", "/users/3", "GET", "GET"},
+		{"example.com", "/users/3", "patch", "PATCH"},
+		{"example.com", "/users/3", "DELETE", ""},
+		{"api.example.com", "/users/3", "DELETE", "DELETE"},
+		{"example.com", "/teams/3", "GET", ""},
+	}
+	for _, tp := range testPreflights {
+		routed = false
+		r, _ := http.NewRequest("OPTIONS", tp.route, nil)
+		r.Host = tp.host
+		r.Header.Set("Origin", "http://example.com")
+		r.Header.Set("Access-Control-Request-Method", tp.method)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if allowed := w.Header().Get("Access-Control-Allow-Methods"); allowed != tp.allowed || routed {
+			t.Errorf("Preflight %s for %s%s failed: Allowed=%q, Routed=%v; Want %q, false", tp.method, tp.host, tp.route, allowed, routed, tp.allowed)
+		}
+	}
+
+	r, _ := http.NewRequest("PATCH", "/users/3", nil)
+	r.Header.Set("Origin", "http://example.com")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if origin := w.Header().Get("Access-Control-Allow-Origin"); origin != "http://example.com" || !routed {
+		t.Errorf("CORS request failed: Origin=%q, Routed=%v; Want %q, true", origin, routed, "http://example.com")
+	}
+}
@@ -0,0 +1,208 @@
+/*
+Package csrf is a net/http middleware which protects cookie-authenticated
+requests from cross-site request forgery.
+
+It uses a "double submit" token: each client is given a random token in a
+cookie, and requests which change state (other than GET, HEAD, OPTIONS, and
+TRACE) must send the same token in a header or form field.  Their Origin or
+Referer must also be the same site as the request, or a trusted origin.
+
+Create a CSRF with options, then insert the handler in the chain:
+
+    protect := csrf.New(csrf.Options{
+        TrustedOrigins: []string{"admin.example.com"},
+        Skip:           func(r *http.Request) bool { return strings.HasPrefix(r.URL.Path, "/webhooks/") },
+    })
+    handler = protect.Handler(handler)
+
+Templates can include the token in forms with csrf.Token(req.Context()).
+*/
+package csrf
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Options configures a CSRF.  The zero value is a secure default.
+type Options struct {
+	// CookieName is the cookie holding the token, or "csrf_token" if empty
+	CookieName string
+	// HeaderName is the request header holding the token, or "X-CSRF-Token" if empty
+	HeaderName string
+	// FieldName is the form field holding the token, or "csrf_token" if empty
+	FieldName string
+
+	// Path and Domain set the cookie's attributes.  The Path is "/" if empty.
+	Path   string
+	Domain string
+	// MaxAge is the lifetime of the cookie in seconds.  If it is zero the
+	// cookie lasts for the browser session.
+	MaxAge int
+	// SameSite is the cookie's SameSite attribute, or http.SameSiteLaxMode if
+	// it is zero
+	SameSite http.SameSite
+	// Insecure allows the cookie to be sent over plain HTTP (eg. in development)
+	Insecure bool
+
+	// TrustedOrigins are the hosts which may send requests other than the
+	// request's own host, such as "admin.example.com".  A host starting with
+	// "*." trusts all of its subdomains.
+	TrustedOrigins []string
+
+	// Skip returns true for requests which shouldn't be checked, such as
+	// webhooks which are authenticated in another way
+	Skip func(r *http.Request) bool
+
+	// ErrorHandler writes the response for a rejected request, or a plain
+	// 403 Forbidden if it is nil
+	ErrorHandler http.Handler
+}
+
+// CSRF is middleware which protects requests from cross-site request forgery.
+type CSRF struct {
+	options Options
+}
+
+// New creates a CSRF with the options.
+func New(options Options) *CSRF {
+	if len(options.CookieName) == 0 {
+		options.CookieName = "csrf_token"
+	}
+	if len(options.HeaderName) == 0 {
+		options.HeaderName = "X-CSRF-Token"
+	}
+	if len(options.FieldName) == 0 {
+		options.FieldName = "csrf_token"
+	}
+	if len(options.Path) == 0 {
+		options.Path = "/"
+	}
+	if options.SameSite == 0 {
+		options.SameSite = http.SameSiteLaxMode
+	}
+	if options.ErrorHandler == nil {
+		options.ErrorHandler = http.HandlerFunc(forbidden)
+	}
+	trusted := make([]string, len(options.TrustedOrigins))
+	for i, origin := range options.TrustedOrigins {
+		trusted[i] = strings.ToLower(origin)
+	}
+	options.TrustedOrigins = trusted
+	return &CSRF{options}
+}
+
+type ctxKey int
+
+const tokenKey ctxKey = 0
+
+// Token returns the token which must be sent with requests from the client,
+// or an empty string if the request wasn't handled by a CSRF.
+func Token(ctx context.Context) string {
+	token, _ := ctx.Value(tokenKey).(string)
+	return token
+}
+
+// Handler returns a Handler which protects the requests to h.
+func (c *CSRF) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := c.cookieToken(r)
+		if len(token) == 0 {
+			token = newToken()
+			http.SetCookie(w, &http.Cookie{
+				Name:     c.options.CookieName,
+				Value:    token,
+				Path:     c.options.Path,
+				Domain:   c.options.Domain,
+				MaxAge:   c.options.MaxAge,
+				Secure:   !c.options.Insecure,
+				HttpOnly: true,
+				SameSite: c.options.SameSite,
+			})
+		}
+		w.Header().Add("Vary", "Cookie")
+		r = r.WithContext(context.WithValue(r.Context(), tokenKey, token))
+
+		switch r.Method {
+		case "GET", "HEAD", "OPTIONS", "TRACE":
+			h.ServeHTTP(w, r)
+			return
+		}
+		if c.options.Skip != nil && c.options.Skip(r) {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		if !c.isSameOrigin(r) || !c.hasToken(r, token) {
+			c.options.ErrorHandler.ServeHTTP(w, r)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+func (c *CSRF) cookieToken(r *http.Request) string {
+	cookie, err := r.Cookie(c.options.CookieName)
+	if err != nil {
+		return ""
+	}
+	if decoded, err := base64.RawURLEncoding.DecodeString(cookie.Value); err != nil || len(decoded) != tokenLength {
+		return ""
+	}
+	return cookie.Value
+}
+
+func (c *CSRF) hasToken(r *http.Request, token string) bool {
+	sent := r.Header.Get(c.options.HeaderName)
+	if len(sent) == 0 {
+		sent = r.PostFormValue(c.options.FieldName)
+	}
+	return subtle.ConstantTimeCompare([]byte(sent), []byte(token)) == 1
+}
+
+// isSameOrigin checks the Origin of the request, or the Referer if there is
+// no Origin.  The Referer is required for HTTPS requests, where browsers
+// always send the header (unlike for HTTP, where proxies may remove it).
+func (c *CSRF) isSameOrigin(r *http.Request) bool {
+	source := r.Header.Get("Origin")
+	if len(source) == 0 || source == "null" {
+		source = r.Header.Get("Referer")
+		if len(source) == 0 {
+			return r.TLS == nil
+		}
+	}
+
+	u, err := url.Parse(source)
+	if err != nil || len(u.Host) == 0 {
+		return false
+	}
+	host := strings.ToLower(u.Host)
+	if host == strings.ToLower(r.Host) {
+		return true
+	}
+	for _, trusted := range c.options.TrustedOrigins {
+		if host == trusted || (strings.HasPrefix(trusted, "*.") && strings.HasSuffix(host, trusted[1:])) {
+			return true
+		}
+	}
+	return false
+}
+
+const tokenLength = 32
+
+func newToken() string {
+	b := make([]byte, tokenLength)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func forbidden(w http.ResponseWriter, r *http.Request) {
+	http.Error(w, http.StatusText(http.StatusForbidden)+": the request could not be verified", http.StatusForbidden)
+}
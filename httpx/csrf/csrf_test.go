@@ -0,0 +1,187 @@
+package csrf
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+var testHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(Token(r.Context())))
+})
+
+// validToken is a token which the CSRF accepts from a cookie
+var validToken = newToken()
+
+func newRequest(method, target string, token string) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	req.AddCookie(&http.Cookie{Name: "csrf_token", Value: validToken})
+	if len(token) > 0 {
+		req.Header.Set("X-CSRF-Token", token)
+	}
+	return req
+}
+
+func TestSetsCookie(t *testing.T) {
+	c := New(Options{Domain: "example.com", MaxAge: 3600, SameSite: http.SameSiteStrictMode})
+
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "http://example.com/form", nil)
+	c.Handler(testHandler).ServeHTTP(res, req)
+
+	cookies := res.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("expected 1 cookie, but got %d", len(cookies))
+	}
+	cookie := cookies[0]
+	if cookie.Name != "csrf_token" || len(cookie.Value) == 0 {
+		t.Errorf("expected a csrf_token cookie, but got %s=%q", cookie.Name, cookie.Value)
+	}
+	if cookie.Path != "/" || cookie.Domain != "example.com" || cookie.MaxAge != 3600 {
+		t.Errorf("wrong cookie attributes: %s", cookie.String())
+	}
+	if !cookie.Secure || !cookie.HttpOnly || cookie.SameSite != http.SameSiteStrictMode {
+		t.Errorf("wrong cookie attributes: %s", cookie.String())
+	}
+	if res.Body.String() != cookie.Value {
+		t.Errorf("expected Token to return the cookie value %q, but got %q", cookie.Value, res.Body.String())
+	}
+}
+
+func TestKeepsCookie(t *testing.T) {
+	c := New(Options{Insecure: true})
+
+	res := httptest.NewRecorder()
+	c.Handler(testHandler).ServeHTTP(res, newRequest("GET", "http://example.com/form", ""))
+	if cookies := res.Result().Cookies(); len(cookies) != 0 {
+		t.Errorf("expected the existing cookie to be kept, but got %v", cookies)
+	}
+	if res.Body.String() != validToken {
+		t.Errorf("expected Token to return %q, but got %q", validToken, res.Body.String())
+	}
+
+	res = httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "http://example.com/form", nil)
+	req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "short"})
+	c.Handler(testHandler).ServeHTTP(res, req)
+	cookies := res.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Value == "short" || cookies[0].Secure {
+		t.Errorf("expected an invalid cookie to be replaced with an insecure one, but got %v", cookies)
+	}
+}
+
+func TestChecksToken(t *testing.T) {
+	c := New(Options{})
+
+	tests := []struct {
+		Method string
+		Token  string
+		Code   int
+	}{
+		{"GET", "", http.StatusOK},
+		{"HEAD", "", http.StatusOK},
+		{"OPTIONS", "", http.StatusOK},
+		{"POST", "", http.StatusForbidden},
+		{"POST", "wrong", http.StatusForbidden},
+		{"POST", validToken, http.StatusOK},
+		{"PUT", validToken, http.StatusOK},
+		{"DELETE", "", http.StatusForbidden},
+	}
+	for _, test := range tests {
+		res := httptest.NewRecorder()
+		c.Handler(testHandler).ServeHTTP(res, newRequest(test.Method, "http://example.com/submit", test.Token))
+		if res.Code != test.Code {
+			t.Errorf("%s with token %q: expected %d, but got %d", test.Method, test.Token, test.Code, res.Code)
+		}
+	}
+}
+
+func TestChecksFormField(t *testing.T) {
+	c := New(Options{FieldName: "authenticity_token"})
+
+	form := url.Values{"authenticity_token": {validToken}}
+	req := httptest.NewRequest("POST", "http://example.com/submit", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(&http.Cookie{Name: "csrf_token", Value: validToken})
+
+	res := httptest.NewRecorder()
+	c.Handler(testHandler).ServeHTTP(res, req)
+	if res.Code != http.StatusOK {
+		t.Errorf("expected the form field to be accepted, but got %d", res.Code)
+	}
+}
+
+func TestChecksOrigin(t *testing.T) {
+	c := New(Options{TrustedOrigins: []string{"admin.example.com", "*.Internal.example.com"}})
+
+	tests := []struct {
+		Origin  string
+		Referer string
+		TLS     bool
+		Code    int
+	}{
+		{"", "", false, http.StatusOK},
+		{"", "", true, http.StatusForbidden},
+		{"https://example.com", "", true, http.StatusOK},
+		{"", "https://example.com/form", true, http.StatusOK},
+		{"https://evil.com", "https://example.com/form", true, http.StatusForbidden},
+		{"", "https://evil.com/form", false, http.StatusForbidden},
+		{"https://admin.example.com", "", true, http.StatusOK},
+		{"https://other.example.com", "", true, http.StatusForbidden},
+		{"https://a.internal.example.com", "", true, http.StatusOK},
+		{"https://internal.example.com", "", true, http.StatusForbidden},
+		{"null", "", true, http.StatusForbidden},
+		{"not a url", "", false, http.StatusForbidden},
+	}
+	for _, test := range tests {
+		req := newRequest("POST", "http://example.com/submit", validToken)
+		if len(test.Origin) > 0 {
+			req.Header.Set("Origin", test.Origin)
+		}
+		if len(test.Referer) > 0 {
+			req.Header.Set("Referer", test.Referer)
+		}
+		if test.TLS {
+			req.TLS = &tls.ConnectionState{}
+		}
+
+		res := httptest.NewRecorder()
+		c.Handler(testHandler).ServeHTTP(res, req)
+		if res.Code != test.Code {
+			t.Errorf("Origin %q, Referer %q: expected %d, but got %d", test.Origin, test.Referer, test.Code, res.Code)
+		}
+	}
+}
+
+func TestSkip(t *testing.T) {
+	c := New(Options{
+		Skip: func(r *http.Request) bool { return strings.HasPrefix(r.URL.Path, "/webhooks/") },
+	})
+
+	res := httptest.NewRecorder()
+	c.Handler(testHandler).ServeHTTP(res, httptest.NewRequest("POST", "http://example.com/webhooks/stripe", nil))
+	if res.Code != http.StatusOK {
+		t.Errorf("expected a skipped route to be allowed, but got %d", res.Code)
+	}
+
+	res = httptest.NewRecorder()
+	c.Handler(testHandler).ServeHTTP(res, httptest.NewRequest("POST", "http://example.com/users", nil))
+	if res.Code != http.StatusForbidden {
+		t.Errorf("expected other routes to be checked, but got %d", res.Code)
+	}
+}
+
+func TestErrorHandler(t *testing.T) {
+	c := New(Options{ErrorHandler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})})
+
+	res := httptest.NewRecorder()
+	c.Handler(testHandler).ServeHTTP(res, newRequest("POST", "http://example.com/submit", ""))
+	if res.Code != http.StatusTeapot {
+		t.Errorf("expected the ErrorHandler to write the response, but got %d", res.Code)
+	}
+}
@@ -1,2 +1,25 @@
-// Package httpx contains extensions built to be compatible with net/http.
+/*
+Package httpx contains extensions built to be compatible with net/http.
+
+Every handler in httpx is a plain http.Handler, and every middleware has the
+signature func(http.Handler) http.Handler, so middleware from the wider
+net/http ecosystem can be used alongside httpx's without adapters:
+
+    chain := httpx.Chain{}
+    chain.Use(httpx.CloseHandler)
+    chain.Use(cors.Default().Handler)
+    chain.Use(thirdparty.Middleware)
+
+    mux := httpx.NewMux()
+    mux.Handle("GET", "/reports", chain.HandlerFunc(reports))
+
+Middleware can also be added to every request served by a Mux with Use, or
+to a single route when it is registered:
+
+    mux.Use(httpx.CloseHandler)
+    mux.GET("/reports/:id", report, requireLogin)
+
+Request-scoped values such as path parameters are carried in the request's
+context.Context (see GetParams and Local) rather than in a custom handler type.
+*/
 package httpx
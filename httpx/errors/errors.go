@@ -64,10 +64,19 @@ type Error struct {
 	RequestID    string
 	MoreInfo     url.URL
 
+	// Fields lists the problems with specific fields of an invalid request
+	Fields []FieldError
+
 	// Meta stores additional data for internal use by the application
 	Meta Metadata `json:"-"`
 }
 
+// FieldError is a problem with one field of a request
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
 type Metadata struct {
 	Reason string
 	Error  error
@@ -79,10 +88,11 @@ func (err *Error) Error() string {
 }
 
 type jsonError struct {
-	UserMessage  string `json:"user_message"`
-	DebugMessage string `json:"debug_message,omitempty"`
-	RequestID    string `json:"request_id,omitempty"`
-	MoreInfo     string `json:"more_info,omitempty"`
+	UserMessage  string       `json:"user_message"`
+	DebugMessage string       `json:"debug_message,omitempty"`
+	RequestID    string       `json:"request_id,omitempty"`
+	MoreInfo     string       `json:"more_info,omitempty"`
+	Fields       []FieldError `json:"fields,omitempty"`
 }
 
 func (err *Error) MarshalJSON() ([]byte, error) {
@@ -96,5 +106,6 @@ func (err *Error) MarshalJSON() ([]byte, error) {
 		DebugMessage: err.DebugMessage,
 		RequestID:    err.RequestID,
 		MoreInfo:     err.MoreInfo.String(),
+		Fields:       err.Fields,
 	})
 }
@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details.
+const ProblemContentType = "application/problem+json"
+
+// Problem is an Error encoded as RFC 7807 problem details, with the error's
+// other fields as extension members.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	DebugMessage string       `json:"debug_message,omitempty"`
+	RequestID    string       `json:"request_id,omitempty"`
+	Fields       []FieldError `json:"fields,omitempty"`
+}
+
+// Problem returns the problem details for the error.  The MoreInfo URL is
+// used as the problem type, and the UserMessage as its detail.
+func (err *Error) Problem() *Problem {
+	problemType := err.MoreInfo.String()
+	if len(problemType) == 0 {
+		problemType = "about:blank"
+	}
+	return &Problem{
+		Type:         problemType,
+		Title:        http.StatusText(err.HTTPStatus),
+		Status:       err.HTTPStatus,
+		Detail:       err.UserMessage,
+		DebugMessage: err.DebugMessage,
+		RequestID:    err.RequestID,
+		Fields:       err.Fields,
+	}
+}
+
+// WithStatus returns an Error with the status for err.  If err is already an
+// *Error, it is copied with the new status.  Otherwise it is kept in the
+// Meta.Error, and (for client errors) its message is the DebugMessage.
+func WithStatus(status int, err error) *Error {
+	var httpErr *Error
+	if errors.As(err, &httpErr) {
+		copied := *httpErr
+		copied.HTTPStatus = status
+		return &copied
+	}
+
+	httpErr = &Error{HTTPStatus: status, Meta: Metadata{Error: err}}
+	if status < 500 && err != nil {
+		httpErr.DebugMessage = err.Error()
+	}
+	return httpErr
+}
+
+// From returns err as an *Error, if it is one (or wraps one), or otherwise
+// an InternalError for it.
+func From(err error) *Error {
+	var httpErr *Error
+	if errors.As(err, &httpErr) {
+		return httpErr
+	}
+	return InternalError(err)
+}
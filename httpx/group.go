@@ -0,0 +1,117 @@
+package httpx
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// A Group registers routes on a Mux with a common path prefix and
+// middleware, so that large APIs can be organized by resource:
+//
+//     users := mux.Group("/users", requireLogin)
+//     users.GET("/", listUsers)        // GET /users/
+//     users.GET("/:id", showUser)      // GET /users/:id
+//
+//     admin := users.Group("/:id/admin", requireAdmin)
+//     admin.POST("/reset", resetUser)  // POST /users/:id/admin/reset
+//
+// The group's middleware runs after the mux's middleware and before the
+// route's own middleware.  Middleware added with Use only applies to the
+// routes which are registered afterwards.
+type Group struct {
+	mux        *Mux
+	host       string
+	prefix     string
+	middleware Chain
+	timeout    time.Duration
+	tags       []string
+}
+
+// Group returns a Group which registers routes on the mux under the prefix.
+func (r *Mux) Group(prefix string, middleware ...Middleware) *Group {
+	return newGroup(r, "", prefix, nil, middleware)
+}
+
+func newGroup(mux *Mux, host, prefix string, chain Chain, middleware []Middleware) *Group {
+	if len(prefix) > 0 && prefix[0] != '/' {
+		panic("prefix must begin with '/' in group '" + prefix + "'")
+	}
+	return &Group{
+		mux:        mux,
+		host:       host,
+		prefix:     strings.TrimSuffix(prefix, "/"),
+		middleware: *chain.With(middleware...),
+	}
+}
+
+// Group returns a nested Group, which has the prefix and middleware of both groups.
+func (g *Group) Group(prefix string, middleware ...Middleware) *Group {
+	nested := newGroup(g.mux, g.host, g.prefix+prefix, g.middleware, middleware)
+	nested.timeout = g.timeout
+	nested.tags = append([]string(nil), g.tags...)
+	return nested
+}
+
+// Timeout sets the timeout (see Route.Timeout) of routes registered afterwards.
+func (g *Group) Timeout(timeout time.Duration) *Group {
+	g.timeout = timeout
+	return g
+}
+
+// Tag adds tags (see Route.Tag) to routes registered afterwards.
+func (g *Group) Tag(tags ...string) *Group {
+	g.tags = append(g.tags, tags...)
+	return g
+}
+
+// Use appends middleware to the group, for routes registered after it is added.
+func (g *Group) Use(middleware ...Middleware) {
+	g.middleware.Add(middleware...)
+}
+
+// Handle registers a new request handler with the group's prefix and middleware.
+func (g *Group) Handle(method, path string, handler http.Handler, middleware ...Middleware) *Route {
+	rt := g.mux.handle(g.host, method, g.prefix+path, g.middleware.With(middleware...).Handler(handler))
+	return rt.Timeout(g.timeout).Tag(g.tags...)
+}
+
+// HandleFunc registers a new request handler with the group's prefix and middleware.
+func (g *Group) HandleFunc(method, path string, handler http.HandlerFunc, middleware ...Middleware) *Route {
+	return g.Handle(method, path, handler, middleware...)
+}
+
+// GET is a shortcut for group.HandleFunc("GET", path, handler, middleware...)
+func (g *Group) GET(path string, handler http.HandlerFunc, middleware ...Middleware) *Route {
+	return g.HandleFunc("GET", path, handler, middleware...)
+}
+
+// HEAD is a shortcut for group.HandleFunc("HEAD", path, handler, middleware...)
+func (g *Group) HEAD(path string, handler http.HandlerFunc, middleware ...Middleware) *Route {
+	return g.HandleFunc("HEAD", path, handler, middleware...)
+}
+
+// OPTIONS is a shortcut for group.HandleFunc("OPTIONS", path, handler, middleware...)
+func (g *Group) OPTIONS(path string, handler http.HandlerFunc, middleware ...Middleware) *Route {
+	return g.HandleFunc("OPTIONS", path, handler, middleware...)
+}
+
+// POST is a shortcut for group.HandleFunc("POST", path, handler, middleware...)
+func (g *Group) POST(path string, handler http.HandlerFunc, middleware ...Middleware) *Route {
+	return g.HandleFunc("POST", path, handler, middleware...)
+}
+
+// PUT is a shortcut for group.HandleFunc("PUT", path, handler, middleware...)
+func (g *Group) PUT(path string, handler http.HandlerFunc, middleware ...Middleware) *Route {
+	return g.HandleFunc("PUT", path, handler, middleware...)
+}
+
+// PATCH is a shortcut for group.HandleFunc("PATCH", path, handler, middleware...)
+func (g *Group) PATCH(path string, handler http.HandlerFunc, middleware ...Middleware) *Route {
+	return g.HandleFunc("PATCH", path, handler, middleware...)
+}
+
+// DELETE is a shortcut for group.HandleFunc("DELETE", path, handler, middleware...)
+func (g *Group) DELETE(path string, handler http.HandlerFunc, middleware ...Middleware) *Route {
+	return g.HandleFunc("DELETE", path, handler, middleware...)
+}
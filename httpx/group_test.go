@@ -0,0 +1,94 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGroup(t *testing.T) {
+	var trace []string
+	router := NewMux()
+	router.Use(traceMiddleware(&trace, "mux"))
+
+	users := router.Group("/users/", traceMiddleware(&trace, "users"))
+	users.GET("/", func(w http.ResponseWriter, r *http.Request) {
+		trace = append(trace, "list")
+	})
+	users.GET("/:id", func(w http.ResponseWriter, r *http.Request) {
+		trace = append(trace, "show "+GetParams(r.Context()).ByName("id"))
+	}, traceMiddleware(&trace, "route"))
+
+	admin := users.Group("/:id/admin")
+	admin.Use(traceMiddleware(&trace, "admin"))
+	admin.POST("/reset", func(w http.ResponseWriter, r *http.Request) {
+		trace = append(trace, "reset "+GetParams(r.Context()).ByName("id"))
+	})
+
+	// middleware added to a group later doesn't change the parent group
+	users.Use(traceMiddleware(&trace, "later"))
+	users.DELETE("/:id", func(w http.ResponseWriter, r *http.Request) {
+		trace = append(trace, "delete")
+	})
+
+	testRoutes := []struct {
+		method string
+		route  string
+		trace  []string
+	}{
+		{"GET", "/users/", []string{"mux", "users", "list"}},
+		{"GET", "/users/3", []string{"mux", "users", "route", "show 3"}},
+		{"POST", "/users/3/admin/reset", []string{"mux", "users", "admin", "reset 3"}},
+		{"DELETE", "/users/3", []string{"mux", "users", "later", "delete"}},
+	}
+	for _, tr := range testRoutes {
+		trace = nil
+		r, _ := http.NewRequest(tr.method, tr.route, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != http.StatusOK || !reflect.DeepEqual(trace, tr.trace) {
+			t.Errorf("Group routing %s %s failed: Code=%d, Trace=%v; Want %v", tr.method, tr.route, w.Code, trace, tr.trace)
+		}
+	}
+}
+
+func TestGroupInvalidPrefix(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a group without a leading '/' to panic")
+		}
+	}()
+	NewMux().Group("users")
+}
+
+func TestGroupMetadata(t *testing.T) {
+	handlerFunc := func(_ http.ResponseWriter, _ *http.Request) {}
+
+	router := NewMux()
+	users := router.Group("/users").Tag("users").Timeout(5 * time.Second)
+	list := users.GET("/", handlerFunc)
+	show := users.GET("/:id", handlerFunc).Timeout(time.Second).Tag("read")
+	admin := users.Group("/:id/admin").Tag("admin")
+	reset := admin.POST("/reset", handlerFunc)
+	users.Tag("later")
+	remove := users.DELETE("/:id", handlerFunc)
+
+	testRoutes := []struct {
+		route   *Route
+		timeout time.Duration
+		tags    []string
+	}{
+		{list, 5 * time.Second, []string{"users"}},
+		{show, time.Second, []string{"users", "read"}},
+		{reset, 5 * time.Second, []string{"users", "admin"}},
+		{remove, 5 * time.Second, []string{"users", "later"}},
+	}
+	for _, tr := range testRoutes {
+		if tr.route.GetTimeout() != tr.timeout || !reflect.DeepEqual(tr.route.GetTags(), tr.tags) {
+			t.Errorf("Wrong metadata for %s %s: Timeout=%v, Tags=%v; Want %v, %v",
+				tr.route.Method, tr.route.Path, tr.route.GetTimeout(), tr.route.GetTags(), tr.timeout, tr.tags)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package httpx
+
+import (
+	"net"
+	"strings"
+)
+
+// Host returns a Group for routes which only match requests for hosts
+// matching the pattern.  A pattern may contain one wildcard (*), which matches
+// one or more characters:
+//
+//     mux.Host("api.*").GET("/users", listUsers)
+//     mux.Host("*.example.com").GET("/", tenantHome)
+//     mux.GET("/", home) // any other host
+//
+// Hosts are compared case-insensitively without the port.  Routes for host
+// patterns are tried in the order the patterns were first used, and then the
+// routes for any host, so a request for a path which isn't routed for its host
+// falls back to the host-agnostic routes.
+func (r *Mux) Host(pattern string, middleware ...Middleware) *Group {
+	if len(pattern) == 0 || strings.Count(pattern, "*") > 1 {
+		panic("host pattern must be non-empty and have at most one '*' in host '" + pattern + "'")
+	}
+	return newGroup(r, pattern, "", nil, middleware)
+}
+
+type hostRoutes struct {
+	pattern        string
+	prefix, suffix string
+	wildcard       bool
+	trees          map[string]*node
+}
+
+func (h *hostRoutes) match(host string) bool {
+	if !h.wildcard {
+		return host == h.prefix
+	}
+	return len(host) > len(h.prefix)+len(h.suffix) &&
+		strings.HasPrefix(host, h.prefix) && strings.HasSuffix(host, h.suffix)
+}
+
+// hostTrees returns the trees for a host pattern, adding them if needed
+func (r *Mux) hostTrees(pattern string) map[string]*node {
+	for _, h := range r.hosts {
+		if h.pattern == pattern {
+			return h.trees
+		}
+	}
+
+	h := &hostRoutes{pattern: pattern, trees: make(map[string]*node)}
+	normalized := strings.ToLower(pattern)
+	if i := strings.IndexByte(normalized, '*'); i >= 0 {
+		h.prefix, h.suffix, h.wildcard = normalized[:i], normalized[i+1:], true
+	} else {
+		h.prefix = normalized
+	}
+	r.hosts = append(r.hosts, h)
+	return h.trees
+}
+
+// treesFor returns the trees used to route a request for a host, in the order
+// that they should be tried
+func (r *Mux) treesFor(host string) []map[string]*node {
+	forest := make([]map[string]*node, 0, 2)
+	if len(r.hosts) > 0 {
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.ToLower(host)
+		for _, h := range r.hosts {
+			if h.match(host) {
+				forest = append(forest, h.trees)
+			}
+		}
+	}
+	return append(forest, r.trees)
+}
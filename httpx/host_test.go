@@ -0,0 +1,76 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHostRouting(t *testing.T) {
+	var routed string
+	route := func(name string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) { routed = name }
+	}
+
+	router := NewMux()
+	router.GET("/", route("home"))
+	router.GET("/status", route("status"))
+	router.Host("api.*").GET("/", route("api"))
+	router.Host("api.*").GET("/users/:id", route("api user"))
+	router.Host("*.example.com").GET("/", route("tenant"))
+	router.Host("*.example.com").POST("/status", route("tenant status"))
+	router.Host("admin.example.com").Group("/teams").GET("/", route("admin teams"))
+
+	testRoutes := []struct {
+		method string
+		host   string
+		route  string
+		code   int
+		routed string
+	}{
+		{"GET", "example.com", "/", 200, "home"},
+		{"GET", "api.example.com", "/", 200, "api"},
+		{"GET", "API.Example.com:8080", "/", 200, "api"},
+		{"GET", "api.example.com", "/users/3", 200, "api user"},
+		{"GET", "www.example.com", "/users/3", 404, ""},
+		{"GET", "api.example.com", "/status", 200, "status"}, // falls back to any host
+		{"GET", "muppets.example.com", "/", 200, "tenant"},
+		{"GET", "admin.example.com", "/teams/", 200, "admin teams"},
+		{"GET", "admin.example.com", "/", 200, "tenant"},
+		{"GET", "www.example.com", "/teams/", 404, ""},
+		{"GET", "api.example.com", "/users/3/", 301, ""},
+		{"DELETE", "muppets.example.com", "/status", 405, ""},
+	}
+	for _, tr := range testRoutes {
+		routed = ""
+		r, _ := http.NewRequest(tr.method, tr.route, nil)
+		r.Host = tr.host
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != tr.code || routed != tr.routed {
+			t.Errorf("Host routing %s %s%s failed: Code=%d, Routed=%q; Want %d, %q", tr.method, tr.host, tr.route, w.Code, routed, tr.code, tr.routed)
+		}
+	}
+
+	// the allowed methods include the host's routes
+	r, _ := http.NewRequest("OPTIONS", "/status", nil)
+	r.Host = "muppets.example.com"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if allow := w.Header().Get("Allow"); allow != "POST, GET, OPTIONS" {
+		t.Errorf("Wrong Allow header for host route: Got %q; Want \"POST, GET, OPTIONS\"", allow)
+	}
+}
+
+func TestHostInvalidPattern(t *testing.T) {
+	for _, pattern := range []string{"", "*.*.example.com"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected host pattern %q to panic", pattern)
+				}
+			}()
+			NewMux().Host(pattern)
+		}()
+	}
+}
@@ -0,0 +1,58 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+)
+
+// A Local is a typed key for a request-scoped value carried in a context.
+//
+// Declare a Local once for each kind of value, then use it to store and read
+// the value without casting from interface{}:
+//
+//     var RequestLog = httpx.NewLocal[*Log]("request log")
+//
+//     ctx = RequestLog.Put(ctx, &Log{})
+//     log := RequestLog.Get(ctx)
+//
+type Local[T any] struct {
+	name string
+}
+
+// NewLocal returns a new Local.  The name is only used for debugging.
+func NewLocal[T any](name string) *Local[T] {
+	return &Local[T]{name: name}
+}
+
+// String returns the name of the local.
+func (l *Local[T]) String() string {
+	return "httpx.Local(" + l.name + ")"
+}
+
+// Put returns a new Context carrying value.
+func (l *Local[T]) Put(ctx context.Context, value T) context.Context {
+	return context.WithValue(ctx, l, value)
+}
+
+// Get extracts the value from ctx, providing the zero value if absent.
+func (l *Local[T]) Get(ctx context.Context) T {
+	value, _ := l.Lookup(ctx)
+	return value
+}
+
+// Lookup extracts the value from ctx, and reports whether it was present.
+func (l *Local[T]) Lookup(ctx context.Context) (T, bool) {
+	value, ok := ctx.Value(l).(T)
+	return value, ok
+}
+
+// Provide returns a Handler which puts the value returned by provider in the
+// request context, so that handlers later in the chain can Get it.
+func (l *Local[T]) Provide(provider func(*http.Request) T) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			req = req.WithContext(l.Put(req.Context(), provider(req)))
+			h.ServeHTTP(w, req)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type testLog struct {
+	Path string
+}
+
+func TestLocal(t *testing.T) {
+	name := NewLocal[string]("name")
+	count := NewLocal[int]("count")
+
+	ctx := context.Background()
+	if _, ok := name.Lookup(ctx); ok {
+		t.Errorf("Expected name to be absent")
+	}
+	if val := count.Get(ctx); val != 0 {
+		t.Errorf("Wrong value for count: Got %d; Want 0", val)
+	}
+
+	ctx = name.Put(ctx, "vanilla")
+	ctx = count.Put(ctx, 3)
+	if val, ok := name.Lookup(ctx); !ok || val != "vanilla" {
+		t.Errorf("Wrong value for name: Got %q, %v; Want \"vanilla\", true", val, ok)
+	}
+	if val := count.Get(ctx); val != 3 {
+		t.Errorf("Wrong value for count: Got %d; Want 3", val)
+	}
+
+	// locals of the same type and name are distinct keys
+	other := NewLocal[string]("name")
+	if _, ok := other.Lookup(ctx); ok {
+		t.Errorf("Expected other name to be absent")
+	}
+}
+
+func TestLocalProvide(t *testing.T) {
+	requestLog := NewLocal[*testLog]("request log")
+
+	var log *testLog
+	chain := Chain{}
+	chain.Use(requestLog.Provide(func(req *http.Request) *testLog {
+		return &testLog{Path: req.URL.Path}
+	}))
+	handler := chain.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		log = requestLog.Get(req.Context())
+	})
+
+	req, _ := http.NewRequest("GET", "/reports", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if log == nil || log.Path != "/reports" {
+		t.Errorf("Wrong value for request log: Got %+v; Want &{Path:/reports}", log)
+	}
+}
@@ -0,0 +1,246 @@
+package httpx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/clock"
+)
+
+// A LogFormat is the format of the lines written by a Logger.
+type LogFormat int
+
+const (
+	// LogText writes a line of text for each request, like:
+	//   2016-07-04T12:00:00Z GET /users/3 200 1.25ms 10.0.0.1 user=3
+	LogText LogFormat = iota
+
+	// LogJSON writes a JSON object for each request, like:
+	//   {"time":"2016-07-04T12:00:00Z","method":"GET","path":"/users/3",
+	//    "status":200,"latency_ms":1.25,"client_ip":"10.0.0.1","user":3}
+	LogJSON
+)
+
+// A Logger is middleware which writes a line to its Output after each
+// request, describing the request and the response.  Handlers can add
+// key/value pairs to the line with the RequestLog in the request context:
+//
+//     logger := &httpx.Logger{Format: httpx.LogJSON}
+//     mux.Use(logger.Handler)
+//
+//     func getUser(w http.ResponseWriter, req *http.Request) {
+//         httpx.GetRequestLog(req.Context()).Set("user", id)
+//     }
+//
+// If the request has an ID (see RequestID), it is included as "request_id".
+// The request's latency is measured with the clock in the request context
+// (see clock.WithClock), so it can be controlled in tests.
+type Logger struct {
+	Output io.Writer // where lines are written, or os.Stderr if nil
+	Format LogFormat
+
+	mu sync.Mutex
+}
+
+// Handler returns a Handler which logs each request that h handles.
+func (l *Logger) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		src := clock.FromContext(req.Context())
+		start := src.UTC()
+
+		reqLog := &RequestLog{}
+		if id := GetRequestID(req.Context()); len(id) > 0 {
+			reqLog.Set("request_id", id)
+		}
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(rec, req.WithContext(requestLogs.Put(req.Context(), reqLog)))
+
+		entry := logEntry{
+			Time:     start,
+			Method:   req.Method,
+			Path:     req.URL.RequestURI(),
+			Status:   rec.status,
+			Latency:  src.UTC().Sub(start),
+			ClientIP: clientIP(req),
+			Fields:   reqLog.Fields(),
+		}
+		l.write(entry)
+	})
+}
+
+func (l *Logger) write(entry logEntry) {
+	var line []byte
+	if l.Format == LogJSON {
+		line = entry.appendJSON(nil)
+	} else {
+		line = entry.appendText(nil)
+	}
+	line = append(line, '\n')
+
+	out := l.Output
+	if out == nil {
+		out = os.Stderr
+	}
+	l.mu.Lock()
+	out.Write(line)
+	l.mu.Unlock()
+}
+
+// A LogField is a key/value pair added to a request's log line
+type LogField struct {
+	Key   string
+	Value interface{}
+}
+
+// A RequestLog collects the key/value pairs to write in a request's log line.
+// It is safe to use from multiple goroutines.
+type RequestLog struct {
+	mu     sync.Mutex
+	fields []LogField
+}
+
+var requestLogs = NewLocal[*RequestLog]("request log")
+
+// GetRequestLog returns the RequestLog for the request with the context.
+// If the request isn't being logged, it returns a RequestLog which is never
+// written, so handlers don't need to check.
+func GetRequestLog(ctx context.Context) *RequestLog {
+	if reqLog, ok := requestLogs.Lookup(ctx); ok {
+		return reqLog
+	}
+	return &RequestLog{}
+}
+
+// Set adds a key/value pair to the log, replacing any value already set
+// for the key.
+func (l *RequestLog) Set(key string, value interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i := range l.fields {
+		if l.fields[i].Key == key {
+			l.fields[i].Value = value
+			return
+		}
+	}
+	l.fields = append(l.fields, LogField{key, value})
+}
+
+// Fields returns the key/value pairs in the order they were first set.
+func (l *RequestLog) Fields() []LogField {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]LogField(nil), l.fields...)
+}
+
+type logEntry struct {
+	Time     time.Time
+	Method   string
+	Path     string
+	Status   int
+	Latency  time.Duration
+	ClientIP string
+	Fields   []LogField
+}
+
+func (e logEntry) appendText(b []byte) []byte {
+	b = e.Time.AppendFormat(b, time.RFC3339)
+	b = append(b, ' ')
+	b = append(b, e.Method...)
+	b = append(b, ' ')
+	b = append(b, e.Path...)
+	b = append(b, ' ')
+	b = strconv.AppendInt(b, int64(e.Status), 10)
+	b = append(b, ' ')
+	b = append(b, e.Latency.String()...)
+	b = append(b, ' ')
+	b = append(b, e.ClientIP...)
+	for _, field := range e.Fields {
+		value := fmt.Sprint(field.Value)
+		if len(value) == 0 || strings.ContainsAny(value, " \t\r\n\"=") {
+			value = strconv.Quote(value)
+		}
+		b = append(b, ' ')
+		b = append(b, field.Key...)
+		b = append(b, '=')
+		b = append(b, value...)
+	}
+	return b
+}
+
+func (e logEntry) appendJSON(b []byte) []byte {
+	buf := bytes.NewBuffer(b)
+	buf.WriteString(`{"time":`)
+	writeJSON(buf, e.Time.Format(time.RFC3339))
+	buf.WriteString(`,"method":`)
+	writeJSON(buf, e.Method)
+	buf.WriteString(`,"path":`)
+	writeJSON(buf, e.Path)
+	buf.WriteString(`,"status":`)
+	writeJSON(buf, e.Status)
+	buf.WriteString(`,"latency_ms":`)
+	writeJSON(buf, float64(e.Latency)/float64(time.Millisecond))
+	buf.WriteString(`,"client_ip":`)
+	writeJSON(buf, e.ClientIP)
+	for _, field := range e.Fields {
+		buf.WriteByte(',')
+		writeJSON(buf, field.Key)
+		buf.WriteByte(':')
+		writeJSON(buf, field.Value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
+
+func writeJSON(buf *bytes.Buffer, value interface{}) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		encoded, _ = json.Marshal(fmt.Sprint(value))
+	}
+	buf.Write(encoded)
+}
+
+// clientIP returns the address of the client which sent the request,
+// without the port
+func clientIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// statusRecorder remembers the status of the response written through it
+type statusRecorder struct {
+	http.ResponseWriter
+	status  int
+	written bool
+}
+
+func (rec *statusRecorder) WriteHeader(status int) {
+	if !rec.written {
+		rec.status = status
+		rec.written = true
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *statusRecorder) Write(b []byte) (int, error) {
+	rec.written = true
+	return rec.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying ResponseWriter
+func (rec *statusRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
@@ -0,0 +1,64 @@
+package httpx
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/clock"
+)
+
+func TestLogger(t *testing.T) {
+	src := clock.NewFake(time.Date(2016, time.July, 4, 12, 0, 0, 0, time.UTC))
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		src.Advance(1250 * time.Microsecond)
+		reqLog := GetRequestLog(req.Context())
+		reqLog.Set("user", 3)
+		reqLog.Set("note", "two words")
+		reqLog.Set("user", 4)
+		w.WriteHeader(http.StatusCreated)
+		w.WriteHeader(http.StatusAccepted) // ignored
+	}
+
+	testFormats := []struct {
+		format LogFormat
+		line   string
+	}{
+		{LogText, `2016-07-04T12:00:00Z POST /users?team=2 201 1.25ms 10.0.0.1 user=4 note="two words"` + "\n"},
+		{LogJSON, `{"time":"2016-07-04T12:00:00Z","method":"POST","path":"/users?team=2","status":201,"latency_ms":1.25,"client_ip":"10.0.0.1","user":4,"note":"two words"}` + "\n"},
+	}
+	for _, tf := range testFormats {
+		var out bytes.Buffer
+		logger := &Logger{Output: &out, Format: tf.format}
+
+		r, _ := http.NewRequest("POST", "/users?team=2", nil)
+		r.RemoteAddr = "10.0.0.1:5678"
+		r = r.WithContext(clock.WithClock(r.Context(), src))
+		logger.Handler(http.HandlerFunc(handler)).ServeHTTP(httptest.NewRecorder(), r)
+		if out.String() != tf.line {
+			t.Errorf("Logger wrote %q; Want %q", out.String(), tf.line)
+		}
+	}
+}
+
+func TestLoggerDefaultStatus(t *testing.T) {
+	var out bytes.Buffer
+	logger := &Logger{Output: &out}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("hello"))
+		w.WriteHeader(http.StatusNotFound) // too late
+	})
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.RemoteAddr = "pipe"
+	r = r.WithContext(clock.WithClock(r.Context(), clock.NewFake(time.Date(2016, time.July, 4, 12, 0, 0, 0, time.UTC))))
+	logger.Handler(handler).ServeHTTP(httptest.NewRecorder(), r)
+	if want := "2016-07-04T12:00:00Z GET / 200 0s pipe\n"; out.String() != want {
+		t.Errorf("Logger wrote %q; Want %q", out.String(), want)
+	}
+
+	// the log can be used when the request isn't logged
+	GetRequestLog(r.Context()).Set("user", 3)
+}
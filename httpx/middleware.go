@@ -2,7 +2,9 @@ package httpx
 
 import (
 	"context"
+	"mime"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -48,7 +50,9 @@ func DeadlineHandler(deadline time.Time) func(http.Handler) http.Handler {
 // TimeoutHandler returns a Handler which adds a timeout to the context.
 //
 // Child handlers are responsible for obeying the context deadline and returning
-// an appropriate error (or not) response in case of timeout.
+// an appropriate error (or not) response in case of timeout.  Nothing is sent
+// to the client when the deadline passes; a handler which ignores the context
+// can still respond late.  Use ResponseTimeout to always respond on time.
 func TimeoutHandler(timeout time.Duration) func(http.Handler) http.Handler {
 	return func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
@@ -60,3 +64,83 @@ func TimeoutHandler(timeout time.Duration) func(http.Handler) http.Handler {
 		})
 	}
 }
+
+// ResponseTimeout returns a Handler which responds with 503 Service Unavailable
+// if the handler doesn't finish within the timeout, and cancels the context.
+// TimeoutHandler only cancels the context and leaves the response to the
+// handler, while ResponseTimeout writes the 503 itself (it wraps
+// http.TimeoutHandler), so the client always receives a response on time.
+// In exchange, the response is buffered until the handler finishes (so it
+// can't be streamed) and the handler's writes after the timeout are discarded.
+//
+// Like any middleware, it can be added to a Group or a single route to give
+// it a different timeout:
+//
+//     mux.Use(httpx.ResponseTimeout(5 * time.Second))
+//     reports := mux.Group("/reports", httpx.ResponseTimeout(time.Minute))
+//
+func ResponseTimeout(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.TimeoutHandler(h, timeout, http.StatusText(http.StatusServiceUnavailable)+": the request timed out\n")
+	}
+}
+
+// MaxBodyBytes returns a Handler which limits the size of request bodies.
+// Requests with a larger Content-Length receive a 413 Request Entity Too
+// Large response, and otherwise reading more than the limit from the body
+// returns an *http.MaxBytesError.
+func MaxBodyBytes(limit int64) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.ContentLength > limit {
+				http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+				return
+			}
+			if req.Body != nil {
+				req.Body = http.MaxBytesReader(w, req.Body, limit)
+			}
+			h.ServeHTTP(w, req)
+		})
+	}
+}
+
+// MethodOverrideHeader is the request header read by MethodOverride.
+const MethodOverrideHeader = "X-HTTP-Method-Override"
+
+// MethodOverride returns a Handler which replaces the method of a POST request
+// with the one named by the X-HTTP-Method-Override header or the "_method"
+// field of a url-encoded form, for clients which can only send GET and POST.
+//
+// Only the given methods may be used as an override; if none are given then
+// PUT, PATCH, and DELETE are allowed.  Other overrides are ignored.
+func MethodOverride(methods ...string) func(http.Handler) http.Handler {
+	if len(methods) == 0 {
+		methods = []string{"PUT", "PATCH", "DELETE"}
+	}
+	allowed := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		allowed[strings.ToUpper(method)] = true
+	}
+
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.Method == "POST" {
+				override := req.Header.Get(MethodOverrideHeader)
+				if len(override) == 0 && isFormEncoded(req) {
+					override = req.PostFormValue("_method")
+				}
+				if override = strings.ToUpper(override); allowed[override] {
+					req = req.WithContext(req.Context()) // shallow copy
+					req.Method = override
+				}
+			}
+
+			h.ServeHTTP(w, req)
+		})
+	}
+}
+
+func isFormEncoded(req *http.Request) bool {
+	contentType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	return contentType == "application/x-www-form-urlencoded"
+}
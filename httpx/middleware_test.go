@@ -0,0 +1,132 @@
+package httpx
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMethodOverride(t *testing.T) {
+	var method string
+	handler := MethodOverride()(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		method = req.Method
+	}))
+
+	examples := []struct {
+		method   string
+		header   string
+		form     string
+		expected string
+	}{
+		{"POST", "", "", "POST"},
+		{"POST", "PUT", "", "PUT"},
+		{"POST", "delete", "", "DELETE"},
+		{"POST", "", "_method=patch", "PATCH"},
+		{"POST", "DELETE", "_method=PUT", "DELETE"},
+		{"POST", "CONNECT", "", "POST"},
+		{"GET", "DELETE", "", "GET"},
+	}
+	for _, example := range examples {
+		req, _ := http.NewRequest(example.method, "/", strings.NewReader(example.form))
+		if len(example.header) > 0 {
+			req.Header.Set(MethodOverrideHeader, example.header)
+		}
+		if len(example.form) > 0 {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		if method != example.expected {
+			t.Errorf("Wrong method for %+v: Got %s; Want %s", example, method, example.expected)
+		}
+	}
+}
+
+func TestMethodOverrideAllowed(t *testing.T) {
+	var method string
+	handler := MethodOverride("delete")(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		method = req.Method
+	}))
+
+	req, _ := http.NewRequest("POST", "/", nil)
+	req.Header.Set(MethodOverrideHeader, "PUT")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if method != "POST" {
+		t.Errorf("Wrong method for disallowed override: Got %s; Want POST", method)
+	}
+
+	req.Header.Set(MethodOverrideHeader, "DELETE")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if method != "DELETE" {
+		t.Errorf("Wrong method for allowed override: Got %s; Want DELETE", method)
+	}
+	if req.Method != "POST" {
+		t.Errorf("Expected the original request to be unchanged")
+	}
+}
+
+func TestResponseTimeout(t *testing.T) {
+	cancelled := make(chan bool, 1)
+	mux := NewMux()
+	mux.Use(ResponseTimeout(time.Hour))
+	mux.GET("/fast", func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	})
+	slow := mux.Group("/slow", ResponseTimeout(10*time.Millisecond))
+	slow.GET("/", func(w http.ResponseWriter, req *http.Request) {
+		<-req.Context().Done()
+		cancelled <- true
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	examples := []struct {
+		path string
+		code int
+	}{
+		{"/fast", http.StatusCreated},
+		{"/slow/", http.StatusServiceUnavailable},
+	}
+	for _, example := range examples {
+		r, _ := http.NewRequest("GET", example.path, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		if w.Code != example.code {
+			t.Errorf("Timeout for %s failed: Code=%d; Want %d", example.path, w.Code, example.code)
+		}
+	}
+	if !<-cancelled {
+		t.Errorf("Timeout didn't cancel the request context")
+	}
+}
+
+func TestMaxBodyBytes(t *testing.T) {
+	var readErr error
+	handler := MaxBodyBytes(5)(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, readErr = io.ReadAll(req.Body)
+	}))
+
+	examples := []struct {
+		body          string
+		contentLength int64
+		code          int
+		tooLarge      bool
+	}{
+		{"hello", 5, http.StatusOK, false},
+		{"hello world", 11, http.StatusRequestEntityTooLarge, false},
+		{"hello world", -1, http.StatusOK, true}, // unknown length
+	}
+	for _, example := range examples {
+		readErr = nil
+		r, _ := http.NewRequest("POST", "/", strings.NewReader(example.body))
+		r.ContentLength = example.contentLength
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		_, tooLarge := readErr.(*http.MaxBytesError)
+		if w.Code != example.code || tooLarge != example.tooLarge {
+			t.Errorf("Body limit for %q failed: Code=%d, Error=%v; Want %d", example.body, w.Code, readErr, example.code)
+		}
+	}
+}
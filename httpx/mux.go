@@ -6,7 +6,10 @@
 
 package httpx
 
-import "net/http"
+import (
+	"net/http"
+	"strings"
+)
 
 // Mux is a http.Handler which can be used to dispatch requests to different
 // handler functions via configurable routes.  Mux is based off Julien Schmidt's
@@ -23,6 +26,12 @@ import "net/http"
 //
 type Mux struct {
 	trees map[string]*node
+	hosts []*hostRoutes // the routes for host patterns, in the order they were added
+
+	middleware Chain
+	handler    http.Handler      // the routing handler wrapped with the middleware
+	names      map[string]*Route // the named routes, for URL
+	routes     []*Route          // all of the routes, in the order they were added
 
 	// Enables automatic redirection if the current route can't be matched but a
 	// handler for the path with (without) the trailing slash exists.
@@ -87,58 +96,92 @@ func NewMux() *Mux {
 	}
 }
 
-// GET is a shortcut for router.HandleFunc("GET", path, handler)
-func (r *Mux) GET(path string, handler http.HandlerFunc) {
-	r.HandleFunc("GET", path, handler)
+// GET is a shortcut for router.HandleFunc("GET", path, handler, middleware...)
+func (r *Mux) GET(path string, handler http.HandlerFunc, middleware ...Middleware) *Route {
+	return r.HandleFunc("GET", path, handler, middleware...)
 }
 
-// HEAD is a shortcut for router.HandleFunc("HEAD", path, handler)
-func (r *Mux) HEAD(path string, handler http.HandlerFunc) {
-	r.HandleFunc("HEAD", path, handler)
+// HEAD is a shortcut for router.HandleFunc("HEAD", path, handler, middleware...)
+func (r *Mux) HEAD(path string, handler http.HandlerFunc, middleware ...Middleware) *Route {
+	return r.HandleFunc("HEAD", path, handler, middleware...)
 }
 
-// OPTIONS is a shortcut for router.HandleFunc("OPTIONS", path, handler)
-func (r *Mux) OPTIONS(path string, handler http.HandlerFunc) {
-	r.HandleFunc("OPTIONS", path, handler)
+// OPTIONS is a shortcut for router.HandleFunc("OPTIONS", path, handler, middleware...)
+func (r *Mux) OPTIONS(path string, handler http.HandlerFunc, middleware ...Middleware) *Route {
+	return r.HandleFunc("OPTIONS", path, handler, middleware...)
 }
 
-// POST is a shortcut for router.HandleFunc("POST", path, handler)
-func (r *Mux) POST(path string, handler http.HandlerFunc) {
-	r.HandleFunc("POST", path, handler)
+// POST is a shortcut for router.HandleFunc("POST", path, handler, middleware...)
+func (r *Mux) POST(path string, handler http.HandlerFunc, middleware ...Middleware) *Route {
+	return r.HandleFunc("POST", path, handler, middleware...)
 }
 
-// PUT is a shortcut for router.HandleFunc("PUT", path, handler)
-func (r *Mux) PUT(path string, handler http.HandlerFunc) {
-	r.HandleFunc("PUT", path, handler)
+// PUT is a shortcut for router.HandleFunc("PUT", path, handler, middleware...)
+func (r *Mux) PUT(path string, handler http.HandlerFunc, middleware ...Middleware) *Route {
+	return r.HandleFunc("PUT", path, handler, middleware...)
 }
 
-// PATCH is a shortcut for router.HandleFunc("PATCH", path, handler)
-func (r *Mux) PATCH(path string, handler http.HandlerFunc) {
-	r.HandleFunc("PATCH", path, handler)
+// PATCH is a shortcut for router.HandleFunc("PATCH", path, handler, middleware...)
+func (r *Mux) PATCH(path string, handler http.HandlerFunc, middleware ...Middleware) *Route {
+	return r.HandleFunc("PATCH", path, handler, middleware...)
 }
 
-// DELETE is a shortcut for router.HandleFunc("DELETE", path, handler)
-func (r *Mux) DELETE(path string, handler http.HandlerFunc) {
-	r.HandleFunc("DELETE", path, handler)
+// DELETE is a shortcut for router.HandleFunc("DELETE", path, handler, middleware...)
+func (r *Mux) DELETE(path string, handler http.HandlerFunc, middleware ...Middleware) *Route {
+	return r.HandleFunc("DELETE", path, handler, middleware...)
+}
+
+// Use appends middleware which wraps every request served by the mux, in the
+// order it is added (so the first middleware is the outermost).
+//
+// The middleware runs before the request is routed, so it also wraps the
+// NotFound and MethodNotAllowed handlers, redirects, and automatic replies to
+// OPTIONS requests.  Path parameters are not in the request context until the
+// request is routed, so middleware which needs them should be added to the
+// routes instead.
+func (r *Mux) Use(middleware ...Middleware) {
+	r.middleware.Add(middleware...)
+	r.handler = r.middleware.HandlerFunc(r.route)
 }
 
 // Handle registers a new request handler with the given path and method.
-func (r *Mux) Handle(method, path string, handler http.Handler) {
+//
+// The handler is wrapped with the route's middleware (if any), which runs
+// after any middleware added to the mux with Use:
+//
+//     mux.Handle("DELETE", "/users/:id", deleteUser, requireAdmin, audit)
+//
+func (r *Mux) Handle(method, path string, handler http.Handler, middleware ...Middleware) *Route {
+	return r.handle("", method, path, Chain(middleware).Handler(handler))
+}
+
+// handle adds a route to the trees for the host pattern (or for any host, if
+// the pattern is empty)
+func (r *Mux) handle(host, method, path string, handler http.Handler) *Route {
 	if path[0] != '/' {
 		panic("path must begin with '/' in path '" + path + "'")
 	}
 
-	if r.trees == nil {
-		r.trees = make(map[string]*node)
+	var trees map[string]*node
+	if len(host) == 0 {
+		if r.trees == nil {
+			r.trees = make(map[string]*node)
+		}
+		trees = r.trees
+	} else {
+		trees = r.hostTrees(host)
 	}
 
-	root := r.trees[method]
+	root := trees[method]
 	if root == nil {
 		root = new(node)
-		r.trees[method] = root
+		trees[method] = root
 	}
 
-	root.addRoute(path, handler)
+	rt := &Route{Host: host, Method: method, Path: path, mux: r}
+	root.addRoute(path, &routeHandler{route: rt, handler: handler})
+	r.routes = append(r.routes, rt)
+	return rt
 }
 
 // HandleFunc registers a new request handler with the given path and method.
@@ -149,8 +192,8 @@ func (r *Mux) Handle(method, path string, handler http.Handler) {
 // This function is intended for bulk loading and to allow the usage of less
 // frequently used, non-standardized or custom methods (e.g. for internal
 // communication with a proxy).
-func (r *Mux) HandleFunc(method, path string, handler http.HandlerFunc) {
-	r.Handle(method, path, http.HandlerFunc(handler))
+func (r *Mux) HandleFunc(method, path string, handler http.HandlerFunc, middleware ...Middleware) *Route {
+	return r.Handle(method, path, handler, middleware...)
 }
 
 // ServeFiles serves files from the given file system root.
@@ -191,45 +234,61 @@ func (r *Mux) recv(w http.ResponseWriter, req *http.Request) {
 // the same path with an extra / without the trailing slash should be performed.
 func (r *Mux) Lookup(method, path string) (http.Handler, Params, bool) {
 	if root := r.trees[method]; root != nil {
-		return root.getValue(path)
+		handler, ps, tsr := root.getValue(path)
+		if rh, ok := handler.(*routeHandler); ok {
+			handler = rh.handler
+		}
+		return handler, ps, tsr
 	}
 	return nil, nil, false
 }
 
-func (r *Mux) allowed(path, reqMethod string) (allow string) {
-	if path == "*" { // server-wide
-		for method := range r.trees {
-			if method == "OPTIONS" {
-				continue
-			}
-
-			// add request method to list of allowed methods
-			if len(allow) == 0 {
-				allow = method
-			} else {
-				allow += ", " + method
+// match returns the route for the request, or nil if none matches
+func (r *Mux) match(req *http.Request) *Route {
+	for _, trees := range r.treesFor(req.Host) {
+		if root := trees[req.Method]; root != nil {
+			if handler, _, _ := root.getValue(req.URL.Path); handler != nil {
+				if rh, ok := handler.(*routeHandler); ok {
+					return rh.route
+				}
+				return nil
 			}
 		}
-	} else { // specific path
-		for method := range r.trees {
+	}
+	return nil
+}
+
+// AllowedMethods returns the methods which are routed for the request's host
+// and path (not including OPTIONS), or for any path if the path is "*".
+func (r *Mux) AllowedMethods(req *http.Request) []string {
+	return r.allowedMethods(r.treesFor(req.Host), req.URL.Path, "")
+}
+
+func (r *Mux) allowedMethods(forest []map[string]*node, path, reqMethod string) (methods []string) {
+	seen := make(map[string]bool)
+	for _, trees := range forest {
+		for method := range trees {
 			// Skip the requested method - we already tried this one
-			if method == reqMethod || method == "OPTIONS" {
+			if seen[method] || method == "OPTIONS" || (path != "*" && method == reqMethod) {
 				continue
 			}
 
-			handler, _, _ := r.trees[method].getValue(path)
-			if handler != nil {
-				// add request method to list of allowed methods
-				if len(allow) == 0 {
-					allow = method
-				} else {
-					allow += ", " + method
+			if path != "*" { // specific path, rather than server-wide
+				if handler, _, _ := trees[method].getValue(path); handler == nil {
+					continue
 				}
 			}
+
+			seen[method] = true
+			methods = append(methods, method)
 		}
 	}
-	if len(allow) > 0 {
-		allow += ", OPTIONS"
+	return methods
+}
+
+func (r *Mux) allowed(forest []map[string]*node, path, reqMethod string) (allow string) {
+	if methods := r.allowedMethods(forest, path, reqMethod); len(methods) > 0 {
+		allow = strings.Join(methods, ", ") + ", OPTIONS"
 	}
 	return
 }
@@ -240,23 +299,48 @@ func (r *Mux) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		defer r.recv(w, req)
 	}
 
+	if r.handler != nil {
+		// let the mux's middleware see the route before it is routed
+		if rt := r.match(req); rt != nil {
+			req = req.WithContext(routes.Put(req.Context(), rt))
+		}
+		r.handler.ServeHTTP(w, req)
+	} else {
+		r.route(w, req)
+	}
+}
+
+// route dispatches the request to the handler for its method and path
+func (r *Mux) route(w http.ResponseWriter, req *http.Request) {
 	path := req.URL.Path
+	forest := r.treesFor(req.Host)
+
+	for _, trees := range forest {
+		if root := trees[req.Method]; root != nil {
+			if handler, ps, _ := root.getValue(path); handler != nil {
+				ctx := ps.Put(req.Context())
+				req = req.WithContext(ctx)
+				handler.ServeHTTP(w, req)
+				return
+			}
+		}
+	}
+
+	if req.Method != "CONNECT" && path != "/" {
+		code := 301 // Permanent redirect, request with GET method
+		if req.Method != "GET" {
+			// Temporary redirect, request with same method
+			// As of Go 1.3, Go does not support status code 308.
+			code = 307
+		}
 
-	if root := r.trees[req.Method]; root != nil {
-		if handler, ps, tsr := root.getValue(path); handler != nil {
-			ctx := ps.Put(req.Context())
-			req = req.WithContext(ctx)
-			handler.ServeHTTP(w, req)
-			return
-		} else if req.Method != "CONNECT" && path != "/" {
-			code := 301 // Permanent redirect, request with GET method
-			if req.Method != "GET" {
-				// Temporary redirect, request with same method
-				// As of Go 1.3, Go does not support status code 308.
-				code = 307
+		for _, trees := range forest {
+			root := trees[req.Method]
+			if root == nil {
+				continue
 			}
 
-			if tsr && r.RedirectTrailingSlash {
+			if _, _, tsr := root.getValue(path); tsr && r.RedirectTrailingSlash {
 				if len(path) > 1 && path[len(path)-1] == '/' {
 					req.URL.Path = path[:len(path)-1]
 				} else {
@@ -284,7 +368,7 @@ func (r *Mux) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	if req.Method == "OPTIONS" {
 		// Handle OPTIONS requests
 		if r.HandleOPTIONS {
-			if allow := r.allowed(path, req.Method); len(allow) > 0 {
+			if allow := r.allowed(forest, path, req.Method); len(allow) > 0 {
 				w.Header().Set("Allow", allow)
 				return
 			}
@@ -292,7 +376,7 @@ func (r *Mux) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	} else {
 		// Handle 405
 		if r.HandleMethodNotAllowed {
-			if allow := r.allowed(path, req.Method); len(allow) > 0 {
+			if allow := r.allowed(forest, path, req.Method); len(allow) > 0 {
 				w.Header().Set("Allow", allow)
 				if r.MethodNotAllowed != nil {
 					r.MethodNotAllowed.ServeHTTP(w, req)
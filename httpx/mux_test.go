@@ -502,3 +502,72 @@ func TestRouterServeFiles(t *testing.T) {
 		t.Error("serving file failed")
 	}
 }
+
+// traceMiddleware appends name to the trace before calling the next handler
+func traceMiddleware(trace *[]string, name string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*trace = append(*trace, name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestRouterMiddleware(t *testing.T) {
+	var trace []string
+	router := NewMux()
+	router.Use(traceMiddleware(&trace, "first"), traceMiddleware(&trace, "second"))
+	router.Use(traceMiddleware(&trace, "third"))
+	router.GET("/user/:name", func(w http.ResponseWriter, r *http.Request) {
+		trace = append(trace, "user "+GetParams(r.Context()).ByName("name"))
+	}, traceMiddleware(&trace, "route"), traceMiddleware(&trace, "route2"))
+	router.POST("/user/:name", func(w http.ResponseWriter, r *http.Request) {
+		trace = append(trace, "post")
+	})
+	router.NotFound = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		trace = append(trace, "not found")
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	testRoutes := []struct {
+		method string
+		route  string
+		code   int
+		trace  []string
+	}{
+		{"GET", "/user/gopher", 200, []string{"first", "second", "third", "route", "route2", "user gopher"}},
+		{"POST", "/user/gopher", 200, []string{"first", "second", "third", "post"}},
+		{"GET", "/nope", 404, []string{"first", "second", "third", "not found"}},
+		{"DELETE", "/user/gopher", 405, []string{"first", "second", "third"}},
+		{"OPTIONS", "/user/gopher", 200, []string{"first", "second", "third"}},
+		{"GET", "/user/gopher/", 301, []string{"first", "second", "third"}},
+	}
+	for _, tr := range testRoutes {
+		trace = nil
+		r, _ := http.NewRequest(tr.method, tr.route, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != tr.code || !reflect.DeepEqual(trace, tr.trace) {
+			t.Errorf("Middleware for %s %s failed: Code=%d, Trace=%v; Want %d, %v", tr.method, tr.route, w.Code, trace, tr.code, tr.trace)
+		}
+	}
+}
+
+func TestRouterMiddlewarePanic(t *testing.T) {
+	router := NewMux()
+	router.PanicHandler = func(w http.ResponseWriter, r *http.Request, rcv interface{}) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("oops")
+		})
+	})
+
+	r, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Panics in middleware should be recovered: Code=%d", w.Code)
+	}
+}
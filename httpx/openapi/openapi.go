@@ -0,0 +1,198 @@
+/*
+Package openapi generates an OpenAPI 3 document describing the routes of an
+httpx.Mux, so that the spec for an API is kept in sync with its routes.
+
+Routes are described by their metadata (see httpx.Route):
+
+    mux.GET("/users/:id", showUser).Name("user.show").Tag("users").
+        Summary("Show a user").Returns(200, User{}).Returns(404, errors.Error{})
+    mux.POST("/users", createUser).Name("user.create").Tag("users").
+        Accepts(NewUser{}).Returns(201, User{})
+
+Then serve the document from the mux itself:
+
+    info := openapi.Info{Title: "Users API", Version: "1.0.0"}
+    mux.GET("/openapi.json", openapi.Handler(mux, info)).Tag(openapi.Hidden)
+
+Request and response types are described by their JSON encoding, and named
+struct types are added to the document's components so they can be reused.
+*/
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/reflexionhealth/vanilla/httpx"
+)
+
+// Hidden is a route tag which leaves the route out of the document.
+const Hidden = "openapi:hidden"
+
+// Info is the metadata of the API.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// A Document is an OpenAPI 3 document.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components *Components         `json:"components,omitempty"`
+}
+
+// A PathItem describes the operations for a path, keyed by lowercase method.
+type PathItem map[string]*Operation
+
+// An Operation describes a route.
+type Operation struct {
+	OperationID string              `json:"operationId,omitempty"`
+	Summary     string              `json:"summary,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// A Parameter describes a path parameter.
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+// A RequestBody describes the body of a request.
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// A Response describes a response for a status code.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// A MediaType describes a body with a content type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components are the schemas which are referenced by the document.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Version is the version of OpenAPI generated.
+const Version = "3.0.3"
+
+// Generate returns a document describing the routes of the mux.  Routes
+// tagged Hidden are left out, and the Hidden tag isn't included in the
+// operation's tags.
+func Generate(mux *httpx.Mux, info Info) *Document {
+	doc := &Document{OpenAPI: Version, Info: info, Paths: make(map[string]PathItem)}
+	schemas := newSchemaSet()
+
+	for _, rt := range mux.Routes() {
+		if rt.HasTag(Hidden) {
+			continue
+		}
+
+		path := pathTemplate(rt.Path)
+		item, exists := doc.Paths[path]
+		if !exists {
+			item = make(PathItem)
+			doc.Paths[path] = item
+		}
+		method := strings.ToLower(rt.Method)
+		if _, exists := item[method]; exists {
+			continue // the same path and method on another host
+		}
+		item[method] = operation(rt, schemas)
+	}
+
+	if len(schemas.components) > 0 {
+		doc.Components = &Components{Schemas: schemas.components}
+	}
+	return doc
+}
+
+func operation(rt *httpx.Route, schemas *schemaSet) *Operation {
+	op := &Operation{
+		OperationID: rt.GetName(),
+		Summary:     rt.GetSummary(),
+		Responses:   make(map[string]Response),
+	}
+	for _, tag := range rt.GetTags() {
+		if tag != Hidden {
+			op.Tags = append(op.Tags, tag)
+		}
+	}
+	for _, name := range rt.Params() {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+
+	if typ := rt.GetRequestType(); typ != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {schemas.schemaFor(typ)}},
+		}
+	}
+
+	responses := rt.GetResponseTypes()
+	if len(responses) == 0 {
+		op.Responses["200"] = Response{Description: http.StatusText(http.StatusOK)}
+	}
+	statuses := make([]int, 0, len(responses))
+	for status := range responses {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses) // so that components are named in a stable order
+	for _, status := range statuses {
+		response := Response{Description: http.StatusText(status)}
+		if typ := responses[status]; typ != nil {
+			response.Content = map[string]MediaType{"application/json": {schemas.schemaFor(typ)}}
+		}
+		op.Responses[strconv.Itoa(status)] = response
+	}
+	return op
+}
+
+// pathTemplate converts a route's path to an OpenAPI path template, eg.
+// "/users/:id" to "/users/{id}"
+func pathTemplate(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if len(segment) > 1 && (segment[0] == ':' || segment[0] == '*') {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// Handler returns a Handler which serves the document for the mux as JSON.
+// The document is generated for each request, so it includes routes which
+// are added after the handler is created.
+func Handler(mux *httpx.Mux, info Info) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		body, err := json.Marshal(Generate(mux, info))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write(body)
+	}
+}
@@ -0,0 +1,135 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/expect"
+	"github.com/reflexionhealth/vanilla/httpx"
+	"github.com/reflexionhealth/vanilla/null"
+	"github.com/reflexionhealth/vanilla/uuid"
+)
+
+type Model struct {
+	ID      uuid.UUID `json:"id"`
+	Created time.Time `json:"created"`
+}
+
+type User struct {
+	Model
+	Name    string      `json:"name"`
+	Email   null.String `json:"email"`
+	Friends []*User     `json:"friends,omitempty"`
+	Age     int         `json:"age,string"`
+	secret  string
+	Ignored string `json:"-"`
+}
+
+type NewUser struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels,omitempty"`
+	Avatar *[]byte           `json:"avatar"`
+}
+
+func handler(w http.ResponseWriter, r *http.Request) {}
+
+func TestGenerate(t *testing.T) {
+	mux := httpx.NewMux()
+	mux.GET("/users/:id", handler).Name("user.show").Tag("users").
+		Summary("Show a user").Returns(200, User{}).Returns(404, nil)
+	mux.POST("/users", handler).Name("user.create").Tag("users").
+		Accepts(NewUser{}).Returns(201, &User{})
+	mux.GET("/files/*filepath", handler)
+	mux.GET("/openapi.json", Handler(mux, Info{})).Tag(Hidden)
+
+	doc := Generate(mux, Info{Title: "Muppets", Version: "1.0.0"})
+	encoded, err := json.MarshalIndent(doc, "", "  ")
+	expect.NoError(t, err)
+
+	var actual, expected interface{}
+	json.Unmarshal(encoded, &actual)
+	expect.NoError(t, json.Unmarshal([]byte(expectedDocument), &expected))
+	expect.Equal(t, actual, expected, "generated document:\n%s", encoded)
+}
+
+const expectedDocument = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Muppets", "version": "1.0.0"},
+  "paths": {
+    "/users/{id}": {
+      "get": {
+        "operationId": "user.show",
+        "summary": "Show a user",
+        "tags": ["users"],
+        "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+        "responses": {
+          "200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}},
+          "404": {"description": "Not Found"}
+        }
+      }
+    },
+    "/users": {
+      "post": {
+        "operationId": "user.create",
+        "tags": ["users"],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/NewUser"}}}
+        },
+        "responses": {
+          "201": {"description": "Created", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}}
+        }
+      }
+    },
+    "/files/{filepath}": {
+      "get": {
+        "parameters": [{"name": "filepath", "in": "path", "required": true, "schema": {"type": "string"}}],
+        "responses": {"200": {"description": "OK"}}
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "User": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "string", "format": "uuid"},
+          "created": {"type": "string", "format": "date-time"},
+          "name": {"type": "string"},
+          "email": {"type": "string", "nullable": true},
+          "friends": {"type": "array", "items": {"$ref": "#/components/schemas/User"}},
+          "age": {"type": "string"}
+        },
+        "required": ["id", "created", "name", "email", "age"]
+      },
+      "NewUser": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string"},
+          "labels": {"type": "object", "additionalProperties": {"type": "string"}},
+          "avatar": {"type": "string", "format": "byte", "nullable": true}
+        },
+        "required": ["name"]
+      }
+    }
+  }
+}`
+
+func TestHandler(t *testing.T) {
+	mux := httpx.NewMux()
+	mux.GET("/openapi.json", Handler(mux, Info{Title: "Muppets", Version: "1"})).Tag(Hidden)
+	mux.DELETE("/users/:id", handler).Name("user.delete")
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/openapi.json", nil)
+	mux.ServeHTTP(w, r)
+	expect.Equal(t, w.Code, 200)
+	expect.Equal(t, w.Header().Get("Content-Type"), "application/json; charset=utf-8")
+	expect.True(t, bytes.Contains(w.Body.Bytes(), []byte(`"/users/{id}":{"delete":{"operationId":"user.delete"`)),
+		"expected routes added after the handler in %s", w.Body.String())
+	expect.False(t, bytes.Contains(w.Body.Bytes(), []byte(`openapi.json`)), "expected the hidden route to be left out")
+}
@@ -0,0 +1,194 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/date"
+	"github.com/reflexionhealth/vanilla/null"
+	"github.com/reflexionhealth/vanilla/uuid"
+)
+
+// A Schema describes a JSON value.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+// knownSchemas are the schemas of types with custom JSON encodings
+var knownSchemas = map[reflect.Type]Schema{
+	reflect.TypeOf(time.Time{}):       {Type: "string", Format: "date-time"},
+	reflect.TypeOf(time.Duration(0)):  {Type: "integer", Format: "int64"},
+	reflect.TypeOf(date.Date{}):       {Type: "string", Format: "date"},
+	reflect.TypeOf(uuid.UUID{}):       {Type: "string", Format: "uuid"},
+	reflect.TypeOf(json.RawMessage{}): {},
+
+	reflect.TypeOf(null.Bool{}):   {Type: "boolean", Nullable: true},
+	reflect.TypeOf(null.String{}): {Type: "string", Nullable: true},
+	reflect.TypeOf(null.Float{}):  {Type: "number", Format: "double", Nullable: true},
+	reflect.TypeOf(null.Int{}):    {Type: "integer", Nullable: true},
+	reflect.TypeOf(null.Int64{}):  {Type: "integer", Format: "int64", Nullable: true},
+	reflect.TypeOf(null.Int32{}):  {Type: "integer", Format: "int32", Nullable: true},
+	reflect.TypeOf(null.Uint{}):   {Type: "integer", Nullable: true},
+	reflect.TypeOf(null.Bytes{}):  {Type: "string", Format: "byte", Nullable: true},
+	reflect.TypeOf(null.JSON{}):   {Nullable: true},
+	reflect.TypeOf(null.Time{}):   {Type: "string", Format: "date-time", Nullable: true},
+	reflect.TypeOf(null.Date{}):   {Type: "string", Format: "date", Nullable: true},
+	reflect.TypeOf(null.UUID{}):   {Type: "string", Format: "uuid", Nullable: true},
+}
+
+var (
+	jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// schemaSet builds the schemas for a document, keeping named struct types in
+// its components
+type schemaSet struct {
+	components map[string]*Schema
+	names      map[reflect.Type]string
+}
+
+func newSchemaSet() *schemaSet {
+	return &schemaSet{components: make(map[string]*Schema), names: make(map[reflect.Type]string)}
+}
+
+func (s *schemaSet) schemaFor(typ reflect.Type) *Schema {
+	nullable := false
+	for typ.Kind() == reflect.Ptr {
+		typ, nullable = typ.Elem(), true
+	}
+
+	schema := s.inlineSchema(typ)
+	if nullable && len(schema.Ref) == 0 {
+		schema.Nullable = true
+	}
+	return schema
+}
+
+func (s *schemaSet) inlineSchema(typ reflect.Type) *Schema {
+	if known, ok := knownSchemas[typ]; ok {
+		return &known
+	}
+	if typ.Implements(jsonMarshaler) || reflect.PtrTo(typ).Implements(jsonMarshaler) {
+		return &Schema{} // the schema can't be known from the type
+	}
+	if typ.Implements(textMarshaler) || reflect.PtrTo(typ).Implements(textMarshaler) {
+		return &Schema{Type: "string"}
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uintptr:
+		return &Schema{Type: "integer"}
+	case reflect.Int32, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 && typ.Kind() == reflect.Slice {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.schemaFor(typ.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.schemaFor(typ.Elem())}
+	case reflect.Struct:
+		if len(typ.Name()) == 0 {
+			return s.structSchema(typ)
+		}
+		return &Schema{Ref: "#/components/schemas/" + s.component(typ)}
+	default:
+		return &Schema{} // interfaces can hold any value
+	}
+}
+
+// component adds a named struct type to the components, returning its name
+func (s *schemaSet) component(typ reflect.Type) string {
+	if name, exists := s.names[typ]; exists {
+		return name
+	}
+
+	// qualify the name with the package if another type has the same name
+	name := typ.Name()
+	if _, taken := s.components[name]; taken {
+		pkg := typ.PkgPath()
+		name = pkg[strings.LastIndexByte(pkg, '/')+1:] + "." + name
+	}
+
+	s.names[typ] = name
+	s.components[name] = &Schema{} // reserved, in case the type is recursive
+	s.components[name] = s.structSchema(typ)
+	return name
+}
+
+func (s *schemaSet) structSchema(typ reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	s.addFields(schema, typ)
+	return schema
+}
+
+// addFields adds the fields of a struct to a schema, including the fields
+// of embedded structs, following the rules of encoding/json
+func (s *schemaSet) addFields(schema *Schema, typ reflect.Type) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		fieldType := field.Type
+		if field.Anonymous && len(name) == 0 {
+			for fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				s.addFields(schema, fieldType)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if len(name) == 0 {
+			name = field.Name
+		}
+
+		var property *Schema
+		if hasOption(opts, "string") {
+			property = &Schema{Type: "string"}
+		} else {
+			property = s.schemaFor(field.Type)
+		}
+		schema.Properties[name] = property
+		if !hasOption(opts, "omitempty") && field.Type.Kind() != reflect.Ptr {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+func hasOption(opts, option string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
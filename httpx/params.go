@@ -1,6 +1,15 @@
 package httpx
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/reflexionhealth/vanilla/date"
+	"github.com/reflexionhealth/vanilla/uuid"
+)
 
 type ctxKey int            // ctxKey is an unexported type for net/context keys.
 const paramsKey ctxKey = 0 // paramsKey is the context key for path params.
@@ -27,6 +36,119 @@ func (ps Params) ByName(name string) string {
 	return ""
 }
 
+// Int returns the value of the named Param parsed as a base 10 int,
+// or a *ParamError if it is missing or invalid.
+func (ps Params) Int(name string) (int, error) {
+	value, err := ps.lookup(name, "integer")
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, &ParamError{name, value, "integer", err}
+	}
+	return n, nil
+}
+
+// Int64 is like Int, but returns an int64.
+func (ps Params) Int64(name string) (int64, error) {
+	value, err := ps.lookup(name, "integer")
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, &ParamError{name, value, "integer", err}
+	}
+	return n, nil
+}
+
+// UUID returns the value of the named Param parsed as a UUID,
+// or a *ParamError if it is missing or invalid.
+func (ps Params) UUID(name string) (uuid.UUID, error) {
+	value, err := ps.lookup(name, "uuid")
+	if err != nil {
+		return uuid.Nil, err
+	}
+	u, err := uuid.FromString(value)
+	if err != nil {
+		return uuid.Nil, &ParamError{name, value, "uuid", err}
+	}
+	return u, nil
+}
+
+// Date returns the value of the named Param parsed as a date like
+// "2006-01-02", or a *ParamError if it is missing or invalid.
+func (ps Params) Date(name string) (date.Date, error) {
+	value, err := ps.lookup(name, "date")
+	if err != nil {
+		return date.Date{}, err
+	}
+	d, err := date.ParseStrict(date.RFC3339, value)
+	if err != nil {
+		return date.Date{}, &ParamError{name, value, "date", err}
+	}
+	return d, nil
+}
+
+func (ps Params) lookup(name, kind string) (string, error) {
+	for i := range ps {
+		if ps[i].Key == name {
+			return ps[i].Value, nil
+		}
+	}
+	return "", &ParamError{name, "", kind, errMissingParam}
+}
+
+var errMissingParam = errors.New("missing param")
+
+// A ParamError is returned by the typed Params methods when a param is
+// missing or can't be parsed.  Err is the underlying parse error.
+type ParamError struct {
+	Key   string
+	Value string
+	Type  string
+	Err   error
+}
+
+func (e *ParamError) Error() string {
+	return "httpx: " + e.describe()
+}
+
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ParamError) describe() string {
+	if e.Err == errMissingParam {
+		return fmt.Sprintf("missing path param '%s'", e.Key)
+	}
+	return fmt.Sprintf("path param '%s' must be a valid %s, but was %q", e.Key, e.Type, e.Value)
+}
+
+// BadParam writes a 400 Bad Request response if err is not nil, and reports
+// whether it did, so that handlers can return early:
+//
+//     id, err := httpx.GetParams(ctx).Int("id")
+//     if httpx.BadParam(w, err) {
+//         return
+//     }
+//
+// If err is a *ParamError the response explains which param was invalid.
+func BadParam(w http.ResponseWriter, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := http.StatusText(http.StatusBadRequest)
+	var paramErr *ParamError
+	if errors.As(err, &paramErr) {
+		msg += ": " + paramErr.describe()
+	}
+	http.Error(w, msg, http.StatusBadRequest)
+	return true
+}
+
 // Put returns a new Context carrying ps.
 func (ps Params) Put(ctx context.Context) context.Context {
 	return context.WithValue(ctx, paramsKey, ps)
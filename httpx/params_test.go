@@ -1,6 +1,11 @@
 package httpx
 
-import "testing"
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
 
 func TestParams(t *testing.T) {
 	ps := Params{
@@ -17,3 +22,69 @@ func TestParams(t *testing.T) {
 		t.Errorf("Expected empty string for not found key; got: %s", val)
 	}
 }
+
+func TestTypedParams(t *testing.T) {
+	ps := Params{
+		Param{"id", "42"},
+		Param{"uuid", "6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+		Param{"day", "2016-02-29"},
+		Param{"name", "kermit"},
+	}
+
+	if n, err := ps.Int("id"); n != 42 || err != nil {
+		t.Errorf("Int(id): Got %d, %v; Want 42, nil", n, err)
+	}
+	if n, err := ps.Int64("id"); n != 42 || err != nil {
+		t.Errorf("Int64(id): Got %d, %v; Want 42, nil", n, err)
+	}
+	if u, err := ps.UUID("uuid"); u.String() != "6ba7b810-9dad-11d1-80b4-00c04fd430c8" || err != nil {
+		t.Errorf("UUID(uuid): Got %s, %v; Want 6ba7b810-9dad-11d1-80b4-00c04fd430c8, nil", u, err)
+	}
+	if d, err := ps.Date("day"); d.String() != "2016-02-29" || err != nil {
+		t.Errorf("Date(day): Got %v, %v; Want 2016-02-29, nil", d, err)
+	}
+
+	testErrors := []struct {
+		name  string
+		parse func(string) error
+		key   string
+		error string
+	}{
+		{"Int", func(k string) error { _, err := ps.Int(k); return err }, "name",
+			`httpx: path param 'name' must be a valid integer, but was "kermit"`},
+		{"Int", func(k string) error { _, err := ps.Int(k); return err }, "missing",
+			`httpx: missing path param 'missing'`},
+		{"Int64", func(k string) error { _, err := ps.Int64(k); return err }, "day",
+			`httpx: path param 'day' must be a valid integer, but was "2016-02-29"`},
+		{"UUID", func(k string) error { _, err := ps.UUID(k); return err }, "id",
+			`httpx: path param 'id' must be a valid uuid, but was "42"`},
+		{"Date", func(k string) error { _, err := ps.Date(k); return err }, "id",
+			`httpx: path param 'id' must be a valid date, but was "42"`},
+	}
+	for _, te := range testErrors {
+		err := te.parse(te.key)
+		var paramErr *ParamError
+		if err == nil || err.Error() != te.error || !errors.As(err, &paramErr) || paramErr.Key != te.key {
+			t.Errorf("%s(%s): Got error %v; Want %s", te.name, te.key, err, te.error)
+		}
+	}
+}
+
+func TestBadParam(t *testing.T) {
+	w := httptest.NewRecorder()
+	if BadParam(w, nil) || w.Code != http.StatusOK {
+		t.Errorf("BadParam with a nil error wrote a response: Code=%d", w.Code)
+	}
+
+	_, err := Params{Param{"id", "kermit"}}.Int("id")
+	w = httptest.NewRecorder()
+	want := "Bad Request: path param 'id' must be a valid integer, but was \"kermit\"\n"
+	if !BadParam(w, err) || w.Code != http.StatusBadRequest || w.Body.String() != want {
+		t.Errorf("BadParam failed: Code=%d, Body=%q; Want %d, %q", w.Code, w.Body.String(), http.StatusBadRequest, want)
+	}
+
+	w = httptest.NewRecorder()
+	if !BadParam(w, errors.New("secret")) || w.Code != http.StatusBadRequest || w.Body.String() != "Bad Request\n" {
+		t.Errorf("BadParam failed: Code=%d, Body=%q; Want %d, %q", w.Code, w.Body.String(), http.StatusBadRequest, "Bad Request\n")
+	}
+}
@@ -0,0 +1,131 @@
+package httpx
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"runtime/debug"
+	"strings"
+
+	"github.com/reflexionhealth/vanilla/httpx/errors"
+)
+
+// An ErrorRenderer writes error responses as JSON, using RFC 7807 problem
+// details (application/problem+json) if the client accepts them.
+//
+// The error is added to the request's log (see Logger) as "error", with its
+// Meta.Reason as "error_reason", and the response includes the request's ID
+// (see RequestID) if it has one.
+type ErrorRenderer struct {
+	// Problem writes problem details even if the client doesn't ask for them
+	Problem bool
+}
+
+// DefaultErrorRenderer is the ErrorRenderer used by WriteError.
+var DefaultErrorRenderer = &ErrorRenderer{}
+
+// WriteError writes an error response with the DefaultErrorRenderer.  If err
+// isn't an *errors.Error it is written as an errors.InternalError, so its
+// message is logged but isn't sent to the client:
+//
+//     if err := httpx.BindJSON(req, &user); err != nil {
+//         httpx.WriteError(w, req, err)
+//         return
+//     }
+//
+func WriteError(w http.ResponseWriter, req *http.Request, err error) {
+	DefaultErrorRenderer.Render(w, req, err)
+}
+
+// AbortWithError writes an error response with the status for err (see
+// errors.WithStatus) with the DefaultErrorRenderer.
+//
+//     if user == nil {
+//         httpx.AbortWithError(w, req, 404, fmt.Errorf("there is no user '%s'", id))
+//         return
+//     }
+//
+func AbortWithError(w http.ResponseWriter, req *http.Request, status int, err error) {
+	DefaultErrorRenderer.Render(w, req, errors.WithStatus(status, err))
+}
+
+// Render writes the error response for err.
+func (er *ErrorRenderer) Render(w http.ResponseWriter, req *http.Request, err error) {
+	httpErr := *errors.From(err) // copied, so the RequestID can be set
+	if httpErr.HTTPStatus == 0 {
+		httpErr.HTTPStatus = http.StatusInternalServerError
+	}
+	if len(httpErr.RequestID) == 0 {
+		httpErr.RequestID = GetRequestID(req.Context())
+	}
+
+	reqLog := GetRequestLog(req.Context())
+	if httpErr.Meta.Error != nil {
+		reqLog.Set("error", httpErr.Meta.Error.Error())
+	} else {
+		reqLog.Set("error", httpErr.Error())
+	}
+	if len(httpErr.Meta.Reason) > 0 {
+		reqLog.Set("error_reason", httpErr.Meta.Reason)
+	}
+
+	var body interface{} = &httpErr
+	contentType := "application/json; charset=utf-8"
+	if er.Problem || acceptsProblem(req) {
+		problem := httpErr.Problem()
+		problem.Instance = req.URL.Path
+		body, contentType = problem, errors.ProblemContentType
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(httpErr.HTTPStatus)
+	json.NewEncoder(w).Encode(body)
+}
+
+// acceptsProblem returns true if the request's Accept header lists problem
+// details
+func acceptsProblem(req *http.Request) bool {
+	for _, accept := range strings.Split(req.Header.Get("Accept"), ",") {
+		typ, _, err := mime.ParseMediaType(accept)
+		if err == nil && typ == errors.ProblemContentType {
+			return true
+		}
+	}
+	return false
+}
+
+// Recover is middleware which recovers from panics in later handlers,
+// responding with a 500 Internal Server Error (see RenderPanic).  It should
+// be used after a Logger, so that the panic is logged:
+//
+//     mux.Use(logger.Handler, httpx.Recover)
+//
+func Recover(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		defer func() {
+			if rcv := recover(); rcv != nil {
+				if rcv == http.ErrAbortHandler {
+					panic(rcv) // the handler is deliberately aborting the response
+				}
+				RenderPanic(w, req, rcv)
+			}
+		}()
+		h.ServeHTTP(w, req)
+	})
+}
+
+// RenderPanic writes an errors.InternalError with the DefaultErrorRenderer
+// for a recovered panic, with the stack trace in its Meta.Trace.  It can be
+// used as a Mux's PanicHandler.
+func RenderPanic(w http.ResponseWriter, req *http.Request, rcv interface{}) {
+	err, isError := rcv.(error)
+	if !isError {
+		err = fmt.Errorf("%v", rcv)
+	}
+
+	internal := errors.InternalError(fmt.Errorf("panic: %w", err))
+	internal.Meta.Trace = strings.Split(strings.TrimSpace(string(debug.Stack())), "\n")
+	WriteError(w, req, internal)
+}
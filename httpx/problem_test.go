@@ -0,0 +1,117 @@
+package httpx
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/reflexionhealth/vanilla/httpx/errors"
+)
+
+func TestWriteError(t *testing.T) {
+	invalid := errors.InvalidRequest("the request has invalid fields")
+	invalid.Fields = []errors.FieldError{{Field: "name", Message: "field 'name' is required"}}
+	invalid.Meta.Reason = "invalid_user"
+
+	testErrors := []struct {
+		err         error
+		accept      string
+		code        int
+		contentType string
+		body        string
+		log         string
+	}{
+		{invalid, "", 422, "application/json; charset=utf-8",
+			`{"user_message":"","debug_message":"the request has invalid fields","request_id":"req-1","fields":[{"field":"name","message":"field 'name' is required"}]}`,
+			`error="Unprocessable Entity - the request has invalid fields" error_reason=invalid_user`},
+		{invalid, "application/json, application/problem+json; q=0.9", 422, "application/problem+json",
+			`{"type":"about:blank","title":"Unprocessable Entity","status":422,"instance":"/users","debug_message":"the request has invalid fields","request_id":"req-1","fields":[{"field":"name","message":"field 'name' is required"}]}`,
+			`error="Unprocessable Entity - the request has invalid fields" error_reason=invalid_user`},
+		{fmt.Errorf("db: connection refused"), "application/problem+json", 500, "application/problem+json",
+			`{"type":"about:blank","title":"Internal Server Error","status":500,"instance":"/users","request_id":"req-1"}`,
+			`error="db: connection refused"`},
+		{fmt.Errorf("creating user: %w", errors.Forbidden("not_admin", "You can't do that")), "", 403, "application/json; charset=utf-8",
+			`{"user_message":"You can't do that","request_id":"req-1"}`,
+			`error="Forbidden - " error_reason=not_admin`},
+	}
+	for _, te := range testErrors {
+		var out bytes.Buffer
+		logger := &Logger{Output: &out}
+		handler := logger.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			WriteError(w, r, te.err)
+		}))
+
+		r, _ := http.NewRequest("POST", "/users", nil)
+		r.Header.Set(RequestIDHeader, "req-1")
+		r.Header.Set("Accept", te.accept)
+		w := httptest.NewRecorder()
+		RequestID(handler).ServeHTTP(w, r)
+
+		body := strings.TrimSpace(w.Body.String())
+		if w.Code != te.code || w.Header().Get("Content-Type") != te.contentType || body != te.body {
+			t.Errorf("Wrong response for %v (Accept %q): Code=%d, Content-Type=%q, Body=%s; Want %d, %q, %s",
+				te.err, te.accept, w.Code, w.Header().Get("Content-Type"), body, te.code, te.contentType, te.body)
+		}
+		if !strings.HasSuffix(strings.TrimSpace(out.String()), te.log) {
+			t.Errorf("Wrong log for %v: Got %q; Want suffix %q", te.err, out.String(), te.log)
+		}
+	}
+
+	if invalid.RequestID != "" {
+		t.Errorf("Expected WriteError not to change the error, but RequestID=%q", invalid.RequestID)
+	}
+}
+
+func TestAbortWithError(t *testing.T) {
+	renderer := &ErrorRenderer{Problem: true}
+	r, _ := http.NewRequest("GET", "/users/3", nil)
+	w := httptest.NewRecorder()
+	renderer.Render(w, r, errors.WithStatus(http.StatusNotFound, fmt.Errorf("there is no user '3'")))
+
+	want := `{"type":"about:blank","title":"Not Found","status":404,"instance":"/users/3","debug_message":"there is no user '3'"}`
+	if w.Code != http.StatusNotFound || strings.TrimSpace(w.Body.String()) != want {
+		t.Errorf("Wrong response: Code=%d, Body=%s; Want 404, %s", w.Code, w.Body.String(), want)
+	}
+
+	w = httptest.NewRecorder()
+	AbortWithError(w, r, http.StatusConflict, errors.NotFound("missing"))
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), `"debug_message":"missing"`) {
+		t.Errorf("Wrong response for an *errors.Error: Code=%d, Body=%s", w.Code, w.Body.String())
+	}
+}
+
+func TestRecover(t *testing.T) {
+	var out bytes.Buffer
+	logger := &Logger{Output: &out}
+	router := NewMux()
+	router.Use(logger.Handler, Recover)
+	router.GET("/panic", func(w http.ResponseWriter, r *http.Request) {
+		panic("oops")
+	})
+	router.GET("/abort", func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+
+	r, _ := http.NewRequest("GET", "/panic", nil)
+	r.Header.Set("Accept", errors.ProblemContentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	want := `{"type":"about:blank","title":"Internal Server Error","status":500,"instance":"/panic"}`
+	if w.Code != http.StatusInternalServerError || strings.TrimSpace(w.Body.String()) != want {
+		t.Errorf("Wrong response for a panic: Code=%d, Body=%s; Want 500, %s", w.Code, w.Body.String(), want)
+	}
+	if !strings.Contains(out.String(), ` 500 `) || !strings.Contains(out.String(), `error="panic: oops"`) {
+		t.Errorf("Wrong log for a panic: %q", out.String())
+	}
+
+	defer func() {
+		if rcv := recover(); rcv != http.ErrAbortHandler {
+			t.Errorf("Expected http.ErrAbortHandler to be re-panicked, but got %v", rcv)
+		}
+	}()
+	r, _ = http.NewRequest("GET", "/abort", nil)
+	router.ServeHTTP(httptest.NewRecorder(), r)
+}
@@ -0,0 +1,98 @@
+package httpx
+
+import (
+	"net/http"
+	"strings"
+)
+
+// An Asset is a resource needed by a page, which the client can be told to
+// fetch early with Preload or Push.
+type Asset struct {
+	Path string // the path (or URL) of the resource, eg. "/static/app.css"
+	As   string // the kind of resource, eg. "style", "script", "font", or "image"
+
+	// CrossOrigin must be set for fonts, and for resources which are fetched
+	// with CORS, or the preloaded response won't be used
+	CrossOrigin bool
+}
+
+// link returns the asset as a Link header value
+func (a Asset) link(nopush bool) string {
+	var b strings.Builder
+	b.WriteString("<" + a.Path + ">; rel=preload")
+	if len(a.As) > 0 {
+		b.WriteString("; as=" + a.As)
+	}
+	if a.CrossOrigin {
+		b.WriteString("; crossorigin")
+	}
+	if nopush {
+		b.WriteString("; nopush")
+	}
+	return b.String()
+}
+
+// Preload adds a "Link: <path>; rel=preload" header for each asset, so that
+// the client can start fetching them before it has parsed the response.  It
+// must be called before the response's header is written.
+func Preload(w http.ResponseWriter, assets ...Asset) {
+	for _, asset := range assets {
+		w.Header().Add("Link", asset.link(false))
+	}
+}
+
+// Push pushes the assets to the client with HTTP/2 server push, if the
+// connection supports it, and adds a preload Link header for each asset (see
+// Preload) so that clients without push support still fetch them early.  It
+// should be called before writing the response:
+//
+//     httpx.Push(w, req,
+//         httpx.Asset{Path: "/static/app.css", As: "style"},
+//         httpx.Asset{Path: "/static/app.js", As: "script"},
+//     )
+//
+// Push degrades silently: on HTTP/1.1 the assets are only preloaded, and an
+// asset which can't be pushed (eg. because the client disabled push) is
+// only preloaded.  Only same-origin paths are pushed, and only in response to
+// GET requests.
+func Push(w http.ResponseWriter, req *http.Request, assets ...Asset) {
+	pusher := findPusher(w)
+	for _, asset := range assets {
+		pushed := false
+		if pusher != nil && req.Method == "GET" && strings.HasPrefix(asset.Path, "/") && !strings.HasPrefix(asset.Path, "//") {
+			pushed = pusher.Push(asset.Path, &http.PushOptions{Header: pushHeader(req)}) == nil
+		}
+
+		// the nopush hint keeps proxies from pushing the asset again
+		w.Header().Add("Link", asset.link(pushed))
+	}
+}
+
+// pushHeader returns the headers for a pushed request, copied from the
+// request that triggered it so that the pushed response can be compressed
+// and authorized in the same way
+func pushHeader(req *http.Request) http.Header {
+	header := make(http.Header)
+	for _, name := range []string{"Accept-Encoding", "Accept-Language", "Cookie", "User-Agent"} {
+		if values := req.Header.Values(name); len(values) > 0 {
+			header[name] = values
+		}
+	}
+	return header
+}
+
+// findPusher returns the http.Pusher of the ResponseWriter, or of the writers
+// it wraps (see http.ResponseController), or nil if there isn't one
+func findPusher(w http.ResponseWriter) http.Pusher {
+	for w != nil {
+		if pusher, ok := w.(http.Pusher); ok {
+			return pusher
+		}
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return nil
+		}
+		w = unwrapper.Unwrap()
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// pushRecorder is a ResponseWriter for an HTTP/2 connection
+type pushRecorder struct {
+	*httptest.ResponseRecorder
+	pushed  []string
+	headers []http.Header
+	err     error
+}
+
+func (rec *pushRecorder) Push(target string, opts *http.PushOptions) error {
+	if rec.err != nil {
+		return rec.err
+	}
+	rec.pushed = append(rec.pushed, target)
+	rec.headers = append(rec.headers, opts.Header)
+	return nil
+}
+
+var testAssets = []Asset{
+	{Path: "/static/app.css", As: "style"},
+	{Path: "/static/font.woff2", As: "font", CrossOrigin: true},
+	{Path: "https://cdn.example.com/lib.js", As: "script"},
+}
+
+func TestPreload(t *testing.T) {
+	w := httptest.NewRecorder()
+	Preload(w, testAssets...)
+
+	want := []string{
+		"</static/app.css>; rel=preload; as=style",
+		"</static/font.woff2>; rel=preload; as=font; crossorigin",
+		"<https://cdn.example.com/lib.js>; rel=preload; as=script",
+	}
+	if links := w.Header().Values("Link"); !reflect.DeepEqual(links, want) {
+		t.Errorf("Wrong Link headers: Got %q; Want %q", links, want)
+	}
+}
+
+func TestPush(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	r.Header.Set("Authorization", "Bearer secret")
+
+	// the pusher is found through middleware which wraps the ResponseWriter
+	rec := &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	Push(&statusRecorder{ResponseWriter: rec}, r, testAssets...)
+
+	if want := []string{"/static/app.css", "/static/font.woff2"}; !reflect.DeepEqual(rec.pushed, want) {
+		t.Errorf("Wrong assets pushed: Got %q; Want %q", rec.pushed, want)
+	}
+	if header := rec.headers[0]; header.Get("Accept-Encoding") != "gzip" || len(header.Get("Authorization")) > 0 {
+		t.Errorf("Wrong headers for pushed request: %v", header)
+	}
+	want := []string{
+		"</static/app.css>; rel=preload; as=style; nopush",
+		"</static/font.woff2>; rel=preload; as=font; crossorigin; nopush",
+		"<https://cdn.example.com/lib.js>; rel=preload; as=script",
+	}
+	if links := rec.Header().Values("Link"); !reflect.DeepEqual(links, want) {
+		t.Errorf("Wrong Link headers: Got %q; Want %q", links, want)
+	}
+}
+
+func TestPushUnsupported(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/", nil)
+	want := []string{"</static/app.css>; rel=preload; as=style"}
+
+	// HTTP/1.1
+	w := httptest.NewRecorder()
+	Push(w, r, testAssets[0])
+	if links := w.Header().Values("Link"); !reflect.DeepEqual(links, want) {
+		t.Errorf("Wrong Link headers without push: Got %q; Want %q", links, want)
+	}
+
+	// push disabled by the client
+	rec := &pushRecorder{ResponseRecorder: httptest.NewRecorder(), err: errors.New("push disabled")}
+	Push(rec, r, testAssets[0])
+	if links := rec.Header().Values("Link"); !reflect.DeepEqual(links, want) {
+		t.Errorf("Wrong Link headers when push fails: Got %q; Want %q", links, want)
+	}
+
+	// not a GET request
+	rec = &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	r, _ = http.NewRequest("POST", "/", nil)
+	Push(rec, r, testAssets[0])
+	if len(rec.pushed) > 0 {
+		t.Errorf("Expected no assets to be pushed for a POST, but pushed %q", rec.pushed)
+	}
+}
@@ -0,0 +1,118 @@
+package httpx
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/clock"
+)
+
+// A RateLimit is middleware which limits how often each client can make
+// requests, using a token bucket for each client.  A client can make Burst
+// requests at once, and then one request each Every.  Requests over the limit
+// receive a 429 Too Many Requests response with a Retry-After header.
+//
+//     limit := &httpx.RateLimit{Every: time.Second, Burst: 10}
+//     mux.Use(limit.Handler)
+//
+type RateLimit struct {
+	Every time.Duration // how often a client is given another request
+	Burst int           // the most requests a client can save up
+
+	// Key returns the client which made a request, or the client's address
+	// (without the port) if it is nil.  Requests for an empty key aren't limited.
+	Key func(req *http.Request) string
+
+	// Clock is the clock used to refill the buckets, or the clock in the
+	// request context (see clock.FromContext) if it is nil
+	Clock *clock.Source
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Handler returns a Handler which rate limits the requests handled by h.
+func (l *RateLimit) Handler(h http.Handler) http.Handler {
+	if l.Every <= 0 || l.Burst <= 0 {
+		panic("httpx: a RateLimit requires a positive Every and Burst")
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		key := clientIP(req)
+		if l.Key != nil {
+			key = l.Key(req)
+		}
+
+		src := l.Clock
+		if src == nil {
+			src = clock.FromContext(req.Context())
+		}
+
+		if wait := l.take(key, src.UTC()); wait > 0 {
+			retryAfter := int64(math.Ceil(wait.Seconds()))
+			w.Header().Set("Retry-After", strconv.FormatInt(retryAfter, 10))
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+		h.ServeHTTP(w, req)
+	})
+}
+
+// take removes a token from the key's bucket, or returns how long to wait
+// until there will be a token
+func (l *RateLimit) take(key string, now time.Time) time.Duration {
+	if len(key) == 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+	bucket, exists := l.buckets[key]
+	if !exists {
+		bucket = &tokenBucket{tokens: float64(l.Burst), last: now}
+		l.buckets[key] = bucket
+	}
+	bucket.refill(now, l.Every, l.Burst)
+
+	if bucket.tokens < 1 {
+		return time.Duration((1 - bucket.tokens) * float64(l.Every))
+	}
+	bucket.tokens--
+	return 0
+}
+
+// sweep forgets the buckets which would be full by now, so the limiter
+// doesn't keep a bucket for every client it has ever seen
+func (l *RateLimit) sweep(now time.Time) {
+	fillTime := l.Every * time.Duration(l.Burst)
+	if l.buckets == nil {
+		l.buckets = make(map[string]*tokenBucket)
+	} else if now.Sub(l.lastSweep) < fillTime {
+		return
+	}
+
+	for key, bucket := range l.buckets {
+		if now.Sub(bucket.last) >= fillTime {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+func (b *tokenBucket) refill(now time.Time, every time.Duration, burst int) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(float64(burst), b.tokens+float64(elapsed)/float64(every))
+		b.last = now
+	}
+}
@@ -0,0 +1,99 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/clock"
+)
+
+func TestRateLimit(t *testing.T) {
+	src := clock.NewFake(time.Date(2016, time.July, 4, 12, 0, 0, 0, time.UTC))
+	limit := &RateLimit{Every: 2 * time.Second, Burst: 3, Clock: src}
+	handler := limit.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	testRequests := []struct {
+		advance    time.Duration
+		client     string
+		code       int
+		retryAfter string
+	}{
+		{0, "10.0.0.1:1000", 200, ""},
+		{0, "10.0.0.1:1001", 200, ""},
+		{0, "10.0.0.1:1002", 200, ""},
+		{0, "10.0.0.1:1003", 429, "2"},
+		{0, "10.0.0.2:1000", 200, ""}, // a different client
+		{500 * time.Millisecond, "10.0.0.1:1000", 429, "2"},
+		{1500 * time.Millisecond, "10.0.0.1:1000", 200, ""},
+		{100 * time.Millisecond, "10.0.0.1:1000", 429, "2"},
+		{time.Minute, "10.0.0.1:1000", 200, ""}, // only saves up to the burst
+		{0, "10.0.0.1:1000", 200, ""},
+		{0, "10.0.0.1:1000", 200, ""},
+		{0, "10.0.0.1:1000", 429, "2"},
+	}
+	for i, tr := range testRequests {
+		src.Advance(tr.advance)
+		r, _ := http.NewRequest("GET", "/", nil)
+		r.RemoteAddr = tr.client
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != tr.code || w.Header().Get("Retry-After") != tr.retryAfter {
+			t.Errorf("Request %d from %s failed: Code=%d, Retry-After=%q; Want %d, %q", i, tr.client, w.Code, w.Header().Get("Retry-After"), tr.code, tr.retryAfter)
+		}
+	}
+
+	// buckets are forgotten once they would be full
+	if _, exists := limit.buckets["10.0.0.2"]; exists || len(limit.buckets) != 1 {
+		t.Errorf("Rate limit kept a full bucket: %v", limit.buckets)
+	}
+	src.Advance(6 * time.Second)
+	r, _ := http.NewRequest("GET", "/", nil)
+	r.RemoteAddr = "10.0.0.3:1000"
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	if _, exists := limit.buckets["10.0.0.3"]; !exists || len(limit.buckets) != 1 {
+		t.Errorf("Rate limit kept a full bucket: %v", limit.buckets)
+	}
+}
+
+func TestRateLimitKey(t *testing.T) {
+	src := clock.NewFake(time.Date(2016, time.July, 4, 12, 0, 0, 0, time.UTC))
+	limit := &RateLimit{
+		Every: time.Hour,
+		Burst: 1,
+		Key:   func(r *http.Request) string { return r.Header.Get("X-Api-Key") },
+	}
+	handler := limit.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	testRequests := []struct {
+		key        string
+		code       int
+		retryAfter string
+	}{
+		{"kermit", 200, ""},
+		{"kermit", 429, "3600"},
+		{"gonzo", 200, ""},
+		{"", 200, ""},
+		{"", 200, ""}, // an empty key isn't limited
+	}
+	for _, tr := range testRequests {
+		r, _ := http.NewRequest("GET", "/", nil)
+		r.Header.Set("X-Api-Key", tr.key)
+		r = r.WithContext(clock.WithClock(r.Context(), src))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		if w.Code != tr.code || w.Header().Get("Retry-After") != tr.retryAfter {
+			t.Errorf("Request with key %q failed: Code=%d, Retry-After=%q; Want %d, %q", tr.key, w.Code, w.Header().Get("Retry-After"), tr.code, tr.retryAfter)
+		}
+	}
+}
+
+func TestRateLimitPanics(t *testing.T) {
+	defer func() {
+		if recovered := recover(); recovered != "httpx: a RateLimit requires a positive Every and Burst" {
+			t.Errorf("RateLimit without a rate panicked with %v", recovered)
+		}
+	}()
+	(&RateLimit{Burst: 3}).Handler(http.NotFoundHandler())
+}
@@ -0,0 +1,51 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/reflexionhealth/vanilla/uuid"
+)
+
+// RequestIDHeader is the request and response header holding a request's ID.
+const RequestIDHeader = "X-Request-Id"
+
+var requestIDs = NewLocal[string]("request id")
+
+// RequestID is middleware which gives each request an ID, so that the logs
+// of the services handling a request can be correlated.  The ID is read from
+// the X-Request-Id header, or a new random UUID is used if the header is
+// missing or invalid.  It is written to the X-Request-Id response header, and
+// the "request_id" field of the request's log line (see Logger).
+func RequestID(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		id := req.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = uuid.NewV4().String()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		GetRequestLog(req.Context()).Set("request_id", id)
+		h.ServeHTTP(w, req.WithContext(requestIDs.Put(req.Context(), id)))
+	})
+}
+
+// GetRequestID returns the ID given to the request by RequestID, or an
+// empty string if it doesn't have one.
+func GetRequestID(ctx context.Context) string {
+	return requestIDs.Get(ctx)
+}
+
+// validRequestID checks that an ID from a client is safe to pass on and log
+func validRequestID(id string) bool {
+	if len(id) == 0 || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == ':' || c == '+' || c == '/' || c == '=') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package httpx
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/clock"
+	"github.com/reflexionhealth/vanilla/uuid"
+)
+
+func TestRequestID(t *testing.T) {
+	t.Cleanup(uuid.SetSource(uuid.NewSeededSource(1)))
+	generated := uuid.NewV4().String()
+	t.Cleanup(uuid.SetSource(uuid.NewSeededSource(1)))
+
+	var handled string
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		handled = GetRequestID(req.Context())
+	}))
+
+	testIDs := []struct {
+		header string
+		id     string
+	}{
+		{"", generated},
+		{"abc-123", "abc-123"},
+		{"Root=1-5759e988-bd862e3fe1be46a994272793;Parent=x", ""},
+		{"two words", ""},
+		{strings.Repeat("a", 129), ""},
+	}
+	for _, ti := range testIDs {
+		r, _ := http.NewRequest("GET", "/", nil)
+		if len(ti.header) > 0 {
+			r.Header.Set(RequestIDHeader, ti.header)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if len(ti.id) > 0 && handled != ti.id {
+			t.Errorf("Request ID for %q was %q; Want %q", ti.header, handled, ti.id)
+		} else if len(ti.id) == 0 && (handled == ti.header || !uuid.IsValid(handled)) {
+			t.Errorf("Request ID for %q was %q; Want a new UUID", ti.header, handled)
+		}
+		if header := w.Header().Get(RequestIDHeader); header != handled {
+			t.Errorf("Request ID header for %q was %q; Want %q", ti.header, header, handled)
+		}
+	}
+
+	if id := GetRequestID(httptest.NewRequest("GET", "/", nil).Context()); id != "" {
+		t.Errorf("Request ID without the middleware was %q; Want empty", id)
+	}
+}
+
+func TestRequestIDLogging(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	for _, loggerFirst := range []bool{true, false} {
+		var out bytes.Buffer
+		logger := &Logger{Output: &out}
+		chain := Chain{RequestID, logger.Handler}
+		if loggerFirst {
+			chain = Chain{logger.Handler, RequestID}
+		}
+
+		r, _ := http.NewRequest("GET", "/", nil)
+		r.RemoteAddr = "10.0.0.1:5678"
+		r.Header.Set(RequestIDHeader, "abc-123")
+		r = r.WithContext(clock.WithClock(r.Context(), clock.NewFake(time.Date(2016, time.July, 4, 12, 0, 0, 0, time.UTC))))
+		chain.Handler(handler).ServeHTTP(httptest.NewRecorder(), r)
+		if want := "2016-07-04T12:00:00Z GET / 200 0s 10.0.0.1 request_id=abc-123\n"; out.String() != want {
+			t.Errorf("Logger (first=%v) wrote %q; Want %q", loggerFirst, out.String(), want)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package httpx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// A Route is a handler registered on a Mux, which can be named to build
+// URLs for it with Mux.URL.
+//
+// Routes can also carry metadata, which handlers and middleware can read
+// with GetRoute, and which is listed by Mux.Routes (eg. to generate docs):
+//
+//     mux.GET("/reports/:id", showReport).Name("report.show").
+//         Timeout(30 * time.Second).Tag("reports", "slow")
+//
+type Route struct {
+	Host   string // the host pattern, or empty if the route matches any host
+	Method string
+	Path   string
+
+	mux     *Mux
+	name    string
+	timeout time.Duration
+	tags    []string
+
+	summary   string
+	request   reflect.Type
+	responses map[int]reflect.Type
+}
+
+// Name names the route, so that URLs for it can be built with Mux.URL.
+// Name panics if another route on the mux already has the name.
+//
+//     mux.GET("/users/:id", showUser).Name("user.show")
+//     mux.URL("user.show", "id", "3") // "/users/3"
+//
+func (rt *Route) Name(name string) *Route {
+	if rt.mux.names == nil {
+		rt.mux.names = make(map[string]*Route)
+	} else if other, exists := rt.mux.names[name]; exists {
+		panic("route name '" + name + "' is already used by '" + other.Method + " " + other.Path + "'")
+	}
+
+	rt.name = name
+	rt.mux.names[name] = rt
+	return rt
+}
+
+// GetName returns the name of the route, or an empty string if it isn't named.
+func (rt *Route) GetName() string {
+	return rt.name
+}
+
+// Timeout sets how long the route's handler may take before the client
+// receives a 503 Service Unavailable, as with ResponseTimeout.  It replaces
+// any timeout set by the route's Group.
+func (rt *Route) Timeout(timeout time.Duration) *Route {
+	rt.timeout = timeout
+	return rt
+}
+
+// GetTimeout returns the route's timeout, or zero if it doesn't have one.
+func (rt *Route) GetTimeout() time.Duration {
+	return rt.timeout
+}
+
+// Tag adds tags to the route, such as the resource it belongs to.
+func (rt *Route) Tag(tags ...string) *Route {
+	for _, tag := range tags {
+		if !rt.HasTag(tag) {
+			rt.tags = append(rt.tags, tag)
+		}
+	}
+	return rt
+}
+
+// GetTags returns the route's tags, in the order they were added.
+func (rt *Route) GetTags() []string {
+	return append([]string(nil), rt.tags...)
+}
+
+// HasTag returns true if the route has the tag.
+func (rt *Route) HasTag(tag string) bool {
+	for _, t := range rt.tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Params returns the names of the route's path params, in order.
+func (rt *Route) Params() []string {
+	var names []string
+	for _, segment := range strings.Split(rt.Path, "/") {
+		if len(segment) > 1 && (segment[0] == ':' || segment[0] == '*') {
+			names = append(names, segment[1:])
+		}
+	}
+	return names
+}
+
+// Summary sets a short description of what the route does, for docs.
+func (rt *Route) Summary(summary string) *Route {
+	rt.summary = summary
+	return rt
+}
+
+// GetSummary returns the route's summary, or an empty string if it has none.
+func (rt *Route) GetSummary() string {
+	return rt.summary
+}
+
+// Accepts records the type of the route's JSON request body, for docs.
+// The body is only used for its type, so it can be a zero value:
+//
+//     mux.POST("/users", createUser).Accepts(NewUser{}).Returns(201, User{})
+//
+func (rt *Route) Accepts(body interface{}) *Route {
+	rt.request = reflect.TypeOf(body)
+	return rt
+}
+
+// GetRequestType returns the type of the route's request body, or nil if it
+// doesn't have one.
+func (rt *Route) GetRequestType() reflect.Type {
+	return rt.request
+}
+
+// Returns records the type of the route's JSON response body for a status
+// code, for docs.  The body may be nil if the response doesn't have one.
+func (rt *Route) Returns(status int, body interface{}) *Route {
+	if rt.responses == nil {
+		rt.responses = make(map[int]reflect.Type)
+	}
+	rt.responses[status] = reflect.TypeOf(body)
+	return rt
+}
+
+// GetResponseTypes returns the types of the route's response bodies by status
+// code.  The type is nil for responses without a body.
+func (rt *Route) GetResponseTypes() map[int]reflect.Type {
+	responses := make(map[int]reflect.Type, len(rt.responses))
+	for status, typ := range rt.responses {
+		responses[status] = typ
+	}
+	return responses
+}
+
+var routes = NewLocal[*Route]("route")
+
+// GetRoute returns the route which matched the request with the context, or
+// nil if the request wasn't routed by a Mux.  The route is available to the
+// mux's middleware as well as to the route's own middleware and handler.
+func GetRoute(ctx context.Context) *Route {
+	return routes.Get(ctx)
+}
+
+// routeHandler is the handler stored in the mux's trees, which provides the
+// route to the request and applies its timeout
+type routeHandler struct {
+	route   *Route
+	handler http.Handler
+}
+
+func (rh *routeHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	req = req.WithContext(routes.Put(req.Context(), rh.route))
+	if rh.route.timeout > 0 {
+		ResponseTimeout(rh.route.timeout)(rh.handler).ServeHTTP(w, req)
+	} else {
+		rh.handler.ServeHTTP(w, req)
+	}
+}
+
+// URL returns the path of the route with its parameters filled in from
+// params, which are pairs of parameter names and values.  Values are escaped,
+// except that the value of a catch-all parameter (like "*filepath") may
+// contain slashes.
+func (rt *Route) URL(params ...string) (string, error) {
+	if len(params)%2 != 0 {
+		return "", fmt.Errorf("httpx: route '%s' was given an odd number of params", rt.name)
+	}
+
+	var buf strings.Builder
+	path := rt.Path
+	for {
+		i := strings.IndexAny(path, ":*")
+		if i < 0 {
+			buf.WriteString(path)
+			break
+		}
+
+		buf.WriteString(path[:i])
+		end := strings.IndexByte(path[i:], '/')
+		if end < 0 {
+			end = len(path)
+		} else {
+			end += i
+		}
+
+		key := path[i+1 : end]
+		value, ok := lookupParam(params, key)
+		if !ok {
+			return "", fmt.Errorf("httpx: route '%s' is missing the param '%s'", rt.name, key)
+		}
+		if path[i] == '*' {
+			segments := strings.Split(strings.TrimPrefix(value, "/"), "/")
+			for j := range segments {
+				segments[j] = url.PathEscape(segments[j])
+			}
+			buf.WriteString(strings.Join(segments, "/"))
+		} else {
+			buf.WriteString(url.PathEscape(value))
+		}
+		path = path[end:]
+	}
+	return buf.String(), nil
+}
+
+func lookupParam(params []string, key string) (string, bool) {
+	for i := 0; i+1 < len(params); i += 2 {
+		if params[i] == key {
+			return params[i+1], true
+		}
+	}
+	return "", false
+}
+
+// URL returns the path of a named route, with its parameters filled in from
+// params (see Route.URL).  It returns an error if there isn't a route with
+// the name, or if a parameter is missing.
+//
+//     w.Header().Set("Location", mux.MustURL("user.show", "id", id))
+//
+func (r *Mux) URL(name string, params ...string) (string, error) {
+	rt, ok := r.names[name]
+	if !ok {
+		return "", fmt.Errorf("httpx: there is no route named '%s'", name)
+	}
+	return rt.URL(params...)
+}
+
+// Routes returns the routes registered on the mux, in the order they were
+// registered.
+func (r *Mux) Routes() []*Route {
+	return append([]*Route(nil), r.routes...)
+}
+
+// MustURL is like URL, but panics if the URL can't be built.
+func (r *Mux) MustURL(name string, params ...string) string {
+	u, err := r.URL(name, params...)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
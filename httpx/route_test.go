@@ -0,0 +1,186 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRouteURL(t *testing.T) {
+	handlerFunc := func(_ http.ResponseWriter, _ *http.Request) {}
+
+	router := NewMux()
+	router.GET("/users", handlerFunc).Name("user.list")
+	router.GET("/users/:id", handlerFunc).Name("user.show")
+	router.GET("/users/:id/posts/:post", handlerFunc).Name("user.post")
+	router.GET("/src/*filepath", handlerFunc).Name("files")
+	router.Group("/admin").POST("/teams/:team", handlerFunc).Name("admin.team")
+
+	testURLs := []struct {
+		name   string
+		params []string
+		url    string
+	}{
+		{"user.list", nil, "/users"},
+		{"user.show", []string{"id", "3"}, "/users/3"},
+		{"user.show", []string{"id", "a b/c"}, "/users/a%20b%2Fc"},
+		{"user.post", []string{"post", "7", "id", "3"}, "/users/3/posts/7"},
+		{"files", []string{"filepath", "/css/main.css"}, "/src/css/main.css"},
+		{"files", []string{"filepath", "my docs/a.txt"}, "/src/my%20docs/a.txt"},
+		{"admin.team", []string{"team", "muppets"}, "/admin/teams/muppets"},
+	}
+	for _, tu := range testURLs {
+		url, err := router.URL(tu.name, tu.params...)
+		if err != nil || url != tu.url {
+			t.Errorf("Wrong URL for %s %v: Got %q, %v; Want %q", tu.name, tu.params, url, err, tu.url)
+		}
+	}
+
+	testErrors := []struct {
+		name   string
+		params []string
+		err    string
+	}{
+		{"user.edit", nil, "httpx: there is no route named 'user.edit'"},
+		{"user.show", nil, "httpx: route 'user.show' is missing the param 'id'"},
+		{"user.post", []string{"id", "3"}, "httpx: route 'user.post' is missing the param 'post'"},
+		{"user.show", []string{"id"}, "httpx: route 'user.show' was given an odd number of params"},
+	}
+	for _, te := range testErrors {
+		_, err := router.URL(te.name, te.params...)
+		if err == nil || err.Error() != te.err {
+			t.Errorf("Wrong error for %s %v: Got %v; Want %q", te.name, te.params, err, te.err)
+		}
+	}
+
+	if url := router.MustURL("user.show", "id", "3"); url != "/users/3" {
+		t.Errorf("Wrong URL from MustURL: Got %q; Want \"/users/3\"", url)
+	}
+}
+
+func TestRouteDuplicateName(t *testing.T) {
+	handlerFunc := func(_ http.ResponseWriter, _ *http.Request) {}
+
+	router := NewMux()
+	router.GET("/users/:id", handlerFunc).Name("user")
+	defer func() {
+		if rcv := recover(); rcv != "route name 'user' is already used by 'GET /users/:id'" {
+			t.Errorf("Wrong panic for a duplicate route name: %v", rcv)
+		}
+	}()
+	router.PUT("/users/:id", handlerFunc).Name("user")
+}
+
+func TestRouteMetadata(t *testing.T) {
+	var seen []string
+	record := func(name string) Middleware {
+		return func(h http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				rt := GetRoute(r.Context())
+				if rt == nil {
+					seen = append(seen, name+": <nil>")
+				} else {
+					seen = append(seen, name+": "+rt.GetName())
+				}
+				h.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	router := NewMux()
+	router.Use(record("mux"))
+	router.GET("/users/:id", func(w http.ResponseWriter, r *http.Request) {
+		if rt := GetRoute(r.Context()); !rt.HasTag("users") || rt.HasTag("admin") {
+			t.Errorf("Wrong tags in handler: %v", rt.GetTags())
+		}
+	}, record("route")).Name("user.show").Tag("users", "users", "read")
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/users/3", nil)
+	router.ServeHTTP(w, r)
+	if want := []string{"mux: user.show", "route: user.show"}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("Wrong routes seen by middleware: Got %v; Want %v", seen, want)
+	}
+
+	seen = nil
+	r, _ = http.NewRequest("GET", "/missing", nil)
+	router.ServeHTTP(httptest.NewRecorder(), r)
+	if want := []string{"mux: <nil>"}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("Wrong routes seen by middleware for a missing route: Got %v; Want %v", seen, want)
+	}
+
+	rt := router.Routes()[0]
+	if tags := rt.GetTags(); !reflect.DeepEqual(tags, []string{"users", "read"}) {
+		t.Errorf("Wrong tags: Got %v; Want [users read]", tags)
+	}
+}
+
+func TestRouteDocs(t *testing.T) {
+	type user struct{ Name string }
+	router := NewMux()
+	rt := router.POST("/teams/:team/users/*rest", func(_ http.ResponseWriter, _ *http.Request) {}).
+		Summary("Add a user").Accepts(user{}).Returns(201, &user{}).Returns(204, nil)
+
+	if params := rt.Params(); !reflect.DeepEqual(params, []string{"team", "rest"}) {
+		t.Errorf("Wrong params: Got %v; Want [team rest]", params)
+	}
+	if rt.GetSummary() != "Add a user" || rt.GetRequestType() != reflect.TypeOf(user{}) {
+		t.Errorf("Wrong docs: Summary=%q, RequestType=%v", rt.GetSummary(), rt.GetRequestType())
+	}
+	want := map[int]reflect.Type{201: reflect.TypeOf(&user{}), 204: nil}
+	if responses := rt.GetResponseTypes(); !reflect.DeepEqual(responses, want) {
+		t.Errorf("Wrong response types: Got %v; Want %v", responses, want)
+	}
+}
+
+func TestRouteTimeout(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	router := NewMux()
+	router.GET("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}).Timeout(10 * time.Millisecond)
+	router.GET("/fast", func(w http.ResponseWriter, r *http.Request) {
+		if _, hasDeadline := r.Context().Deadline(); hasDeadline {
+			t.Error("Expected a route without a timeout to have no deadline")
+		}
+	})
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest("GET", "/slow", nil)
+	router.ServeHTTP(w, r)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Wrong code for a route which timed out: Got %d; Want 503", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r, _ = http.NewRequest("GET", "/fast", nil)
+	router.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Errorf("Wrong code for a route without a timeout: Got %d; Want 200", w.Code)
+	}
+}
+
+func TestMuxRoutes(t *testing.T) {
+	handlerFunc := func(_ http.ResponseWriter, _ *http.Request) {}
+
+	router := NewMux()
+	router.GET("/users", handlerFunc).Name("user.list")
+	router.Host("api.*").POST("/users", handlerFunc)
+	router.DELETE("/users/:id", handlerFunc)
+
+	var got []string
+	for _, rt := range router.Routes() {
+		got = append(got, rt.Host+" "+rt.Method+" "+rt.Path+" "+rt.GetName())
+	}
+	want := []string{" GET /users user.list", "api.* POST /users ", " DELETE /users/:id "}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Wrong routes: Got %q; Want %q", got, want)
+	}
+}
@@ -0,0 +1,398 @@
+/*
+Package session is a net/http middleware which keeps a session for each
+client in an encrypted cookie.
+
+The cookie is encrypted and authenticated with AES-GCM, so clients can't read
+or change it.  By default the session's values are stored in the cookie
+itself; set a Store to keep them on the server, with only the session's ID
+in the cookie.
+
+    sessions := &session.Manager{
+        Keys:   [][]byte{key}, // 32 random bytes
+        MaxAge: 24 * time.Hour,
+    }
+    mux.Use(sessions.Handler)
+
+Handlers read and change the session from the request context:
+
+    func login(w http.ResponseWriter, req *http.Request) {
+        s := session.FromContext(req.Context())
+        s.Rotate() // use a new ID whenever the user's privileges change
+        s.Set("user_id", userID)
+    }
+
+The session is saved when the response's header is written, so it must be
+changed before writing the body.
+*/
+package session
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/clock"
+	"github.com/reflexionhealth/vanilla/httpx"
+)
+
+// DefaultCookieName is the cookie used by a Manager which doesn't set a
+// CookieName.
+const DefaultCookieName = "session"
+
+// A Manager is middleware which loads the session for each request, and
+// saves it when the response is written.
+type Manager struct {
+	// Keys are the AES keys (16, 24, or 32 bytes long) used to encrypt the
+	// cookie.  The first key encrypts new cookies, and the others are still
+	// accepted so that keys can be rotated without ending every session.
+	Keys [][]byte
+
+	// Store keeps the session values on the server, if it is not nil
+	Store Store
+
+	// CookieName is the name of the cookie, or DefaultCookieName if empty
+	CookieName string
+	// Path and Domain set the cookie's attributes.  The Path is "/" if empty.
+	Path   string
+	Domain string
+	// MaxAge is how long a session lasts after it is last saved.  If it is
+	// zero the cookie lasts for the browser session (and for a day in a Store).
+	MaxAge time.Duration
+	// SameSite is the cookie's SameSite attribute, or http.SameSiteLaxMode if
+	// it is zero
+	SameSite http.SameSite
+	// Insecure allows the cookie to be sent over plain HTTP (eg. in development)
+	Insecure bool
+
+	// Clock is the clock used to expire sessions, or the clock in the request
+	// context (see clock.FromContext) if it is nil
+	Clock *clock.Source
+}
+
+// A Session holds the values for a client between requests.
+// It is not safe to use from multiple goroutines.
+type Session struct {
+	id      string
+	values  map[string]string
+	isNew   bool
+	changed bool
+
+	// rotated is the previous ID of a rotated session, which must be deleted
+	rotated   string
+	destroyed bool
+}
+
+var sessions = httpx.NewLocal[*Session]("session")
+
+// FromContext returns the session for the request with the context, or nil
+// if the request wasn't handled by a Manager.
+func FromContext(ctx context.Context) *Session {
+	return sessions.Get(ctx)
+}
+
+// ID returns the session's ID, which changes when it is rotated.
+func (s *Session) ID() string {
+	return s.id
+}
+
+// IsNew returns true if the session was created for the request.
+func (s *Session) IsNew() bool {
+	return s.isNew
+}
+
+// Get returns the value for the key, or an empty string if it isn't set.
+func (s *Session) Get(key string) string {
+	return s.values[key]
+}
+
+// Lookup returns the value for the key, and reports whether it was set.
+func (s *Session) Lookup(key string) (string, bool) {
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// Set sets the value for the key.
+func (s *Session) Set(key string, value string) {
+	s.values[key] = value
+	s.changed = true
+}
+
+// Delete removes the value for the key.
+func (s *Session) Delete(key string) {
+	if _, ok := s.values[key]; ok {
+		delete(s.values, key)
+		s.changed = true
+	}
+}
+
+// Rotate gives the session a new ID, keeping its values.  It should be called
+// when the client's privileges change (eg. when they log in or out), so that
+// an ID which leaked before can't be used to act with the new privileges.
+func (s *Session) Rotate() {
+	if !s.isNew && len(s.rotated) == 0 {
+		s.rotated = s.id
+	}
+	s.id = newID()
+	s.changed = true
+}
+
+// Destroy removes all of the session's values and its cookie.
+func (s *Session) Destroy() {
+	s.values = make(map[string]string)
+	s.destroyed = true
+	s.changed = true
+}
+
+// Handler returns a Handler which provides a session to h.
+func (m *Manager) Handler(h http.Handler) http.Handler {
+	if len(m.Keys) == 0 {
+		panic("session: Manager must have at least one key")
+	}
+	for _, key := range m.Keys {
+		if _, err := aes.NewCipher(key); err != nil {
+			panic("session: " + err.Error())
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		s, err := m.load(req)
+		if err != nil {
+			internalError(w)
+			return
+		}
+
+		sw := &sessionWriter{ResponseWriter: w, manager: m, session: s, req: req}
+		h.ServeHTTP(sw, req.WithContext(sessions.Put(req.Context(), s)))
+		if !sw.saved {
+			sw.save()
+		}
+	})
+}
+
+// cookiePayload is the encrypted content of a session cookie
+type cookiePayload struct {
+	ID      string            `json:"id"`
+	Values  map[string]string `json:"v,omitempty"`
+	Expires int64             `json:"exp,omitempty"`
+}
+
+func (m *Manager) load(req *http.Request) (*Session, error) {
+	fresh := &Session{id: newID(), values: make(map[string]string), isNew: true}
+
+	cookie, err := req.Cookie(m.cookieName())
+	if err != nil {
+		return fresh, nil
+	}
+	var payload cookiePayload
+	if !m.decrypt(cookie.Value, &payload) {
+		return fresh, nil
+	}
+	if payload.Expires != 0 && m.now(req.Context()).Unix() >= payload.Expires {
+		return fresh, nil
+	}
+
+	values := payload.Values
+	if m.Store != nil {
+		values, err = m.Store.Load(req.Context(), payload.ID)
+		if err != nil {
+			return nil, err
+		}
+		if values == nil {
+			return fresh, nil
+		}
+	}
+	if values == nil {
+		values = make(map[string]string)
+	}
+	return &Session{id: payload.ID, values: values}, nil
+}
+
+func (m *Manager) save(w http.ResponseWriter, req *http.Request, s *Session) error {
+	ctx := req.Context()
+	if m.Store != nil && len(s.rotated) > 0 {
+		if err := m.Store.Delete(ctx, s.rotated); err != nil {
+			return err
+		}
+	}
+
+	cookie := &http.Cookie{
+		Name:     m.cookieName(),
+		Path:     m.Path,
+		Domain:   m.Domain,
+		Secure:   !m.Insecure,
+		HttpOnly: true,
+		SameSite: m.SameSite,
+	}
+	if len(cookie.Path) == 0 {
+		cookie.Path = "/"
+	}
+	if cookie.SameSite == 0 {
+		cookie.SameSite = http.SameSiteLaxMode
+	}
+
+	if s.destroyed {
+		if m.Store != nil && !s.isNew {
+			if err := m.Store.Delete(ctx, s.id); err != nil {
+				return err
+			}
+		}
+		cookie.MaxAge = -1
+		http.SetCookie(w, cookie)
+		return nil
+	}
+
+	payload := cookiePayload{ID: s.id}
+	if m.MaxAge > 0 {
+		payload.Expires = m.now(ctx).Add(m.MaxAge).Unix()
+		cookie.MaxAge = int(m.MaxAge / time.Second)
+	}
+	if m.Store != nil {
+		maxAge := m.MaxAge
+		if maxAge <= 0 {
+			maxAge = 24 * time.Hour
+		}
+		if err := m.Store.Save(ctx, s.id, s.values, maxAge); err != nil {
+			return err
+		}
+	} else {
+		payload.Values = s.values
+	}
+
+	value, err := m.encrypt(payload)
+	if err != nil {
+		return err
+	}
+	cookie.Value = value
+	http.SetCookie(w, cookie)
+	return nil
+}
+
+func (m *Manager) cookieName() string {
+	if len(m.CookieName) > 0 {
+		return m.CookieName
+	}
+	return DefaultCookieName
+}
+
+func (m *Manager) now(ctx context.Context) time.Time {
+	if m.Clock != nil {
+		return m.Clock.UTC()
+	}
+	return clock.FromContext(ctx).UTC()
+}
+
+// errCookieTooLarge is returned when the session values don't fit in a cookie
+var errCookieTooLarge = errors.New("session: the session is too large to store in a cookie")
+
+// maxCookieSize is the largest cookie value which browsers are sure to keep
+const maxCookieSize = 4000
+
+func (m *Manager) encrypt(payload cookiePayload) (string, error) {
+	plaintext, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	aead := m.aead(m.Keys[0])
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	// the cookie name is authenticated too, so a cookie can't be reused
+	// with a different purpose
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(m.cookieName()))
+	value := base64.RawURLEncoding.EncodeToString(sealed)
+	if len(value) > maxCookieSize {
+		return "", errCookieTooLarge
+	}
+	return value, nil
+}
+
+func (m *Manager) decrypt(value string, payload *cookiePayload) bool {
+	sealed, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return false
+	}
+	for _, key := range m.Keys {
+		aead := m.aead(key)
+		if len(sealed) < aead.NonceSize() {
+			return false
+		}
+		nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+		plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(m.cookieName()))
+		if err == nil {
+			return json.Unmarshal(plaintext, payload) == nil && len(payload.ID) > 0
+		}
+	}
+	return false
+}
+
+func (m *Manager) aead(key []byte) cipher.AEAD {
+	block, _ := aes.NewCipher(key) // the keys are checked by Handler
+	aead, _ := cipher.NewGCM(block)
+	return aead
+}
+
+func newID() string {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func internalError(w http.ResponseWriter) {
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+// sessionWriter saves the session before the response's header is written
+type sessionWriter struct {
+	http.ResponseWriter
+	manager *Manager
+	session *Session
+	req     *http.Request
+
+	saved  bool
+	failed bool
+}
+
+func (sw *sessionWriter) save() {
+	sw.saved = true
+	if !sw.session.changed && (sw.session.isNew || sw.manager.MaxAge == 0) {
+		return // nothing to save, and the expiry doesn't need refreshing
+	}
+	if err := sw.manager.save(sw.ResponseWriter, sw.req, sw.session); err != nil {
+		sw.failed = true
+		internalError(sw.ResponseWriter)
+	}
+}
+
+func (sw *sessionWriter) WriteHeader(status int) {
+	if !sw.saved {
+		sw.save()
+	}
+	if !sw.failed {
+		sw.ResponseWriter.WriteHeader(status)
+	}
+}
+
+func (sw *sessionWriter) Write(b []byte) (int, error) {
+	if !sw.saved {
+		sw.save()
+	}
+	if sw.failed {
+		return len(b), nil
+	}
+	return sw.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying ResponseWriter
+func (sw *sessionWriter) Unwrap() http.ResponseWriter {
+	return sw.ResponseWriter
+}
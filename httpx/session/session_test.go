@@ -0,0 +1,186 @@
+package session
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/clock"
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+// serve sends a request with the cookie (if any) and returns the response's
+// session cookie (if any)
+func serve(t *testing.T, m *Manager, cookie *http.Cookie, handler func(s *Session)) *http.Cookie {
+	h := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(FromContext(r.Context()))
+		w.Write([]byte("ok"))
+	}))
+
+	r := httptest.NewRequest("GET", "/", nil)
+	if cookie != nil {
+		r.AddCookie(cookie)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	expect.Equal(t, w.Code, 200, "response code")
+
+	for _, c := range w.Result().Cookies() {
+		if c.Name == m.cookieName() {
+			return c
+		}
+	}
+	return nil
+}
+
+func TestCookieSession(t *testing.T) {
+	m := &Manager{Keys: [][]byte{testKey}, Domain: "example.com", SameSite: http.SameSiteStrictMode}
+
+	cookie := serve(t, m, nil, func(s *Session) {
+		expect.True(t, s.IsNew(), "first request should have a new session")
+		s.Set("user_id", "kermit")
+		s.Set("theme", "green")
+	})
+	if cookie == nil {
+		t.Fatal("expected a session cookie")
+	}
+	expect.Equal(t, cookie.Path, "/")
+	expect.Equal(t, cookie.Domain, "example.com")
+	expect.True(t, cookie.Secure && cookie.HttpOnly, "cookie should be Secure and HttpOnly")
+	expect.Equal(t, cookie.SameSite, http.SameSiteStrictMode)
+	expect.NotContains(t, cookie.Value, "kermit", "cookie should be encrypted")
+
+	next := serve(t, m, cookie, func(s *Session) {
+		expect.False(t, s.IsNew(), "second request should have the existing session")
+		expect.Equal(t, s.Get("user_id"), "kermit")
+		s.Delete("theme")
+	})
+	if next == nil {
+		t.Fatal("expected the changed session to be saved")
+	}
+
+	unchanged := serve(t, m, next, func(s *Session) {
+		_, ok := s.Lookup("theme")
+		expect.False(t, ok, "deleted value should be gone")
+		expect.Equal(t, s.Get("user_id"), "kermit")
+	})
+	expect.Nil(t, unchanged, "an unchanged session shouldn't be saved")
+}
+
+func TestCookieTampering(t *testing.T) {
+	m := &Manager{Keys: [][]byte{testKey}}
+	cookie := serve(t, m, nil, func(s *Session) { s.Set("admin", "false") })
+
+	tampered := *cookie
+	tampered.Value = cookie.Value[:len(cookie.Value)-2] + "AA"
+	serve(t, m, &tampered, func(s *Session) {
+		expect.True(t, s.IsNew(), "a tampered cookie should start a new session")
+	})
+
+	renamed := *cookie
+	other := &Manager{Keys: [][]byte{testKey}, CookieName: "other"}
+	renamed.Name = "other"
+	serve(t, other, &renamed, func(s *Session) {
+		expect.True(t, s.IsNew(), "a cookie with a different name should be rejected")
+	})
+}
+
+func TestKeyRotation(t *testing.T) {
+	oldKey := []byte("fedcba9876543210")
+	old := &Manager{Keys: [][]byte{oldKey}}
+	cookie := serve(t, old, nil, func(s *Session) { s.Set("user_id", "gonzo") })
+
+	m := &Manager{Keys: [][]byte{testKey, oldKey}}
+	serve(t, m, cookie, func(s *Session) {
+		expect.Equal(t, s.Get("user_id"), "gonzo", "old keys should still be accepted")
+	})
+
+	retired := &Manager{Keys: [][]byte{testKey}}
+	serve(t, retired, cookie, func(s *Session) {
+		expect.True(t, s.IsNew(), "retired keys should not be accepted")
+	})
+
+	expect.Panics(t, func() { (&Manager{}).Handler(nil) }, "a Manager without keys should panic")
+	expect.Panics(t, func() { (&Manager{Keys: [][]byte{[]byte("short")}}).Handler(nil) }, "an invalid key should panic")
+}
+
+func TestExpiry(t *testing.T) {
+	fake := clock.NewFake(time.Date(2016, 7, 4, 12, 0, 0, 0, time.UTC))
+	m := &Manager{Keys: [][]byte{testKey}, MaxAge: time.Hour, Clock: fake}
+
+	cookie := serve(t, m, nil, func(s *Session) { s.Set("user_id", "fozzie") })
+	expect.Equal(t, cookie.MaxAge, 3600)
+
+	fake.Advance(50 * time.Minute)
+	refreshed := serve(t, m, cookie, func(s *Session) {
+		expect.Equal(t, s.Get("user_id"), "fozzie")
+	})
+	if refreshed == nil {
+		t.Fatal("the expiry should be refreshed on each request")
+	}
+
+	fake.Advance(50 * time.Minute)
+	serve(t, m, cookie, func(s *Session) {
+		expect.True(t, s.IsNew(), "the original cookie should have expired")
+	})
+	serve(t, m, refreshed, func(s *Session) {
+		expect.Equal(t, s.Get("user_id"), "fozzie", "the refreshed cookie should not have expired")
+	})
+}
+
+func TestStoreSession(t *testing.T) {
+	store := &MemoryStore{}
+	m := &Manager{Keys: [][]byte{testKey}, Store: store}
+
+	var firstID string
+	cookie := serve(t, m, nil, func(s *Session) {
+		firstID = s.ID()
+		s.Set("user_id", "animal")
+	})
+	expect.Equal(t, store.Len(), 1)
+	values, _ := store.Load(context.Background(), firstID)
+	expect.Equal(t, values, map[string]string{"user_id": "animal"})
+
+	rotated := serve(t, m, cookie, func(s *Session) {
+		expect.Equal(t, s.ID(), firstID)
+		s.Rotate()
+		s.Set("admin", "true")
+		expect.NotEqual(t, s.ID(), firstID, "Rotate should change the ID")
+	})
+	expect.Equal(t, store.Len(), 1, "the old session should be deleted")
+	serve(t, m, cookie, func(s *Session) {
+		expect.True(t, s.IsNew(), "the old session ID should not be usable")
+	})
+
+	destroyed := serve(t, m, rotated, func(s *Session) {
+		expect.Equal(t, s.Get("user_id"), "animal")
+		expect.Equal(t, s.Get("admin"), "true")
+		s.Destroy()
+	})
+	if destroyed == nil {
+		t.Fatal("expected Destroy to clear the cookie")
+	}
+	expect.Equal(t, destroyed.MaxAge, -1)
+	expect.Equal(t, store.Len(), 0, "the session should be deleted from the store")
+}
+
+func TestMemoryStoreExpiry(t *testing.T) {
+	fake := clock.NewFake(time.Date(2016, 7, 4, 12, 0, 0, 0, time.UTC))
+	store := &MemoryStore{Clock: fake}
+
+	expect.NoError(t, store.Save(context.Background(), "a", map[string]string{"k": "v"}, time.Minute))
+	expect.NoError(t, store.Save(context.Background(), "b", map[string]string{"k": "v"}, time.Hour))
+	values, err := store.Load(context.Background(), "a")
+	expect.NoError(t, err)
+	expect.Equal(t, values, map[string]string{"k": "v"})
+
+	fake.Advance(2 * time.Minute)
+	values, err = store.Load(context.Background(), "a")
+	expect.NoError(t, err)
+	expect.Nil(t, values, "session 'a' should have expired")
+	expect.Equal(t, store.Len(), 1)
+}
@@ -0,0 +1,104 @@
+package session
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/clock"
+)
+
+// A Store keeps session values on the server, such as in a database or cache.
+type Store interface {
+	// Load returns the values of the session with the ID, or nil if the
+	// session doesn't exist or has expired.
+	Load(ctx context.Context, id string) (map[string]string, error)
+
+	// Save stores the values of the session, replacing any values already
+	// stored for the ID.  The session may be removed after maxAge.
+	Save(ctx context.Context, id string, values map[string]string, maxAge time.Duration) error
+
+	// Delete removes the session, if it exists.
+	Delete(ctx context.Context, id string) error
+}
+
+// A MemoryStore is a Store which keeps sessions in memory.  It is useful for
+// tests and for servers which run as a single process.
+type MemoryStore struct {
+	// Clock is the clock used to expire sessions, or the clock in the request
+	// context (see clock.FromContext) if it is nil
+	Clock *clock.Source
+
+	mu       sync.Mutex
+	sessions map[string]memorySession
+}
+
+type memorySession struct {
+	values  map[string]string
+	expires time.Time
+}
+
+// Load returns the values of the session, if it hasn't expired.
+func (s *MemoryStore) Load(ctx context.Context, id string) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved, ok := s.sessions[id]
+	if !ok {
+		return nil, nil
+	}
+	if !s.now(ctx).Before(saved.expires) {
+		delete(s.sessions, id)
+		return nil, nil
+	}
+	return copyValues(saved.values), nil
+}
+
+// Save stores a copy of the values, and removes any expired sessions.
+func (s *MemoryStore) Save(ctx context.Context, id string, values map[string]string, maxAge time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now(ctx)
+	if s.sessions == nil {
+		s.sessions = make(map[string]memorySession)
+	}
+	for other, saved := range s.sessions {
+		if !now.Before(saved.expires) {
+			delete(s.sessions, other)
+		}
+	}
+	s.sessions[id] = memorySession{copyValues(values), now.Add(maxAge)}
+	return nil
+}
+
+// Delete removes the session.
+func (s *MemoryStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+	return nil
+}
+
+// Len returns the number of sessions in the store, including expired
+// sessions which haven't been removed yet.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sessions)
+}
+
+func (s *MemoryStore) now(ctx context.Context) time.Time {
+	if s.Clock != nil {
+		return s.Clock.UTC()
+	}
+	return clock.FromContext(ctx).UTC()
+}
+
+func copyValues(values map[string]string) map[string]string {
+	copied := make(map[string]string, len(values))
+	for key, value := range values {
+		copied[key] = value
+	}
+	return copied
+}
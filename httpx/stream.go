@@ -0,0 +1,118 @@
+package httpx
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Stream writes a response in parts, flushing after each.  It calls step
+// until step returns false, and reports whether it stopped early because the
+// client disconnected (or because the response can't be flushed):
+//
+//     httpx.Stream(w, req, func(w io.Writer) bool {
+//         msg, ok := <-messages
+//         if ok {
+//             fmt.Fprintln(w, msg)
+//         }
+//         return ok
+//     })
+//
+// Flushing uses http.ResponseController, so middleware which wraps the
+// ResponseWriter should implement "Unwrap() http.ResponseWriter" to let
+// flushes (and hijacking) pass through to the connection.
+func Stream(w http.ResponseWriter, req *http.Request, step func(w io.Writer) bool) bool {
+	ctl := http.NewResponseController(w)
+	done := req.Context().Done()
+	for {
+		select {
+		case <-done:
+			return true
+		default:
+			keepOpen := step(w)
+			if err := ctl.Flush(); err != nil {
+				return true
+			}
+			if !keepOpen {
+				return false
+			}
+		}
+	}
+}
+
+// An Event is a message sent to the client of a server-sent event stream.
+type Event struct {
+	ID    string        // sets the client's last event id, if not empty
+	Event string        // the event type, or "message" if empty
+	Data  string        // may contain multiple lines
+	Retry time.Duration // sets the client's reconnection delay, if not zero
+}
+
+// An EventStream writes server-sent events (the text/event-stream format
+// used by EventSource in browsers), flushing each event as it is sent.
+type EventStream struct {
+	w   http.ResponseWriter
+	ctl *http.ResponseController
+}
+
+// NewEventStream writes the headers of an event stream response, and returns
+// an EventStream for sending events.  The Last-Event-ID header of the request
+// holds the ID of the last event received by a reconnecting client.
+func NewEventStream(w http.ResponseWriter) *EventStream {
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("X-Accel-Buffering", "no") // don't buffer the stream in nginx
+	header.Del("Content-Length")
+	w.WriteHeader(http.StatusOK)
+
+	s := &EventStream{w, http.NewResponseController(w)}
+	s.ctl.Flush()
+	return s
+}
+
+// Send writes an event and flushes it to the client.  An error is returned
+// if the client has disconnected, or if the response can't be flushed.
+func (s *EventStream) Send(event Event) error {
+	var msg strings.Builder
+	if len(event.ID) > 0 {
+		writeField(&msg, "id", event.ID)
+	}
+	if len(event.Event) > 0 {
+		writeField(&msg, "event", event.Event)
+	}
+	if event.Retry > 0 {
+		writeField(&msg, "retry", strconv.FormatInt(int64(event.Retry/time.Millisecond), 10))
+	}
+	for _, line := range strings.Split(strings.Replace(event.Data, "\r\n", "\n", -1), "\n") {
+		writeField(&msg, "data", line)
+	}
+	msg.WriteString("\n")
+	return s.write(msg.String())
+}
+
+// Comment writes a comment, which is ignored by the client.  It can be sent
+// periodically to keep the connection from being closed by proxies.
+func (s *EventStream) Comment(text string) error {
+	var msg strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		msg.WriteString(": " + line + "\n")
+	}
+	msg.WriteString("\n")
+	return s.write(msg.String())
+}
+
+func (s *EventStream) write(msg string) error {
+	if _, err := io.WriteString(s.w, msg); err != nil {
+		return err
+	}
+	return s.ctl.Flush()
+}
+
+func writeField(msg *strings.Builder, name, value string) {
+	// newlines would end the field, so they aren't allowed outside of data
+	value = strings.NewReplacer("\r", "", "\n", "").Replace(value)
+	msg.WriteString(name + ": " + value + "\n")
+}
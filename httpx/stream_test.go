@@ -0,0 +1,89 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type wrappedWriter struct {
+	http.ResponseWriter
+}
+
+func (w wrappedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+type plainWriter struct {
+	header http.Header
+}
+
+func (w plainWriter) Header() http.Header         { return w.header }
+func (w plainWriter) Write(p []byte) (int, error) { return len(p), nil }
+func (w plainWriter) WriteHeader(int)             {}
+
+func TestStream(t *testing.T) {
+	r, _ := http.NewRequest("GET", "/events", nil)
+	w := httptest.NewRecorder()
+	steps := 0
+	gone := Stream(wrappedWriter{w}, r, func(w io.Writer) bool {
+		steps++
+		fmt.Fprintf(w, "step %d\n", steps)
+		return steps < 3
+	})
+	if gone || steps != 3 || !w.Flushed || w.Body.String() != "step 1\nstep 2\nstep 3\n" {
+		t.Errorf("Stream failed: Gone=%v, Steps=%d, Flushed=%v, Body=%q", gone, steps, w.Flushed, w.Body.String())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	steps = 0
+	gone = Stream(httptest.NewRecorder(), r.WithContext(ctx), func(w io.Writer) bool {
+		steps++
+		return true
+	})
+	if !gone || steps != 0 {
+		t.Errorf("Stream after the client disconnected failed: Gone=%v, Steps=%d; Want true, 0", gone, steps)
+	}
+
+	steps = 0
+	gone = Stream(plainWriter{http.Header{}}, r, func(w io.Writer) bool {
+		steps++
+		return true
+	})
+	if !gone || steps != 1 {
+		t.Errorf("Stream without flushing failed: Gone=%v, Steps=%d; Want true, 1", gone, steps)
+	}
+}
+
+func TestEventStream(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set("Content-Length", "3")
+	events := NewEventStream(w)
+	if contentType := w.Header().Get("Content-Type"); contentType != "text/event-stream" || w.Header().Get("Content-Length") != "" {
+		t.Errorf("Event stream headers are wrong: %v", w.Header())
+	}
+
+	events.Send(Event{Data: "hello"})
+	events.Send(Event{ID: "2", Event: "update", Data: "line 1\nline 2", Retry: 3 * time.Second})
+	events.Send(Event{ID: "bad\nid", Data: ""})
+	events.Comment("keep-alive")
+
+	want := "data: hello\n\n" +
+		"id: 2\nevent: update\nretry: 3000\ndata: line 1\ndata: line 2\n\n" +
+		"id: badid\ndata: \n\n" +
+		": keep-alive\n\n"
+	if w.Body.String() != want || !w.Flushed {
+		t.Errorf("Event stream wrote %q (Flushed=%v); Want %q", w.Body.String(), w.Flushed, want)
+	}
+
+	events = NewEventStream(plainWriter{http.Header{}})
+	if err := events.Send(Event{Data: "hello"}); !errors.Is(err, http.ErrNotSupported) {
+		t.Errorf("Sending an event without flushing returned %v; Want %v", err, http.ErrNotSupported)
+	}
+}
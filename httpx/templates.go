@@ -0,0 +1,120 @@
+package httpx
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"sync"
+)
+
+// Templates renders pages from html templates.  Each page is parsed with its
+// own copy of the shared templates, so pages can fill in the blocks of a
+// common layout without conflicting with each other:
+//
+//     {{/* layouts/base.html */}}
+//     <html><body>{{block "content" .}}{{end}}</body></html>
+//
+//     {{/* pages/users.html */}}
+//     {{define "content"}}{{range .}}<p>{{.Name}}</p>{{end}}{{end}}
+//     {{template "base.html" .}}
+//
+//     views := &httpx.Templates{Pages: "pages/*.html", Shared: "layouts/*.html"}
+//     if err := views.Load(); err != nil {
+//         ...
+//     }
+//     views.Render(w, http.StatusOK, "users.html", users)
+//
+type Templates struct {
+	// Pages is a glob matching the page templates, which are named by their
+	// file name (eg. "users.html")
+	Pages string
+
+	// Shared is a glob matching the layouts and partials used by the pages.
+	// It may be empty if the pages don't need any.
+	Shared string
+
+	// Funcs are the functions available to every template
+	Funcs template.FuncMap
+
+	// FS is where the templates are read from, or the working directory if nil
+	FS fs.FS
+
+	// Reload parses the templates again before each page is rendered, so that
+	// changes are seen without restarting the server (eg. in development).
+	Reload bool
+
+	mu    sync.RWMutex
+	pages map[string]*template.Template
+}
+
+// Load parses the templates, returning an error if any can't be parsed.
+// It must be called before rendering, unless Reload is set.
+func (t *Templates) Load() error {
+	fsys := t.FS
+	if fsys == nil {
+		fsys = os.DirFS(".")
+	}
+
+	shared := template.New("").Funcs(t.Funcs)
+	if len(t.Shared) > 0 {
+		if _, err := shared.ParseFS(fsys, t.Shared); err != nil {
+			return err
+		}
+	}
+
+	files, err := fs.Glob(fsys, t.Pages)
+	if err != nil {
+		return err
+	} else if len(files) == 0 {
+		return fmt.Errorf("httpx: no templates match the pattern '%s'", t.Pages)
+	}
+
+	pages := make(map[string]*template.Template, len(files))
+	for _, file := range files {
+		page, err := shared.Clone()
+		if err != nil {
+			return err
+		}
+		if _, err := page.New(path.Base(file)).ParseFS(fsys, file); err != nil {
+			return err
+		}
+		pages[path.Base(file)] = page
+	}
+
+	t.mu.Lock()
+	t.pages = pages
+	t.mu.Unlock()
+	return nil
+}
+
+// Render writes a response with the status code and the named page executed
+// with data.  The page is executed before anything is written, so if it
+// returns an error the response can still be used to report the error.
+func (t *Templates) Render(w http.ResponseWriter, code int, name string, data interface{}) error {
+	if t.Reload {
+		if err := t.Load(); err != nil {
+			return err
+		}
+	}
+
+	t.mu.RLock()
+	page := t.pages[name]
+	t.mu.RUnlock()
+	if page == nil {
+		return fmt.Errorf("httpx: there is no template named '%s'", name)
+	}
+
+	var buf bytes.Buffer
+	if err := page.ExecuteTemplate(&buf, name, data); err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(code)
+	_, err := buf.WriteTo(w)
+	return err
+}
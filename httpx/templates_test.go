@@ -0,0 +1,87 @@
+package httpx
+
+import (
+	"html/template"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestTemplates(t *testing.T) {
+	files := fstest.MapFS{
+		"layouts/base.html":  {Data: []byte(`<title>{{block "title" .}}Muppets{{end}}</title>{{block "content" .}}{{end}}`)},
+		"layouts/name.html":  {Data: []byte(`{{define "name"}}<b>{{shout .}}</b>{{end}}`)},
+		"pages/users.html":   {Data: []byte(`{{define "content"}}{{range .}}{{template "name" .}}{{end}}{{end}}{{template "base.html" .}}`)},
+		"pages/profile.html": {Data: []byte(`{{define "title"}}{{.}}{{end}}{{define "content"}}{{template "name" .}}{{end}}{{template "base.html" .}}`)},
+		"pages/broken.html":  {Data: []byte(`{{template "missing" .}}`)},
+	}
+	views := &Templates{
+		Pages:  "pages/*.html",
+		Shared: "layouts/*.html",
+		Funcs:  template.FuncMap{"shout": strings.ToUpper},
+		FS:     files,
+	}
+	if err := views.Load(); err != nil {
+		t.Fatalf("Loading templates failed: %v", err)
+	}
+
+	testPages := []struct {
+		name string
+		data interface{}
+		body string
+	}{
+		{"users.html", []string{"kermit", "<gonzo>"}, `<title>Muppets</title><b>KERMIT</b><b>&lt;GONZO&gt;</b>`},
+		{"profile.html", "fozzie", `<title>fozzie</title><b>FOZZIE</b>`},
+	}
+	for _, tp := range testPages {
+		w := httptest.NewRecorder()
+		if err := views.Render(w, 201, tp.name, tp.data); err != nil {
+			t.Errorf("Rendering %s failed: %v", tp.name, err)
+		} else if w.Code != 201 || w.Body.String() != tp.body || w.Header().Get("Content-Type") != "text/html; charset=utf-8" {
+			t.Errorf("Rendering %s failed: Code=%d, Body=%q; Want %d, %q", tp.name, w.Code, w.Body.String(), 201, tp.body)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	if err := views.Render(w, 200, "broken.html", nil); err == nil || w.Body.Len() > 0 || len(w.Header()) > 0 {
+		t.Errorf("Rendering a broken template should fail without writing: Error=%v, Body=%q", err, w.Body.String())
+	}
+	if err := views.Render(w, 200, "missing.html", nil); err == nil || err.Error() != "httpx: there is no template named 'missing.html'" {
+		t.Errorf("Rendering a missing template returned %v", err)
+	}
+
+	// changes are only seen when reloading
+	files["pages/profile.html"] = &fstest.MapFile{Data: []byte(`hello {{.}}`)}
+	w = httptest.NewRecorder()
+	views.Render(w, 200, "profile.html", "fozzie")
+	if w.Body.String() != `<title>fozzie</title><b>FOZZIE</b>` {
+		t.Errorf("Template changed without reloading: Body=%q", w.Body.String())
+	}
+	views.Reload = true
+	w = httptest.NewRecorder()
+	views.Render(w, 200, "profile.html", "fozzie")
+	if w.Body.String() != `hello fozzie` {
+		t.Errorf("Template didn't change after reloading: Body=%q", w.Body.String())
+	}
+}
+
+func TestTemplatesLoadErrors(t *testing.T) {
+	files := fstest.MapFS{
+		"pages/users.html":  {Data: []byte(`{{range}}`)},
+		"layouts/base.html": {Data: []byte(`{{end}}`)},
+	}
+	testErrors := []struct {
+		views *Templates
+		error string
+	}{
+		{&Templates{Pages: "views/*.html", FS: files}, "httpx: no templates match the pattern 'views/*.html'"},
+		{&Templates{Pages: "pages/*.html", FS: files}, "template: users.html:1: missing value for range"},
+		{&Templates{Pages: "pages/*.html", Shared: "layouts/*.html", FS: files}, "template: base.html:1: unexpected {{end}}"},
+	}
+	for _, te := range testErrors {
+		if err := te.views.Load(); err == nil || err.Error() != te.error {
+			t.Errorf("Loading %s returned %v; Want %s", te.views.Pages, err, te.error)
+		}
+	}
+}
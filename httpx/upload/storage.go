@@ -0,0 +1,146 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// A Storage keeps uploaded files, such as in a directory or a bucket.
+type Storage interface {
+	// Save stores the contents of r with the key, replacing any file which
+	// is already stored with the key.
+	Save(ctx context.Context, key string, r io.Reader) error
+
+	// Open returns the contents of the file with the key.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the file with the key, if it exists.
+	Delete(ctx context.Context, key string) error
+}
+
+// Dir is a Storage which keeps files in a directory on the local file
+// system, like http.Dir.  Keys are slash-separated paths within the directory.
+type Dir string
+
+// Save writes the file to a temporary file, then renames it, so that a
+// partially written file is never visible.
+func (d Dir) Save(ctx context.Context, key string, r io.Reader) error {
+	name, err := d.resolve(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op once it has been renamed
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+// Open opens the file for reading.
+func (d Dir) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	name, err := d.resolve(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(name)
+}
+
+// Delete removes the file.
+func (d Dir) Delete(ctx context.Context, key string) error {
+	name, err := d.resolve(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// resolve returns the file name for a key, rejecting keys which would be
+// outside of the directory
+func (d Dir) resolve(key string) (string, error) {
+	if len(key) == 0 || strings.Contains(key, "\x00") || strings.ContainsRune(key, '\\') ||
+		path.IsAbs(key) || path.Clean(key) != key || key == "." || key == ".." || strings.HasPrefix(key, "../") {
+		return "", fmt.Errorf("upload: invalid key %q", key)
+	}
+	dir := string(d)
+	if len(dir) == 0 {
+		dir = "."
+	}
+	return filepath.Join(dir, filepath.FromSlash(key)), nil
+}
+
+// Memory is a Storage which keeps files in memory, for tests.
+type Memory struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+// Save stores the file's contents in memory.
+func (m *Memory) Save(ctx context.Context, key string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.files == nil {
+		m.files = make(map[string][]byte)
+	}
+	m.files[key] = data
+	return nil
+}
+
+// Open returns the file's contents.
+func (m *Memory) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	data, ok := m.Bytes(key)
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: key, Err: os.ErrNotExist}
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// Delete removes the file.
+func (m *Memory) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.files, key)
+	return nil
+}
+
+// Bytes returns the contents of the file, and reports whether it exists.
+func (m *Memory) Bytes(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.files[key]
+	return data, ok
+}
+
+// Len returns the number of files stored.
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.files)
+}
@@ -0,0 +1,321 @@
+/*
+Package upload receives files from multipart/form-data requests, streaming
+them to a Storage while enforcing limits on their size and type.
+
+    uploads := &upload.Uploader{
+        Storage:      upload.Dir("/var/uploads"),
+        MaxFileSize:  10 << 20, // 10 MiB
+        AllowedTypes: []string{"application/pdf", "image/*"},
+    }
+
+    func uploadDocument(w http.ResponseWriter, req *http.Request) {
+        form, err := uploads.Receive(req)
+        if err != nil {
+            httpx.WriteError(w, req, err)
+            return
+        }
+        doc := form.File("document") // doc.Key is where it was stored
+    }
+
+Files are never buffered in memory or temporary files as a whole, so large
+uploads can be rejected as soon as they exceed the limit.  The errors
+returned are *errors.Error with the appropriate HTTP status.
+*/
+package upload
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/reflexionhealth/vanilla/httpx/errors"
+	"github.com/reflexionhealth/vanilla/uuid"
+)
+
+// DefaultMaxFileSize is the largest file received by an Uploader which
+// doesn't set a MaxFileSize.
+const DefaultMaxFileSize = 32 << 20 // 32 MiB
+
+// DefaultMaxValuesSize is the largest total size of the non-file fields
+// received by an Uploader which doesn't set a MaxValuesSize.
+const DefaultMaxValuesSize = 1 << 20 // 1 MiB
+
+// An Uploader receives files from requests and saves them to its Storage.
+type Uploader struct {
+	Storage Storage
+
+	// MaxFileSize is the largest size of each file, in bytes
+	MaxFileSize int64
+	// MaxFiles is the most files in a request, or any number if it is zero
+	MaxFiles int
+	// MaxValuesSize is the largest total size of the other fields, in bytes
+	MaxValuesSize int64
+
+	// AllowedTypes are the media types of files which may be uploaded, such as
+	// "application/pdf", or "image/*" for any image.  If it is empty, files of
+	// any type may be uploaded.
+	//
+	// The type of each file is detected from its contents (see
+	// http.DetectContentType), so clients can't disguise a file's type, unless
+	// it is a type which can't be detected; then the part's Content-Type is used.
+	AllowedTypes []string
+
+	// Key returns the key to store a file with, or if it is nil a random UUID
+	// followed by the file's extension is used
+	Key func(file *File) string
+}
+
+// A File is a file received by an Uploader.
+type File struct {
+	Field       string // the name of the form field
+	Filename    string // the file name sent by the client, without any directory
+	ContentType string // the media type of the file
+	Size        int64  // the size of the file in bytes
+	Key         string // the key that the file was stored with
+}
+
+// A Form is the contents of a request received by an Uploader.
+type Form struct {
+	Values url.Values // the fields which aren't files
+	Files  []*File
+}
+
+// File returns the first file uploaded for the field, or nil if there isn't one.
+func (f *Form) File(field string) *File {
+	for _, file := range f.Files {
+		if file.Field == field {
+			return file
+		}
+	}
+	return nil
+}
+
+// Receive reads a multipart/form-data request, saving its files to the
+// Storage.  If the request is rejected, any files which were already saved
+// are deleted from the Storage.
+func (u *Uploader) Receive(req *http.Request) (*Form, error) {
+	contentType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if contentType != "multipart/form-data" {
+		return nil, &errors.Error{
+			HTTPStatus:   http.StatusUnsupportedMediaType,
+			DebugMessage: fmt.Sprintf("expected a Content-Type of \"multipart/form-data\", but received %q", contentType),
+		}
+	}
+	reader, err := req.MultipartReader()
+	if err != nil {
+		return nil, errors.BadRequest("the multipart request is malformed")
+	}
+
+	form := &Form{Values: make(url.Values)}
+	if err := u.receiveParts(req.Context(), reader, form); err != nil {
+		for _, file := range form.Files {
+			u.Storage.Delete(req.Context(), file.Key)
+		}
+		return nil, err
+	}
+	return form, nil
+}
+
+func (u *Uploader) receiveParts(ctx context.Context, reader *multipart.Reader, form *Form) error {
+	valuesBudget := u.maxValuesSize()
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.BadRequest("the multipart request is malformed")
+		}
+
+		field := part.FormName()
+		if len(field) == 0 {
+			part.Close()
+			continue
+		}
+		if len(part.FileName()) == 0 {
+			value, err := io.ReadAll(io.LimitReader(part, valuesBudget+1))
+			if err != nil {
+				return errors.BadRequest("the multipart request is malformed")
+			}
+			if valuesBudget -= int64(len(value)); valuesBudget < 0 {
+				return tooLarge(fmt.Sprintf("the form fields must be at most %d bytes", u.maxValuesSize()))
+			}
+			form.Values.Add(field, string(value))
+			continue
+		}
+
+		if u.MaxFiles > 0 && len(form.Files) >= u.MaxFiles {
+			return tooLarge(fmt.Sprintf("at most %d files may be uploaded", u.MaxFiles))
+		}
+		if err := u.receiveFile(ctx, part, form); err != nil {
+			return err
+		}
+	}
+}
+
+func (u *Uploader) receiveFile(ctx context.Context, part *multipart.Part, form *Form) error {
+	defer part.Close()
+
+	// read enough of the file to detect its type
+	head := make([]byte, 512)
+	n, err := io.ReadFull(part, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return errors.BadRequest("the multipart request is malformed")
+	}
+	head = head[:n]
+
+	file := &File{
+		Field:       part.FormName(),
+		Filename:    path.Base(strings.ReplaceAll(part.FileName(), "\\", "/")),
+		ContentType: detectType(head, part.Header.Get("Content-Type")),
+	}
+	if !u.isAllowed(file.ContentType) {
+		return &errors.Error{
+			HTTPStatus:   http.StatusUnsupportedMediaType,
+			DebugMessage: fmt.Sprintf("files of type %q may not be uploaded", file.ContentType),
+		}
+	}
+
+	if u.Key != nil {
+		file.Key = u.Key(file)
+	} else {
+		file.Key = uuid.NewV4().String() + extension(file.Filename)
+	}
+
+	body := &sizeLimitReader{r: io.MultiReader(bytes.NewReader(head), part), limit: u.maxFileSize()}
+	if err := u.Storage.Save(ctx, file.Key, body); err != nil {
+		u.Storage.Delete(ctx, file.Key)
+		if body.exceeded {
+			return body.tooLarge()
+		}
+		return errors.InternalError(err)
+	}
+	file.Size = body.n
+	form.Files = append(form.Files, file)
+	return nil
+}
+
+func (u *Uploader) maxFileSize() int64 {
+	if u.MaxFileSize > 0 {
+		return u.MaxFileSize
+	}
+	return DefaultMaxFileSize
+}
+
+func (u *Uploader) maxValuesSize() int64 {
+	if u.MaxValuesSize > 0 {
+		return u.MaxValuesSize
+	}
+	return DefaultMaxValuesSize
+}
+
+func (u *Uploader) isAllowed(contentType string) bool {
+	if len(u.AllowedTypes) == 0 {
+		return true
+	}
+	media, _, _ := strings.Cut(contentType, "/")
+	for _, allowed := range u.AllowedTypes {
+		allowed = strings.ToLower(allowed)
+		if allowed == contentType || allowed == media+"/*" {
+			return true
+		}
+	}
+	return false
+}
+
+// detectType returns the media type of a file from its first bytes, or
+// from the declared type if the contents aren't a type which can be detected
+func detectType(head []byte, declared string) string {
+	detected, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if detected == "application/octet-stream" || detected == "text/plain" {
+		if typ, _, err := mime.ParseMediaType(declared); err == nil {
+			// a text file can't claim to be a type (eg. a pdf) which would
+			// have been detected from its contents
+			if detected == "application/octet-stream" || strings.HasPrefix(typ, "text/") {
+				return typ
+			}
+		}
+	}
+	return detected
+}
+
+// extension returns the file's extension, if it is safe to use in a key
+func extension(filename string) string {
+	ext := strings.ToLower(path.Ext(filename))
+	if len(ext) < 2 || len(ext) > 10 {
+		return ""
+	}
+	for _, c := range ext[1:] {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') {
+			return ""
+		}
+	}
+	return ext
+}
+
+func tooLarge(debugMessage string) error {
+	return &errors.Error{
+		HTTPStatus:   http.StatusRequestEntityTooLarge,
+		DebugMessage: debugMessage,
+	}
+}
+
+// sizeLimitReader counts the bytes read, returning a 413 error if there are
+// more than the limit
+type sizeLimitReader struct {
+	r        io.Reader
+	n        int64
+	limit    int64
+	exceeded bool
+}
+
+func (r *sizeLimitReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	r.n += int64(n)
+	if r.n > r.limit {
+		r.exceeded = true
+		return 0, r.tooLarge()
+	}
+	return n, err
+}
+
+func (r *sizeLimitReader) tooLarge() error {
+	return tooLarge(fmt.Sprintf("each file must be at most %d bytes", r.limit))
+}
+
+// DefaultMaxMemory is the memory used by FormFile to parse a request; larger
+// files are stored in temporary files.
+const DefaultMaxMemory = 32 << 20 // 32 MiB
+
+// FormFile returns the first file uploaded for the field in a multipart
+// request, like http.Request.FormFile, with an *errors.Error if the request
+// is malformed or the file is missing.
+func FormFile(req *http.Request, field string) (*multipart.FileHeader, error) {
+	if req.MultipartForm == nil {
+		if err := req.ParseMultipartForm(DefaultMaxMemory); err != nil {
+			return nil, errors.BadRequest("the multipart request is malformed")
+		}
+	}
+	if files := req.MultipartForm.File[field]; len(files) > 0 {
+		return files[0], nil
+	}
+	invalid := errors.InvalidRequest("the request is missing a file")
+	invalid.Fields = []errors.FieldError{{Field: field, Message: fmt.Sprintf("field '%s' must be a file", field)}}
+	return nil, invalid
+}
+
+// SaveUploadedFile saves a file from FormFile to the storage with the key.
+func SaveUploadedFile(ctx context.Context, storage Storage, header *multipart.FileHeader, key string) error {
+	file, err := header.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return storage.Save(ctx, key, file)
+}
@@ -0,0 +1,161 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/reflexionhealth/vanilla/expect"
+	"github.com/reflexionhealth/vanilla/httpx/errors"
+)
+
+const pdf = "%PDF-1.4\n%\xe2\xe3\xcf\xd3\n1 0 obj\n<< /Type /Catalog >>\nendobj\n"
+
+// part is a part of a multipart request, which is a file if it has a filename
+type part struct {
+	field, filename, contentType, body string
+}
+
+func newRequest(parts ...part) *http.Request {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for _, p := range parts {
+		if len(p.filename) == 0 {
+			mw.WriteField(p.field, p.body)
+			continue
+		}
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", `form-data; name="`+p.field+`"; filename="`+p.filename+`"`)
+		if len(p.contentType) > 0 {
+			header.Set("Content-Type", p.contentType)
+		}
+		w, _ := mw.CreatePart(header)
+		io.WriteString(w, p.body)
+	}
+	mw.Close()
+
+	req, _ := http.NewRequest("POST", "/documents", &buf)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func statusOf(err error) int {
+	if httpErr, ok := err.(*errors.Error); ok {
+		return httpErr.HTTPStatus
+	}
+	return 0
+}
+
+func TestReceive(t *testing.T) {
+	storage := &Memory{}
+	uploader := &Uploader{
+		Storage:      storage,
+		AllowedTypes: []string{"application/pdf", "text/*"},
+		Key:          func(file *File) string { return file.Field + "/" + file.Filename },
+	}
+
+	form, err := uploader.Receive(newRequest(
+		part{field: "patient", body: "kermit"},
+		part{field: "document", filename: `C:\scans\intake.pdf`, contentType: "application/octet-stream", body: pdf},
+		part{field: "notes", filename: "notes.csv", contentType: "text/csv", body: "a,b\n1,2\n"},
+	))
+	expect.NoError(t, err)
+	if form == nil {
+		t.FailNow()
+	}
+
+	expect.Equal(t, form.Values.Get("patient"), "kermit")
+	expect.Equal(t, len(form.Files), 2)
+	doc := form.File("document")
+	expect.Equal(t, *doc, File{"document", "intake.pdf", "application/pdf", int64(len(pdf)), "document/intake.pdf"})
+	notes := form.File("notes")
+	expect.Equal(t, *notes, File{"notes", "notes.csv", "text/csv", 8, "notes/notes.csv"})
+	expect.Nil(t, form.File("missing"))
+
+	data, _ := storage.Bytes("document/intake.pdf")
+	expect.Equal(t, string(data), pdf)
+}
+
+func TestReceiveRejected(t *testing.T) {
+	examples := []struct {
+		uploader Uploader
+		parts    []part
+		status   int
+	}{
+		// the type is detected from the contents
+		{Uploader{AllowedTypes: []string{"image/*"}},
+			[]part{{field: "a", filename: "a.png", contentType: "image/png", body: pdf}}, 415},
+		{Uploader{AllowedTypes: []string{"application/pdf"}},
+			[]part{{field: "a", filename: "a.pdf", contentType: "application/pdf", body: "plain text"}}, 415},
+		{Uploader{MaxFileSize: 10},
+			[]part{{field: "a", filename: "a.txt", body: "0123456789"}, {field: "b", filename: "b.txt", body: "0123456789a"}}, 413},
+		{Uploader{MaxFiles: 1},
+			[]part{{field: "a", filename: "a.txt", body: "a"}, {field: "b", filename: "b.txt", body: "b"}}, 413},
+		{Uploader{MaxValuesSize: 5},
+			[]part{{field: "a", filename: "a.txt", body: "a"}, {field: "x", body: "abc"}, {field: "y", body: "abc"}}, 413},
+	}
+	for i, example := range examples {
+		storage := &Memory{}
+		example.uploader.Storage = storage
+		_, err := example.uploader.Receive(newRequest(example.parts...))
+		expect.Equal(t, statusOf(err), example.status, "example %d: %v", i, err)
+		expect.Equal(t, storage.Len(), 0, "example %d: expected the saved files to be deleted", i)
+	}
+
+	req, _ := http.NewRequest("POST", "/documents", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	_, err := (&Uploader{Storage: &Memory{}}).Receive(req)
+	expect.Equal(t, statusOf(err), 415)
+}
+
+func TestFormFile(t *testing.T) {
+	req := newRequest(part{field: "document", filename: "intake.pdf", body: pdf})
+	header, err := FormFile(req, "document")
+	expect.NoError(t, err)
+	if header == nil {
+		t.FailNow()
+	}
+	expect.Equal(t, header.Filename, "intake.pdf")
+
+	storage := &Memory{}
+	expect.NoError(t, SaveUploadedFile(context.Background(), storage, header, "intake.pdf"))
+	data, _ := storage.Bytes("intake.pdf")
+	expect.Equal(t, string(data), pdf)
+
+	_, err = FormFile(req, "missing")
+	expect.Equal(t, statusOf(err), 422)
+}
+
+func TestDir(t *testing.T) {
+	ctx := context.Background()
+	dir := Dir(t.TempDir())
+
+	expect.NoError(t, dir.Save(ctx, "patients/3/intake.pdf", strings.NewReader(pdf)))
+	data, err := os.ReadFile(filepath.Join(string(dir), "patients", "3", "intake.pdf"))
+	expect.NoError(t, err)
+	expect.Equal(t, string(data), pdf)
+
+	file, err := dir.Open(ctx, "patients/3/intake.pdf")
+	expect.NoError(t, err)
+	if file != nil {
+		data, _ = io.ReadAll(file)
+		file.Close()
+		expect.Equal(t, string(data), pdf)
+	}
+
+	expect.NoError(t, dir.Delete(ctx, "patients/3/intake.pdf"))
+	expect.NoError(t, dir.Delete(ctx, "patients/3/intake.pdf"), "deleting a missing file should succeed")
+	_, err = dir.Open(ctx, "patients/3/intake.pdf")
+	expect.True(t, os.IsNotExist(err), "expected the file to be deleted, but got %v", err)
+
+	for _, key := range []string{"", "../secret", "a/../../b", "/etc/passwd", `a\b`, "a//b", "."} {
+		expect.Error(t, dir.Save(ctx, key, strings.NewReader("x")), "expected key %q to be rejected", key)
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Package inflect converts the names of Go identifiers to the spellings used
+by other systems, such as the columns of a table or the fields of a form.
+
+    inflect.Snakecase("HostURL")   // "host_url"
+    inflect.Camelcase("HostURL")   // "hostURL"
+*/
+package inflect
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Camelcase downcases the first character of the name, eg. "UserID" => "userID"
+func Camelcase(input string) string {
+	r, size := utf8.DecodeRuneInString(input)
+	if unicode.IsUpper(r) {
+		return string(unicode.ToLower(r)) + input[size:]
+	} else {
+		return input
+	}
+}
+
+// Pascalcase upcases the first character of the name, eg. "userID" => "UserID"
+func Pascalcase(input string) string {
+	r, size := utf8.DecodeRuneInString(input)
+	if unicode.IsLower(r) {
+		return string(unicode.ToUpper(r)) + input[size:]
+	} else {
+		return input
+	}
+}
+
+// Snakecase downcases all characters of the name, inserting an underscore
+// before each word (other than the first), eg. "HTTPPost" => "http_post".
+// The input is expected to be camelCase or PascalCase.
+func Snakecase(input string) string {
+	var output bytes.Buffer
+	var runes = []rune(input)
+	for i, char := range runes {
+		if unicode.IsUpper(char) {
+			// NOTE: If this is not the first character AND it is before or after a lowercase character
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i != len(runes)-1 && unicode.IsLower(runes[i+1]))) {
+				output.WriteRune('_')
+			}
+			output.WriteRune(unicode.ToLower(char))
+		} else {
+			output.WriteRune(char)
+		}
+	}
+
+	return output.String()
+}
@@ -0,0 +1,39 @@
+package inflect
+
+import (
+	"testing"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+func TestCamelcase(t *testing.T) {
+	expect.Equal(t, Camelcase("UserID"), "userID")
+	expect.Equal(t, Camelcase("userID"), "userID")
+	expect.Equal(t, Camelcase("Élan"), "élan")
+	expect.Equal(t, Camelcase(""), "")
+}
+
+func TestPascalcase(t *testing.T) {
+	expect.Equal(t, Pascalcase("userID"), "UserID")
+	expect.Equal(t, Pascalcase("UserID"), "UserID")
+	expect.Equal(t, Pascalcase("élan"), "Élan")
+	expect.Equal(t, Pascalcase(""), "")
+}
+
+func TestSnakecase(t *testing.T) {
+	examples := []struct {
+		Input  string
+		Output string
+	}{
+		{Input: "snake_case", Output: "snake_case"}, // NOTE: expected input is camelCase or pascalCase
+		{Input: "camelCase", Output: "camel_case"},
+		{Input: "PascalCase", Output: "pascal_case"},
+		{Input: "exampleID", Output: "example_id"},
+		{Input: "HTTPPost", Output: "http_post"},
+		{Input: "HostURL", Output: "host_url"},
+		{Input: "XMLHttpRequest", Output: "xml_http_request"},
+	}
+	for _, ex := range examples {
+		expect.Equal(t, Snakecase(ex.Input), ex.Output)
+	}
+}
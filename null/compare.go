@@ -0,0 +1,123 @@
+package null
+
+import "bytes"
+
+// The null types implement IsZero, which reports whether the value is null
+// (eg. for the json "omitzero" option), and Equal, which reports whether two
+// values are both null or both have equal values.
+//
+// Equal compares values like the value's own Equal method if it has one, so
+// times are equal at the same instant, dates ignore their location, and
+// versions ignore build metadata.  Json documents are compared byte-for-byte.
+
+func (n Bool) IsZero() bool {
+	return !n.Valid
+}
+
+func (n Bool) Equal(o Bool) bool {
+	return n.Valid == o.Valid && (!n.Valid || n.Bool == o.Bool)
+}
+
+func (n String) IsZero() bool {
+	return !n.Valid
+}
+
+func (n String) Equal(o String) bool {
+	return n.Valid == o.Valid && (!n.Valid || n.String == o.String)
+}
+
+func (n Float) IsZero() bool {
+	return !n.Valid
+}
+
+func (n Float) Equal(o Float) bool {
+	return n.Valid == o.Valid && (!n.Valid || n.Float == o.Float)
+}
+
+func (n Int) IsZero() bool {
+	return !n.Valid
+}
+
+func (n Int) Equal(o Int) bool {
+	return n.Valid == o.Valid && (!n.Valid || n.Int == o.Int)
+}
+
+func (n Int64) IsZero() bool {
+	return !n.Valid
+}
+
+func (n Int64) Equal(o Int64) bool {
+	return n.Valid == o.Valid && (!n.Valid || n.Int64 == o.Int64)
+}
+
+func (n Int32) IsZero() bool {
+	return !n.Valid
+}
+
+func (n Int32) Equal(o Int32) bool {
+	return n.Valid == o.Valid && (!n.Valid || n.Int32 == o.Int32)
+}
+
+func (n Uint) IsZero() bool {
+	return !n.Valid
+}
+
+func (n Uint) Equal(o Uint) bool {
+	return n.Valid == o.Valid && (!n.Valid || n.Uint == o.Uint)
+}
+
+func (n Duration) IsZero() bool {
+	return !n.Valid
+}
+
+func (n Duration) Equal(o Duration) bool {
+	return n.Valid == o.Valid && (!n.Valid || n.Duration == o.Duration)
+}
+
+func (n Bytes) IsZero() bool {
+	return !n.Valid
+}
+
+func (n Bytes) Equal(o Bytes) bool {
+	return n.Valid == o.Valid && (!n.Valid || bytes.Equal(n.Bytes, o.Bytes))
+}
+
+func (n JSON) IsZero() bool {
+	return !n.Valid
+}
+
+func (n JSON) Equal(o JSON) bool {
+	return n.Valid == o.Valid && (!n.Valid || bytes.Equal(n.JSON, o.JSON))
+}
+
+func (n Time) IsZero() bool {
+	return !n.Valid
+}
+
+func (n Time) Equal(o Time) bool {
+	return n.Valid == o.Valid && (!n.Valid || n.Time.Equal(o.Time))
+}
+
+func (n Date) IsZero() bool {
+	return !n.Valid
+}
+
+func (n Date) Equal(o Date) bool {
+	return n.Valid == o.Valid && (!n.Valid || n.Date.Equal(o.Date))
+}
+
+func (n UUID) IsZero() bool {
+	return !n.Valid
+}
+
+func (n UUID) Equal(o UUID) bool {
+	return n.Valid == o.Valid && (!n.Valid || n.UUID == o.UUID)
+}
+
+func (n Version) IsZero() bool {
+	return !n.Valid
+}
+
+func (n Version) Equal(o Version) bool {
+	return n.Valid == o.Valid && (!n.Valid || n.Version.Equal(o.Version))
+}
@@ -0,0 +1,71 @@
+package null
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/date"
+	"github.com/reflexionhealth/vanilla/expect"
+	"github.com/reflexionhealth/vanilla/semver"
+)
+
+func TestIsZero(t *testing.T) {
+	expect.True(t, NoString.IsZero())
+	expect.False(t, SomeString("").IsZero())
+	expect.True(t, NoInt64.IsZero())
+	expect.False(t, SomeInt64(0).IsZero())
+	expect.True(t, NoTime.IsZero())
+	expect.False(t, SomeTime(time.Time{}).IsZero())
+	expect.True(t, NoJSON.IsZero())
+	expect.False(t, SomeJSON(json.RawMessage("null")).IsZero())
+}
+
+func TestEqual(t *testing.T) {
+	expect.True(t, NoInt.Equal(Int{Int: 3})) // the value of a null is ignored
+	expect.True(t, SomeInt(3).Equal(SomeInt(3)))
+	expect.False(t, SomeInt(3).Equal(SomeInt(4)))
+	expect.False(t, SomeInt(0).Equal(NoInt))
+	expect.False(t, NoString.Equal(SomeString("")))
+
+	instant := time.Date(2010, time.July, 3, 13, 24, 33, 0, time.UTC)
+	expect.True(t, SomeTime(instant).Equal(SomeTime(instant.In(time.FixedZone("", -4*60*60)))))
+	expect.False(t, SomeTime(instant).Equal(SomeTime(instant.Add(time.Nanosecond))))
+
+	expect.True(t, SomeDate(date.At(2010, time.July, 3, nil)).Equal(SomeDate(date.At(2010, time.July, 3, time.UTC))))
+	expect.True(t, SomeBytes(nil).Equal(SomeBytes([]byte{})))
+	expect.False(t, SomeJSON(json.RawMessage(`{"a":1}`)).Equal(SomeJSON(json.RawMessage(`{"a": 1}`))))
+
+	v1 := semver.Version{Major: 1, Build: "abc"}
+	v2 := semver.Version{Major: 1, Build: "def"}
+	expect.True(t, SomeVersion(v1).Equal(SomeVersion(v2)))
+}
+
+func TestConvertSqlNullTypes(t *testing.T) {
+	expect.Equal(t, FromNullBool(sql.NullBool{Bool: true, Valid: true}), SomeBool(true))
+	expect.Equal(t, SomeBool(true).NullBool(), sql.NullBool{Bool: true, Valid: true})
+
+	expect.Equal(t, FromNullString(sql.NullString{String: "kermit", Valid: true}), SomeString("kermit"))
+	expect.Equal(t, NoString.NullString(), sql.NullString{})
+
+	expect.Equal(t, FromNullFloat64(sql.NullFloat64{Float64: 2.5, Valid: true}), SomeFloat(2.5))
+	expect.Equal(t, SomeFloat(2.5).NullFloat64(), sql.NullFloat64{Float64: 2.5, Valid: true})
+
+	expect.Equal(t, FromNullInt64(sql.NullInt64{Int64: 3, Valid: true}), SomeInt64(3))
+	expect.Equal(t, FromNullInt64(sql.NullInt64{}).Int(), NoInt)
+	expect.Equal(t, SomeInt64(3).Int(), SomeInt(3))
+	expect.Equal(t, SomeInt64(3).NullInt64(), sql.NullInt64{Int64: 3, Valid: true})
+	expect.Equal(t, SomeInt(3).NullInt64(), sql.NullInt64{Int64: 3, Valid: true})
+
+	expect.Equal(t, FromNullInt32(sql.NullInt32{Int32: 3, Valid: true}), SomeInt32(3))
+	expect.Equal(t, NoInt32.NullInt32(), sql.NullInt32{})
+
+	instant := time.Date(2010, time.July, 3, 13, 24, 33, 0, time.UTC)
+	expect.Equal(t, FromNullTime(sql.NullTime{Time: instant, Valid: true}), SomeTime(instant))
+	expect.Equal(t, SomeTime(instant).NullTime(), sql.NullTime{Time: instant, Valid: true})
+
+	expect.Equal(t, SomeDate(date.At(2010, time.July, 3, nil)).NullTime(),
+		sql.NullTime{Time: time.Date(2010, time.July, 3, 0, 0, 0, 0, time.UTC), Valid: true})
+	expect.Equal(t, NoDate.NullTime(), sql.NullTime{})
+}
@@ -0,0 +1,76 @@
+package null
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Conversions to and from the nullable types in database/sql, for libraries
+// which expect them
+
+func FromNullBool(value sql.NullBool) Bool {
+	return Bool(value)
+}
+
+func (n Bool) NullBool() sql.NullBool {
+	return sql.NullBool(n)
+}
+
+func FromNullString(value sql.NullString) String {
+	return String(value)
+}
+
+func (n String) NullString() sql.NullString {
+	return sql.NullString(n)
+}
+
+func FromNullFloat64(value sql.NullFloat64) Float {
+	return Float{Float: value.Float64, Valid: value.Valid}
+}
+
+func (n Float) NullFloat64() sql.NullFloat64 {
+	return sql.NullFloat64{Float64: n.Float, Valid: n.Valid}
+}
+
+// FromNullInt64 converts a sql.NullInt64 to an Int64 (see Int64.Int for an Int)
+func FromNullInt64(value sql.NullInt64) Int64 {
+	return Int64{Int64: value.Int64, Valid: value.Valid}
+}
+
+func (n Int64) NullInt64() sql.NullInt64 {
+	return sql.NullInt64{Int64: n.Int64, Valid: n.Valid}
+}
+
+// Int converts an Int64 to an Int, which may overflow if int is 32 bits
+func (n Int64) Int() Int {
+	return Int{Int: int(n.Int64), Valid: n.Valid}
+}
+
+func (n Int) NullInt64() sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(n.Int), Valid: n.Valid}
+}
+
+func FromNullInt32(value sql.NullInt32) Int32 {
+	return Int32{Int32: value.Int32, Valid: value.Valid}
+}
+
+func (n Int32) NullInt32() sql.NullInt32 {
+	return sql.NullInt32{Int32: n.Int32, Valid: n.Valid}
+}
+
+func FromNullTime(value sql.NullTime) Time {
+	return Time{Time: value.Time, Valid: value.Valid}
+}
+
+func (n Time) NullTime() sql.NullTime {
+	return sql.NullTime{Time: n.Time, Valid: n.Valid}
+}
+
+// NullTime converts a Date to a sql.NullTime at the beginning of the day in UTC,
+// which is the same time as its Value
+func (n Date) NullTime() sql.NullTime {
+	if !n.Valid {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: n.Date.BeginningOfDayIn(time.UTC), Valid: true}
+}
@@ -0,0 +1,17 @@
+// Package null provides nullable types which support database/sql,
+// encoding/gob, encoding/json, and encoding text, without requiring an extra
+// allocation or dereference like a pointer would.
+//
+// Each type has a Valid field, which is false if the value is null, and a
+// field for the value named after the type (eg. Int64.Int64).  Values can be
+// created with the SomeType functions, and the NoType variables are null.
+//
+//	Bool, String, Float, Int, Int64, Int32, Uint  - basic types
+//	Bytes, JSON                                   - binary and json columns
+//	Time, Date, Duration                          - times and dates
+//	UUID, Version                                 - other vanilla types
+//
+// This is the only null package in vanilla; code using the database/sql
+// NullBool, NullString, etc. can be converted with the FromNullType functions
+// and the NullType methods.
+package null
@@ -0,0 +1,245 @@
+package null
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NoDuration is a null.Duration constant for convenience and readability
+var NoDuration Duration = Duration{Valid: false}
+
+// A DurationFormat is a way to encode a Duration in json
+type DurationFormat int
+
+const (
+	DurationString       DurationFormat = iota // a string like "1h30m0s", as formatted by time.Duration
+	DurationISO8601                            // a string like "PT1H30M"
+	DurationMilliseconds                       // a number of milliseconds, like 5400000
+)
+
+// DurationJSONFormat is the format used to marshal Durations to json.
+// Durations can be unmarshaled from any of the formats, where a number is
+// always a number of milliseconds.
+var DurationJSONFormat = DurationString
+
+// Duration is a nullable time.Duration that doesn't require an extra allocation or dereference.
+// It is stored in the database as an integer number of nanoseconds.
+type Duration struct {
+	Duration time.Duration
+	Valid    bool
+}
+
+func SomeDuration(value time.Duration) Duration {
+	return Duration{Duration: value, Valid: true}
+}
+
+func (n *Duration) Set(value time.Duration) {
+	n.Valid = true
+	n.Duration = value
+}
+
+func (n *Duration) Unset() {
+	n.Valid = false
+	n.Duration = 0
+}
+
+// Implement sql.Scanner interface
+func (n *Duration) Scan(src interface{}) error {
+	n.Valid = false
+	n.Duration = 0
+	switch t := src.(type) {
+	case nil:
+		return nil
+	case int64:
+		n.Set(time.Duration(t))
+	case string:
+		return n.parse(t)
+	case []byte:
+		return n.parse(string(t))
+	default:
+		return errors.New("null: scan value was not an int64, []byte, string, or nil")
+	}
+	return nil
+}
+
+// parse reads a duration from a string of nanoseconds, a Go duration string, or an ISO 8601 duration
+func (n *Duration) parse(src string) error {
+	if d, err := strconv.ParseInt(src, 10, 64); err == nil {
+		n.Set(time.Duration(d))
+	} else if d, err := time.ParseDuration(src); err == nil {
+		n.Set(d)
+	} else if d, err := parseISO8601Duration(src); err == nil {
+		n.Set(d)
+	} else {
+		return fmt.Errorf("null: could not parse %q as a duration", src)
+	}
+	return nil
+}
+
+// Implement driver.Valuer interface
+func (n Duration) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	} else {
+		return int64(n.Duration), nil
+	}
+}
+
+// Implement json.Marshaler interface
+func (n Duration) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return JsonNull, nil
+	}
+
+	switch DurationJSONFormat {
+	case DurationISO8601:
+		return json.Marshal(formatISO8601Duration(n.Duration))
+	case DurationMilliseconds:
+		return json.Marshal(int64(n.Duration / time.Millisecond))
+	default:
+		return json.Marshal(n.Duration.String())
+	}
+}
+
+// Implement json.Unmarshaler interface
+func (n *Duration) UnmarshalJSON(bytes []byte) error {
+	n.Valid = false
+	if bytes == nil || string(bytes) == `""` || string(bytes) == "null" {
+		n.Duration = 0
+		return nil
+	}
+
+	if bytes[0] == '"' {
+		var str string
+		if err := json.Unmarshal(bytes, &str); err != nil {
+			return err
+		}
+		if d, err := time.ParseDuration(str); err == nil {
+			n.Set(d)
+		} else if d, err := parseISO8601Duration(str); err == nil {
+			n.Set(d)
+		} else {
+			return fmt.Errorf("null: could not parse %s as a duration", bytes)
+		}
+		return nil
+	}
+
+	var ms int64
+	if err := json.Unmarshal(bytes, &ms); err != nil {
+		return err
+	}
+	if ms > math.MaxInt64/int64(time.Millisecond) || ms < math.MinInt64/int64(time.Millisecond) {
+		return fmt.Errorf("null: duration of %d milliseconds is out of range", ms)
+	}
+	n.Set(time.Duration(ms) * time.Millisecond)
+	return nil
+}
+
+// formatISO8601Duration formats a duration like "PT1H30M0.5S", using hours
+// as the largest unit because days aren't always 24 hours long
+func formatISO8601Duration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+
+	var buf strings.Builder
+	u := uint64(d)
+	if d < 0 {
+		buf.WriteByte('-')
+		u = -u
+	}
+	buf.WriteString("PT")
+
+	hours := u / uint64(time.Hour)
+	u -= hours * uint64(time.Hour)
+	minutes := u / uint64(time.Minute)
+	u -= minutes * uint64(time.Minute)
+	seconds := u / uint64(time.Second)
+	nanos := u - seconds*uint64(time.Second)
+
+	if hours > 0 {
+		buf.WriteString(strconv.FormatUint(hours, 10) + "H")
+	}
+	if minutes > 0 {
+		buf.WriteString(strconv.FormatUint(minutes, 10) + "M")
+	}
+	if seconds > 0 || nanos > 0 {
+		buf.WriteString(strconv.FormatUint(seconds, 10))
+		if nanos > 0 {
+			buf.WriteString(strings.TrimRight(fmt.Sprintf(".%09d", nanos), "0"))
+		}
+		buf.WriteString("S")
+	}
+	return buf.String()
+}
+
+// parseISO8601Duration parses a duration like "P1DT2H30M" or "PT0.5S".
+// Years and months aren't supported because they don't have a fixed length,
+// and days are always 24 hours.
+func parseISO8601Duration(src string) (time.Duration, error) {
+	invalid := fmt.Errorf("null: invalid ISO 8601 duration %q", src)
+
+	s := src
+	negative := strings.HasPrefix(s, "-")
+	if negative {
+		s = s[1:]
+	}
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, invalid
+	}
+	s = s[1:]
+
+	var total float64
+	inTime := false
+	for len(s) > 0 {
+		if s[0] == 'T' {
+			if inTime || len(s) == 1 {
+				return 0, invalid
+			}
+			inTime = true
+			s = s[1:]
+		}
+
+		end := strings.IndexAny(s, "WDHMS")
+		if end <= 0 {
+			return 0, invalid
+		}
+		value, err := strconv.ParseFloat(strings.Replace(s[:end], ",", ".", 1), 64)
+		if err != nil || value < 0 {
+			return 0, invalid
+		}
+
+		var unit time.Duration
+		switch s[end] {
+		case 'W':
+			unit = 7 * 24 * time.Hour
+		case 'D':
+			unit = 24 * time.Hour
+		case 'H':
+			unit = time.Hour
+		case 'M':
+			unit = time.Minute
+		case 'S':
+			unit = time.Second
+		}
+		if (unit < 24*time.Hour) != inTime {
+			return 0, invalid // the time components must follow the "T"
+		}
+		total += value * float64(unit)
+		s = s[end+1:]
+	}
+
+	if total > math.MaxInt64 {
+		return 0, invalid
+	}
+	if negative {
+		total = -total
+	}
+	return time.Duration(math.Round(total)), nil
+}
@@ -0,0 +1,114 @@
+package null
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+func TestScanNullDuration(t *testing.T) {
+	var n Duration
+	var err error
+	err = n.Scan(int64(90 * time.Second))
+	expect.Nil(t, err)
+	expect.Equal(t, n, SomeDuration(90*time.Second))
+
+	err = n.Scan("1h30m")
+	expect.Nil(t, err)
+	expect.Equal(t, n, SomeDuration(90*time.Minute))
+
+	err = n.Scan([]byte("PT1M30.5S"))
+	expect.Nil(t, err)
+	expect.Equal(t, n, SomeDuration(90*time.Second+500*time.Millisecond))
+
+	err = n.Scan("1500")
+	expect.Nil(t, err)
+	expect.Equal(t, n, SomeDuration(1500))
+
+	err = n.Scan("bogus")
+	expect.NotNil(t, err)
+	expect.False(t, n.Valid)
+
+	err = n.Scan(3.5)
+	expect.NotNil(t, err)
+	expect.False(t, n.Valid)
+
+	err = n.Scan(nil)
+	expect.Nil(t, err)
+	expect.Equal(t, n, NoDuration)
+
+	val, err := SomeDuration(time.Second).Value()
+	expect.Nil(t, err)
+	expect.Equal(t, val, int64(time.Second))
+
+	val, err = NoDuration.Value()
+	expect.Nil(t, err)
+	expect.Nil(t, val)
+}
+
+func TestMarshalNullDuration(t *testing.T) {
+	defer func(format DurationFormat) { DurationJSONFormat = format }(DurationJSONFormat)
+
+	durations := []Duration{SomeDuration(90*time.Minute + 500*time.Millisecond), SomeDuration(0), NoDuration}
+	examples := []struct {
+		Format DurationFormat
+		Result string
+	}{
+		{DurationString, `["1h30m0.5s","0s",null]`},
+		{DurationISO8601, `["PT1H30M0.5S","PT0S",null]`},
+		{DurationMilliseconds, `[5400500,0,null]`},
+	}
+
+	for _, example := range examples {
+		DurationJSONFormat = example.Format
+
+		bytes, err := json.Marshal(durations)
+		expect.Nil(t, err)
+		expect.Equal(t, string(bytes), example.Result)
+
+		// each format can be unmarshaled regardless of the format setting
+		DurationJSONFormat = DurationString
+		var decoded []Duration
+		err = json.Unmarshal(bytes, &decoded)
+		expect.Nil(t, err)
+		expect.Equal(t, decoded, durations)
+	}
+}
+
+func TestUnmarshalNullDuration(t *testing.T) {
+	examples := []struct {
+		Input  string
+		Result Duration
+		Error  bool
+	}{
+		{Input: `null`, Result: NoDuration},
+		{Input: `""`, Result: NoDuration},
+		{Input: `"-2h45m"`, Result: SomeDuration(-165 * time.Minute)},
+		{Input: `"P1DT12H"`, Result: SomeDuration(36 * time.Hour)},
+		{Input: `"P2W"`, Result: SomeDuration(14 * 24 * time.Hour)},
+		{Input: `"-PT0,25S"`, Result: SomeDuration(-250 * time.Millisecond)},
+		{Input: `1500`, Result: SomeDuration(1500 * time.Millisecond)},
+		{Input: `"P"`, Error: true},
+		{Input: `"PT"`, Error: true},
+		{Input: `"P1H"`, Error: true},
+		{Input: `"PT1D"`, Error: true},
+		{Input: `"P1Y"`, Error: true},
+		{Input: `"bogus"`, Error: true},
+		{Input: `1.5`, Error: true},
+		{Input: `true`, Error: true},
+	}
+
+	for _, example := range examples {
+		var n Duration
+		err := json.Unmarshal([]byte(example.Input), &n)
+		if example.Error {
+			expect.NotNil(t, err, example.Input)
+			expect.False(t, n.Valid, example.Input)
+		} else {
+			expect.Nil(t, err, example.Input)
+			expect.Equal(t, n, example.Result, example.Input)
+		}
+	}
+}
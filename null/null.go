@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"time"
 
@@ -24,6 +25,11 @@ var (
 	NoString  String  = String{Valid: false}
 	NoFloat   Float   = Float{Valid: false}
 	NoInt     Int     = Int{Valid: false}
+	NoInt64   Int64   = Int64{Valid: false}
+	NoInt32   Int32   = Int32{Valid: false}
+	NoUint    Uint    = Uint{Valid: false}
+	NoBytes   Bytes   = Bytes{Valid: false}
+	NoJSON    JSON    = JSON{Valid: false}
 	NoTime    Time    = Time{Valid: false}
 	NoDate    Date    = Date{Valid: false}
 	NoUUID    UUID    = UUID{Valid: false}
@@ -299,6 +305,414 @@ func (n *Int) UnmarshalJSON(bytes []byte) error {
 	return nil
 }
 
+// Int64 is a nullable int64 that doesn't require an extra allocation or dereference.
+// The builting sql package has a NullInt64, but it doesn't implement json.Marshaler.
+type Int64 struct {
+	Int64 int64
+	Valid bool
+}
+
+func SomeInt64(value int64) Int64 {
+	return Int64{Int64: value, Valid: true}
+}
+
+func (n *Int64) Set(value int64) {
+	n.Valid = true
+	n.Int64 = value
+}
+
+func (n *Int64) Unset() {
+	n.Valid = false
+	n.Int64 = 0
+}
+
+// Implement sql.Scanner interface
+func (n *Int64) Scan(src interface{}) error {
+	n.Valid = false
+	n.Int64 = 0
+	i64, ok, err := scanInt64(src, "null.Int64")
+	if ok {
+		n.Set(i64)
+	}
+	return err
+}
+
+// Implement driver.Valuer interface
+func (n Int64) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	} else {
+		return n.Int64, nil
+	}
+}
+
+// Implement json.Marshaler interface
+func (n Int64) MarshalJSON() ([]byte, error) {
+	if n.Valid {
+		return json.Marshal(n.Int64)
+	} else {
+		return JsonNull, nil
+	}
+}
+
+// Implement json.Unmarshaler interface
+func (n *Int64) UnmarshalJSON(bytes []byte) error {
+	n.Valid = false
+	if bytes == nil || string(bytes) == "null" {
+		n.Int64 = 0
+		return nil
+	}
+
+	err := json.Unmarshal(bytes, &n.Int64)
+	if err != nil {
+		return err
+	}
+
+	n.Valid = true
+	return nil
+}
+
+// Int32 is a nullable int32 that doesn't require an extra allocation or dereference.
+// Scanning a value which doesn't fit in an int32 returns an error.
+type Int32 struct {
+	Int32 int32
+	Valid bool
+}
+
+func SomeInt32(value int32) Int32 {
+	return Int32{Int32: value, Valid: true}
+}
+
+func (n *Int32) Set(value int32) {
+	n.Valid = true
+	n.Int32 = value
+}
+
+func (n *Int32) Unset() {
+	n.Valid = false
+	n.Int32 = 0
+}
+
+// Implement sql.Scanner interface
+func (n *Int32) Scan(src interface{}) error {
+	n.Valid = false
+	n.Int32 = 0
+	i64, ok, err := scanInt64(src, "null.Int32")
+	if ok {
+		if i64 < math.MinInt32 || i64 > math.MaxInt32 {
+			return fmt.Errorf("null: converting driver.Value type %T (%d) to a null.Int32: value out of range", src, i64)
+		}
+		n.Set(int32(i64))
+	}
+	return err
+}
+
+// Implement driver.Valuer interface
+func (n Int32) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	} else {
+		return int64(n.Int32), nil
+	}
+}
+
+// Implement json.Marshaler interface
+func (n Int32) MarshalJSON() ([]byte, error) {
+	if n.Valid {
+		return json.Marshal(n.Int32)
+	} else {
+		return JsonNull, nil
+	}
+}
+
+// Implement json.Unmarshaler interface
+func (n *Int32) UnmarshalJSON(bytes []byte) error {
+	n.Valid = false
+	if bytes == nil || string(bytes) == "null" {
+		n.Int32 = 0
+		return nil
+	}
+
+	err := json.Unmarshal(bytes, &n.Int32)
+	if err != nil {
+		return err
+	}
+
+	n.Valid = true
+	return nil
+}
+
+// Uint is a nullable uint that doesn't require an extra allocation or dereference.
+// Scanning a negative value returns an error, and because database/sql only
+// supports int64 values, values larger than math.MaxInt64 can't be stored.
+type Uint struct {
+	Uint  uint
+	Valid bool
+}
+
+func SomeUint(value uint) Uint {
+	return Uint{Uint: value, Valid: true}
+}
+
+func (n *Uint) Set(value uint) {
+	n.Valid = true
+	n.Uint = value
+}
+
+func (n *Uint) Unset() {
+	n.Valid = false
+	n.Uint = 0
+}
+
+// Implement sql.Scanner interface
+func (n *Uint) Scan(src interface{}) error {
+	n.Valid = false
+	n.Uint = 0
+	if src == nil {
+		return nil
+	}
+	switch t := src.(type) {
+	case string:
+		u64, err := strconv.ParseUint(t, 10, strconv.IntSize)
+		if err != nil {
+			return fmt.Errorf("null: converting driver.Value type %T (%q) to a null.Uint: %v", src, t, strconvErr(err))
+		}
+		n.Set(uint(u64))
+	case []byte:
+		u64, err := strconv.ParseUint(string(t), 10, strconv.IntSize)
+		if err != nil {
+			return fmt.Errorf("null: converting driver.Value type %T (%q) to a null.Uint: %v", src, t, strconvErr(err))
+		}
+		n.Set(uint(u64))
+	case int64:
+		if t < 0 {
+			return fmt.Errorf("null: converting driver.Value type %T (%d) to a null.Uint: value out of range", src, t)
+		}
+		n.Set(uint(t))
+	case int:
+		if t < 0 {
+			return fmt.Errorf("null: converting driver.Value type %T (%d) to a null.Uint: value out of range", src, t)
+		}
+		n.Set(uint(t))
+	default:
+		return fmt.Errorf("null: converting driver.Value type %T to a null.Uint: unsupported type", src)
+	}
+	return nil
+}
+
+// Implement driver.Valuer interface
+func (n Uint) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	} else if uint64(n.Uint) > math.MaxInt64 {
+		return nil, fmt.Errorf("null: null.Uint value %d is too large for a driver.Value", n.Uint)
+	} else {
+		return int64(n.Uint), nil
+	}
+}
+
+// Implement json.Marshaler interface
+func (n Uint) MarshalJSON() ([]byte, error) {
+	if n.Valid {
+		return json.Marshal(n.Uint)
+	} else {
+		return JsonNull, nil
+	}
+}
+
+// Implement json.Unmarshaler interface
+func (n *Uint) UnmarshalJSON(bytes []byte) error {
+	n.Valid = false
+	if bytes == nil || string(bytes) == "null" {
+		n.Uint = 0
+		return nil
+	}
+
+	err := json.Unmarshal(bytes, &n.Uint)
+	if err != nil {
+		return err
+	}
+
+	n.Valid = true
+	return nil
+}
+
+// Bytes is a nullable []byte, for binary columns like bytea or blob.
+// It is encoded in json as a base64 string, like a []byte.
+type Bytes struct {
+	Bytes []byte
+	Valid bool
+}
+
+func SomeBytes(value []byte) Bytes {
+	return Bytes{Bytes: value, Valid: true}
+}
+
+func (n *Bytes) Set(value []byte) {
+	n.Valid = true
+	n.Bytes = value
+}
+
+func (n *Bytes) Unset() {
+	n.Valid = false
+	n.Bytes = nil
+}
+
+// Implement sql.Scanner interface
+func (n *Bytes) Scan(src interface{}) error {
+	n.Valid = false
+	n.Bytes = nil
+	switch t := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		// the driver may reuse the src buffer, so it must be copied
+		n.Set(append([]byte{}, t...))
+	case string:
+		n.Set([]byte(t))
+	default:
+		return errors.New("null: scan value was not a []byte, string, or nil")
+	}
+	return nil
+}
+
+// Implement driver.Valuer interface
+func (n Bytes) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	} else {
+		return n.Bytes, nil
+	}
+}
+
+// Implement json.Marshaler interface
+func (n Bytes) MarshalJSON() ([]byte, error) {
+	if n.Valid {
+		return json.Marshal(n.Bytes)
+	} else {
+		return JsonNull, nil
+	}
+}
+
+// Implement json.Unmarshaler interface
+func (n *Bytes) UnmarshalJSON(bytes []byte) error {
+	n.Valid = false
+	if bytes == nil || string(bytes) == "null" {
+		n.Bytes = nil
+		return nil
+	}
+
+	err := json.Unmarshal(bytes, &n.Bytes)
+	if err != nil {
+		return err
+	}
+
+	n.Valid = true
+	return nil
+}
+
+// JSON is a nullable json document, for json columns like jsonb in Postgres.
+// It holds the raw json, which is embedded as-is when it is encoded in json.
+type JSON struct {
+	JSON  json.RawMessage
+	Valid bool
+}
+
+func SomeJSON(value json.RawMessage) JSON {
+	return JSON{JSON: value, Valid: true}
+}
+
+func (n *JSON) Set(value json.RawMessage) {
+	n.Valid = true
+	n.JSON = value
+}
+
+func (n *JSON) Unset() {
+	n.Valid = false
+	n.JSON = nil
+}
+
+// Implement sql.Scanner interface
+func (n *JSON) Scan(src interface{}) error {
+	n.Valid = false
+	n.JSON = nil
+
+	var raw []byte
+	switch t := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		// the driver may reuse the src buffer, so it must be copied
+		raw = append([]byte{}, t...)
+	case string:
+		raw = []byte(t)
+	default:
+		return errors.New("null: scan value was not a []byte, string, or nil")
+	}
+
+	if !json.Valid(raw) {
+		return errors.New("null: scan value was not valid json")
+	}
+	n.Set(raw)
+	return nil
+}
+
+// Implement driver.Valuer interface.
+// The json is written as a string, which is accepted by both json and text columns.
+func (n JSON) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	} else {
+		return string(n.JSON), nil
+	}
+}
+
+// Implement json.Marshaler interface
+func (n JSON) MarshalJSON() ([]byte, error) {
+	if n.Valid && len(n.JSON) > 0 {
+		return n.JSON, nil
+	} else {
+		return JsonNull, nil
+	}
+}
+
+// Implement json.Unmarshaler interface
+func (n *JSON) UnmarshalJSON(bytes []byte) error {
+	n.Valid = false
+	if bytes == nil || string(bytes) == "null" {
+		n.JSON = nil
+		return nil
+	}
+
+	// the decoder may reuse the bytes, so they must be copied
+	n.Set(append(json.RawMessage{}, bytes...))
+	return nil
+}
+
+// TimeLayouts are the layouts tried (in order) when a Time is scanned from a
+// string, which some drivers return instead of a time.Time.  Fractional
+// seconds are accepted after the seconds of any layout.
+var TimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05-07", // eg. a postgres timestamptz
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// TimeLocation is the timezone of a Time scanned from a string without a timezone
+var TimeLocation = time.UTC
+
+func parseTime(src string) (time.Time, error) {
+	for _, layout := range TimeLayouts {
+		if t, err := time.ParseInLocation(layout, src, TimeLocation); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("null: could not parse %q as a time", src)
+}
+
 // Time is a nullable time.Time that doesn't require an extra allocation or dereference.
 // It supports encoding/decoding with database/sql, encoding/gob, and encoding/json.
 type Time struct {
@@ -330,13 +744,13 @@ func (n *Time) Scan(src interface{}) error {
 	switch t := src.(type) {
 	case string:
 		var err error
-		n.Time, err = time.Parse("2006-01-02 15:04:05", t)
+		n.Time, err = parseTime(t)
 		if err != nil {
 			return err
 		}
 	case []byte:
 		var err error
-		n.Time, err = time.Parse("2006-01-02 15:04:05", string(t))
+		n.Time, err = parseTime(string(t))
 		if err != nil {
 			return err
 		}
@@ -622,6 +1036,32 @@ func (n *Version) UnmarshalJSON(bytes []byte) error {
 	return nil
 }
 
+// scanInt64 converts a driver.Value to an int64, returning false if it is nil
+func scanInt64(src interface{}, typeName string) (int64, bool, error) {
+	switch t := src.(type) {
+	case nil:
+		return 0, false, nil
+	case string:
+		i64, err := strconv.ParseInt(t, 10, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("null: converting driver.Value type %T (%q) to a %s: %v", src, t, typeName, strconvErr(err))
+		}
+		return i64, true, nil
+	case []byte:
+		i64, err := strconv.ParseInt(string(t), 10, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("null: converting driver.Value type %T (%q) to a %s: %v", src, t, typeName, strconvErr(err))
+		}
+		return i64, true, nil
+	case int64:
+		return t, true, nil
+	case int:
+		return int64(t), true, nil
+	default:
+		return 0, false, fmt.Errorf("null: converting driver.Value type %T to a %s: unsupported type", src, typeName)
+	}
+}
+
 // copied from database/sql/convert.go
 func strconvErr(err error) error {
 	if ne, ok := err.(*strconv.NumError); ok {
@@ -6,6 +6,7 @@ import (
 	"database/sql/driver"
 	"encoding/gob"
 	"encoding/json"
+	"strconv"
 	"testing"
 	"time"
 
@@ -14,68 +15,18 @@ import (
 	"github.com/reflexionhealth/vanilla/uuid"
 )
 
-func TestImplementsJsonMarshaller(t *testing.T) {
-	var marshaler json.Marshaler
-	marshaler = Date{}
-	expect.NotNil(t, marshaler)
-	marshaler = Time{}
-	expect.NotNil(t, marshaler)
-	marshaler = String{}
-	expect.NotNil(t, marshaler)
-	marshaler = Int{}
-	expect.NotNil(t, marshaler)
-	marshaler = Bool{}
-	expect.NotNil(t, marshaler)
-	marshaler = UUID{}
-	expect.NotNil(t, marshaler)
-}
-
-func TestImplementsJsonUnmarshaller(t *testing.T) {
-	var unmarshaler json.Unmarshaler
-	unmarshaler = &Date{}
-	expect.NotNil(t, unmarshaler)
-	unmarshaler = &Time{}
-	expect.NotNil(t, unmarshaler)
-	unmarshaler = &String{}
-	expect.NotNil(t, unmarshaler)
-	unmarshaler = &Int{}
-	expect.NotNil(t, unmarshaler)
-	unmarshaler = &Bool{}
-	expect.NotNil(t, unmarshaler)
-	unmarshaler = &UUID{}
-	expect.NotNil(t, unmarshaler)
-}
-
-func TestImplementsSqlValuer(t *testing.T) {
-	var valuer driver.Valuer
-	valuer = Date{}
-	expect.NotNil(t, valuer)
-	valuer = Time{}
-	expect.NotNil(t, valuer)
-	valuer = String{}
-	expect.NotNil(t, valuer)
-	valuer = Int{}
-	expect.NotNil(t, valuer)
-	valuer = Bool{}
-	expect.NotNil(t, valuer)
-	valuer = UUID{}
-	expect.NotNil(t, valuer)
-}
+func TestImplementsInterfaces(t *testing.T) {
+	values := []interface{}{Date{}, Time{}, String{}, Int{}, Int64{}, Int32{}, Uint{}, Bytes{}, JSON{}, Bool{}, UUID{}}
+	for _, value := range values {
+		expect.Implements(t, (*json.Marshaler)(nil), value)
+		expect.Implements(t, (*driver.Valuer)(nil), value)
+	}
 
-func TestImplementSqlScanner(t *testing.T) {
-	var scanner sql.Scanner
-	scanner = &Date{}
-	expect.NotNil(t, scanner)
-	scanner = &Time{}
-	expect.NotNil(t, scanner)
-	scanner = &String{}
-	expect.NotNil(t, scanner)
-	scanner = &Int{}
-	expect.NotNil(t, scanner)
-	scanner = &Bool{}
-	expect.NotNil(t, scanner)
-	scanner = &UUID{}
-	expect.NotNil(t, scanner)
+	pointers := []interface{}{&Date{}, &Time{}, &String{}, &Int{}, &Int64{}, &Int32{}, &Uint{}, &Bytes{}, &JSON{}, &Bool{}, &UUID{}}
+	for _, pointer := range pointers {
+		expect.Implements(t, (*json.Unmarshaler)(nil), pointer)
+		expect.Implements(t, (*sql.Scanner)(nil), pointer)
+	}
 }
 
 func TestGobEncodeDecode(t *testing.T) {
@@ -89,7 +40,7 @@ func TestGobEncodeDecode(t *testing.T) {
 	buf.Reset()
 
 	var destTime, srcTime Time
-	srcTime.Set(time.Now())
+	srcTime.Set(time.Now().Round(0)) // strip the monotonic clock reading, which isn't encoded
 	expect.Nil(t, gob.NewEncoder(&buf).Encode(srcTime))
 	expect.Nil(t, gob.NewDecoder(&buf).Decode(&destTime))
 	expect.Equal(t, destTime, srcTime)
@@ -109,6 +60,27 @@ func TestGobEncodeDecode(t *testing.T) {
 	expect.Equal(t, destInt, srcInt)
 	buf.Reset()
 
+	var destInt64, srcInt64 Int64
+	srcInt64.Set(-1 << 40)
+	expect.Nil(t, gob.NewEncoder(&buf).Encode(srcInt64))
+	expect.Nil(t, gob.NewDecoder(&buf).Decode(&destInt64))
+	expect.Equal(t, destInt64, srcInt64)
+	buf.Reset()
+
+	var destInt32, srcInt32 Int32
+	srcInt32.Set(-154)
+	expect.Nil(t, gob.NewEncoder(&buf).Encode(srcInt32))
+	expect.Nil(t, gob.NewDecoder(&buf).Decode(&destInt32))
+	expect.Equal(t, destInt32, srcInt32)
+	buf.Reset()
+
+	var destUint, srcUint Uint
+	srcUint.Set(154)
+	expect.Nil(t, gob.NewEncoder(&buf).Encode(srcUint))
+	expect.Nil(t, gob.NewDecoder(&buf).Decode(&destUint))
+	expect.Equal(t, destUint, srcUint)
+	buf.Reset()
+
 	var destBool, srcBool Bool
 	srcBool.Set(true)
 	expect.Nil(t, gob.NewEncoder(&buf).Encode(srcBool))
@@ -224,6 +196,126 @@ func TestUnmarshalNullInt(t *testing.T) {
 	expect.Equal(t, n.Int, 1602525)
 }
 
+func TestUnmarshalNullSizedInts(t *testing.T) {
+	var jsonNull string = `null`
+	var bigPositive string = `4294967296`
+	var validNegative string = `-300`
+
+	var n64 Int64
+	var err error
+	err = json.Unmarshal([]byte(jsonNull), &n64)
+	expect.Nil(t, err)
+	expect.False(t, n64.Valid)
+
+	err = json.Unmarshal([]byte(bigPositive), &n64)
+	expect.Nil(t, err)
+	expect.True(t, n64.Valid)
+	expect.Equal(t, n64.Int64, int64(4294967296))
+
+	var n32 Int32
+	err = json.Unmarshal([]byte(bigPositive), &n32)
+	expect.NotNil(t, err)
+	expect.False(t, n32.Valid)
+
+	err = json.Unmarshal([]byte(validNegative), &n32)
+	expect.Nil(t, err)
+	expect.True(t, n32.Valid)
+	expect.Equal(t, n32.Int32, int32(-300))
+
+	var nu Uint
+	err = json.Unmarshal([]byte(validNegative), &nu)
+	expect.NotNil(t, err)
+	expect.False(t, nu.Valid)
+
+	err = json.Unmarshal([]byte(bigPositive), &nu)
+	expect.Nil(t, err)
+	expect.True(t, nu.Valid)
+	expect.Equal(t, nu.Uint, uint(4294967296))
+
+	bytes, err := json.Marshal([]interface{}{SomeInt64(-3), NoInt64, SomeInt32(7), SomeUint(9), NoUint})
+	expect.Nil(t, err)
+	expect.Equal(t, string(bytes), `[-3,null,7,9,null]`)
+}
+
+func TestMarshalNullJSON(t *testing.T) {
+	type document struct {
+		Settings JSON
+		Avatar   Bytes
+	}
+
+	bytes, err := json.Marshal(document{SomeJSON(json.RawMessage(`{"theme":"dark"}`)), SomeBytes([]byte("gif"))})
+	expect.Nil(t, err)
+	expect.Equal(t, string(bytes), `{"Settings":{"theme":"dark"},"Avatar":"Z2lm"}`)
+
+	bytes, err = json.Marshal(document{})
+	expect.Nil(t, err)
+	expect.Equal(t, string(bytes), `{"Settings":null,"Avatar":null}`)
+
+	var doc document
+	err = json.Unmarshal([]byte(`{"Settings": [1, 2, {"three": 3}], "Avatar": "Z2lm"}`), &doc)
+	expect.Nil(t, err)
+	expect.Equal(t, doc.Settings, SomeJSON(json.RawMessage(`[1, 2, {"three": 3}]`)))
+	expect.Equal(t, doc.Avatar, SomeBytes([]byte("gif")))
+
+	err = json.Unmarshal([]byte(`{"Settings": null, "Avatar": null}`), &doc)
+	expect.Nil(t, err)
+	expect.Equal(t, doc.Settings, NoJSON)
+	expect.Equal(t, doc.Avatar, NoBytes)
+
+	err = json.Unmarshal([]byte(`{"Avatar": 3}`), &doc)
+	expect.NotNil(t, err)
+}
+
+func TestScanNullJSON(t *testing.T) {
+	src := []byte(`{"theme": "dark"}`)
+
+	var n JSON
+	var err error
+	err = n.Scan(src)
+	expect.Nil(t, err)
+	expect.True(t, n.Valid)
+	src[2] = 'X' // the scanned value must not share the driver's buffer
+	expect.Equal(t, string(n.JSON), `{"theme": "dark"}`)
+
+	err = n.Scan(`[1, 2]`)
+	expect.Nil(t, err)
+	expect.Equal(t, n, SomeJSON(json.RawMessage(`[1, 2]`)))
+
+	err = n.Scan(`{"theme":`)
+	expect.NotNil(t, err)
+	expect.False(t, n.Valid)
+
+	err = n.Scan(3)
+	expect.NotNil(t, err)
+	expect.False(t, n.Valid)
+
+	err = n.Scan(nil)
+	expect.Nil(t, err)
+	expect.Equal(t, n, NoJSON)
+
+	val, err := SomeJSON(json.RawMessage(`[1, 2]`)).Value()
+	expect.Nil(t, err)
+	expect.Equal(t, val, `[1, 2]`)
+
+	val, err = NoJSON.Value()
+	expect.Nil(t, err)
+	expect.Nil(t, val)
+
+	var b Bytes
+	err = b.Scan(src)
+	expect.Nil(t, err)
+	src[2] = 't'
+	expect.Equal(t, string(b.Bytes), `{"Xheme": "dark"}`)
+
+	err = b.Scan(nil)
+	expect.Nil(t, err)
+	expect.Equal(t, b, NoBytes)
+
+	val, err = SomeBytes([]byte("gif")).Value()
+	expect.Nil(t, err)
+	expect.Equal(t, val, []byte("gif"))
+}
+
 func TestUnmarshalNullString(t *testing.T) {
 	var jsonNull string = `null`
 	var jsonNumber string = `3`
@@ -339,6 +431,45 @@ func TestScanNullTime(t *testing.T) {
 	expect.False(t, n.Valid)
 }
 
+func TestScanNullTimeLayouts(t *testing.T) {
+	pacific, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skip("timezone database is not available:", err)
+	}
+	eastern := time.FixedZone("", -4*60*60)
+
+	examples := []struct {
+		Input  string
+		Result time.Time
+	}{
+		{"2010-07-03T13:24:33Z", time.Date(2010, time.July, 3, 13, 24, 33, 0, time.UTC)},
+		{"2010-07-03T13:24:33.123456-04:00", time.Date(2010, time.July, 3, 13, 24, 33, 123456000, eastern)},
+		{"2010-07-03 13:24:33.5-04:00", time.Date(2010, time.July, 3, 13, 24, 33, 5e8, eastern)},
+		{"2010-07-03 13:24:33.5-04", time.Date(2010, time.July, 3, 13, 24, 33, 5e8, eastern)},
+		{"2010-07-03T13:24:33", time.Date(2010, time.July, 3, 13, 24, 33, 0, pacific)},
+		{"2010-07-03 13:24:33.25", time.Date(2010, time.July, 3, 13, 24, 33, 25e7, pacific)},
+		{"2010-07-03", time.Date(2010, time.July, 3, 0, 0, 0, 0, pacific)},
+	}
+
+	defer func(location *time.Location) { TimeLocation = location }(TimeLocation)
+	TimeLocation = pacific
+
+	for _, example := range examples {
+		var n Time
+		err := n.Scan(example.Input)
+		expect.Nil(t, err, example.Input)
+		expect.True(t, n.Valid, example.Input)
+		expect.True(t, n.Time.Equal(example.Result), example.Input, n.Time)
+	}
+
+	var n Time
+	err = n.Scan("07/03/2010")
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.Error(), `null: could not parse "07/03/2010" as a time`)
+	}
+	expect.False(t, n.Valid)
+}
+
 func TestScanNullDate(t *testing.T) {
 	var rawTime = time.Date(2010, time.July, 3, 13, 24, 33, 999, time.UTC)
 	var mysqlTime = "2010-07-03 13:24:33"
@@ -383,6 +514,70 @@ func TestScanNullDate(t *testing.T) {
 	expect.False(t, n.Valid)
 }
 
+func TestScanNullSizedInts(t *testing.T) {
+	var n64 Int64
+	var err error
+	err = n64.Scan(int64(1) << 40)
+	expect.Nil(t, err)
+	expect.Equal(t, n64, SomeInt64(1<<40))
+
+	err = n64.Scan([]byte("-42"))
+	expect.Nil(t, err)
+	expect.Equal(t, n64, SomeInt64(-42))
+
+	err = n64.Scan(nil)
+	expect.Nil(t, err)
+	expect.Equal(t, n64, NoInt64)
+
+	err = n64.Scan("bogus")
+	expect.Equal(t, err.Error(), `null: converting driver.Value type string ("bogus") to a null.Int64: invalid syntax`)
+	expect.False(t, n64.Valid)
+
+	err = n64.Scan(3.5)
+	expect.Equal(t, err.Error(), `null: converting driver.Value type float64 to a null.Int64: unsupported type`)
+
+	var n32 Int32
+	err = n32.Scan("-42")
+	expect.Nil(t, err)
+	expect.Equal(t, n32, SomeInt32(-42))
+
+	err = n32.Scan(int64(1) << 40)
+	expect.Equal(t, err.Error(), `null: converting driver.Value type int64 (1099511627776) to a null.Int32: value out of range`)
+	expect.False(t, n32.Valid)
+
+	var nu Uint
+	err = nu.Scan(int64(42))
+	expect.Nil(t, err)
+	expect.Equal(t, nu, SomeUint(42))
+
+	err = nu.Scan(int64(-42))
+	expect.Equal(t, err.Error(), `null: converting driver.Value type int64 (-42) to a null.Uint: value out of range`)
+	expect.False(t, nu.Valid)
+
+	err = nu.Scan([]byte("-42"))
+	expect.NotNil(t, err)
+	expect.False(t, nu.Valid)
+}
+
+func TestValueNullSizedInts(t *testing.T) {
+	val, err := SomeInt32(-3).Value()
+	expect.Nil(t, err)
+	expect.Equal(t, val, int64(-3))
+
+	val, err = SomeUint(3).Value()
+	expect.Nil(t, err)
+	expect.Equal(t, val, int64(3))
+
+	val, err = NoUint.Value()
+	expect.Nil(t, err)
+	expect.Nil(t, val)
+
+	if strconv.IntSize == 64 {
+		_, err = SomeUint(^uint(0)).Value()
+		expect.NotNil(t, err)
+	}
+}
+
 func TestScanNullUUID(t *testing.T) {
 	// start with a null UUID and scan a typical UUID
 	{
@@ -0,0 +1,329 @@
+package null
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/date"
+	"github.com/reflexionhealth/vanilla/semver"
+	"github.com/reflexionhealth/vanilla/uuid"
+)
+
+// The null types implement encoding.TextMarshaler and encoding.TextUnmarshaler
+// (eg. for csv files and url queries), where null values are empty text.
+// Unmarshaling empty text always produces a null value, even for String.
+//
+// They also implement fmt.Stringer using the same text, except for String
+// which can't have a String method because it has a String field.
+
+func (n Bool) String() string {
+	if !n.Valid {
+		return ""
+	}
+	return strconv.FormatBool(n.Bool)
+}
+
+// Implement encoding.TextMarshaler interface
+func (n Bool) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+}
+
+// Implement encoding.TextUnmarshaler interface
+func (n *Bool) UnmarshalText(text []byte) error {
+	n.Unset()
+	if len(text) == 0 {
+		return nil
+	}
+
+	value, err := strconv.ParseBool(string(text))
+	if err != nil {
+		return err
+	}
+	n.Set(value)
+	return nil
+}
+
+// Implement encoding.TextMarshaler interface
+func (n String) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return []byte{}, nil
+	}
+	return []byte(n.String), nil
+}
+
+// Implement encoding.TextUnmarshaler interface
+func (n *String) UnmarshalText(text []byte) error {
+	n.Unset()
+	if len(text) > 0 {
+		n.Set(string(text))
+	}
+	return nil
+}
+
+func (n Float) String() string {
+	if !n.Valid {
+		return ""
+	}
+	return strconv.FormatFloat(n.Float, 'g', -1, 64)
+}
+
+// Implement encoding.TextMarshaler interface
+func (n Float) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+}
+
+// Implement encoding.TextUnmarshaler interface
+func (n *Float) UnmarshalText(text []byte) error {
+	n.Unset()
+	if len(text) == 0 {
+		return nil
+	}
+
+	value, err := strconv.ParseFloat(string(text), 64)
+	if err != nil {
+		return err
+	}
+	n.Set(value)
+	return nil
+}
+
+func (n Int) String() string {
+	if !n.Valid {
+		return ""
+	}
+	return strconv.Itoa(n.Int)
+}
+
+// Implement encoding.TextMarshaler interface
+func (n Int) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+}
+
+// Implement encoding.TextUnmarshaler interface
+func (n *Int) UnmarshalText(text []byte) error {
+	n.Unset()
+	if len(text) == 0 {
+		return nil
+	}
+
+	value, err := strconv.Atoi(string(text))
+	if err != nil {
+		return err
+	}
+	n.Set(value)
+	return nil
+}
+
+func (n Int64) String() string {
+	if !n.Valid {
+		return ""
+	}
+	return strconv.FormatInt(n.Int64, 10)
+}
+
+// Implement encoding.TextMarshaler interface
+func (n Int64) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+}
+
+// Implement encoding.TextUnmarshaler interface
+func (n *Int64) UnmarshalText(text []byte) error {
+	n.Unset()
+	if len(text) == 0 {
+		return nil
+	}
+
+	value, err := strconv.ParseInt(string(text), 10, 64)
+	if err != nil {
+		return err
+	}
+	n.Set(value)
+	return nil
+}
+
+func (n Int32) String() string {
+	if !n.Valid {
+		return ""
+	}
+	return strconv.FormatInt(int64(n.Int32), 10)
+}
+
+// Implement encoding.TextMarshaler interface
+func (n Int32) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+}
+
+// Implement encoding.TextUnmarshaler interface
+func (n *Int32) UnmarshalText(text []byte) error {
+	n.Unset()
+	if len(text) == 0 {
+		return nil
+	}
+
+	value, err := strconv.ParseInt(string(text), 10, 32)
+	if err != nil {
+		return err
+	}
+	n.Set(int32(value))
+	return nil
+}
+
+func (n Uint) String() string {
+	if !n.Valid {
+		return ""
+	}
+	return strconv.FormatUint(uint64(n.Uint), 10)
+}
+
+// Implement encoding.TextMarshaler interface
+func (n Uint) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+}
+
+// Implement encoding.TextUnmarshaler interface
+func (n *Uint) UnmarshalText(text []byte) error {
+	n.Unset()
+	if len(text) == 0 {
+		return nil
+	}
+
+	value, err := strconv.ParseUint(string(text), 10, strconv.IntSize)
+	if err != nil {
+		return err
+	}
+	n.Set(uint(value))
+	return nil
+}
+
+func (n Duration) String() string {
+	if !n.Valid {
+		return ""
+	}
+	return n.Duration.String()
+}
+
+// Implement encoding.TextMarshaler interface
+func (n Duration) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+}
+
+// Implement encoding.TextUnmarshaler interface
+func (n *Duration) UnmarshalText(text []byte) error {
+	n.Unset()
+	if len(text) == 0 {
+		return nil
+	}
+	return n.parse(string(text))
+}
+
+// String formats the time like time.RFC3339Nano
+func (n Time) String() string {
+	if !n.Valid {
+		return ""
+	}
+	return n.Time.Format(time.RFC3339Nano)
+}
+
+// Implement encoding.TextMarshaler interface
+func (n Time) MarshalText() ([]byte, error) {
+	if !n.Valid {
+		return []byte{}, nil
+	}
+	return n.Time.MarshalText()
+}
+
+// Implement encoding.TextUnmarshaler interface
+func (n *Time) UnmarshalText(text []byte) error {
+	n.Unset()
+	if len(text) == 0 {
+		return nil
+	}
+
+	var value time.Time
+	if err := value.UnmarshalText(text); err != nil {
+		return err
+	}
+	n.Set(value)
+	return nil
+}
+
+func (n Date) String() string {
+	if !n.Valid {
+		return ""
+	}
+	return n.Date.String()
+}
+
+// Implement encoding.TextMarshaler interface
+func (n Date) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+}
+
+// Implement encoding.TextUnmarshaler interface
+func (n *Date) UnmarshalText(text []byte) error {
+	n.Unset()
+	if len(text) == 0 {
+		return nil
+	}
+
+	value, err := date.Parse(date.RFC3339, string(text))
+	if err != nil {
+		return err
+	}
+	n.Set(value)
+	return nil
+}
+
+func (n UUID) String() string {
+	if !n.Valid {
+		return ""
+	}
+	return n.UUID.String()
+}
+
+// Implement encoding.TextMarshaler interface
+func (n UUID) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+}
+
+// Implement encoding.TextUnmarshaler interface
+func (n *UUID) UnmarshalText(text []byte) error {
+	n.Unset()
+	if len(text) == 0 {
+		return nil
+	}
+
+	var value uuid.UUID
+	if err := value.UnmarshalText(text); err != nil {
+		return err
+	}
+	n.Set(value)
+	return nil
+}
+
+func (n Version) String() string {
+	if !n.Valid {
+		return ""
+	}
+	return n.Version.String()
+}
+
+// Implement encoding.TextMarshaler interface
+func (n Version) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+}
+
+// Implement encoding.TextUnmarshaler interface
+func (n *Version) UnmarshalText(text []byte) error {
+	n.Unset()
+	if len(text) == 0 {
+		return nil
+	}
+
+	var value semver.Version
+	if err := value.UnmarshalText(text); err != nil {
+		return err
+	}
+	n.Set(value)
+	return nil
+}
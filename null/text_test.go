@@ -0,0 +1,120 @@
+package null
+
+import (
+	"encoding"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/date"
+	"github.com/reflexionhealth/vanilla/expect"
+	"github.com/reflexionhealth/vanilla/semver"
+	"github.com/reflexionhealth/vanilla/uuid"
+)
+
+type textValue interface {
+	encoding.TextMarshaler
+	encoding.TextUnmarshaler
+}
+
+func TestMarshalText(t *testing.T) {
+	examples := []struct {
+		Value textValue
+		Text  string
+	}{
+		{&Bool{Bool: true, Valid: true}, "true"},
+		{&String{String: "muppet", Valid: true}, "muppet"},
+		{&Float{Float: 2.5, Valid: true}, "2.5"},
+		{&Int{Int: -3, Valid: true}, "-3"},
+		{&Int64{Int64: 1 << 40, Valid: true}, "1099511627776"},
+		{&Int32{Int32: -32, Valid: true}, "-32"},
+		{&Uint{Uint: 32, Valid: true}, "32"},
+		{&Duration{Duration: 90 * time.Second, Valid: true}, "1m30s"},
+		{&Time{Time: time.Date(2010, time.July, 3, 13, 24, 33, 5e8, time.UTC), Valid: true}, "2010-07-03T13:24:33.5Z"},
+		{&Date{Date: date.At(2010, time.July, 3, time.UTC), Valid: true}, "2010-07-03"},
+		{&UUID{UUID: uuid.UUID{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}, Valid: true},
+			"6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+		{&Version{Version: semver.Version{Major: 1, Minor: 2, Patch: 3}, Valid: true}, "1.2.3"},
+
+		// null values are empty
+		{&Bool{}, ""},
+		{&String{}, ""},
+		{&Float{}, ""},
+		{&Int{}, ""},
+		{&Int64{}, ""},
+		{&Int32{}, ""},
+		{&Uint{}, ""},
+		{&Duration{}, ""},
+		{&Time{}, ""},
+		{&Date{}, ""},
+		{&UUID{}, ""},
+		{&Version{}, ""},
+	}
+
+	for _, example := range examples {
+		text, err := example.Value.MarshalText()
+		expect.Nil(t, err)
+		expect.Equal(t, string(text), example.Text, example.Value)
+		if stringer, ok := example.Value.(fmt.Stringer); ok {
+			expect.Equal(t, stringer.String(), example.Text, example.Value)
+		}
+
+		// unmarshaling the text produces the same value
+		decoded := newZero(example.Value)
+		err = decoded.UnmarshalText(text)
+		expect.Nil(t, err, example.Text)
+		expect.Equal(t, decoded, example.Value, example.Text)
+	}
+}
+
+// newZero returns a pointer to an empty value of the same type as v
+func newZero(v textValue) textValue {
+	switch v.(type) {
+	case *Bool:
+		return &Bool{}
+	case *String:
+		return &String{}
+	case *Float:
+		return &Float{}
+	case *Int:
+		return &Int{}
+	case *Int64:
+		return &Int64{}
+	case *Int32:
+		return &Int32{}
+	case *Uint:
+		return &Uint{}
+	case *Duration:
+		return &Duration{}
+	case *Time:
+		return &Time{}
+	case *Date:
+		return &Date{}
+	case *UUID:
+		return &UUID{}
+	case *Version:
+		return &Version{}
+	default:
+		panic(fmt.Sprintf("unexpected type %T", v))
+	}
+}
+
+func TestUnmarshalTextErrors(t *testing.T) {
+	invalid := []textValue{&Bool{}, &Float{}, &Int{}, &Int64{}, &Int32{}, &Uint{}, &Duration{}, &Time{}, &Date{}, &UUID{}, &Version{}}
+	for _, value := range invalid {
+		err := value.UnmarshalText([]byte("bogus"))
+		expect.NotNil(t, err, fmt.Sprintf("%T", value))
+	}
+
+	n := SomeInt32(3)
+	expect.NotNil(t, n.UnmarshalText([]byte("4294967296")))
+	expect.Equal(t, n, NoInt32)
+
+	s := SomeString("muppet")
+	expect.Nil(t, s.UnmarshalText([]byte{}))
+	expect.Equal(t, s, NoString)
+}
+
+func TestFormatPrintsText(t *testing.T) {
+	expect.Equal(t, fmt.Sprintf("%v,%v,%v", SomeInt(3), NoInt, SomeDate(date.At(2010, time.July, 3, nil))), "3,,2010-07-03")
+}
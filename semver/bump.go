@@ -0,0 +1,43 @@
+package semver
+
+import (
+	"strconv"
+	"strings"
+)
+
+// IncMajor returns the next major version, eg. 1.2.3 => 2.0.0
+func (v Version) IncMajor() Version {
+	return Version{Major: v.Major + 1}
+}
+
+// IncMinor returns the next minor version, eg. 1.2.3 => 1.3.0
+func (v Version) IncMinor() Version {
+	return Version{Major: v.Major, Minor: v.Minor + 1}
+}
+
+// IncPatch returns the next patch version, eg. 1.2.3 => 1.2.4
+func (v Version) IncPatch() Version {
+	return Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1}
+}
+
+// NextPrerelease returns the next prerelease with the given label:
+//
+//     1.2.3        => 1.2.4-rc.1  // starts a prerelease of the next patch
+//     1.2.4-beta.2 => 1.2.4-rc.1  // starts a new label for the same version
+//     1.2.4-rc     => 1.2.4-rc.1
+//     1.2.4-rc.1   => 1.2.4-rc.2
+//
+func (v Version) NextPrerelease(label string) Version {
+	if len(v.Prerelease) == 0 {
+		v = v.IncPatch()
+	}
+
+	next := Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch, Prerelease: label + ".1"}
+	if strings.HasPrefix(v.Prerelease, label+".") {
+		n, err := strconv.Atoi(v.Prerelease[len(label)+1:])
+		if err == nil {
+			next.Prerelease = label + "." + strconv.Itoa(n+1)
+		}
+	}
+	return next
+}
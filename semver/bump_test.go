@@ -0,0 +1,44 @@
+package semver
+
+import (
+	"testing"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+func TestIncrement(t *testing.T) {
+	examples := []struct {
+		Version             Version
+		Major, Minor, Patch Version
+	}{
+		{Version{0, 0, 0, "", ""}, Version{1, 0, 0, "", ""}, Version{0, 1, 0, "", ""}, Version{0, 0, 1, "", ""}},
+		{Version{1, 2, 3, "", ""}, Version{2, 0, 0, "", ""}, Version{1, 3, 0, "", ""}, Version{1, 2, 4, "", ""}},
+		{Version{1, 2, 3, "rc.1", "build.5"}, Version{2, 0, 0, "", ""}, Version{1, 3, 0, "", ""}, Version{1, 2, 4, "", ""}},
+	}
+
+	for _, ex := range examples {
+		expect.Equal(t, ex.Version.IncMajor(), ex.Major, ex.Version.String())
+		expect.Equal(t, ex.Version.IncMinor(), ex.Minor, ex.Version.String())
+		expect.Equal(t, ex.Version.IncPatch(), ex.Patch, ex.Version.String())
+	}
+}
+
+func TestNextPrerelease(t *testing.T) {
+	examples := []struct {
+		Version Version
+		Label   string
+		Next    Version
+	}{
+		{Version{1, 2, 3, "", ""}, "rc", Version{1, 2, 4, "rc.1", ""}},
+		{Version{1, 2, 3, "", "build.5"}, "rc", Version{1, 2, 4, "rc.1", ""}},
+		{Version{1, 2, 4, "beta.2", ""}, "rc", Version{1, 2, 4, "rc.1", ""}},
+		{Version{1, 2, 4, "rc", ""}, "rc", Version{1, 2, 4, "rc.1", ""}},
+		{Version{1, 2, 4, "rc.1", ""}, "rc", Version{1, 2, 4, "rc.2", ""}},
+		{Version{1, 2, 4, "rc.9", "build.5"}, "rc", Version{1, 2, 4, "rc.10", ""}},
+		{Version{1, 2, 4, "rcx.2", ""}, "rc", Version{1, 2, 4, "rc.1", ""}},
+	}
+
+	for _, ex := range examples {
+		expect.Equal(t, ex.Version.NextPrerelease(ex.Label), ex.Next, ex.Version.String())
+	}
+}
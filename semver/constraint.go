@@ -0,0 +1,279 @@
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Constraint is a set of version ranges, as parsed by ParseConstraint.
+type Constraint struct {
+	input  string
+	groups [][]comparator // the ranges, where each range is a set of comparators
+}
+
+// ParseConstraint parses a constraint from a string like "^1.2, <2.0 || ~3.1.x".
+//
+// A constraint is one or more ranges separated by "||", and a version matches
+// the constraint if it matches any range.  A range is a set of comparisons
+// separated by commas or spaces, which must all match.  Comparisons can be:
+//
+//     1.2.3          // exactly 1.2.3 (also "=1.2.3")
+//     >1.2.3         // also ">=", "<", and "<="
+//     1.2.x          // wildcards, same as ">=1.2.0, <1.3.0" (also "1.2", "1.2.*")
+//     *              // any version
+//     ^1.2.3         // compatible, same as ">=1.2.3, <2.0.0" (or "<0.3.0" for "^0.2.3")
+//     ~1.2.3         // patch updates, same as ">=1.2.3, <1.3.0"
+//     1.2.3 - 2.3    // inclusive, same as ">=1.2.3, <2.4.0"
+//
+// Versions are compared by precedence, so "<2.0.0" matches "2.0.0-rc.1".
+func ParseConstraint(input string) (Constraint, error) {
+	c := Constraint{input: input}
+	for _, group := range strings.Split(input, "||") {
+		comparators, err := parseRange(group)
+		if err != nil {
+			return Constraint{}, err
+		}
+		c.groups = append(c.groups, comparators)
+	}
+	return c, nil
+}
+
+// Check returns true if the version satisfies the constraint.
+func (c Constraint) Check(v Version) bool {
+	for _, group := range c.groups {
+		if matchRange(group, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the constraint as it was parsed.
+func (c Constraint) String() string {
+	return c.input
+}
+
+type operator int
+
+const (
+	opEqual operator = iota
+	opGreater
+	opGreaterOrEqual
+	opLess
+	opLessOrEqual
+)
+
+type comparator struct {
+	op      operator
+	version Version
+}
+
+func (cmp comparator) match(v Version) bool {
+	c := Compare(v, cmp.version)
+	switch cmp.op {
+	case opEqual:
+		return c == 0
+	case opGreater:
+		return c > 0
+	case opGreaterOrEqual:
+		return c >= 0
+	case opLess:
+		return c < 0
+	case opLessOrEqual:
+		return c <= 0
+	default:
+		panic("semver: unhandled operator")
+	}
+}
+
+func matchRange(comparators []comparator, v Version) bool {
+	for _, cmp := range comparators {
+		if !cmp.match(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseRange(input string) ([]comparator, error) {
+	terms := strings.Fields(strings.Replace(input, ",", " ", -1))
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("semver: empty range in constraint")
+	}
+
+	var comparators []comparator
+	for i := 0; i < len(terms); i++ {
+		// hyphen ranges, eg. "1.2.3 - 2.3.4"
+		if i+2 < len(terms) && terms[i+1] == "-" {
+			lower, err := parsePartial(terms[i])
+			if err != nil {
+				return nil, err
+			}
+			upper, err := parsePartial(terms[i+2])
+			if err != nil {
+				return nil, err
+			}
+			comparators = append(comparators, lower.atLeast()...)
+			comparators = append(comparators, upper.atMost()...)
+			i += 2
+			continue
+		}
+
+		expanded, err := parseComparison(terms[i])
+		if err != nil {
+			return nil, err
+		}
+		comparators = append(comparators, expanded...)
+	}
+	return comparators, nil
+}
+
+func parseComparison(term string) ([]comparator, error) {
+	op := term[:len(term)-len(strings.TrimLeft(term, "=<>^~"))]
+	p, err := parsePartial(term[len(op):])
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case "", "=":
+		return p.equal(), nil
+	case ">":
+		return p.greater(), nil
+	case ">=":
+		return p.atLeast(), nil
+	case "<":
+		return p.less(), nil
+	case "<=":
+		return p.atMost(), nil
+	case "^":
+		return p.caret(), nil
+	case "~":
+		return p.tilde(), nil
+	default:
+		return nil, fmt.Errorf("semver: unknown operator %q in constraint", op)
+	}
+}
+
+// partial is a version where the trailing components may be wildcards
+type partial struct {
+	version Version
+	parts   int // the number of components which are not wildcards
+}
+
+func parsePartial(input string) (partial, error) {
+	p := partial{}
+	rest := strings.TrimPrefix(input, "v")
+	if i := strings.IndexByte(rest, '+'); i >= 0 {
+		p.version.Build = rest[i+1:]
+		rest = rest[:i]
+	}
+	if i := strings.IndexByte(rest, '-'); i >= 0 {
+		p.version.Prerelease = rest[i+1:]
+		rest = rest[:i]
+	}
+
+	components := strings.Split(rest, ".")
+	if len(components) > 3 {
+		return p, fmt.Errorf("semver: invalid version %q in constraint", input)
+	}
+
+	numbers := []*int{&p.version.Major, &p.version.Minor, &p.version.Patch}
+	wildcard := false
+	for i, component := range components {
+		if component == "x" || component == "X" || component == "*" {
+			wildcard = true
+			continue
+		}
+
+		n, err := strconv.Atoi(component)
+		if err != nil || wildcard {
+			return p, fmt.Errorf("semver: invalid version %q in constraint", input)
+		}
+		*numbers[i] = n
+		p.parts = i + 1
+	}
+
+	if p.parts < 3 && len(p.version.Prerelease) > 0 {
+		return p, fmt.Errorf("semver: invalid version %q in constraint", input)
+	}
+	return p, nil
+}
+
+// next returns the lowest version which is higher than every version matching p
+func (p partial) next() Version {
+	switch p.parts {
+	case 1:
+		return Version{Major: p.version.Major + 1}
+	case 2:
+		return Version{Major: p.version.Major, Minor: p.version.Minor + 1}
+	default:
+		return Version{Major: p.version.Major, Minor: p.version.Minor, Patch: p.version.Patch + 1}
+	}
+}
+
+func (p partial) equal() []comparator {
+	if p.parts == 3 {
+		return []comparator{{opEqual, p.version}}
+	}
+	return append(p.atLeast(), p.atMost()...)
+}
+
+func (p partial) greater() []comparator {
+	if p.parts == 0 {
+		return []comparator{{opLess, Version{}}} // nothing is greater than "*"
+	} else if p.parts == 3 {
+		return []comparator{{opGreater, p.version}}
+	}
+	return []comparator{{opGreaterOrEqual, p.next()}}
+}
+
+func (p partial) atLeast() []comparator {
+	return []comparator{{opGreaterOrEqual, p.version}}
+}
+
+func (p partial) less() []comparator {
+	if p.parts == 0 {
+		return []comparator{{opLess, Version{}}} // nothing is less than "*"
+	}
+	return []comparator{{opLess, p.version}}
+}
+
+func (p partial) atMost() []comparator {
+	if p.parts == 0 {
+		return nil
+	} else if p.parts == 3 {
+		return []comparator{{opLessOrEqual, p.version}}
+	}
+	return []comparator{{opLess, p.next()}}
+}
+
+// caret allows changes which don't modify the left-most non-zero component
+func (p partial) caret() []comparator {
+	if p.parts == 0 {
+		return nil
+	}
+
+	upper := p
+	switch {
+	case p.version.Major > 0 || p.parts == 1:
+		upper.parts = 1
+	case p.version.Minor > 0 || p.parts == 2:
+		upper.parts = 2
+	}
+	return append(p.atLeast(), comparator{opLess, upper.next()})
+}
+
+// tilde allows patch-level changes, or minor-level changes if minor is omitted
+func (p partial) tilde() []comparator {
+	if p.parts == 0 {
+		return nil
+	}
+
+	upper := p
+	if p.parts > 2 {
+		upper.parts = 2
+	}
+	return append(p.atLeast(), comparator{opLess, upper.next()})
+}
@@ -0,0 +1,89 @@
+package semver
+
+import (
+	"testing"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+func TestConstraint(t *testing.T) {
+	examples := []struct {
+		Constraint string
+		Matches    []string
+		Excludes   []string
+	}{
+		{"1.2.3", []string{"1.2.3"}, []string{"1.2.4", "1.2.3-rc.1"}},
+		{"=1.2.3", []string{"1.2.3", "1.2.3+build.5"}, []string{"1.2.2"}},
+		{">1.2.3", []string{"1.2.4", "2.0.0"}, []string{"1.2.3", "1.0.0"}},
+		{">=1.2.3", []string{"1.2.3", "1.3.0"}, []string{"1.2.2"}},
+		{"<1.2.3", []string{"1.2.2", "0.9.0"}, []string{"1.2.3"}},
+		{"<=1.2.3", []string{"1.2.3", "1.2.0"}, []string{"1.2.4"}},
+		{">1.2", []string{"1.3.0"}, []string{"1.2.9"}},
+		{"<=1.2", []string{"1.2.9"}, []string{"1.3.0"}},
+
+		{"*", []string{"0.0.0", "5.4.3"}, nil},
+		{"1.x", []string{"1.0.0", "1.9.9"}, []string{"2.0.0", "0.9.0"}},
+		{"1.2.x", []string{"1.2.0", "1.2.9"}, []string{"1.3.0", "1.1.9"}},
+		{"1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}},
+		{"1.2.*", []string{"1.2.5"}, []string{"1.3.0"}},
+
+		{"^1.2.3", []string{"1.2.3", "1.9.0"}, []string{"1.2.2", "2.0.0"}},
+		{"^1.2", []string{"1.2.0", "1.9.0"}, []string{"1.1.9", "2.0.0"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0", "0.2.2"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4", "0.0.2"}},
+		{"^0.0", []string{"0.0.9"}, []string{"0.1.0"}},
+		{"^1.x", []string{"1.5.0"}, []string{"2.0.0"}},
+
+		{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0", "1.2.2"}},
+		{"~1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		{"~3.1.x", []string{"3.1.0", "3.1.9"}, []string{"3.2.0"}},
+
+		{"1.2.3 - 2.3.4", []string{"1.2.3", "2.3.4"}, []string{"1.2.2", "2.3.5"}},
+		{"1.2 - 2.3", []string{"1.2.0", "2.3.9"}, []string{"1.1.9", "2.4.0"}},
+
+		{">=1.2, <1.4", []string{"1.2.0", "1.3.9"}, []string{"1.4.0", "1.1.0"}},
+		{">=1.2 <1.4", []string{"1.3.0"}, []string{"1.4.0"}},
+		{"^1.2, <2.0 || ~3.1.x", []string{"1.2.0", "1.9.9", "3.1.4"}, []string{"2.0.0", "3.2.0", "1.1.0"}},
+		{"<1.0.0 || >=2.0.0", []string{"0.9.0", "2.0.0"}, []string{"1.0.0", "1.5.0"}},
+
+		{">=1.0.0-rc.1", []string{"1.0.0-rc.2", "1.0.0"}, []string{"1.0.0-beta"}},
+	}
+
+	for _, ex := range examples {
+		c, err := ParseConstraint(ex.Constraint)
+		expect.Nil(t, err, ex.Constraint)
+		expect.Equal(t, c.String(), ex.Constraint)
+		for _, s := range ex.Matches {
+			v, _ := Parse(s)
+			expect.True(t, c.Check(v), ex.Constraint+" should match "+s)
+		}
+		for _, s := range ex.Excludes {
+			v, _ := Parse(s)
+			expect.False(t, c.Check(v), ex.Constraint+" should not match "+s)
+		}
+	}
+}
+
+func TestConstraintErrors(t *testing.T) {
+	examples := []struct {
+		Constraint string
+		Error      string
+	}{
+		{"", `semver: empty range in constraint`},
+		{"1.2 ||", `semver: empty range in constraint`},
+		{"!1.2.3", `semver: invalid version "!1.2.3" in constraint`},
+		{"=>1.2.3", `semver: unknown operator "=>" in constraint`},
+		{"1.2.3.4", `semver: invalid version "1.2.3.4" in constraint`},
+		{"1.x.3", `semver: invalid version "1.x.3" in constraint`},
+		{"1.2-rc.1", `semver: invalid version "1.2-rc.1" in constraint`},
+		{"abc", `semver: invalid version "abc" in constraint`},
+	}
+
+	for _, ex := range examples {
+		_, err := ParseConstraint(ex.Constraint)
+		if expect.NotNil(t, err, ex.Constraint) {
+			expect.Equal(t, err.Error(), ex.Error, ex.Constraint)
+		}
+	}
+}
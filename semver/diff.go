@@ -0,0 +1,47 @@
+package semver
+
+// DiffLevel is the most significant component which differs between versions.
+// Levels are ordered, so DiffMinor > DiffPatch.
+type DiffLevel int
+
+const (
+	DiffNone DiffLevel = iota
+	DiffPrerelease
+	DiffPatch
+	DiffMinor
+	DiffMajor
+)
+
+func (d DiffLevel) String() string {
+	switch d {
+	case DiffNone:
+		return "none"
+	case DiffPrerelease:
+		return "prerelease"
+	case DiffPatch:
+		return "patch"
+	case DiffMinor:
+		return "minor"
+	case DiffMajor:
+		return "major"
+	default:
+		return "unknown"
+	}
+}
+
+// Diff returns the most significant component which differs between a and b.
+// Build metadata is ignored.
+func Diff(a, b Version) DiffLevel {
+	switch {
+	case a.Major != b.Major:
+		return DiffMajor
+	case a.Minor != b.Minor:
+		return DiffMinor
+	case a.Patch != b.Patch:
+		return DiffPatch
+	case a.Prerelease != b.Prerelease:
+		return DiffPrerelease
+	default:
+		return DiffNone
+	}
+}
@@ -0,0 +1,31 @@
+package semver
+
+import (
+	"testing"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+func TestDiff(t *testing.T) {
+	examples := []struct {
+		A, B  Version
+		Level DiffLevel
+	}{
+		{Version{1, 2, 3, "", ""}, Version{1, 2, 3, "", ""}, DiffNone},
+		{Version{1, 2, 3, "", "build.1"}, Version{1, 2, 3, "", "build.2"}, DiffNone},
+		{Version{1, 2, 3, "rc.1", ""}, Version{1, 2, 3, "", ""}, DiffPrerelease},
+		{Version{1, 2, 3, "rc.1", ""}, Version{1, 2, 3, "rc.2", ""}, DiffPrerelease},
+		{Version{1, 2, 3, "", ""}, Version{1, 2, 4, "", ""}, DiffPatch},
+		{Version{1, 2, 3, "", ""}, Version{1, 3, 3, "", ""}, DiffMinor},
+		{Version{1, 2, 3, "", ""}, Version{1, 3, 0, "rc.1", ""}, DiffMinor},
+		{Version{1, 2, 3, "", ""}, Version{0, 2, 3, "", ""}, DiffMajor},
+	}
+
+	for _, ex := range examples {
+		expect.Equal(t, Diff(ex.A, ex.B), ex.Level, ex.A.String()+" vs "+ex.B.String())
+		expect.Equal(t, Diff(ex.B, ex.A), ex.Level, ex.B.String()+" vs "+ex.A.String())
+	}
+
+	expect.True(t, DiffMajor > DiffMinor)
+	expect.Equal(t, DiffPatch.String(), "patch")
+}
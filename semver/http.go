@@ -0,0 +1,65 @@
+package semver
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/reflexionhealth/vanilla/httpx"
+)
+
+// FromHeader parses a client version from a request header.  The header may
+// contain a bare version (eg. "X-Client-Version: 1.2.3") or a product token
+// (eg. "User-Agent: MyApp/1.2.3 (iOS 10.0)").
+func FromHeader(r *http.Request, header string) (Version, bool) {
+	value := strings.TrimSpace(r.Header.Get(header))
+	if v, ok := Parse(value); ok {
+		return v, true
+	}
+
+	_, v, ok := ProductToken(value)
+	return v, ok
+}
+
+// ProductToken returns the name and version of the first product in a
+// User-Agent string which has a version, eg. "MyApp/1.2.3 (iOS 10.0)" returns
+// "MyApp" and 1.2.3.  Comments (in parenthesis) are skipped.
+func ProductToken(userAgent string) (name string, v Version, ok bool) {
+	depth := 0
+	for _, field := range strings.Fields(userAgent) {
+		if depth > 0 || strings.HasPrefix(field, "(") {
+			depth += strings.Count(field, "(") - strings.Count(field, ")")
+			continue
+		}
+
+		slash := strings.IndexByte(field, '/')
+		if slash <= 0 {
+			continue
+		}
+		if v, ok = Parse(field[slash+1:]); ok {
+			return field[:slash], v, true
+		}
+	}
+	return "", Version{}, false
+}
+
+// ClientVersion is the request local set by ClientVersionHandler.
+var ClientVersion = httpx.NewLocal[Version]("client version")
+
+// ClientVersionHandler returns a Handler which parses the client version from
+// the named header (see FromHeader), and puts it in the request context.
+//
+// Handlers can then gate features on the version:
+//
+//     if v, ok := semver.ClientVersion.Lookup(req.Context()); ok && v.AtLeast(minimum) {
+//
+func ClientVersionHandler(header string) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if v, ok := FromHeader(req, header); ok {
+				req = req.WithContext(ClientVersion.Put(req.Context(), v))
+			}
+
+			h.ServeHTTP(w, req)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package semver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+func TestProductToken(t *testing.T) {
+	examples := []struct {
+		UserAgent string
+		Name      string
+		Version   Version
+		NotOk     bool
+	}{
+		{UserAgent: "MyApp/1.2.3", Name: "MyApp", Version: Version{1, 2, 3, "", ""}},
+		{UserAgent: "MyApp/1.2.3-rc.1 (iOS 10.0; iPhone)", Name: "MyApp", Version: Version{1, 2, 3, "rc.1", ""}},
+		{UserAgent: "(Windows NT 10.0; x64) MyApp/2.0 CFNetwork/808.2.16", Name: "MyApp", Version: Version{2, 0, 0, "", ""}},
+		{UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_12_1)", Name: "Mozilla", Version: Version{5, 0, 0, "", ""}},
+		{UserAgent: "MyApp/unknown Other/3.1", Name: "Other", Version: Version{3, 1, 0, "", ""}},
+		{UserAgent: "MyApp (build 1/2)", NotOk: true},
+		{UserAgent: "", NotOk: true},
+	}
+
+	for _, ex := range examples {
+		name, v, ok := ProductToken(ex.UserAgent)
+		expect.Equal(t, name, ex.Name, ex.UserAgent)
+		expect.Equal(t, v, ex.Version, ex.UserAgent)
+		expect.Equal(t, !ok, ex.NotOk, ex.UserAgent)
+	}
+}
+
+func TestFromHeader(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.Header.Set("X-Client-Version", " 1.4.0 ")
+	req.Header.Set("User-Agent", "MyApp/1.2.3 (iOS 10.0)")
+
+	v, ok := FromHeader(req, "X-Client-Version")
+	expect.True(t, ok)
+	expect.Equal(t, v, Version{1, 4, 0, "", ""})
+	v, ok = FromHeader(req, "User-Agent")
+	expect.True(t, ok)
+	expect.Equal(t, v, Version{1, 2, 3, "", ""})
+	_, ok = FromHeader(req, "X-Missing")
+	expect.False(t, ok)
+}
+
+func TestClientVersionHandler(t *testing.T) {
+	var v Version
+	var ok bool
+	handler := ClientVersionHandler("User-Agent")(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		v, ok = ClientVersion.Lookup(req.Context())
+	}))
+
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.Header.Set("User-Agent", "MyApp/2.1.0")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	expect.True(t, ok)
+	expect.Equal(t, v, Version{2, 1, 0, "", ""})
+
+	req.Header.Set("User-Agent", "curl")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	expect.False(t, ok)
+}
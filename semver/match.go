@@ -0,0 +1,34 @@
+package semver
+
+// Match returns true if the version matches a pattern with wildcards,
+// eg. "2.x" matches any 2.*.* version and "1.2.*" any 1.2.* version.
+//
+// Unlike a Constraint, components are compared one by one, so "2.x" matches
+// "2.1.0-rc.1" but not "3.0.0-rc.1".  Invalid patterns never match.
+func Match(pattern string, v Version) bool {
+	p, err := parsePartial(pattern)
+	if err != nil {
+		return false
+	}
+
+	switch p.parts {
+	case 0:
+		return true
+	case 1:
+		return v.SameMajor(p.version)
+	case 2:
+		return v.SameMinorAs(p.version)
+	default:
+		return v.Equal(p.version)
+	}
+}
+
+// SameMajor returns true if both versions have the same major version.
+func (v Version) SameMajor(o Version) bool {
+	return v.Major == o.Major
+}
+
+// SameMinorAs returns true if both versions have the same major and minor version.
+func (v Version) SameMinorAs(o Version) bool {
+	return v.Major == o.Major && v.Minor == o.Minor
+}
@@ -0,0 +1,46 @@
+package semver
+
+import (
+	"testing"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+func TestMatch(t *testing.T) {
+	examples := []struct {
+		Pattern  string
+		Matches  []string
+		Excludes []string
+	}{
+		{"*", []string{"0.0.0", "2.1.0-rc.1"}, nil},
+		{"x.x.x", []string{"3.4.5"}, nil},
+		{"2", []string{"2.0.0", "2.9.9"}, []string{"1.9.9", "3.0.0"}},
+		{"2.x", []string{"2.0.0", "2.1.0-rc.1"}, []string{"3.0.0-rc.1", "1.0.0"}},
+		{"1.2.x", []string{"1.2.0", "1.2.9"}, []string{"1.3.0", "1.1.0"}},
+		{"1.2.*", []string{"1.2.3"}, []string{"2.2.3"}},
+		{"1.2.3", []string{"1.2.3", "1.2.3+build.5"}, []string{"1.2.3-rc.1", "1.2.4"}},
+		{"1.2.3-rc.1", []string{"1.2.3-rc.1"}, []string{"1.2.3"}},
+		{"bogus", nil, []string{"0.0.0", "1.0.0"}},
+		{"1.x.3", nil, []string{"1.0.3"}},
+	}
+
+	for _, ex := range examples {
+		for _, s := range ex.Matches {
+			v, _ := Parse(s)
+			expect.True(t, Match(ex.Pattern, v), ex.Pattern+" should match "+s)
+		}
+		for _, s := range ex.Excludes {
+			v, _ := Parse(s)
+			expect.False(t, Match(ex.Pattern, v), ex.Pattern+" should not match "+s)
+		}
+	}
+}
+
+func TestSamePartial(t *testing.T) {
+	a := Version{2, 1, 0, "", ""}
+	expect.True(t, a.SameMajor(Version{2, 5, 3, "", ""}))
+	expect.False(t, a.SameMajor(Version{3, 1, 0, "", ""}))
+	expect.True(t, a.SameMinorAs(Version{2, 1, 9, "rc.1", ""}))
+	expect.False(t, a.SameMinorAs(Version{2, 2, 0, "", ""}))
+	expect.False(t, a.SameMinorAs(Version{1, 1, 0, "", ""}))
+}
@@ -0,0 +1,40 @@
+package semver
+
+import "sort"
+
+// Versions is a slice of versions which implements sort.Interface,
+// sorting from lowest to highest precedence.
+type Versions []Version
+
+func (vs Versions) Len() int           { return len(vs) }
+func (vs Versions) Less(i, j int) bool { return vs[i].LessThan(vs[j]) }
+func (vs Versions) Swap(i, j int)      { vs[i], vs[j] = vs[j], vs[i] }
+
+// Sort sorts versions from lowest to highest precedence.
+func Sort(versions []Version) {
+	sort.Sort(Versions(versions))
+}
+
+// Max returns the version with the highest precedence,
+// or the zero Version if no versions are given.
+func Max(versions ...Version) Version {
+	var max Version
+	for i, v := range versions {
+		if i == 0 || v.GreaterThan(max) {
+			max = v
+		}
+	}
+	return max
+}
+
+// Min returns the version with the lowest precedence,
+// or the zero Version if no versions are given.
+func Min(versions ...Version) Version {
+	var min Version
+	for i, v := range versions {
+		if i == 0 || v.LessThan(min) {
+			min = v
+		}
+	}
+	return min
+}
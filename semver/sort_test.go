@@ -0,0 +1,48 @@
+package semver
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+func TestSort(t *testing.T) {
+	versions := []Version{
+		{1, 0, 0, "", ""},
+		{0, 9, 1, "", ""},
+		{1, 0, 0, "rc.1", ""},
+		{2, 1, 0, "", ""},
+		{1, 0, 0, "beta", ""},
+		{0, 10, 0, "", ""},
+	}
+
+	Sort(versions)
+	expect.Equal(t, versions, []Version{
+		{0, 9, 1, "", ""},
+		{0, 10, 0, "", ""},
+		{1, 0, 0, "beta", ""},
+		{1, 0, 0, "rc.1", ""},
+		{1, 0, 0, "", ""},
+		{2, 1, 0, "", ""},
+	})
+
+	sort.Sort(sort.Reverse(Versions(versions)))
+	expect.Equal(t, versions[0], Version{2, 1, 0, "", ""})
+	expect.Equal(t, versions[5], Version{0, 9, 1, "", ""})
+}
+
+func TestMaxMin(t *testing.T) {
+	versions := []Version{
+		{1, 0, 0, "rc.1", ""},
+		{0, 9, 1, "", ""},
+		{1, 0, 0, "", ""},
+		{1, 0, 0, "beta", ""},
+	}
+	expect.Equal(t, Max(versions...), Version{1, 0, 0, "", ""})
+	expect.Equal(t, Min(versions...), Version{0, 9, 1, "", ""})
+	expect.Equal(t, Max(versions[0], versions[3]), Version{1, 0, 0, "rc.1", ""})
+	expect.Equal(t, Min(versions[0], versions[3]), Version{1, 0, 0, "beta", ""})
+	expect.Equal(t, Max(), Version{})
+	expect.Equal(t, Min(), Version{})
+}
@@ -0,0 +1,103 @@
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseError describes why a version string is not valid SemVer.
+type ParseError struct {
+	Input  string
+	Reason string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("semver: invalid version %q: %s", e.Input, e.Reason)
+}
+
+// ParseStrict parses a version which must match the SemVer 2.0 grammar exactly,
+// eg. "1.2.3" or "1.2.3-rc.1+build.5".  Unlike Parse, it rejects a "v" prefix,
+// missing components, leading zeros, and trailing characters.
+func ParseStrict(input string) (Version, error) {
+	var v Version
+	rest := input
+	if i := strings.IndexByte(rest, '+'); i >= 0 {
+		v.Build = rest[i+1:]
+		rest = rest[:i]
+		if reason := checkIdentifiers(v.Build, false); len(reason) > 0 {
+			return Version{}, &ParseError{input, "build " + reason}
+		}
+	}
+	if i := strings.IndexByte(rest, '-'); i >= 0 {
+		v.Prerelease = rest[i+1:]
+		rest = rest[:i]
+		if reason := checkIdentifiers(v.Prerelease, true); len(reason) > 0 {
+			return Version{}, &ParseError{input, "prerelease " + reason}
+		}
+	}
+
+	components := strings.Split(rest, ".")
+	if len(components) != 3 {
+		return Version{}, &ParseError{input, "expected MAJOR.MINOR.PATCH"}
+	}
+
+	names := []string{"major", "minor", "patch"}
+	numbers := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, component := range components {
+		if !isNumeric(component) {
+			return Version{}, &ParseError{input, names[i] + " version must be a number"}
+		} else if len(component) > 1 && component[0] == '0' {
+			return Version{}, &ParseError{input, names[i] + " version must not have leading zeros"}
+		}
+
+		n, err := strconv.Atoi(component)
+		if err != nil {
+			return Version{}, &ParseError{input, names[i] + " version is out of range"}
+		}
+		*numbers[i] = n
+	}
+
+	return v, nil
+}
+
+// MustParse is like ParseStrict but panics if the version cannot be parsed.
+// It simplifies safe intialization of global variables.
+func MustParse(input string) Version {
+	v, err := ParseStrict(input)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// checkIdentifiers returns the reason the dot-separated identifiers are invalid,
+// or an empty string if they are valid
+func checkIdentifiers(text string, prerelease bool) string {
+	for _, ident := range strings.Split(text, ".") {
+		if len(ident) == 0 {
+			return "must not have empty identifiers"
+		}
+		for _, r := range ident {
+			if !(r == '-' || '0' <= r && r <= '9' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z') {
+				return fmt.Sprintf("has invalid character %q", r)
+			}
+		}
+		if prerelease && len(ident) > 1 && ident[0] == '0' && isNumeric(ident) {
+			return "must not have numeric identifiers with leading zeros"
+		}
+	}
+	return ""
+}
+
+func isNumeric(text string) bool {
+	if len(text) == 0 {
+		return false
+	}
+	for _, r := range text {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package semver
+
+import (
+	"testing"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+func TestParseStrict(t *testing.T) {
+	examples := []struct {
+		String  string
+		Version Version
+	}{
+		{"0.0.0", Version{0, 0, 0, "", ""}},
+		{"14.54.23", Version{14, 54, 23, "", ""}},
+		{"1.2.3-rc.1", Version{1, 2, 3, "rc.1", ""}},
+		{"1.2.3-0.3.7", Version{1, 2, 3, "0.3.7", ""}},
+		{"1.2.3-x-y-z.--", Version{1, 2, 3, "x-y-z.--", ""}},
+		{"1.2.3-rc.1+build.5", Version{1, 2, 3, "rc.1", "build.5"}},
+		{"1.2.3+001", Version{1, 2, 3, "", "001"}},
+	}
+
+	for _, ex := range examples {
+		parsed, err := ParseStrict(ex.String)
+		expect.Nil(t, err, ex.String)
+		expect.Equal(t, parsed, ex.Version, ex.String)
+	}
+
+	badExamples := []struct {
+		String string
+		Error  string
+	}{
+		{"", `semver: invalid version "": expected MAJOR.MINOR.PATCH`},
+		{"1.2", `semver: invalid version "1.2": expected MAJOR.MINOR.PATCH`},
+		{"1.2.3.4", `semver: invalid version "1.2.3.4": expected MAJOR.MINOR.PATCH`},
+		{"v1.2.3", `semver: invalid version "v1.2.3": major version must be a number`},
+		{"v9a", `semver: invalid version "v9a": expected MAJOR.MINOR.PATCH`},
+		{"1.2.3cc", `semver: invalid version "1.2.3cc": patch version must be a number`},
+		{"01.2.3", `semver: invalid version "01.2.3": major version must not have leading zeros`},
+		{"1.02.3", `semver: invalid version "1.02.3": minor version must not have leading zeros`},
+		{"1.2.3-", `semver: invalid version "1.2.3-": prerelease must not have empty identifiers`},
+		{"1.2.3-rc..1", `semver: invalid version "1.2.3-rc..1": prerelease must not have empty identifiers`},
+		{"1.2.3-rc.01", `semver: invalid version "1.2.3-rc.01": prerelease must not have numeric identifiers with leading zeros`},
+		{"1.2.3-rc_1", `semver: invalid version "1.2.3-rc_1": prerelease has invalid character '_'`},
+		{"1.2.3+", `semver: invalid version "1.2.3+": build must not have empty identifiers`},
+		{"1.2.3+build!", `semver: invalid version "1.2.3+build!": build has invalid character '!'`},
+	}
+
+	for _, ex := range badExamples {
+		_, err := ParseStrict(ex.String)
+		if expect.NotNil(t, err, ex.String) {
+			expect.Equal(t, err.Error(), ex.Error, ex.String)
+		}
+	}
+}
+
+func TestMustParse(t *testing.T) {
+	expect.Equal(t, MustParse("1.2.3-rc.1"), Version{1, 2, 3, "rc.1", ""})
+
+	defer func() {
+		err, _ := recover().(*ParseError)
+		if expect.NotNil(t, err) {
+			expect.Equal(t, err.Input, "1.2")
+		}
+	}()
+	MustParse("1.2")
+}
@@ -6,62 +6,142 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 )
 
 type Version struct {
 	Major int
 	Minor int
 	Patch int
+
+	// Prerelease is the dot-separated identifiers after the "-" (eg. "rc.1")
+	Prerelease string
+	// Build is the dot-separated build metadata after the "+" (eg. "build.5")
+	Build string
 }
 
 func (v Version) String() string {
-	return fmt.Sprintf("%v.%v.%v", v.Major, v.Minor, v.Patch)
+	s := fmt.Sprintf("%v.%v.%v", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s += "-" + v.Prerelease
+	}
+	if len(v.Build) > 0 {
+		s += "+" + v.Build
+	}
+	return s
 }
 
-var Regexp = regexp.MustCompile(`v?(\d+)(?:\.(\d+))?(?:\.(\d+))?`)
+var Regexp = regexp.MustCompile(`v?(\d+)(?:\.(\d+))?(?:\.(\d+))?` +
+	`(?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?` +
+	`(?:\+([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?`)
 var StrictRegexp = regexp.MustCompile("^" + Regexp.String())
 
 // Parse will parse a semantive version from a string in any of these formats:
 //
-//     1        // only major
-//     1.0      // major/minor
-//     1.0.0    // major/minor/patch
-//    v1.0.0    // prefixed with "v"
-//     1.0.0cc  // with trailing characters (currently ignored)
+//     1                  // only major
+//     1.0                // major/minor
+//     1.0.0              // major/minor/patch
+//    v1.0.0              // prefixed with "v"
+//     1.0.0-rc.1         // with a prerelease
+//     1.0.0-rc.1+build.5 // with a prerelease and build metadata
+//     1.0.0cc            // with trailing characters (currently ignored)
 //
+// Parse is lenient so that it can read versions out of strings like User-Agent
+// headers; use ParseStrict to validate a version against the SemVer grammar.
 func Parse(input string) (v Version, ok bool) {
 	matches := StrictRegexp.FindStringSubmatch(input)
-	switch len(matches) {
-	case 4:
-		v.Patch, _ = strconv.Atoi(matches[3])
-		fallthrough
-	case 3:
-		v.Minor, _ = strconv.Atoi(matches[2])
-		fallthrough
-	case 2:
-		v.Major, _ = strconv.Atoi(matches[1])
-		break
-	default:
+	if matches == nil {
 		return
 	}
 
+	v.Major, _ = strconv.Atoi(matches[1])
+	v.Minor, _ = strconv.Atoi(matches[2])
+	v.Patch, _ = strconv.Atoi(matches[3])
+	v.Prerelease = matches[4]
+	v.Build = matches[5]
 	return v, true
 }
 
+// Compare returns -1, 0, or +1 if a has lower, equal, or higher precedence
+// than b, following SemVer 2.0 (build metadata is ignored).
+//
+// It can be used with sort.Slice or slices.SortFunc, and for binary searches.
+func Compare(a, b Version) int {
+	switch {
+	case a.Major != b.Major:
+		return compareInt(a.Major, b.Major)
+	case a.Minor != b.Minor:
+		return compareInt(a.Minor, b.Minor)
+	case a.Patch != b.Patch:
+		return compareInt(a.Patch, b.Patch)
+	default:
+		return comparePrerelease(a.Prerelease, b.Prerelease)
+	}
+}
+
+func compareInt(a, b int) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+// comparePrerelease compares prerelease strings, where a version without a
+// prerelease has higher precedence than one with a prerelease.
+func comparePrerelease(a, b string) int {
+	if a == b {
+		return 0
+	} else if len(a) == 0 {
+		return 1
+	} else if len(b) == 0 {
+		return -1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if c := compareIdentifier(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(as), len(bs))
+}
+
+// compareIdentifier compares numeric identifiers numerically, and others
+// lexically in ASCII order.  Numeric identifiers are lower than others.
+func compareIdentifier(a, b string) int {
+	an, aErr := strconv.ParseUint(a, 10, 64)
+	bn, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		if an < bn {
+			return -1
+		} else if an > bn {
+			return 1
+		}
+		return 0
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// Equal returns true if the versions have the same precedence,
+// which ignores build metadata unlike v == o.
+func (v Version) Equal(o Version) bool {
+	return Compare(v, o) == 0
+}
+
 func (v Version) LessThan(o Version) bool {
-	return v.Major < o.Major ||
-		(v.Major == o.Major &&
-			(v.Minor < o.Minor ||
-				(v.Minor == o.Minor &&
-					(v.Patch < o.Patch))))
+	return Compare(v, o) < 0
 }
 
 func (v Version) GreaterThan(o Version) bool {
-	return v.Major > o.Major ||
-		(v.Major == o.Major &&
-			(v.Minor > o.Minor ||
-				(v.Minor == o.Minor &&
-					(v.Patch > o.Patch))))
+	return Compare(v, o) > 0
 }
 
 func (v Version) AtLeast(o Version) bool {
@@ -74,12 +154,17 @@ func (v Version) AtMost(o Version) bool {
 
 // Implements sql.Scanner interface
 func (v *Version) Scan(src interface{}) error {
-	t, ok := src.([]byte)
-	if !ok {
-		return errors.New("semver: scan value was not bytes")
+	var text string
+	switch t := src.(type) {
+	case []byte:
+		text = string(t)
+	case string:
+		text = t
+	default:
+		return errors.New("semver: scan value was not bytes or a string")
 	}
 
-	version, ok := Parse(string(t))
+	version, ok := Parse(text)
 	if !ok {
 		return errors.New("semver: scan value is not a valid version string")
 	}
@@ -95,12 +180,12 @@ func (v Version) Value() (driver.Value, error) {
 
 // Implements json.Marshaler interface
 func (v Version) MarshalJSON() ([]byte, error) {
-	return []byte(fmt.Sprintf(`"%v.%v.%v"`, v.Major, v.Minor, v.Patch)), nil
+	return []byte(`"` + v.String() + `"`), nil
 }
 
 // Implements json.Unmarshaler interface
 func (v *Version) UnmarshalJSON(bytes []byte) error {
-	if bytes[0] != '"' || bytes[len(bytes)-1] != '"' {
+	if len(bytes) < 2 || bytes[0] != '"' || bytes[len(bytes)-1] != '"' {
 		return errors.New("semver: cannot parse version from non-string JSON value")
 	}
 
@@ -112,3 +197,19 @@ func (v *Version) UnmarshalJSON(bytes []byte) error {
 	*v = parsed
 	return nil
 }
+
+// Implements encoding.TextMarshaler interface
+func (v Version) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// Implements encoding.TextUnmarshaler interface
+func (v *Version) UnmarshalText(text []byte) error {
+	parsed, ok := Parse(string(text))
+	if !ok {
+		return errors.New("semver: text is not a valid version")
+	}
+
+	*v = parsed
+	return nil
+}
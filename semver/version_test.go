@@ -2,6 +2,7 @@ package semver
 
 import (
 	"encoding/json"
+	"sort"
 	"testing"
 
 	"github.com/reflexionhealth/vanilla/expect"
@@ -13,13 +14,18 @@ func TestParse(t *testing.T) {
 		Version Version
 		NotOk   bool
 	}{
-		{String: "0.0.0", Version: Version{0, 0, 0}},
-		{String: "1.0.0", Version: Version{1, 0, 0}},
-		{String: "14.54.23", Version: Version{14, 54, 23}},
-		{String: "0.2.4", Version: Version{0, 2, 4}},
-		{String: "v15.0.3", Version: Version{15, 0, 3}},
-		{String: "v9a", Version: Version{9, 0, 0}},
-		{String: "v9.1a", Version: Version{9, 1, 0}},
+		{String: "0.0.0", Version: Version{0, 0, 0, "", ""}},
+		{String: "1.0.0", Version: Version{1, 0, 0, "", ""}},
+		{String: "14.54.23", Version: Version{14, 54, 23, "", ""}},
+		{String: "0.2.4", Version: Version{0, 2, 4, "", ""}},
+		{String: "v15.0.3", Version: Version{15, 0, 3, "", ""}},
+		{String: "v9a", Version: Version{9, 0, 0, "", ""}},
+		{String: "v9.1a", Version: Version{9, 1, 0, "", ""}},
+		{String: "1.2.3-rc.1", Version: Version{1, 2, 3, "rc.1", ""}},
+		{String: "1.2.3-rc.1+build.5", Version: Version{1, 2, 3, "rc.1", "build.5"}},
+		{String: "1.2.3+build-5", Version: Version{1, 2, 3, "", "build-5"}},
+		{String: "1.2.3-x-y-z.-", Version: Version{1, 2, 3, "x-y-z.-", ""}},
+		{String: "1.2.3-", Version: Version{1, 2, 3, "", ""}},
 
 		{String: "hello world", NotOk: true},
 		{String: "good 1", NotOk: true},
@@ -39,18 +45,18 @@ func TestComparisons(t *testing.T) {
 		Gt, Gte bool
 	}{
 		// TODO: Constraint based testing (ie. https://golang.org/pkg/testing/quick)
-		{Version{0, 0, 0}, Version{0, 0, 0}, false, true, false, true},
-		{Version{0, 0, 1}, Version{0, 0, 0}, false, false, true, true},
-		{Version{0, 1, 0}, Version{0, 0, 0}, false, false, true, true},
-		{Version{1, 0, 0}, Version{0, 0, 0}, false, false, true, true},
-		{Version{0, 0, 0}, Version{1, 0, 0}, true, true, false, false},
-		{Version{0, 0, 1}, Version{1, 0, 0}, true, true, false, false},
-		{Version{0, 1, 0}, Version{1, 0, 0}, true, true, false, false},
-		{Version{1, 0, 0}, Version{1, 0, 0}, false, true, false, true},
+		{Version{0, 0, 0, "", ""}, Version{0, 0, 0, "", ""}, false, true, false, true},
+		{Version{0, 0, 1, "", ""}, Version{0, 0, 0, "", ""}, false, false, true, true},
+		{Version{0, 1, 0, "", ""}, Version{0, 0, 0, "", ""}, false, false, true, true},
+		{Version{1, 0, 0, "", ""}, Version{0, 0, 0, "", ""}, false, false, true, true},
+		{Version{0, 0, 0, "", ""}, Version{1, 0, 0, "", ""}, true, true, false, false},
+		{Version{0, 0, 1, "", ""}, Version{1, 0, 0, "", ""}, true, true, false, false},
+		{Version{0, 1, 0, "", ""}, Version{1, 0, 0, "", ""}, true, true, false, false},
+		{Version{1, 0, 0, "", ""}, Version{1, 0, 0, "", ""}, false, true, false, true},
 
-		{Version{1, 2, 3}, Version{3, 2, 1}, true, true, false, false},
-		{Version{0, 3, 1}, Version{0, 1, 3}, false, false, true, true},
-		{Version{1, 1, 4}, Version{1, 1, 6}, true, true, false, false},
+		{Version{1, 2, 3, "", ""}, Version{3, 2, 1, "", ""}, true, true, false, false},
+		{Version{0, 3, 1, "", ""}, Version{0, 1, 3, "", ""}, false, false, true, true},
+		{Version{1, 1, 4, "", ""}, Version{1, 1, 6, "", ""}, true, true, false, false},
 	}
 
 	for _, ex := range examples {
@@ -61,18 +67,73 @@ func TestComparisons(t *testing.T) {
 	}
 }
 
+func TestPrereleasePrecedence(t *testing.T) {
+	// Example from https://semver.org/#spec-item-11
+	ordered := []Version{
+		{1, 0, 0, "alpha", ""},
+		{1, 0, 0, "alpha.1", ""},
+		{1, 0, 0, "alpha.beta", ""},
+		{1, 0, 0, "beta", ""},
+		{1, 0, 0, "beta.2", ""},
+		{1, 0, 0, "beta.11", ""},
+		{1, 0, 0, "rc.1", ""},
+		{1, 0, 0, "", ""},
+		{1, 0, 1, "alpha", ""},
+	}
+
+	for i := 1; i < len(ordered); i++ {
+		a, b := ordered[i-1], ordered[i]
+		expect.True(t, a.LessThan(b), a.String()+" < "+b.String())
+		expect.True(t, b.GreaterThan(a), b.String()+" > "+a.String())
+	}
+
+	// build metadata is ignored
+	a, b := Version{1, 0, 0, "rc.1", "build.1"}, Version{1, 0, 0, "rc.1", "build.2"}
+	expect.False(t, a.LessThan(b), a.String()+" < "+b.String())
+	expect.False(t, a.GreaterThan(b), a.String()+" > "+b.String())
+	expect.True(t, a.AtLeast(b), a.String()+" >= "+b.String())
+	expect.True(t, a.AtMost(b), a.String()+" <= "+b.String())
+}
+
+func TestCompare(t *testing.T) {
+	examples := []struct {
+		A, B   Version
+		Result int
+	}{
+		{Version{1, 2, 3, "", ""}, Version{1, 2, 3, "", ""}, 0},
+		{Version{1, 2, 3, "", "build.1"}, Version{1, 2, 3, "", "build.2"}, 0},
+		{Version{1, 2, 3, "", ""}, Version{1, 2, 4, "", ""}, -1},
+		{Version{1, 3, 0, "", ""}, Version{1, 2, 4, "", ""}, 1},
+		{Version{1, 2, 3, "rc.1", ""}, Version{1, 2, 3, "", ""}, -1},
+		{Version{1, 2, 3, "rc.10", ""}, Version{1, 2, 3, "rc.9", ""}, 1},
+	}
+
+	for _, ex := range examples {
+		expect.Equal(t, Compare(ex.A, ex.B), ex.Result, ex.A.String()+" <=> "+ex.B.String())
+		expect.Equal(t, Compare(ex.B, ex.A), -ex.Result, ex.B.String()+" <=> "+ex.A.String())
+		expect.Equal(t, ex.A.Equal(ex.B), ex.Result == 0, ex.A.String()+" == "+ex.B.String())
+	}
+
+	versions := []Version{{1, 0, 0, "", ""}, {1, 1, 0, "", ""}, {2, 0, 0, "rc.1", ""}, {2, 0, 0, "", ""}}
+	i := sort.Search(len(versions), func(i int) bool { return Compare(versions[i], Version{2, 0, 0, "", ""}) >= 0 })
+	expect.Equal(t, i, 3)
+}
+
 func TestString(t *testing.T) {
 	examples := []struct {
 		Version Version
 		String  string
 	}{
-		{Version: Version{0, 0, 0}, String: "0.0.0"},
-		{Version: Version{1, 0, 0}, String: "1.0.0"},
-		{Version: Version{14, 54, 23}, String: "14.54.23"},
-		{Version: Version{0, 2, 4}, String: "0.2.4"},
-		{Version: Version{15, 0, 3}, String: "15.0.3"},
-		{Version: Version{9, 0, 0}, String: "9.0.0"},
-		{Version: Version{9, 1, 0}, String: "9.1.0"},
+		{Version: Version{0, 0, 0, "", ""}, String: "0.0.0"},
+		{Version: Version{1, 0, 0, "", ""}, String: "1.0.0"},
+		{Version: Version{14, 54, 23, "", ""}, String: "14.54.23"},
+		{Version: Version{0, 2, 4, "", ""}, String: "0.2.4"},
+		{Version: Version{15, 0, 3, "", ""}, String: "15.0.3"},
+		{Version: Version{9, 0, 0, "", ""}, String: "9.0.0"},
+		{Version: Version{9, 1, 0, "", ""}, String: "9.1.0"},
+		{Version: Version{1, 2, 3, "rc.1", ""}, String: "1.2.3-rc.1"},
+		{Version: Version{1, 2, 3, "", "build.5"}, String: "1.2.3+build.5"},
+		{Version: Version{1, 2, 3, "rc.1", "build.5"}, String: "1.2.3-rc.1+build.5"},
 	}
 
 	for _, ex := range examples {
@@ -81,12 +142,15 @@ func TestString(t *testing.T) {
 }
 
 func TestMarshalJSON(t *testing.T) {
-	b1, err1 := json.Marshal(Version{1, 0, 0})
+	b1, err1 := json.Marshal(Version{1, 0, 0, "", ""})
 	expect.Nil(t, err1)
 	expect.Equal(t, string(b1), `"1.0.0"`)
-	b2, err2 := json.Marshal(Version{2, 0, 30})
+	b2, err2 := json.Marshal(Version{2, 0, 30, "", ""})
 	expect.Nil(t, err2)
 	expect.Equal(t, string(b2), `"2.0.30"`)
+	b3, err3 := json.Marshal(Version{2, 0, 30, "beta.1", "sha.5114f85"})
+	expect.Nil(t, err3)
+	expect.Equal(t, string(b3), `"2.0.30-beta.1+sha.5114f85"`)
 }
 
 func TestUnmarshalJSON(t *testing.T) {
@@ -94,10 +158,11 @@ func TestUnmarshalJSON(t *testing.T) {
 		Json    string
 		Version Version
 	}{
-		{`"5.0.0"`, Version{5, 0, 0}},
-		{`"v2.4.12"`, Version{2, 4, 12}},
-		{`"3.5.0ab"`, Version{3, 5, 0}},
-		{`"8.22"`, Version{8, 22, 0}},
+		{`"5.0.0"`, Version{5, 0, 0, "", ""}},
+		{`"v2.4.12"`, Version{2, 4, 12, "", ""}},
+		{`"3.5.0ab"`, Version{3, 5, 0, "", ""}},
+		{`"8.22"`, Version{8, 22, 0, "", ""}},
+		{`"1.0.0-rc.1+build.5"`, Version{1, 0, 0, "rc.1", "build.5"}},
 	}
 
 	var v Version
@@ -123,3 +188,53 @@ func TestUnmarshalJSON(t *testing.T) {
 		}
 	}
 }
+
+func TestUnmarshalJSONShort(t *testing.T) {
+	var v Version
+	err := v.UnmarshalJSON([]byte(`"`))
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.Error(), "semver: cannot parse version from non-string JSON value")
+	}
+}
+
+func TestMarshalText(t *testing.T) {
+	text, err := Version{1, 2, 3, "rc.1", "build.5"}.MarshalText()
+	expect.Nil(t, err)
+	expect.Equal(t, string(text), "1.2.3-rc.1+build.5")
+
+	var v Version
+	err = v.UnmarshalText([]byte("v2.4.12-beta"))
+	expect.Nil(t, err)
+	expect.Equal(t, v, Version{2, 4, 12, "beta", ""})
+
+	err = v.UnmarshalText([]byte("bogus"))
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.Error(), "semver: text is not a valid version")
+	}
+
+	// as a map key
+	b, err := json.Marshal(map[Version]bool{{1, 0, 0, "", ""}: true})
+	expect.Nil(t, err)
+	expect.Equal(t, string(b), `{"1.0.0":true}`)
+}
+
+func TestScanValue(t *testing.T) {
+	var v Version
+	expect.Nil(t, v.Scan([]byte("1.2.3")))
+	expect.Equal(t, v, Version{1, 2, 3, "", ""})
+	expect.Nil(t, v.Scan("2.0.0-rc.1"))
+	expect.Equal(t, v, Version{2, 0, 0, "rc.1", ""})
+
+	err := v.Scan(12)
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.Error(), "semver: scan value was not bytes or a string")
+	}
+	err = v.Scan("bogus")
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.Error(), "semver: scan value is not a valid version string")
+	}
+
+	value, err := Version{2, 0, 0, "rc.1", ""}.Value()
+	expect.Nil(t, err)
+	expect.Equal(t, value, "2.0.0-rc.1")
+}
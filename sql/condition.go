@@ -0,0 +1,262 @@
+package sql
+
+import "bytes"
+
+// Condition is the interface for boolean expressions used by the WhereCond
+// and HavingCond methods of the statement builders.
+//
+// Conditions write their own placeholders, so they can be composed without
+// knowing how many arguments the statement already has:
+//
+//     sql.Select("*").From("users").WhereCond(sql.And(
+//         sql.Eq("team_id", 3),
+//         sql.Or(sql.In("role", "admin", "owner"), sql.IsNull("deleted_at")),
+//     ))
+//
+type Condition interface {
+	// WriteSql writes the condition to buf, where the condition's first
+	// placeholder is number argOffset+1 in the statement
+	WriteSql(buf *bytes.Buffer, dct *Dialect, argOffset int)
+
+	// Args returns the values for the condition's placeholders, in order
+	Args() []interface{}
+}
+
+// Eq is the condition "column = value"
+func Eq(column string, value interface{}) Condition { return &comparison{column, "=", value} }
+
+// NotEq is the condition "column <> value"
+func NotEq(column string, value interface{}) Condition { return &comparison{column, "<>", value} }
+
+// Lt is the condition "column < value"
+func Lt(column string, value interface{}) Condition { return &comparison{column, "<", value} }
+
+// Lte is the condition "column <= value"
+func Lte(column string, value interface{}) Condition { return &comparison{column, "<=", value} }
+
+// Gt is the condition "column > value"
+func Gt(column string, value interface{}) Condition { return &comparison{column, ">", value} }
+
+// Gte is the condition "column >= value"
+func Gte(column string, value interface{}) Condition { return &comparison{column, ">=", value} }
+
+// Like is the condition "column LIKE pattern"
+func Like(column string, pattern interface{}) Condition { return &comparison{column, "LIKE", pattern} }
+
+// In is the condition "column IN (values...)".
+// If there are no values, the condition is always false.
+func In(column string, values ...interface{}) Condition { return &inList{column, false, values} }
+
+// NotIn is the condition "column NOT IN (values...)".
+// If there are no values, the condition is always true.
+func NotIn(column string, values ...interface{}) Condition { return &inList{column, true, values} }
+
+// InQuery is the condition "column IN (query)"
+func InQuery(column string, query Sqler) Condition { return &subquery{column + " IN ", query} }
+
+// NotInQuery is the condition "column NOT IN (query)"
+func NotInQuery(column string, query Sqler) Condition { return &subquery{column + " NOT IN ", query} }
+
+// Exists is the condition "EXISTS (query)"
+func Exists(query Sqler) Condition { return &subquery{"EXISTS ", query} }
+
+// NotExists is the condition "NOT EXISTS (query)"
+func NotExists(query Sqler) Condition { return &subquery{"NOT EXISTS ", query} }
+
+// IsNull is the condition "column IS NULL"
+func IsNull(column string) Condition { return &nullCheck{column, false} }
+
+// IsNotNull is the condition "column IS NOT NULL"
+func IsNotNull(column string) Condition { return &nullCheck{column, true} }
+
+// And is true if all of the conditions are true.
+// If there are no conditions, it is always true.
+func And(conds ...Condition) Condition { return &group{"AND", conds} }
+
+// Or is true if any of the conditions are true.
+// If there are no conditions, it is always false.
+func Or(conds ...Condition) Condition { return &group{"OR", conds} }
+
+// Not is true if the condition is false
+func Not(cond Condition) Condition { return &negation{cond} }
+
+// Raw is a condition which is written as-is, for expressions that can't be
+// built from the other conditions.  The sql must contain its own placeholders,
+// and should be wrapped in parentheses if it contains OR.
+//
+// If the args are Named, the sql can refer to them as ":name" instead, and
+// they are replaced with the Dialect's placeholders.  Raw will panic with
+// NamedArgError if named and positional args are mixed.
+func Raw(sql string, args ...interface{}) Condition {
+	if hasNamedArgs(args) {
+		return newNamedCondition(sql, args)
+	}
+	return &rawCondition{sql, args}
+}
+
+// orConditions joins cond to a list of conditions (which would be joined
+// with AND) using OR, returning a new list with a single condition
+func orConditions(conds []Condition, cond Condition) []Condition {
+	if len(conds) == 0 {
+		return []Condition{cond}
+	}
+	return []Condition{Or(And(conds...), cond)}
+}
+
+type comparison struct {
+	column   string
+	operator string
+	value    interface{}
+}
+
+func (c *comparison) WriteSql(buf *bytes.Buffer, dct *Dialect, argOffset int) {
+	buf.WriteString(c.column)
+	buf.WriteString(" ")
+	buf.WriteString(c.operator)
+	buf.WriteString(" ")
+	buf.WriteString(dct.Placeholder(argOffset + 1))
+}
+
+func (c *comparison) Args() []interface{} {
+	return []interface{}{c.value}
+}
+
+type inList struct {
+	column string
+	not    bool
+	values []interface{}
+}
+
+func (c *inList) WriteSql(buf *bytes.Buffer, dct *Dialect, argOffset int) {
+	// "x IN ()" isn't valid sql, so use a constant expression instead
+	if len(c.values) == 0 {
+		if c.not {
+			buf.WriteString("1 = 1")
+		} else {
+			buf.WriteString("1 = 0")
+		}
+		return
+	}
+
+	buf.WriteString(c.column)
+	if c.not {
+		buf.WriteString(" NOT IN (")
+	} else {
+		buf.WriteString(" IN (")
+	}
+	for i := range c.values {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(dct.Placeholder(argOffset + i + 1))
+	}
+	buf.WriteString(")")
+}
+
+func (c *inList) Args() []interface{} {
+	return c.values
+}
+
+type subquery struct {
+	prefix string
+	query  Sqler
+}
+
+func (c *subquery) WriteSql(buf *bytes.Buffer, dct *Dialect, argOffset int) {
+	buf.WriteString(c.prefix)
+	buf.WriteString("(")
+	writeQuery(buf, dct, c.query, argOffset)
+	buf.WriteString(")")
+}
+
+func (c *subquery) Args() []interface{} {
+	return c.query.Args()
+}
+
+type nullCheck struct {
+	column string
+	not    bool
+}
+
+func (c *nullCheck) WriteSql(buf *bytes.Buffer, dct *Dialect, argOffset int) {
+	buf.WriteString(c.column)
+	if c.not {
+		buf.WriteString(" IS NOT NULL")
+	} else {
+		buf.WriteString(" IS NULL")
+	}
+}
+
+func (c *nullCheck) Args() []interface{} {
+	return nil
+}
+
+type group struct {
+	operator   string
+	conditions []Condition
+}
+
+func (g *group) WriteSql(buf *bytes.Buffer, dct *Dialect, argOffset int) {
+	if len(g.conditions) == 0 {
+		if g.operator == "AND" {
+			buf.WriteString("1 = 1")
+		} else {
+			buf.WriteString("1 = 0")
+		}
+		return
+	}
+
+	for i, cond := range g.conditions {
+		if i > 0 {
+			buf.WriteString(" ")
+			buf.WriteString(g.operator)
+			buf.WriteString(" ")
+		}
+
+		// AND has a higher precedence than OR, but be explicit when mixing them
+		inner, isGroup := cond.(*group)
+		if isGroup && inner.operator != g.operator && len(inner.conditions) > 1 && len(g.conditions) > 1 {
+			buf.WriteString("(")
+			cond.WriteSql(buf, dct, argOffset)
+			buf.WriteString(")")
+		} else {
+			cond.WriteSql(buf, dct, argOffset)
+		}
+		argOffset += len(cond.Args())
+	}
+}
+
+func (g *group) Args() []interface{} {
+	var args []interface{}
+	for _, cond := range g.conditions {
+		args = append(args, cond.Args()...)
+	}
+	return args
+}
+
+type negation struct {
+	cond Condition
+}
+
+func (n *negation) WriteSql(buf *bytes.Buffer, dct *Dialect, argOffset int) {
+	buf.WriteString("NOT (")
+	n.cond.WriteSql(buf, dct, argOffset)
+	buf.WriteString(")")
+}
+
+func (n *negation) Args() []interface{} {
+	return n.cond.Args()
+}
+
+type rawCondition struct {
+	sql  string
+	args []interface{}
+}
+
+func (r *rawCondition) WriteSql(buf *bytes.Buffer, dct *Dialect, argOffset int) {
+	buf.WriteString(r.sql)
+}
+
+func (r *rawCondition) Args() []interface{} {
+	return r.args
+}
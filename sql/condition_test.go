@@ -0,0 +1,151 @@
+package sql
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+func TestConditions(t *testing.T) {
+	postgres := Dialect{IdentOpen: '"', IdentClose: '"', Placeholder: PlaceholderDollar}
+
+	examples := []struct {
+		Cond Condition
+		Sql  string
+		Args []interface{}
+	}{
+		{Eq("id", 3), "id = $3", []interface{}{3}},
+		{NotEq("id", 3), "id <> $3", []interface{}{3}},
+		{Lt("age", 18), "age < $3", []interface{}{18}},
+		{Lte("age", 18), "age <= $3", []interface{}{18}},
+		{Gt("age", 65), "age > $3", []interface{}{65}},
+		{Gte("age", 65), "age >= $3", []interface{}{65}},
+		{Like("name", "A%"), "name LIKE $3", []interface{}{"A%"}},
+		{In("status", "new", "open"), "status IN ($3, $4)", []interface{}{"new", "open"}},
+		{NotIn("status", "closed"), "status NOT IN ($3)", []interface{}{"closed"}},
+		{In("status"), "1 = 0", nil},
+		{NotIn("status"), "1 = 1", nil},
+		{IsNull("deleted_at"), "deleted_at IS NULL", nil},
+		{IsNotNull("deleted_at"), "deleted_at IS NOT NULL", nil},
+		{And(), "1 = 1", nil},
+		{Or(), "1 = 0", nil},
+		{And(Eq("a", 1)), "a = $3", []interface{}{1}},
+		{
+			And(Eq("a", 1), Gt("b", 2), IsNull("c")),
+			"a = $3 AND b > $4 AND c IS NULL",
+			[]interface{}{1, 2},
+		},
+		{
+			And(Eq("a", 1), Or(Eq("b", 2), In("c", 3, 4)), And(Eq("d", 5), Eq("e", 6))),
+			"a = $3 AND (b = $4 OR c IN ($5, $6)) AND d = $7 AND e = $8",
+			[]interface{}{1, 2, 3, 4, 5, 6},
+		},
+		{
+			Or(And(Eq("a", 1), Eq("b", 2)), Not(Eq("c", 3))),
+			"(a = $3 AND b = $4) OR NOT (c = $5)",
+			[]interface{}{1, 2, 3},
+		},
+		{Raw("lower(email) = $9", "a@b.c"), "lower(email) = $9", []interface{}{"a@b.c"}},
+	}
+	for _, ex := range examples {
+		buf := bytes.Buffer{}
+		ex.Cond.WriteSql(&buf, &postgres, 2)
+		expect.Equal(t, buf.String(), ex.Sql)
+		expect.Equal(t, ex.Cond.Args(), ex.Args)
+	}
+}
+
+func TestSelectWhereCond(t *testing.T) {
+	postgres := Dialect{IdentOpen: '"', IdentClose: '"', Placeholder: PlaceholderDollar}
+
+	stmt := postgres.Select("team_id, count(*)").From("users").
+		LeftJoin("teams", "teams.id = users.team_id AND teams.kind = $1", "club").
+		Where("users.active = $2", true).
+		WhereCond(Or(In("users.role", "admin", "owner"), IsNull("users.role"))).
+		GroupBy("team_id").
+		HavingCond(Gt("count(*)", 5))
+	expected := `SELECT team_id, count(*) FROM "users" ` +
+		`LEFT JOIN "teams" ON teams.id = users.team_id AND teams.kind = $1 ` +
+		`WHERE users.active = $2 AND (users.role IN ($3, $4) OR users.role IS NULL) ` +
+		`GROUP BY team_id HAVING count(*) > $5`
+	expect.Equal(t, stmt.Sql(), expected)
+	expect.Equal(t, stmt.Args(), []interface{}{"club", true, "admin", "owner", 5})
+}
+
+func TestInCondition(t *testing.T) {
+	postgres := Dialect{IdentOpen: '"', IdentClose: '"', Placeholder: PlaceholderDollar}
+	expect.Equal(t, InCondition("id", 3, 0, &postgres), "id IN ($1, $2, $3)")
+	expect.Equal(t, NotInCondition("id", 2, 1, &postgres), "id NOT IN ($2, $3)")
+	expect.Equal(t, InCondition("id", 2, 0, nil), "id IN (?, ?)")
+
+	// the placeholders follow the statement's existing placeholders
+	roles := []interface{}{"admin", "owner"}
+	stmt := postgres.Select("*").From("users").Where("team_id = $1", 3)
+	stmt.Where(InCondition("role", len(roles), len(stmt.Args()), &postgres), roles...)
+	stmt.Where(NotInCondition("id", 1, len(stmt.Args()), &postgres), 7)
+	expect.Equal(t, stmt.Sql(), `SELECT * FROM "users" WHERE team_id = $1 AND role IN ($2, $3) AND id NOT IN ($4)`)
+	expect.Equal(t, stmt.Args(), []interface{}{3, "admin", "owner", 7})
+}
+
+func TestUpdateDeleteWhere(t *testing.T) {
+	postgres := Dialect{IdentOpen: '"', IdentClose: '"', Placeholder: PlaceholderDollar}
+
+	var expected string
+	update := postgres.Update("users").Set("active", false).
+		Where("team_id = $2", 3).
+		Where("role = $3", "guest")
+	expected = `UPDATE "users" SET "active" = $1 WHERE team_id = $2 AND role = $3`
+	expect.Equal(t, update.Sql(), expected)
+	expect.Equal(t, update.Args(), []interface{}{false, 3, "guest"})
+
+	update = postgres.Update("users").Set("active", false).
+		WhereCond(Eq("team_id", 3)).
+		WhereCond(Or(Lt("last_login", "2016-01-01"), IsNull("last_login")))
+	expected = `UPDATE "users" SET "active" = $1 WHERE team_id = $2 AND (last_login < $3 OR last_login IS NULL)`
+	expect.Equal(t, update.Sql(), expected)
+	expect.Equal(t, update.Args(), []interface{}{false, 3, "2016-01-01"})
+
+	remove := Delete("users").Where("team_id = ?", 3)
+	expect.Equal(t, remove.Sql(), `DELETE FROM "users" WHERE team_id = ?`)
+	remove.Where("role = ?", "guest").WhereOr("expired")
+	expect.Equal(t, remove.Sql(), `DELETE FROM "users" WHERE (team_id = ? AND role = ?) OR expired`)
+	remove.WhereCond(IsNotNull("email"))
+	expect.Equal(t, remove.Sql(), `DELETE FROM "users" WHERE ((team_id = ? AND role = ?) OR expired) AND email IS NOT NULL`)
+	expect.Equal(t, remove.Args(), []interface{}{3, "guest"})
+
+	// WhereOr with no previous conditions is the same as Where
+	expect.Equal(t, Delete("users").WhereOr("expired").Sql(), `DELETE FROM "users" WHERE expired`)
+	expect.Equal(t, Select("*").From("users").Where("a").WhereOr("b").Sql(), `SELECT * FROM "users" WHERE a OR b`)
+}
+
+func TestSubqueries(t *testing.T) {
+	postgres := Dialect{IdentOpen: '"', IdentClose: '"', Placeholder: PlaceholderDollar}
+
+	admins := Select("user_id").From("roles").WhereCond(Eq("role", "admin"))
+	stmt := postgres.Select("*").From("users").
+		WhereCond(Eq("team_id", 3)).
+		WhereCond(InQuery("id", admins)).
+		WhereCond(NotExists(Select("1").From("bans").Where("bans.user_id = users.id").WhereCond(Gt("bans.until", "now"))))
+	expected := `SELECT * FROM "users" WHERE team_id = $1 ` +
+		`AND id IN (SELECT user_id FROM "roles" WHERE role = $2) ` +
+		`AND NOT EXISTS (SELECT 1 FROM "bans" WHERE bans.user_id = users.id AND bans.until > $3)`
+	expect.Equal(t, stmt.Sql(), expected)
+	expect.Equal(t, stmt.Args(), []interface{}{3, "admin", "now"})
+
+	recent := Select("team_id, max(created_at) AS latest").From("posts").WhereCond(Gt("created_at", "2016-01-01")).GroupBy("team_id")
+	stmt = postgres.Select("teams.name, recent.latest").FromQuery(recent, "recent").
+		Join("teams", "teams.id = recent.team_id").
+		WhereCond(NotInQuery("teams.id", rawQuery{"SELECT team_id FROM archived WHERE reason = $2", []interface{}{"spam"}})).
+		WhereCond(Exists(Select("1").From("members").Where("members.team_id = teams.id")))
+	expected = `SELECT teams.name, recent.latest FROM (SELECT team_id, max(created_at) AS latest FROM "posts" WHERE created_at > $1 GROUP BY team_id) "recent" ` +
+		`JOIN "teams" ON teams.id = recent.team_id ` +
+		`WHERE teams.id NOT IN (SELECT team_id FROM archived WHERE reason = $2) ` +
+		`AND EXISTS (SELECT 1 FROM "members" WHERE members.team_id = teams.id)`
+	expect.Equal(t, stmt.Sql(), expected)
+	expect.Equal(t, stmt.Args(), []interface{}{"2016-01-01", "spam"})
+
+	remove := postgres.Delete("users").WhereCond(InQuery("id", Select("user_id").From("bans").WhereCond(Eq("permanent", true))))
+	expect.Equal(t, remove.Sql(), `DELETE FROM "users" WHERE id IN (SELECT user_id FROM "bans" WHERE permanent = $1)`)
+	expect.Equal(t, remove.Args(), []interface{}{true})
+}
@@ -19,17 +19,26 @@ type Dialect struct {
 	IdentOpen   rune
 	IdentClose  rune
 	Placeholder func(n int) string
+
+	// SupportsReturning is true if the engine accepts a RETURNING clause
+	// after INSERT, UPDATE, and DELETE statements
+	SupportsReturning bool
+
+	// CurrentSchema is the expression for the name of the schema which
+	// unqualified table names refer to, used by Table.Validate and Table.Diff.
+	// If it is empty, "current_schema()" is used.
+	CurrentSchema string
 }
 
 // The SQL dialect defined by ANSI, using the most compatible rules among popular engines where the standard is ambiguous
 //
 // Other dialects provided for reference:
 //
-//     var mssql    = sql.Dialect{IdentOpen: '[', IdentClose: ']', Placeholder: sql.PlaceholderQuestion}
-//     var mysql    = sql.Dialect{IdentOpen: '`', IdentClose: '`', Placeholder: sql.PlaceholderColon}
+//     var mssql    = sql.Dialect{IdentOpen: '[', IdentClose: ']', Placeholder: sql.PlaceholderQuestion, CurrentSchema: "SCHEMA_NAME()"}
+//     var mysql    = sql.Dialect{IdentOpen: '`', IdentClose: '`', Placeholder: sql.PlaceholderColon, CurrentSchema: "DATABASE()"}
 //     var oracle   = sql.Dialect{IdentOpen: '"', IdentClose: '"', Placeholder: sql.PlaceholderColon}
-//     var postgres = sql.Dialect{IdentOpen: '"', IdentClose: '"', Placeholder: sql.PlaceholderDollar}
-//     var sqlite   = sql.Dialect{IdentOpen: '"', IdentClose: '"', Placeholder: sql.PlaceholderQuestion}
+//     var postgres = sql.Dialect{IdentOpen: '"', IdentClose: '"', Placeholder: sql.PlaceholderDollar, SupportsReturning: true}
+//     var sqlite   = sql.Dialect{IdentOpen: '"', IdentClose: '"', Placeholder: sql.PlaceholderQuestion, SupportsReturning: true}
 //
 var Ansi = Dialect{IdentOpen: '"', IdentClose: '"', Placeholder: PlaceholderQuestion}
 
@@ -50,6 +59,13 @@ func useDialect(dialect *Dialect) *Dialect {
 	}
 }
 
+func (d *Dialect) currentSchema() string {
+	if len(d.CurrentSchema) > 0 {
+		return d.CurrentSchema
+	}
+	return "current_schema()"
+}
+
 func (d *Dialect) WriteIdentifier(buf *bytes.Buffer, ident string) {
 	buf.WriteRune(d.IdentOpen)
 	buf.WriteString(ident)
@@ -64,6 +80,14 @@ func (d *Dialect) AlterTable(name string) *AlterTableStmt {
 	return AlterTable(name).Dialect(d)
 }
 
+func (d *Dialect) CreateIndex(name string) *CreateIndexStmt {
+	return CreateIndex(name).Dialect(d)
+}
+
+func (d *Dialect) DropIndex(name string) *DropIndexStmt {
+	return DropIndex(name).Dialect(d)
+}
+
 func (d *Dialect) Select(selection string) *SelectStmt {
 	return Select(selection).Dialect(d)
 }
@@ -80,6 +104,10 @@ func (d *Dialect) InsertColumns(columns []Column) *InsertStmt {
 	return InsertColumns(columns).Dialect(d)
 }
 
+func (d *Dialect) InsertStruct(value interface{}, flags ColumnsFlag) *InsertStmt {
+	return InsertStruct(value, flags).Dialect(d)
+}
+
 func (d *Dialect) Update(name string) *UpdateStmt {
 	return Update(name).Dialect(d)
 }
@@ -0,0 +1,139 @@
+package sql
+
+import (
+	conn "database/sql"
+	"database/sql/driver"
+	"io"
+	"strconv"
+	"sync"
+)
+
+// recorder is a database/sql driver that records the statements it is given
+type recorder struct {
+	mutex     sync.Mutex
+	events    []string
+	commitErr []error            // the errors returned by the next calls to Commit
+	results   [][][]driver.Value // the rows returned by the next calls to Query
+}
+
+var recorders = struct {
+	sync.Mutex
+	count int
+}{}
+
+// openRecorder returns a new recorder, and a database connected to it
+func openRecorder() (*recorder, *conn.DB) {
+	recorders.Lock()
+	recorders.count += 1
+	name := "sql_test_recorder_" + strconv.Itoa(recorders.count)
+	recorders.Unlock()
+
+	rec := &recorder{}
+	conn.Register(name, rec)
+	db, err := conn.Open(name, "")
+	if err != nil {
+		panic(err)
+	}
+	return rec, db
+}
+
+func (r *recorder) record(event string) {
+	r.mutex.Lock()
+	r.events = append(r.events, event)
+	r.mutex.Unlock()
+}
+
+func (r *recorder) Events() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]string(nil), r.events...)
+}
+
+func (r *recorder) Open(name string) (driver.Conn, error) { return &recorderConn{r}, nil }
+
+type recorderConn struct{ rec *recorder }
+
+func (c *recorderConn) Prepare(query string) (driver.Stmt, error) {
+	return &recorderStmt{c.rec, query}, nil
+}
+
+func (c *recorderConn) Close() error { return nil }
+
+func (c *recorderConn) Begin() (driver.Tx, error) {
+	c.rec.record("BEGIN")
+	return &recorderTx{c.rec}, nil
+}
+
+type recorderTx struct{ rec *recorder }
+
+func (tx *recorderTx) Commit() error {
+	tx.rec.mutex.Lock()
+	defer tx.rec.mutex.Unlock()
+	var err error
+	if len(tx.rec.commitErr) > 0 {
+		err, tx.rec.commitErr = tx.rec.commitErr[0], tx.rec.commitErr[1:]
+	}
+	if err != nil {
+		tx.rec.events = append(tx.rec.events, "COMMIT failed")
+	} else {
+		tx.rec.events = append(tx.rec.events, "COMMIT")
+	}
+	return err
+}
+
+func (tx *recorderTx) Rollback() error {
+	tx.rec.record("ROLLBACK")
+	return nil
+}
+
+type recorderStmt struct {
+	rec   *recorder
+	query string
+}
+
+func (s *recorderStmt) Close() error  { return nil }
+func (s *recorderStmt) NumInput() int { return -1 }
+
+func (s *recorderStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.rec.record(s.query)
+	return driver.RowsAffected(len(args)), nil
+}
+
+func (s *recorderStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.rec.mutex.Lock()
+	defer s.rec.mutex.Unlock()
+	s.rec.events = append(s.rec.events, s.query)
+	if len(s.rec.results) > 0 {
+		rows := s.rec.results[0]
+		s.rec.results = s.rec.results[1:]
+		return &recorderRows{rows: rows}, nil
+	}
+	return &recorderRows{rows: [][]driver.Value{args}}, nil
+}
+
+// recorderRows returns the queued results, or a single row with the
+// query's args as the columns
+type recorderRows struct {
+	rows [][]driver.Value
+}
+
+func (r *recorderRows) Columns() []string {
+	var columns []string
+	if len(r.rows) > 0 {
+		for i := range r.rows[0] {
+			columns = append(columns, "column"+strconv.Itoa(i+1))
+		}
+	}
+	return columns
+}
+
+func (r *recorderRows) Close() error { return nil }
+
+func (r *recorderRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,81 @@
+package sql
+
+import (
+	"context"
+	conn "database/sql"
+	"sync"
+	"time"
+)
+
+// A Runner can execute queries, such as a *database/sql.DB, *database/sql.Tx,
+// or *database/sql.Conn
+type Runner interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (conn.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*conn.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *conn.Row
+}
+
+// A TraceFunc is called after each statement executed by Exec, Query, or
+// QueryRow, with the time the statement took and the error it returned.
+type TraceFunc func(ctx context.Context, query string, args []interface{}, elapsed time.Duration, err error)
+
+var tracer struct {
+	sync.RWMutex
+	trace TraceFunc
+}
+
+// SetTraceFunc sets a function to be called after every statement executed
+// by Exec, Query, or QueryRow, which is useful for logging slow queries or
+// recording metrics.  Passing nil removes the function.
+//
+//     sql.SetTraceFunc(func(ctx context.Context, query string, args []interface{}, elapsed time.Duration, err error) {
+//         if elapsed > time.Second {
+//             log.Printf("slow query (%v): %v", elapsed, query)
+//         }
+//     })
+//
+func SetTraceFunc(fn TraceFunc) {
+	tracer.Lock()
+	tracer.trace = fn
+	tracer.Unlock()
+}
+
+func trace(ctx context.Context, query string, args []interface{}, started time.Time, err error) {
+	tracer.RLock()
+	fn := tracer.trace
+	tracer.RUnlock()
+	if fn != nil {
+		fn(ctx, query, args, time.Since(started), err)
+	}
+}
+
+// Exec executes the statement with db, without returning any rows
+//
+//     result, err := sql.Exec(ctx, db, sql.Delete("users").Where("id = ?", id))
+//
+func Exec(ctx context.Context, db Runner, stmt Sqler) (conn.Result, error) {
+	query, args := stmt.Sql(), stmt.Args()
+	started := time.Now()
+	result, err := db.ExecContext(ctx, query, args...)
+	trace(ctx, query, args, started, err)
+	return result, err
+}
+
+// Query executes the statement with db, returning the resulting rows
+func Query(ctx context.Context, db Runner, stmt Sqler) (*conn.Rows, error) {
+	query, args := stmt.Sql(), stmt.Args()
+	started := time.Now()
+	rows, err := db.QueryContext(ctx, query, args...)
+	trace(ctx, query, args, started, err)
+	return rows, err
+}
+
+// QueryRow executes the statement with db, which is expected to return
+// at most one row.  Errors are deferred until the row's Scan is called.
+func QueryRow(ctx context.Context, db Runner, stmt Sqler) *conn.Row {
+	query, args := stmt.Sql(), stmt.Args()
+	started := time.Now()
+	row := db.QueryRowContext(ctx, query, args...)
+	trace(ctx, query, args, started, row.Err())
+	return row
+}
@@ -0,0 +1,88 @@
+package sql
+
+import (
+	"context"
+	conn "database/sql"
+	"testing"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+func TestExecQuery(t *testing.T) {
+	rec, db := openRecorder()
+	defer db.Close()
+	ctx := context.Background()
+
+	result, err := Exec(ctx, db, Delete("users").Where("id = ?", 3).Where("team_id = ?", 5))
+	expect.Nil(t, err)
+	affected, err := result.RowsAffected()
+	expect.Nil(t, err)
+	expect.Equal(t, affected, int64(2)) // the recorder reports the number of args
+
+	rows, err := Query(ctx, db, Select("*").From("users").WhereCond(Eq("id", int64(3))))
+	expect.Nil(t, err)
+	expect.True(t, rows.Next())
+	var arg int64
+	expect.Nil(t, rows.Scan(&arg))
+	expect.Equal(t, arg, int64(3))
+	expect.False(t, rows.Next())
+	expect.Nil(t, rows.Err())
+
+	var name string
+	err = QueryRow(ctx, db, Select("name").From("users").Where("name = ?", "ana")).Scan(&name)
+	expect.Nil(t, err)
+	expect.Equal(t, name, "ana")
+
+	expect.Equal(t, rec.Events(), []string{
+		`DELETE FROM "users" WHERE id = ? AND team_id = ?`,
+		`SELECT * FROM "users" WHERE id = ?`,
+		`SELECT name FROM "users" WHERE name = ?`,
+	})
+
+	// statements can be run in a transaction too
+	rec, db = openRecorder()
+	err = WithTx(ctx, db, func(tx *conn.Tx) error {
+		_, err := Exec(ctx, tx, Update("users").Set("active", false))
+		return err
+	})
+	expect.Nil(t, err)
+	expect.Equal(t, rec.Events(), []string{"BEGIN", `UPDATE "users" SET "active" = ?`, "COMMIT"})
+}
+
+func TestSetTraceFunc(t *testing.T) {
+	_, db := openRecorder()
+	defer db.Close()
+	ctx := context.Background()
+
+	var traced []string
+	var tracedArgs [][]interface{}
+	SetTraceFunc(func(ctx context.Context, query string, args []interface{}, elapsed time.Duration, err error) {
+		expect.Nil(t, err)
+		expect.True(t, elapsed >= 0)
+		traced = append(traced, query)
+		tracedArgs = append(tracedArgs, args)
+	})
+	defer SetTraceFunc(nil)
+
+	_, err := Exec(ctx, db, Delete("users").Where("id = ?", 3))
+	expect.Nil(t, err)
+	rows, err := Query(ctx, db, Select("*").From("users"))
+	expect.Nil(t, err)
+	rows.Close()
+	var id int64
+	expect.Nil(t, QueryRow(ctx, db, Select("id").From("users").WhereCond(Eq("id", int64(5)))).Scan(&id))
+
+	expect.Equal(t, traced, []string{
+		`DELETE FROM "users" WHERE id = ?`,
+		`SELECT * FROM "users"`,
+		`SELECT id FROM "users" WHERE id = ?`,
+	})
+	expect.Equal(t, tracedArgs, [][]interface{}{{3}, nil, {int64(5)}})
+
+	// setting nil stops tracing
+	SetTraceFunc(nil)
+	_, err = Exec(ctx, db, Delete("users"))
+	expect.Nil(t, err)
+	expect.Equal(t, len(traced), 3)
+}
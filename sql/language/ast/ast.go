@@ -7,15 +7,32 @@ type Stmt interface {
 func (s *SelectStmt) ImplementsStmt() {}
 func (s *InsertStmt) ImplementsStmt() {}
 func (s *UpdateStmt) ImplementsStmt() {}
+func (s *DeleteStmt) ImplementsStmt() {}
 
 type Expr interface {
 	ImplementsExpr()
 }
 
-func (e *BinaryExpr) ImplementsExpr() {}
-func (e *UnaryExpr) ImplementsExpr()  {}
-func (i *Identifier) ImplementsExpr() {}
-func (l *Literal) ImplementsExpr()    {}
+func (e *BinaryExpr) ImplementsExpr()    {}
+func (e *UnaryExpr) ImplementsExpr()     {}
+func (i *Identifier) ImplementsExpr()    {}
+func (n *QualifiedName) ImplementsExpr() {}
+func (c *CallExpr) ImplementsExpr()      {}
+func (e *ParenExpr) ImplementsExpr()     {}
+func (l *ListExpr) ImplementsExpr()      {}
+func (c *CaseExpr) ImplementsExpr()      {}
+func (l *Literal) ImplementsExpr()       {}
+func (p *Param) ImplementsExpr()         {}
+
+// A TableExpr is a table in the FROM clause of a SelectStmt, which may be an
+// Identifier, an AliasedTable, or a JoinExpr
+type TableExpr interface {
+	ImplementsTableExpr()
+}
+
+func (i *Identifier) ImplementsTableExpr()   {}
+func (t *AliasedTable) ImplementsTableExpr() {}
+func (j *JoinExpr) ImplementsTableExpr()     {}
 
 type Direction int
 
@@ -32,23 +49,100 @@ const (
 	DISTINCT_ROW
 )
 
+// NullsOrder is where NULL values are sorted by an OrderItem, if specified
+type NullsOrder int
+
+const (
+	NULLS_DEFAULT NullsOrder = iota
+	NULLS_FIRST
+	NULLS_LAST
+)
+
 type SelectStmt struct {
-	Type     SelectType
-	Select   []Expr
-	Star     bool
-	From     *Identifier
-	Where    Expr
-	Having   Expr
-	GroupBy  string
-	Grouping Direction
-	OrderBy  string
-	Ordering Direction
-	Limit    int
-	Offset   int
+	Type    SelectType
+	Select  []Expr
+	Star    bool
+	From    TableExpr
+	Where   Expr
+	GroupBy []Expr
+	Having  Expr
+	OrderBy []*OrderItem
+	Limit   Expr
+	Offset  Expr
+}
+
+// An OrderItem is one of the expressions in an ORDER BY clause
+type OrderItem struct {
+	Expr      Expr
+	Direction Direction
+	Nulls     NullsOrder
+}
+
+func Order(expr Expr, dir Direction, nulls NullsOrder) *OrderItem {
+	return &OrderItem{expr, dir, nulls}
+}
+
+// An AliasedTable is a table given another name, as in "FROM users AS u"
+type AliasedTable struct {
+	Table *Identifier
+	Alias *Identifier
 }
 
-type InsertStmt struct{}
-type UpdateStmt struct{}
+func Alias(table *Identifier, alias *Identifier) *AliasedTable {
+	return &AliasedTable{table, alias}
+}
+
+type JoinType int
+
+const (
+	INNER_JOIN JoinType = iota
+	LEFT_JOIN
+	RIGHT_JOIN
+	FULL_JOIN
+	CROSS_JOIN
+)
+
+// A JoinExpr joins two tables, using either an On condition or the columns
+// in Using (except for a CROSS_JOIN, which has neither)
+type JoinExpr struct {
+	Type  JoinType
+	Left  TableExpr
+	Right TableExpr
+	On    Expr
+	Using []*Identifier
+}
+
+// An InsertStmt inserts either rows of Values or the result of a Select
+type InsertStmt struct {
+	Table   *Identifier
+	Columns []*Identifier
+	Values  [][]Expr
+	Select  *SelectStmt
+}
+
+// An UpdateStmt sets columns of the rows in Table which match Where
+type UpdateStmt struct {
+	Table *Identifier
+	Set   []*Assignment
+	Where Expr
+}
+
+// An Assignment is a "column = value" pair in the SET clause of an UpdateStmt
+type Assignment struct {
+	Column *Identifier
+	Value  Expr
+}
+
+func Assign(column *Identifier, value Expr) *Assignment {
+	return &Assignment{column, value}
+}
+
+// A DeleteStmt deletes the rows in a table which match Where, up to Limit rows
+type DeleteStmt struct {
+	From  *Identifier
+	Where Expr
+	Limit Expr
+}
 
 type Identifier struct {
 	Name   string
@@ -58,12 +152,69 @@ type Identifier struct {
 func Name(name string) *Identifier   { return &Identifier{name, false} }
 func Quoted(name string) *Identifier { return &Identifier{name, true} }
 
+// A QualifiedName is a column name prefixed by its table, as in "users.id"
+type QualifiedName struct {
+	Qualifier *Identifier
+	Name      *Identifier
+}
+
+func Qualified(qualifier *Identifier, name *Identifier) *QualifiedName {
+	return &QualifiedName{qualifier, name}
+}
+
 type Literal struct {
 	Raw string
 }
 
 func Lit(raw string) *Literal { return &Literal{raw} }
 
+// A CallExpr is a function call, such as "COUNT(*)" or "MAX(DISTINCT size)"
+type CallExpr struct {
+	Name     *Identifier
+	Distinct bool
+	Star     bool
+	Args     []Expr
+}
+
+func Call(name *Identifier, args ...Expr) *CallExpr {
+	return &CallExpr{Name: name, Args: args}
+}
+
+// A Param is a bind parameter.  Positional parameters like "$1" or "?" have
+// an Index (starting at 1), and named parameters like ":id" have a Name.
+type Param struct {
+	Index int
+	Name  string
+}
+
+// A ParenExpr is an expression in grouping parentheses
+type ParenExpr struct {
+	Expr Expr
+}
+
+func Paren(expr Expr) *ParenExpr { return &ParenExpr{expr} }
+
+// A ListExpr is a parenthesized list of expressions, as in "id IN (1, 2, 3)"
+type ListExpr struct {
+	Exprs []Expr
+}
+
+func List(exprs ...Expr) *ListExpr { return &ListExpr{exprs} }
+
+// A CaseExpr is "CASE WHEN cond THEN result ... ELSE result END", or if the
+// Operand is not nil, "CASE operand WHEN value THEN result ... END"
+type CaseExpr struct {
+	Operand Expr
+	Whens   []*When
+	Else    Expr
+}
+
+// A When is one of the "WHEN cond THEN result" clauses of a CaseExpr
+type When struct {
+	Cond   Expr
+	Result Expr
+}
+
 type BinaryExpr struct {
 	Left     Expr
 	Operator OpType
@@ -16,6 +16,8 @@ const (
 	LIKE
 	ILIKE
 	REGEXP
+	SIMILAR_TO
+	GLOB
 	BETWEEN
 	OVERLAPS
 	LESS
@@ -34,6 +36,7 @@ const (
 	BIT_AND
 	BIT_OR
 	BIT_XOR
+	CAST
 
 	// Unary operators
 	NOT
@@ -8,9 +8,19 @@ For a "complete", fast, general-purpose parser, consider adapting code from
 an actual database like [cockroachdb](https://github.com/cockroachdb/cockroach).
 
 Currently supported behavior:
- + Parsing simple SELECT statements with expressions
+ + Parsing SELECT statements with expressions, grouping, ordering, and limits
+ + Parsing joins, table aliases, and qualified column names
+ + Parsing function calls and aggregates like COUNT(*) and MAX(DISTINCT x)
+ + Parsing parenthesized expressions and CASE expressions
+ + Parsing NULL, TRUE, FALSE, BETWEEN, and negations like NOT IN and NOT LIKE
+ + Parsing INSERT statements with VALUES or a SELECT
+ + Parsing UPDATE and DELETE statements
+ + Parsing scripts with multiple statements (see Parser.ParseStatements)
+ + Bind parameters like $1, ?, and :name (depending on the ruleset)
+ + Rulesets for ANSI SQL, MySQL, Postgres, and SQLite
  + Expressions have correct operator precedence in each dialect
- + Syntax validation (but not semantic validation)
+ + Syntax validation, reporting every error in a script (see Parser.ParseAll)
+ + Semantic validation against the tables of a schema (see Validator)
 
 */
 package language
@@ -13,6 +13,28 @@ var MysqlRuleset = Ruleset{
 	ScanRules: scanner.Ruleset{
 		BacktickIsQuotemark: true,
 		DoubleQuoteIsString: true,
+		QuestionParams:      true,
+	},
+}
+var PostgresRuleset = Ruleset{
+	CanSelectWithoutFrom: true,
+
+	Operators: PostgresOperators,
+	ScanRules: scanner.Ruleset{
+		DollarIsLetter:      true,
+		DollarQuotedStrings: true,
+		DollarParams:        true,
+	},
+}
+var SqliteRuleset = Ruleset{
+	CanSelectWithoutFrom: true,
+
+	Operators: SqliteOperators,
+	ScanRules: scanner.Ruleset{
+		BracketsAreQuotes:   true,
+		BacktickIsQuotemark: true,
+		QuestionParams:      true,
+		ColonParams:         true,
 	},
 }
 
@@ -27,15 +49,17 @@ const (
 )
 
 // AnsiOperators gives a set of the operators defined in the SQL standard
-// all with left-associtivity and equal precedence except Assignment which
-// has right-associativity and the lowest precedence.
+// all with left-associtivity and equal precedence except the logical
+// operators which have a lower precedence (with NOT above AND above OR).
 //
 // The precedence of operators between SQL implementations is very diverse,
 // so when parsing complicated expressions, use the OperatorSet appropriate
 // to the database being used.
 var AnsiOperators = OperatorSet{
 	Literals: [3]map[string]Operator{
-		Prefix: {},
+		Prefix: {
+			"NOT": Operator{"NOT", NOT, Prefix, RightAssoc, LOGICAL + 6},
+		},
 		Infix: {
 			"=":  Operator{"=", EQUAL, Infix, LeftAssoc, COMPARE},
 			"<>": Operator{"<>", NOT_EQUAL, Infix, LeftAssoc, COMPARE},
@@ -48,6 +72,9 @@ var AnsiOperators = OperatorSet{
 			"LIKE":    Operator{"LIKE", LIKE, Infix, LeftAssoc, COMPARE},
 			"IS":      Operator{"IS", IS, Infix, LeftAssoc, COMPARE},
 			"IN":      Operator{"IN", IN, Infix, LeftAssoc, COMPARE},
+
+			"AND": Operator{"AND", AND, Infix, LeftAssoc, LOGICAL + 4},
+			"OR":  Operator{"OR", OR, Infix, LeftAssoc, LOGICAL},
 		},
 	},
 }
@@ -101,3 +128,77 @@ var MysqlOperators = OperatorSet{
 		},
 	},
 }
+
+// PostgresOperators gives the set of the operators defined by Postgres
+var PostgresOperators = OperatorSet{
+	Literals: [3]map[string]Operator{
+		Prefix: {
+			"NOT": Operator{"NOT", NOT, Prefix, RightAssoc, LOGICAL + 6},
+			"-":   Operator{"-", NEGATE, Prefix, RightAssoc, UNARY},
+		},
+		Infix: {
+			"::": Operator{"::", CAST, Infix, LeftAssoc, UNARY + 10},
+
+			"*": Operator{"*", MULTIPLY, Infix, LeftAssoc, NUMERIC + 8},
+			"/": Operator{"/", DIVIDE, Infix, LeftAssoc, NUMERIC + 8},
+			"%": Operator{"%", MODULO, Infix, LeftAssoc, NUMERIC + 8},
+			"+": Operator{"+", ADD, Infix, LeftAssoc, NUMERIC + 6},
+			"-": Operator{"-", SUBTRACT, Infix, LeftAssoc, NUMERIC + 6},
+
+			// pattern matching and ranges bind tighter than comparisons
+			"IN":      Operator{"IN", IN, Infix, LeftAssoc, COMPARE + 4},
+			"LIKE":    Operator{"LIKE", LIKE, Infix, LeftAssoc, COMPARE + 4},
+			"ILIKE":   Operator{"ILIKE", ILIKE, Infix, LeftAssoc, COMPARE + 4},
+			"SIMILAR": Operator{"SIMILAR", SIMILAR_TO, Infix, LeftAssoc, COMPARE + 4},
+			"BETWEEN": Operator{"BETWEEN", BETWEEN, Infix, LeftAssoc, COMPARE + 4},
+
+			"<":  Operator{"<", LESS, Infix, LeftAssoc, COMPARE + 2},
+			">":  Operator{">", GREATER, Infix, LeftAssoc, COMPARE + 2},
+			"=":  Operator{"=", EQUAL, Infix, LeftAssoc, COMPARE + 2},
+			"<=": Operator{"<=", LESS_OR_EQUAL, Infix, LeftAssoc, COMPARE + 2},
+			">=": Operator{">=", GREATER_OR_EQUAL, Infix, LeftAssoc, COMPARE + 2},
+			"<>": Operator{"<>", NOT_EQUAL, Infix, LeftAssoc, COMPARE + 2},
+			"!=": Operator{"!=", NOT_EQUAL, Infix, LeftAssoc, COMPARE + 2},
+			"IS": Operator{"IS", IS, Infix, LeftAssoc, COMPARE},
+
+			"AND": Operator{"AND", AND, Infix, LeftAssoc, LOGICAL + 4},
+			"OR":  Operator{"OR", OR, Infix, LeftAssoc, LOGICAL},
+		},
+	},
+}
+
+// SqliteOperators gives the set of the operators defined by SQLite
+var SqliteOperators = OperatorSet{
+	Literals: [3]map[string]Operator{
+		Prefix: {
+			"NOT": Operator{"NOT", NOT, Prefix, RightAssoc, LOGICAL + 6},
+			"-":   Operator{"-", NEGATE, Prefix, RightAssoc, UNARY},
+		},
+		Infix: {
+			"*": Operator{"*", MULTIPLY, Infix, LeftAssoc, NUMERIC + 8},
+			"/": Operator{"/", DIVIDE, Infix, LeftAssoc, NUMERIC + 8},
+			"%": Operator{"%", MODULO, Infix, LeftAssoc, NUMERIC + 8},
+			"+": Operator{"+", ADD, Infix, LeftAssoc, NUMERIC + 6},
+			"-": Operator{"-", SUBTRACT, Infix, LeftAssoc, NUMERIC + 6},
+
+			"<":  Operator{"<", LESS, Infix, LeftAssoc, COMPARE + 2},
+			"<=": Operator{"<=", LESS_OR_EQUAL, Infix, LeftAssoc, COMPARE + 2},
+			">":  Operator{">", GREATER, Infix, LeftAssoc, COMPARE + 2},
+			">=": Operator{">=", GREATER_OR_EQUAL, Infix, LeftAssoc, COMPARE + 2},
+
+			// equality and pattern matching have the same precedence
+			"=":       Operator{"=", EQUAL, Infix, LeftAssoc, COMPARE},
+			"!=":      Operator{"!=", NOT_EQUAL, Infix, LeftAssoc, COMPARE},
+			"<>":      Operator{"<>", NOT_EQUAL, Infix, LeftAssoc, COMPARE},
+			"IS":      Operator{"IS", IS, Infix, LeftAssoc, COMPARE},
+			"IN":      Operator{"IN", IN, Infix, LeftAssoc, COMPARE},
+			"LIKE":    Operator{"LIKE", LIKE, Infix, LeftAssoc, COMPARE},
+			"GLOB":    Operator{"GLOB", GLOB, Infix, LeftAssoc, COMPARE},
+			"REGEXP":  Operator{"REGEXP", REGEXP, Infix, LeftAssoc, COMPARE},
+			"BETWEEN": Operator{"BETWEEN", BETWEEN, Infix, LeftAssoc, COMPARE},
+
+			"AND": Operator{"AND", AND, Infix, LeftAssoc, LOGICAL + 4},
+			"OR":  Operator{"OR", OR, Infix, LeftAssoc, LOGICAL},
+		},
+	},
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"runtime"
+	"strconv"
 	"strings"
 
 	"github.com/reflexionhealth/vanilla/sql/language/ast"
@@ -29,6 +30,10 @@ type Ruleset struct {
 	Initialize func(os *ast.OperatorSet)
 }
 
+// Positions maps each node of a parsed statement (a pointer to an ast type)
+// to the position of its first token.
+type Positions map[interface{}]token.Position
+
 type ParseError struct {
 	Pos token.Position
 	Msg string
@@ -38,6 +43,19 @@ func (e *ParseError) Error() string {
 	return e.Pos.String() + ": " + e.Msg
 }
 
+// An ErrorList is a list of the errors found by Parser.ParseAll
+type ErrorList []*ParseError
+
+func (l ErrorList) Error() string {
+	switch len(l) {
+	case 0:
+		return "no errors"
+	case 1:
+		return l[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", l[0], len(l)-1)
+}
+
 // A parser holds the parser's internal state while processing
 // a given text.  It can be allocated as part of another data
 // structure but must be initialized via Init before use.
@@ -45,11 +63,18 @@ type Parser struct {
 	scanner scanner.Scanner
 	rules   Ruleset
 
-	pos int         // next token offset
-	tok token.Token // next token type
-	lit string      // next token literal
+	pos    int            // next token offset
+	tok    token.Token    // next token type
+	lit    string         // next token literal
+	tokPos token.Position // next token position
+	params int            // count of "?" params in the statement
+
+	incomplete bool // whether unimplemented syntax was skipped
 
-	Trace io.Writer // output for trace (no output if nil)
+	scanErr *ParseError // the first error from scanning the next token
+
+	Trace     io.Writer // output for trace (no output if nil)
+	Positions Positions // records the position of parsed nodes (if not nil)
 }
 
 // New will allocate initialize a parser when you don't want to allocate one yourself
@@ -61,19 +86,129 @@ func New(src []byte, rules Ruleset) *Parser {
 
 // Init prepares the parser p to convert a text src into an ast.
 func (p *Parser) Init(src []byte, rules Ruleset) {
-	scanError := func(pos token.Position, msg string) { p.error(pos, msg) }
+	// scan errors are raised by next, after the scanner has finished the token
+	scanError := func(pos token.Position, msg string) {
+		if p.scanErr == nil {
+			p.scanErr = &ParseError{pos, msg}
+		}
+	}
 	p.scanner.Init(src, scanError, rules.ScanRules)
 	p.rules = rules
+	p.incomplete = false
+}
+
+// Incomplete reports whether the parser skipped syntax which isn't
+// implemented yet (see Ruleset.AllowNotImplemented), in which case the ast
+// of a parsed statement is missing the rest of the statement.
+func (p *Parser) Incomplete() bool {
+	return p.incomplete
 }
 
 // ParseStatement attempts to parse a statement or returns the first error found
 func (p *Parser) ParseStatement() (stmt ast.Stmt, err error) {
 	defer p.recoverStopped(&err)
 	p.next() // scan first
-	stmt = p.parseStatement()
+	result := p.parseStatement()
+	if p.tok == token.SEMICOLON {
+		p.next()
+	}
+	if p.tok != token.EOS {
+		p.error(p.scanner.Pos(), `statement does not end at semicolon`)
+	}
+
+	stmt = result
+	return
+}
+
+// ParseStatements parses a script of semicolon-separated statements, such as
+// a migration file.  If there is an error, it returns the statements which
+// were parsed before the error.
+func (p *Parser) ParseStatements() (stmts []ast.Stmt, err error) {
+	defer p.recoverStopped(&err)
+	p.next() // scan first
+	for p.tok != token.EOS {
+		if p.tok == token.SEMICOLON {
+			p.next() // skip empty statements
+			continue
+		}
+
+		stmt := p.parseStatement()
+		if p.tok != token.EOS {
+			p.expect(token.SEMICOLON)
+		}
+		stmts = append(stmts, stmt)
+	}
+	return
+}
+
+// ParseAll is like ParseStatements, but it doesn't stop at the first
+// error.  Instead it skips to the end of the statement containing the error
+// and continues parsing, so that a script can be checked all at once.
+//
+// It returns the statements which were parsed without errors, and an
+// ErrorList if there were any errors.
+func (p *Parser) ParseAll() (stmts []ast.Stmt, err error) {
+	defer p.recoverStopped(&err)
+
+	var errors ErrorList
+	if e := p.try(p.next); e != nil { // scan first
+		errors = append(errors, e)
+		p.synchronize(&errors)
+	}
+	for p.tok != token.EOS {
+		if p.tok == token.SEMICOLON {
+			if e := p.try(p.next); e != nil { // skip empty statements
+				errors = append(errors, e)
+				p.synchronize(&errors)
+			}
+			continue
+		}
+
+		var stmt ast.Stmt
+		e := p.try(func() {
+			stmt = p.parseStatement()
+			if p.tok != token.EOS {
+				p.expect(token.SEMICOLON)
+			}
+		})
+		if e != nil {
+			errors = append(errors, e)
+			p.synchronize(&errors)
+		} else {
+			stmts = append(stmts, stmt)
+		}
+	}
+
+	if len(errors) > 0 {
+		err = errors
+	}
 	return
 }
 
+// synchronize skips to the end of the current statement after an error
+func (p *Parser) synchronize(errors *ErrorList) {
+	for p.tok != token.SEMICOLON && p.tok != token.EOS {
+		if e := p.try(p.next); e != nil {
+			*errors = append(*errors, e)
+		}
+	}
+}
+
+// try calls fn, returning the error if parsing is stopped
+func (p *Parser) try(fn func()) (err *ParseError) {
+	defer func() {
+		if e := recover(); e != nil {
+			stop, ok := e.(stopParsing)
+			if !ok {
+				panic(e)
+			}
+			err = stop.err
+		}
+	}()
+	fn()
+	return nil
+}
+
 // A stopParsing panic is raised to indicate early termination.
 //
 // In most cases I consider panics to be a code smell when they are used for
@@ -113,6 +248,17 @@ func (p *Parser) expected(what string) {
 	p.error(p.scanner.Pos(), fmt.Sprintf(`expected '%v' but received '%v'`, what, p.tok))
 }
 
+// peek returns the token after the next token, without consuming either
+func (p *Parser) peek() token.Token {
+	scanner, scanErr := p.scanner, p.scanErr
+	_, tok, _ := scanner.Scan()
+	for tok == token.COMMENT {
+		_, tok, _ = scanner.Scan()
+	}
+	p.scanErr = scanErr // the error is reported when the token is consumed
+	return tok
+}
+
 func (p *Parser) next() {
 	if p.Trace != nil && (p.pos > 0 || p.tok != token.INVALID) {
 		pc, _, line, _ := runtime.Caller(1)
@@ -133,9 +279,27 @@ func (p *Parser) next() {
 	}
 
 	p.pos, p.tok, p.lit = p.scanner.Scan()
+	for p.tok == token.COMMENT {
+		p.pos, p.tok, p.lit = p.scanner.Scan()
+	}
+	p.tokPos = p.scanner.TokenPos()
+
+	if p.scanErr != nil {
+		err := p.scanErr
+		p.scanErr = nil
+		p.error(err.Pos, err.Msg)
+	}
+}
+
+// mark records the position of an ast node if Positions is being recorded
+func (p *Parser) mark(node interface{}, pos token.Position) {
+	if p.Positions != nil {
+		p.Positions[node] = pos
+	}
 }
 
 func (p *Parser) parseStatement() ast.Stmt {
+	p.params = 0
 	switch p.tok {
 	case token.SELECT:
 		return p.parseSelect()
@@ -143,15 +307,18 @@ func (p *Parser) parseStatement() ast.Stmt {
 		return p.parseInsert()
 	case token.UPDATE:
 		return p.parseUpdate()
+	case token.DELETE:
+		return p.parseDelete()
 	default:
-		p.expected("SELECT, INSERT, or UPDATE")
+		p.expected("SELECT, INSERT, UPDATE, or DELETE")
 		return nil
 	}
 }
 
 func (p *Parser) parseSelect() *ast.SelectStmt {
-	p.expect(token.SELECT)
 	stmt := &ast.SelectStmt{}
+	p.mark(stmt, p.tokPos)
+	p.expect(token.SELECT)
 	stmt.Type = ast.SELECT_ALL
 	switch p.tok {
 	case token.ALL:
@@ -173,68 +340,312 @@ func (p *Parser) parseSelect() *ast.SelectStmt {
 		stmt.Star = true
 		p.next()
 	} else {
-		stmt.Select = []ast.Expr{p.parseExpression()}
-		for p.tok == token.COMMA {
-			p.next() // eat comma
-			stmt.Select = append(stmt.Select, p.parseExpression())
-		}
+		stmt.Select = p.parseExpressionList()
 	}
 
 	// NOTE: The FROM clause is sometimes optional, but since this would be an
 	// error in most common uses cases, the default will be that it is required
 	// even for dialects where it is technically optional.
-	if p.rules.CanSelectWithoutFrom && p.tok == token.EOS {
+	if p.rules.CanSelectWithoutFrom && (p.tok == token.EOS || p.tok == token.SEMICOLON) {
 		return stmt
 	}
 
 	p.expect(token.FROM)
-	switch p.tok {
-	case token.IDENT:
-		stmt.From = ast.Name(p.lit)
-		p.next()
-	case token.QUOTED_IDENT:
-		stmt.From = ast.Quoted(p.lit)
-		p.next()
-	default:
-		p.expected("a table name")
-	}
+	stmt.From = p.parseTableExpr()
 
 	if p.tok == token.WHERE {
 		p.next() // eat WHERE
 		stmt.Where = p.parseExpression()
 	}
 
-	// if p.tok == token.GROUP {
-	// 	panic("TODO: parse GROUP BY")
-	// }
-	//
-	// if p.tok == token.HAVING {
-	// 	panic("TODO: parse HAVING")
-	// }
-	//
-	// if p.tok == token.ORDER {
-	// 	panic("TODO: parse ORDER")
-	// }
-	//
-	// if p.tok == token.LIMIT {
-	// 	panic("TODO: parse LIMIT")
-	// }
+	if p.tok == token.GROUP {
+		p.next() // eat GROUP
+		p.expect(token.BY)
+		stmt.GroupBy = p.parseExpressionList()
+	}
+
+	if p.tok == token.HAVING {
+		p.next() // eat HAVING
+		stmt.Having = p.parseExpression()
+	}
+
+	if p.tok == token.ORDER {
+		p.next() // eat ORDER
+		p.expect(token.BY)
+		stmt.OrderBy = []*ast.OrderItem{p.parseOrderItem()}
+		for p.tok == token.COMMA {
+			p.next() // eat comma
+			stmt.OrderBy = append(stmt.OrderBy, p.parseOrderItem())
+		}
+	}
+
+	if p.tok == token.LIMIT {
+		p.next() // eat LIMIT
+		stmt.Limit = p.parseExpression()
+	}
+
+	if p.tok == token.OFFSET {
+		p.next() // eat OFFSET
+		stmt.Offset = p.parseExpression()
+	}
 
 	p.eatUnimplemented("clause")
 	return stmt
 }
 
+// parseTableExpr parses a table reference followed by any joined tables
+func (p *Parser) parseTableExpr() ast.TableExpr {
+	pos := p.tokPos
+	table := p.parseTableReference()
+	for {
+		join := &ast.JoinExpr{Left: table}
+		switch {
+		case p.tok == token.COMMA:
+			join.Type = ast.CROSS_JOIN
+			p.next()
+		case p.isWord("JOIN"):
+			join.Type = ast.INNER_JOIN
+			p.next()
+		case p.isWord("INNER"):
+			join.Type = ast.INNER_JOIN
+			p.next()
+			p.expectWord("JOIN")
+		case p.isWord("CROSS"):
+			join.Type = ast.CROSS_JOIN
+			p.next()
+			p.expectWord("JOIN")
+		case p.isWord("LEFT"):
+			join.Type = ast.LEFT_JOIN
+			p.parseOuterJoin()
+		case p.isWord("RIGHT"):
+			join.Type = ast.RIGHT_JOIN
+			p.parseOuterJoin()
+		case p.isWord("FULL"):
+			join.Type = ast.FULL_JOIN
+			p.parseOuterJoin()
+		default:
+			return table
+		}
+
+		join.Right = p.parseTableReference()
+		if join.Type != ast.CROSS_JOIN {
+			switch {
+			case p.isWord("ON"):
+				p.next() // eat ON
+				join.On = p.parseExpression()
+			case p.isWord("USING"):
+				p.next() // eat USING
+				p.expect(token.LEFT_PAREN)
+				join.Using = []*ast.Identifier{p.parseIdentifier("a column name")}
+				for p.tok == token.COMMA {
+					p.next() // eat comma
+					join.Using = append(join.Using, p.parseIdentifier("a column name"))
+				}
+				p.expect(token.RIGHT_PAREN)
+			default:
+				p.expected("ON or USING")
+			}
+		}
+
+		p.mark(join, pos)
+		table = join
+	}
+}
+
+// parseOuterJoin eats "LEFT JOIN", "LEFT OUTER JOIN", and the like
+func (p *Parser) parseOuterJoin() {
+	p.next() // eat LEFT, RIGHT, or FULL
+	if p.isWord("OUTER") {
+		p.next()
+	}
+	p.expectWord("JOIN")
+}
+
+// joinWords can't be used as a table alias without AS, because they begin
+// a join (or its condition) when they follow a table name
+var joinWords = []string{"JOIN", "INNER", "CROSS", "LEFT", "RIGHT", "FULL", "ON", "USING"}
+
+func (p *Parser) isJoinWord() bool {
+	for _, word := range joinWords {
+		if p.isWord(word) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTableReference parses a table name with an optional alias
+func (p *Parser) parseTableReference() ast.TableExpr {
+	pos := p.tokPos
+	table := p.parseIdentifier("a table name")
+
+	var alias *ast.Identifier
+	if p.tok == token.AS {
+		p.next() // eat AS
+		alias = p.parseIdentifier("an alias")
+	} else if (p.tok == token.IDENT && !p.isJoinWord()) || p.tok == token.QUOTED_IDENT {
+		alias = p.parseIdentifier("an alias")
+	} else {
+		return table
+	}
+
+	aliased := ast.Alias(table, alias)
+	p.mark(aliased, pos)
+	return aliased
+}
+
+func (p *Parser) parseOrderItem() *ast.OrderItem {
+	item := &ast.OrderItem{}
+	p.mark(item, p.tokPos)
+	item.Expr = p.parseExpression()
+
+	switch p.tok {
+	case token.ASC:
+		p.next()
+	case token.DESC:
+		item.Direction = ast.DESC
+		p.next()
+	}
+
+	// NULLS, FIRST, and LAST aren't reserved words, so they are still identifiers
+	if p.isWord("NULLS") {
+		p.next() // eat NULLS
+		switch {
+		case p.isWord("FIRST"):
+			item.Nulls = ast.NULLS_FIRST
+		case p.isWord("LAST"):
+			item.Nulls = ast.NULLS_LAST
+		default:
+			p.expected("FIRST or LAST")
+		}
+		p.next()
+	}
+
+	return item
+}
+
 func (p *Parser) parseInsert() *ast.InsertStmt {
+	stmt := &ast.InsertStmt{}
+	p.mark(stmt, p.tokPos)
 	p.expect(token.INSERT)
 	p.expect(token.INTO)
-	p.eatUnimplemented("clause")
-	return nil
+	stmt.Table = p.parseIdentifier("a table name")
+
+	if p.tok == token.LEFT_PAREN {
+		p.next() // eat paren
+		stmt.Columns = []*ast.Identifier{p.parseIdentifier("a column name")}
+		for p.tok == token.COMMA {
+			p.next() // eat comma
+			stmt.Columns = append(stmt.Columns, p.parseIdentifier("a column name"))
+		}
+		p.expect(token.RIGHT_PAREN)
+	}
+
+	switch p.tok {
+	case token.VALUES:
+		p.next() // eat VALUES
+		stmt.Values = [][]ast.Expr{p.parseValues(len(stmt.Columns))}
+		for p.tok == token.COMMA {
+			p.next() // eat comma
+			stmt.Values = append(stmt.Values, p.parseValues(len(stmt.Values[0])))
+		}
+		p.eatUnimplemented("clause")
+	case token.SELECT:
+		stmt.Select = p.parseSelect()
+	default:
+		p.expected("VALUES or SELECT")
+	}
+
+	return stmt
+}
+
+// parseValues parses one parenthesized row of an INSERT's VALUES clause,
+// which must have count values if count is not zero
+func (p *Parser) parseValues(count int) []ast.Expr {
+	pos := p.tokPos
+	p.expect(token.LEFT_PAREN)
+	values := p.parseExpressionList()
+	p.expect(token.RIGHT_PAREN)
+
+	if count > 0 && len(values) != count {
+		p.error(pos, fmt.Sprintf(`expected %d values in row but received %d`, count, len(values)))
+	}
+	return values
 }
 
 func (p *Parser) parseUpdate() *ast.UpdateStmt {
+	stmt := &ast.UpdateStmt{}
+	p.mark(stmt, p.tokPos)
 	p.expect(token.UPDATE)
+	stmt.Table = p.parseIdentifier("a table name")
+
+	p.expect(token.SET)
+	stmt.Set = []*ast.Assignment{p.parseAssignment()}
+	for p.tok == token.COMMA {
+		p.next() // eat comma
+		stmt.Set = append(stmt.Set, p.parseAssignment())
+	}
+
+	if p.tok == token.WHERE {
+		p.next() // eat WHERE
+		stmt.Where = p.parseExpression()
+	}
+
 	p.eatUnimplemented("clause")
-	return nil
+	return stmt
+}
+
+func (p *Parser) parseDelete() *ast.DeleteStmt {
+	stmt := &ast.DeleteStmt{}
+	p.mark(stmt, p.tokPos)
+	p.expect(token.DELETE)
+	p.expect(token.FROM)
+	stmt.From = p.parseIdentifier("a table name")
+
+	if p.tok == token.WHERE {
+		p.next() // eat WHERE
+		stmt.Where = p.parseExpression()
+	}
+
+	if p.tok == token.LIMIT {
+		p.next() // eat LIMIT
+		stmt.Limit = p.parseExpression()
+	}
+
+	p.eatUnimplemented("clause")
+	return stmt
+}
+
+func (p *Parser) parseAssignment() *ast.Assignment {
+	pos := p.tokPos
+	column := p.parseIdentifier("a column name")
+	p.expect(token.EQUALS)
+	assign := ast.Assign(column, p.parseExpression())
+	p.mark(assign, pos)
+	return assign
+}
+
+// isWord reports whether the next token is an identifier matching a non-reserved keyword
+func (p *Parser) isWord(word string) bool {
+	return p.tok == token.IDENT && strings.EqualFold(p.lit, word)
+}
+
+// expectWord is like expect, but for a non-reserved keyword (see isWord)
+func (p *Parser) expectWord(word string) {
+	if !p.isWord(word) {
+		p.expected(word)
+	}
+	p.next()
+}
+
+// parseExpressionList parses one or more comma-separated expressions
+func (p *Parser) parseExpressionList() []ast.Expr {
+	list := []ast.Expr{p.parseExpression()}
+	for p.tok == token.COMMA {
+		p.next() // eat comma
+		list = append(list, p.parseExpression())
+	}
+	return list
 }
 
 // parseExpression uses table-based operator parsing (see parseExprWithOperators)
@@ -243,35 +654,51 @@ func (p *Parser) parseExpression() ast.Expr {
 }
 
 func (p *Parser) parseExprWithOperators(precedence ast.OpPrecedence) ast.Expr {
+	pos := p.tokPos
 	lhs := p.parseBaseExpression()
 	if p.tok == token.LEFT_PAREN {
-		// TODO: functions like MAX(), MIN(), AVERAGE()
 		p.eatUnimplemented("expression")
 	} else if !p.tok.IsOperator() {
 		return lhs
 	}
 
-	op, exists := p.rules.Operators.Lookup(p.tok.String(), ast.Infix)
-	if !exists {
-		msg := `statement includes '` + p.tok.String() + `', but it is not defined as an operator`
-		p.error(p.scanner.Pos(), msg)
-	}
-
+	op, negated := p.lookupInfix()
 	consumable := ast.MaxPrecedence
 	for (op.Kind == ast.Infix) &&
 		(precedence <= op.Precedence && op.Precedence <= consumable) {
 
 		p.next() // eat operator
-		rhs := p.parseExprWithOperators(rightPrec(op))
+		if negated {
+			p.next() // eat the operator after NOT
+		}
+		if op.Type == ast.SIMILAR_TO {
+			// TO isn't a reserved word, so it is still an identifier
+			if !p.isWord("TO") {
+				p.expected("TO")
+			}
+			p.next()
+		}
+
+		var rhs ast.Expr
+		if op.Type == ast.BETWEEN {
+			// the range "low AND high" is the right side of a BETWEEN
+			lowPos := p.tokPos
+			low := p.parseExprWithOperators(rightPrec(op))
+			p.expect(token.AND)
+			rhs = ast.Binary(low, ast.AND, p.parseExprWithOperators(rightPrec(op)))
+			p.mark(rhs, lowPos)
+		} else {
+			rhs = p.parseExprWithOperators(rightPrec(op))
+		}
 		lhs = ast.Binary(lhs, op.Type, rhs)
+		p.mark(lhs, pos)
+		if negated {
+			lhs = ast.Unary(ast.NOT, lhs)
+			p.mark(lhs, pos)
+		}
 
 		if p.tok.IsOperator() {
-			var exists bool
-			op, exists = p.rules.Operators.Lookup(p.tok.String(), ast.Infix)
-			if !exists {
-				msg := `statement includes '` + p.tok.String() + `', but it is not defined as an operator`
-				p.error(p.scanner.Pos(), msg)
-			}
+			op, negated = p.lookupInfix()
 			consumable = nextPrec(op)
 		} else {
 			break
@@ -281,6 +708,37 @@ func (p *Parser) parseExprWithOperators(precedence ast.OpPrecedence) ast.Expr {
 	return lhs
 }
 
+// negatable are the infix operators which can follow NOT, as in "NOT IN"
+var negatable = map[ast.OpType]bool{
+	ast.IN:         true,
+	ast.BETWEEN:    true,
+	ast.LIKE:       true,
+	ast.ILIKE:      true,
+	ast.SIMILAR_TO: true,
+	ast.REGEXP:     true,
+	ast.GLOB:       true,
+}
+
+// lookupInfix returns the infix operator at the next token, and whether it
+// is negated (eg. "NOT LIKE"), in which case the operator is after the NOT
+func (p *Parser) lookupInfix() (ast.Operator, bool) {
+	if p.tok == token.NOT {
+		if next := p.peek(); next.IsOperator() {
+			op, exists := p.rules.Operators.Lookup(next.String(), ast.Infix)
+			if exists && negatable[op.Type] {
+				return op, true
+			}
+		}
+	}
+
+	op, exists := p.rules.Operators.Lookup(p.tok.String(), ast.Infix)
+	if !exists {
+		msg := `statement includes '` + p.tok.String() + `', but it is not defined as an operator`
+		p.error(p.scanner.Pos(), msg)
+	}
+	return op, false
+}
+
 func rightPrec(op ast.Operator) ast.OpPrecedence {
 	if op.Assoc == ast.RightAssoc {
 		return op.Precedence
@@ -297,7 +755,26 @@ func nextPrec(op ast.Operator) ast.OpPrecedence {
 	}
 }
 
+// parseIdentifier parses a (possibly quoted) name, such as a table or column
+func (p *Parser) parseIdentifier(what string) *ast.Identifier {
+	var ident *ast.Identifier
+	switch p.tok {
+	case token.IDENT:
+		ident = ast.Name(p.lit)
+	case token.QUOTED_IDENT:
+		ident = ast.Quoted(p.lit)
+	default:
+		p.expected(what)
+	}
+
+	p.mark(ident, p.tokPos)
+	p.next()
+	return ident
+}
+
 func (p *Parser) parseBaseExpression() ast.Expr {
+	pos := p.tokPos
+
 	/* handle prefix operator */
 	if p.tok.IsOperator() {
 		op, exists := p.rules.Operators.Lookup(p.tok.String(), ast.Prefix)
@@ -307,29 +784,119 @@ func (p *Parser) parseBaseExpression() ast.Expr {
 		}
 
 		p.next() // eat operator
-		expr := p.parseExprWithOperators(op.Precedence)
-		return ast.Unary(op.Type, expr)
+		expr := ast.Unary(op.Type, p.parseExprWithOperators(op.Precedence))
+		p.mark(expr, pos)
+		return expr
 	}
 
 	switch p.tok {
-	case token.IDENT:
-		ident := ast.Name(p.lit)
-		p.next()
-		return ident
-	case token.QUOTED_IDENT:
-		ident := ast.Quoted(p.lit)
-		p.next()
+	case token.IDENT, token.QUOTED_IDENT:
+		ident := p.parseIdentifier("an identifier")
+		if p.tok == token.LEFT_PAREN {
+			call := p.parseCall(ident)
+			p.mark(call, pos)
+			return call
+		} else if p.tok == token.PERIOD {
+			p.next() // eat period
+			name := ast.Qualified(ident, p.parseIdentifier("a column name"))
+			p.mark(name, pos)
+			return name
+		}
 		return ident
 	case token.STRING, token.NUMBER:
 		lit := ast.Lit(p.lit)
+		p.mark(lit, pos)
+		p.next()
+		return lit
+	case token.NULL, token.TRUE, token.FALSE:
+		lit := ast.Lit(p.tok.String())
+		p.mark(lit, pos)
 		p.next()
 		return lit
+	case token.PARAM:
+		param := &ast.Param{}
+		if p.lit == "" {
+			p.params++ // a "?", which is numbered by its position
+			param.Index = p.params
+		} else if index, err := strconv.Atoi(p.lit); err == nil {
+			param.Index = index
+		} else {
+			param.Name = p.lit
+		}
+		p.mark(param, pos)
+		p.next()
+		return param
+	case token.LEFT_PAREN:
+		p.next() // eat paren
+		var paren ast.Expr
+		exprs := p.parseExpressionList()
+		if len(exprs) == 1 {
+			paren = ast.Paren(exprs[0])
+		} else {
+			paren = ast.List(exprs...)
+		}
+		p.expect(token.RIGHT_PAREN)
+		p.mark(paren, pos)
+		return paren
+	case token.CASE:
+		expr := p.parseCase()
+		p.mark(expr, pos)
+		return expr
 	default:
 		p.eatUnimplemented("expression")
 		return nil
 	}
 }
 
+func (p *Parser) parseCase() *ast.CaseExpr {
+	expr := &ast.CaseExpr{}
+	p.expect(token.CASE)
+	if p.tok != token.WHEN {
+		expr.Operand = p.parseExpression()
+	}
+
+	for {
+		when := &ast.When{}
+		p.mark(when, p.tokPos)
+		p.expect(token.WHEN)
+		when.Cond = p.parseExpression()
+		p.expect(token.THEN)
+		when.Result = p.parseExpression()
+		expr.Whens = append(expr.Whens, when)
+		if p.tok != token.WHEN {
+			break
+		}
+	}
+
+	if p.tok == token.ELSE {
+		p.next() // eat ELSE
+		expr.Else = p.parseExpression()
+	}
+
+	p.expectWord("END") // END isn't reserved, so that it can be a column name
+	return expr
+}
+
+// parseCall parses the arguments of a function call, after the function name
+func (p *Parser) parseCall(name *ast.Identifier) *ast.CallExpr {
+	call := &ast.CallExpr{Name: name}
+	p.expect(token.LEFT_PAREN)
+	if p.tok == token.DISTINCT {
+		call.Distinct = true
+		p.next()
+	}
+
+	if p.tok == token.ASTERISK && !call.Distinct {
+		call.Star = true
+		p.next()
+	} else if p.tok != token.RIGHT_PAREN || call.Distinct {
+		call.Args = p.parseExpressionList()
+	}
+
+	p.expect(token.RIGHT_PAREN)
+	return call
+}
+
 // eatUnimplemented eats till the end of statement if AllowsNotImplemented is true
 func (p *Parser) eatUnimplemented(what string) {
 	if !p.rules.AllowNotImplemented && !(p.tok == token.EOS || p.tok == token.SEMICOLON) {
@@ -344,13 +911,8 @@ func (p *Parser) eatUnimplemented(what string) {
 	}
 
 	// eat till the end of statement
-	for p.tok != token.EOS {
-		if p.tok == token.SEMICOLON {
-			p.next()
-			if p.tok != token.EOS {
-				p.error(p.scanner.Pos(), `statement does not end at semicolon`)
-			}
-		}
+	for p.tok != token.EOS && p.tok != token.SEMICOLON {
+		p.incomplete = true
 		p.next()
 	}
 }
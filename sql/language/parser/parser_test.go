@@ -9,6 +9,7 @@ import (
 
 	"github.com/reflexionhealth/vanilla/expect"
 	"github.com/reflexionhealth/vanilla/sql/language/ast"
+	"github.com/reflexionhealth/vanilla/sql/language/scanner"
 	"github.com/reflexionhealth/vanilla/utils"
 )
 
@@ -24,7 +25,7 @@ func TestTraceParser(t *testing.T) {
 		regexp.QuoteMeta(`  SELECT : SELECT         @ Parser.parseSelect:`) + "[0-9]+",
 		regexp.QuoteMeta(`         : *              @ Parser.parseSelect:`) + "[0-9]+",
 		regexp.QuoteMeta(`    FROM : FROM           @ Parser.parseSelect:`) + "[0-9]+",
-		regexp.QuoteMeta(` table_~ : Identifier     @ Parser.parseSelect:`) + "[0-9]+",
+		regexp.QuoteMeta(` table_~ : Identifier     @ Parser.parseIdentifier:`) + "[0-9]+",
 		regexp.QuoteMeta(`   WHERE : WHERE          @ Parser.parseSelect:`) + "[0-9]+",
 		regexp.QuoteMeta(` (error) sql:1:42: unexpected character U+266B '♫'`),
 		"$", // string ends with newline
@@ -50,7 +51,7 @@ func TestParseErrors(t *testing.T) {
 		Error string
 	}{
 		{Input: `mytable`,
-			Error: `sql:1:8: expected 'SELECT, INSERT, or UPDATE' but received 'Identifier'`},
+			Error: `sql:1:8: expected 'SELECT, INSERT, UPDATE, or DELETE' but received 'Identifier'`},
 		{Input: `SELECT * WHERE`,
 			Error: `sql:1:15: expected 'FROM' but received 'WHERE'`},
 		{Input: `SELECT * FROM *`,
@@ -63,9 +64,33 @@ func TestParseErrors(t *testing.T) {
 			Error: `sql:1:1: unexpected character U+007E '~'`},
 		{Input: `SELECT * FROM foos; SELECT * FROM bars;`,
 			Error: `sql:1:27: statement does not end at semicolon`},
-		{Input: `SELECT * FROM mytable PROCEDURE compute(foo)`, // with HasLiteral
+		{Input: `SELECT * FROM mytable ORDER id`,
+			Error: `sql:1:31: expected 'BY' but received 'Identifier'`},
+		{Input: `SELECT * FROM mytable ORDER BY id NULLS MIDDLE`,
+			Error: `sql:1:47: expected 'FIRST or LAST' but received 'Identifier'`},
+		{Input: `SELECT * FROM mytable LIMIT 10 ORDER BY id`,
+			Error: `sql:1:37: cannot parse statement; reached unimplemented clause at 'ORDER'`},
+		{Input: `SELECT * FROM users AS WHERE`,
+			Error: `sql:1:29: expected 'an alias' but received 'WHERE'`},
+		{Input: `SELECT * FROM users JOIN teams WHERE id = 3`,
+			Error: `sql:1:37: expected 'ON or USING' but received 'WHERE'`},
+		{Input: `SELECT * FROM users LEFT teams ON users.team_id = teams.id`,
+			Error: `sql:1:31: expected 'JOIN' but received 'Identifier'`},
+		{Input: `SELECT users. FROM users`,
+			Error: `sql:1:19: expected 'a column name' but received 'FROM'`},
+		{Input: `SELECT COUNT(DISTINCT) FROM mytable`,
+			Error: `sql:1:23: cannot parse statement; reached unimplemented expression at ')'`},
+		{Input: `SELECT MAX(size FROM mytable`,
+			Error: `sql:1:21: expected ')' but received 'FROM'`},
+		{Input: `SELECT * FROM mytable WHERE (id`,
+			Error: `sql:1:32: expected ')' but received 'End of statement'`},
+		{Input: `SELECT CASE a ELSE 1 END FROM mytable`,
+			Error: `sql:1:19: expected 'WHEN' but received 'ELSE'`},
+		{Input: `SELECT CASE WHEN a THEN 1 FROM mytable`,
+			Error: `sql:1:31: expected 'END' but received 'FROM'`},
+		{Input: `SELECT * FROM mytable PROCEDURE compute(foo)`, // a keyword
 			Error: `sql:1:32: cannot parse statement; reached unimplemented clause at 'PROCEDURE'`},
-		{Input: `SELECT * FROM mytable +`, // without HasLiteral
+		{Input: `SELECT * FROM mytable +`, // an operator
 			Error: `sql:1:24: cannot parse statement; reached unimplemented clause at '+'`},
 	}
 
@@ -165,6 +190,249 @@ func TestParseSelect(t *testing.T) {
 				),
 			}},
 
+		// GROUP BY and HAVING
+		{Input: `SELECT kind FROM mytable GROUP BY kind, size HAVING size > 3`,
+			Rules: AnsiRuleset,
+			Result: &ast.SelectStmt{
+				Type:    ast.SELECT_ALL,
+				Select:  []ast.Expr{ast.Name("kind")},
+				From:    ast.Name("mytable"),
+				GroupBy: []ast.Expr{ast.Name("kind"), ast.Name("size")},
+				Having:  ast.Binary(ast.Name("size"), ast.GREATER, ast.Lit("3")),
+			}},
+
+		// ORDER BY with directions and null ordering
+		{Input: `SELECT * FROM mytable ORDER BY kind, size DESC, id ASC NULLS FIRST, name nulls last`,
+			Result: &ast.SelectStmt{
+				Type: ast.SELECT_ALL,
+				Star: true,
+				From: ast.Name("mytable"),
+				OrderBy: []*ast.OrderItem{
+					ast.Order(ast.Name("kind"), ast.ASC, ast.NULLS_DEFAULT),
+					ast.Order(ast.Name("size"), ast.DESC, ast.NULLS_DEFAULT),
+					ast.Order(ast.Name("id"), ast.ASC, ast.NULLS_FIRST),
+					ast.Order(ast.Name("name"), ast.ASC, ast.NULLS_LAST),
+				},
+			}},
+
+		// LIMIT and OFFSET after a WHERE clause
+		{Input: `SELECT * FROM mytable WHERE id > 3 ORDER BY id LIMIT 10 OFFSET 20;`,
+			Rules: AnsiRuleset,
+			Result: &ast.SelectStmt{
+				Type:    ast.SELECT_ALL,
+				Star:    true,
+				From:    ast.Name("mytable"),
+				Where:   ast.Binary(ast.Name("id"), ast.GREATER, ast.Lit("3")),
+				OrderBy: []*ast.OrderItem{ast.Order(ast.Name("id"), ast.ASC, ast.NULLS_DEFAULT)},
+				Limit:   ast.Lit("10"),
+				Offset:  ast.Lit("20"),
+			}},
+
+		// table aliases, with and without AS
+		{Input: `SELECT u.name FROM users AS u`,
+			Result: &ast.SelectStmt{
+				Type:   ast.SELECT_ALL,
+				Select: []ast.Expr{ast.Qualified(ast.Name("u"), ast.Name("name"))},
+				From:   ast.Alias(ast.Name("users"), ast.Name("u")),
+			}},
+		{Input: `SELECT * FROM users "u"`,
+			Result: &ast.SelectStmt{
+				Type: ast.SELECT_ALL,
+				Star: true,
+				From: ast.Alias(ast.Name("users"), ast.Quoted("u")),
+			}},
+
+		// joins with ON and USING
+		{Input: `SELECT * FROM users u JOIN teams t ON u.team_id = t.id LEFT OUTER JOIN roles USING (role_id, team_id)`,
+			Rules: AnsiRuleset,
+			Result: &ast.SelectStmt{
+				Type: ast.SELECT_ALL,
+				Star: true,
+				From: &ast.JoinExpr{
+					Type: ast.LEFT_JOIN,
+					Left: &ast.JoinExpr{
+						Type:  ast.INNER_JOIN,
+						Left:  ast.Alias(ast.Name("users"), ast.Name("u")),
+						Right: ast.Alias(ast.Name("teams"), ast.Name("t")),
+						On: ast.Binary(
+							ast.Qualified(ast.Name("u"), ast.Name("team_id")),
+							ast.EQUAL,
+							ast.Qualified(ast.Name("t"), ast.Name("id")),
+						),
+					},
+					Right: ast.Name("roles"),
+					Using: []*ast.Identifier{ast.Name("role_id"), ast.Name("team_id")},
+				},
+			}},
+
+		// each kind of join
+		{Input: `SELECT * FROM a INNER JOIN b ON x RIGHT JOIN c ON y FULL JOIN d ON z CROSS JOIN e, f`,
+			Result: &ast.SelectStmt{
+				Type: ast.SELECT_ALL,
+				Star: true,
+				From: &ast.JoinExpr{
+					Type: ast.CROSS_JOIN,
+					Left: &ast.JoinExpr{
+						Type: ast.CROSS_JOIN,
+						Left: &ast.JoinExpr{
+							Type: ast.FULL_JOIN,
+							Left: &ast.JoinExpr{
+								Type: ast.RIGHT_JOIN,
+								Left: &ast.JoinExpr{
+									Type:  ast.INNER_JOIN,
+									Left:  ast.Name("a"),
+									Right: ast.Name("b"),
+									On:    ast.Name("x"),
+								},
+								Right: ast.Name("c"),
+								On:    ast.Name("y"),
+							},
+							Right: ast.Name("d"),
+							On:    ast.Name("z"),
+						},
+						Right: ast.Name("e"),
+					},
+					Right: ast.Name("f"),
+				},
+			}},
+
+		// the words in joins and CASE aren't reserved, so they can be names
+		{Input: `SELECT left(name, 1), right(name, 2) FROM users`,
+			Rules: AnsiRuleset,
+			Result: &ast.SelectStmt{
+				Type: ast.SELECT_ALL,
+				Select: []ast.Expr{
+					ast.Call(ast.Name("left"), ast.Name("name"), ast.Lit("1")),
+					ast.Call(ast.Name("right"), ast.Name("name"), ast.Lit("2")),
+				},
+				From: ast.Name("users"),
+			}},
+		{Input: `SELECT start, end FROM events AS left LEFT JOIN users ON left.owner = users.id`,
+			Rules: AnsiRuleset,
+			Result: &ast.SelectStmt{
+				Type:   ast.SELECT_ALL,
+				Select: []ast.Expr{ast.Name("start"), ast.Name("end")},
+				From: &ast.JoinExpr{
+					Type:  ast.LEFT_JOIN,
+					Left:  ast.Alias(ast.Name("events"), ast.Name("left")),
+					Right: ast.Name("users"),
+					On: ast.Binary(
+						ast.Qualified(ast.Name("left"), ast.Name("owner")),
+						ast.EQUAL,
+						ast.Qualified(ast.Name("users"), ast.Name("id")),
+					),
+				},
+			}},
+		{Input: `SELECT CASE WHEN end > 5 THEN end END FROM events`,
+			Rules: AnsiRuleset,
+			Result: &ast.SelectStmt{
+				Type: ast.SELECT_ALL,
+				Select: []ast.Expr{&ast.CaseExpr{
+					Whens: []*ast.When{{Cond: ast.Binary(ast.Name("end"), ast.GREATER, ast.Lit("5")), Result: ast.Name("end")}},
+				}},
+				From: ast.Name("events"),
+			}},
+
+		// function calls and aggregates
+		{Input: `SELECT COUNT(*), MAX(DISTINCT size), COALESCE(a, b), now() FROM mytable`,
+			Result: &ast.SelectStmt{
+				Type: ast.SELECT_ALL,
+				Select: []ast.Expr{
+					&ast.CallExpr{Name: ast.Name("COUNT"), Star: true},
+					&ast.CallExpr{Name: ast.Name("MAX"), Distinct: true, Args: []ast.Expr{ast.Name("size")}},
+					ast.Call(ast.Name("COALESCE"), ast.Name("a"), ast.Name("b")),
+					ast.Call(ast.Name("now")),
+				},
+				From: ast.Name("mytable"),
+			}},
+		{Input: `SELECT kind FROM mytable GROUP BY kind HAVING count(id) > 1 ORDER BY sum(size) DESC`,
+			Rules: AnsiRuleset,
+			Result: &ast.SelectStmt{
+				Type:    ast.SELECT_ALL,
+				Select:  []ast.Expr{ast.Name("kind")},
+				From:    ast.Name("mytable"),
+				GroupBy: []ast.Expr{ast.Name("kind")},
+				Having:  ast.Binary(ast.Call(ast.Name("count"), ast.Name("id")), ast.GREATER, ast.Lit("1")),
+				OrderBy: []*ast.OrderItem{
+					ast.Order(ast.Call(ast.Name("sum"), ast.Name("size")), ast.DESC, ast.NULLS_DEFAULT),
+				},
+			}},
+
+		// grouping parentheses and lists
+		{Input: `SELECT * FROM mytable WHERE (a OR b) AND id IN (1, 2, 3)`,
+			Rules: MysqlRuleset,
+			Result: &ast.SelectStmt{
+				Type: ast.SELECT_ALL,
+				Star: true,
+				From: ast.Name("mytable"),
+				Where: ast.Binary(
+					ast.Paren(ast.Binary(ast.Name("a"), ast.OR, ast.Name("b"))),
+					ast.AND,
+					ast.Binary(ast.Name("id"), ast.IN, ast.List(ast.Lit("1"), ast.Lit("2"), ast.Lit("3"))),
+				),
+			}},
+		{Input: `SELECT * FROM mytable WHERE -(size + 1) * 2 > 5`,
+			Rules: MysqlRuleset,
+			Result: &ast.SelectStmt{
+				Type: ast.SELECT_ALL,
+				Star: true,
+				From: ast.Name("mytable"),
+				Where: ast.Binary(
+					ast.Binary(
+						ast.Unary(ast.NEGATE, ast.Paren(ast.Binary(ast.Name("size"), ast.ADD, ast.Lit("1")))),
+						ast.MULTIPLY,
+						ast.Lit("2"),
+					),
+					ast.GREATER,
+					ast.Lit("5"),
+				),
+			}},
+
+		// CASE with and without an operand
+		{Input: `SELECT CASE WHEN size > 5 THEN 'big' WHEN size > 2 THEN 'medium' ELSE 'small' END FROM mytable`,
+			Rules: AnsiRuleset,
+			Result: &ast.SelectStmt{
+				Type: ast.SELECT_ALL,
+				Select: []ast.Expr{&ast.CaseExpr{
+					Whens: []*ast.When{
+						{Cond: ast.Binary(ast.Name("size"), ast.GREATER, ast.Lit("5")), Result: ast.Lit("'big'")},
+						{Cond: ast.Binary(ast.Name("size"), ast.GREATER, ast.Lit("2")), Result: ast.Lit("'medium'")},
+					},
+					Else: ast.Lit("'small'"),
+				}},
+				From: ast.Name("mytable"),
+			}},
+		{Input: `SELECT * FROM mytable WHERE CASE kind WHEN 'muppet' THEN 1 END = 1`,
+			Rules: AnsiRuleset,
+			Result: &ast.SelectStmt{
+				Type: ast.SELECT_ALL,
+				Star: true,
+				From: ast.Name("mytable"),
+				Where: ast.Binary(
+					&ast.CaseExpr{
+						Operand: ast.Name("kind"),
+						Whens:   []*ast.When{{Cond: ast.Lit("'muppet'"), Result: ast.Lit("1")}},
+					},
+					ast.EQUAL,
+					ast.Lit("1"),
+				),
+			}},
+
+		// comments, which are ignored even if the scanner keeps them
+		{Input: "SELECT * -- everything\nFROM /* the table */ mytable",
+			Result: &ast.SelectStmt{
+				Type: ast.SELECT_ALL,
+				Star: true,
+				From: ast.Name("mytable"),
+			}},
+		{Input: "SELECT * -- everything\nFROM /* the table */ mytable",
+			Rules: Ruleset{ScanRules: scanner.Ruleset{KeepComments: true}},
+			Result: &ast.SelectStmt{
+				Type: ast.SELECT_ALL,
+				Star: true,
+				From: ast.Name("mytable"),
+			}},
+
 		// allow table-less select if someone says its ok
 		{Input: `SELECT *`, // TODO: eventually I'd like this to be `SELECT 1+1;`
 			Rules:  Ruleset{CanSelectWithoutFrom: true},
@@ -192,19 +460,498 @@ func TestParseSelect(t *testing.T) {
 }
 
 func TestParseInsert(t *testing.T) {
-	parser := New([]byte(`INSERT INTO mytable`), Ruleset{})
-	stmt, err := parser.ParseStatement()
-	expect.Nil(t, stmt)
-	if expect.NotNil(t, err) {
-		expect.Equal(t, err.Error(), `sql:1:20: cannot parse statement; reached unimplemented clause at 'mytable'`)
+	examples := []struct {
+		Input  string
+		Rules  Ruleset
+		Result ast.Stmt
+		Trace  bool // for debugging
+	}{
+		{Input: `INSERT INTO mytable VALUES (1, 'foo')`, // without columns
+			Result: &ast.InsertStmt{
+				Table:  ast.Name("mytable"),
+				Values: [][]ast.Expr{{ast.Lit("1"), ast.Lit("'foo'")}},
+			}},
+		{Input: `INSERT INTO "mytable" (id, "name") VALUES (1, 'foo');`, // with columns
+			Result: &ast.InsertStmt{
+				Table:   ast.Quoted("mytable"),
+				Columns: []*ast.Identifier{ast.Name("id"), ast.Quoted("name")},
+				Values:  [][]ast.Expr{{ast.Lit("1"), ast.Lit("'foo'")}},
+			}},
+		{Input: `INSERT INTO mytable (id, size) VALUES (1, 2), (3, -4)`, // multiple rows
+			Rules: MysqlRuleset,
+			Result: &ast.InsertStmt{
+				Table:   ast.Name("mytable"),
+				Columns: []*ast.Identifier{ast.Name("id"), ast.Name("size")},
+				Values: [][]ast.Expr{
+					{ast.Lit("1"), ast.Lit("2")},
+					{ast.Lit("3"), ast.Unary(ast.NEGATE, ast.Lit("4"))},
+				},
+			}},
+		{Input: `INSERT INTO mytable (id) SELECT id FROM othertable WHERE id > 3`, // INSERT ... SELECT
+			Rules: AnsiRuleset,
+			Result: &ast.InsertStmt{
+				Table:   ast.Name("mytable"),
+				Columns: []*ast.Identifier{ast.Name("id")},
+				Select: &ast.SelectStmt{
+					Type:   ast.SELECT_ALL,
+					Select: []ast.Expr{ast.Name("id")},
+					From:   ast.Name("othertable"),
+					Where:  ast.Binary(ast.Name("id"), ast.GREATER, ast.Lit("3")),
+				},
+			}},
+	}
+
+	for _, example := range examples {
+		parser := New([]byte(example.Input), example.Rules)
+		if example.Trace {
+			parser.Trace = os.Stdout
+		}
+		stmt, err := parser.ParseStatement()
+		expect.Nil(t, err, "Error for `"+example.Input+"`")
+		expect.Equal(t, stmt, example.Result, example.Input)
+	}
+}
+
+func TestParseInsertErrors(t *testing.T) {
+	examples := []struct {
+		Input string
+		Error string
+	}{
+		{Input: `INSERT INTO mytable`,
+			Error: `sql:1:20: expected 'VALUES or SELECT' but received 'End of statement'`},
+		{Input: `INSERT mytable VALUES (1)`,
+			Error: `sql:1:15: expected 'INTO' but received 'Identifier'`},
+		{Input: `INSERT INTO mytable (id, 3) VALUES (1, 2)`,
+			Error: `sql:1:27: expected 'a column name' but received 'Number'`},
+		{Input: `INSERT INTO mytable (id, name) VALUES (1)`,
+			Error: `sql:1:39: expected 2 values in row but received 1`},
+		{Input: `INSERT INTO mytable VALUES (1, 2), (3)`,
+			Error: `sql:1:36: expected 2 values in row but received 1`},
+		{Input: `INSERT INTO mytable VALUES (1, 2) ON DUPLICATE KEY UPDATE`,
+			Error: `sql:1:37: cannot parse statement; reached unimplemented clause at 'ON'`},
+	}
+
+	for _, example := range examples {
+		parser := New([]byte(example.Input), Ruleset{})
+		stmt, err := parser.ParseStatement()
+		expect.Nil(t, stmt)
+		if expect.NotNil(t, err, "expected a parsing error") {
+			expect.Equal(t, err.Error(), example.Error)
+		}
 	}
 }
 
 func TestParseUpdate(t *testing.T) {
-	parser := New([]byte(`UPDATE mytable SET a = 1`), Ruleset{})
-	stmt, err := parser.ParseStatement()
-	expect.Nil(t, stmt)
+	examples := []struct {
+		Input  string
+		Rules  Ruleset
+		Result ast.Stmt
+		Trace  bool // for debugging
+	}{
+		{Input: `UPDATE mytable SET a = 1`, // basics
+			Result: &ast.UpdateStmt{
+				Table: ast.Name("mytable"),
+				Set:   []*ast.Assignment{ast.Assign(ast.Name("a"), ast.Lit("1"))},
+			}},
+		{Input: `UPDATE "mytable" SET a = 'foo', "b" = c;`, // multiple assignments
+			Result: &ast.UpdateStmt{
+				Table: ast.Quoted("mytable"),
+				Set: []*ast.Assignment{
+					ast.Assign(ast.Name("a"), ast.Lit("'foo'")),
+					ast.Assign(ast.Quoted("b"), ast.Name("c")),
+				},
+			}},
+		{Input: `UPDATE mytable SET size = size + 1 WHERE id = 3 OR id = 5`, // with expressions
+			Rules: MysqlRuleset,
+			Result: &ast.UpdateStmt{
+				Table: ast.Name("mytable"),
+				Set: []*ast.Assignment{
+					ast.Assign(ast.Name("size"), ast.Binary(ast.Name("size"), ast.ADD, ast.Lit("1"))),
+				},
+				Where: ast.Binary(
+					ast.Binary(ast.Name("id"), ast.EQUAL, ast.Lit("3")),
+					ast.OR,
+					ast.Binary(ast.Name("id"), ast.EQUAL, ast.Lit("5")),
+				),
+			}},
+	}
+
+	for _, example := range examples {
+		parser := New([]byte(example.Input), example.Rules)
+		if example.Trace {
+			parser.Trace = os.Stdout
+		}
+		stmt, err := parser.ParseStatement()
+		expect.Nil(t, err, "Error for `"+example.Input+"`")
+		expect.Equal(t, stmt, example.Result, example.Input)
+	}
+}
+
+func TestParseUpdateErrors(t *testing.T) {
+	examples := []struct {
+		Input string
+		Error string
+	}{
+		{Input: `UPDATE mytable`,
+			Error: `sql:1:15: expected 'SET' but received 'End of statement'`},
+		{Input: `UPDATE mytable SET WHERE id = 3`,
+			Error: `sql:1:25: expected 'a column name' but received 'WHERE'`},
+		{Input: `UPDATE mytable SET a 1`,
+			Error: `sql:1:23: expected '=' but received 'Number'`},
+		{Input: `UPDATE mytable SET a = 1 ORDER BY id`,
+			Error: `sql:1:31: cannot parse statement; reached unimplemented clause at 'ORDER'`},
+	}
+
+	for _, example := range examples {
+		parser := New([]byte(example.Input), Ruleset{})
+		stmt, err := parser.ParseStatement()
+		expect.Nil(t, stmt)
+		if expect.NotNil(t, err, "expected a parsing error") {
+			expect.Equal(t, err.Error(), example.Error)
+		}
+	}
+}
+
+func TestParseDialects(t *testing.T) {
+	examples := []struct {
+		Input  string
+		Rules  Ruleset
+		Result ast.Stmt
+		Trace  bool // for debugging
+	}{
+		// postgres casts, params, and pattern matching
+		{Input: `SELECT * FROM mytable WHERE id::text ILIKE $1 AND name SIMILAR TO $$%(b|d)%$$`,
+			Rules: PostgresRuleset,
+			Result: &ast.SelectStmt{
+				Type: ast.SELECT_ALL,
+				Star: true,
+				From: ast.Name("mytable"),
+				Where: ast.Binary(
+					ast.Binary(ast.Binary(ast.Name("id"), ast.CAST, ast.Name("text")), ast.ILIKE, &ast.Param{Index: 1}),
+					ast.AND,
+					ast.Binary(ast.Name("name"), ast.SIMILAR_TO, ast.Lit("$$%(b|d)%$$")),
+				),
+			}},
+		{Input: `UPDATE mytable SET size = size % 3 + $2 WHERE id = $1`,
+			Rules: PostgresRuleset,
+			Result: &ast.UpdateStmt{
+				Table: ast.Name("mytable"),
+				Set: []*ast.Assignment{ast.Assign(ast.Name("size"), ast.Binary(
+					ast.Binary(ast.Name("size"), ast.MODULO, ast.Lit("3")),
+					ast.ADD,
+					&ast.Param{Index: 2},
+				))},
+				Where: ast.Binary(ast.Name("id"), ast.EQUAL, &ast.Param{Index: 1}),
+			}},
+		// mysql and sqlite params are numbered by their position in the statement
+		{Input: `INSERT INTO mytable (a, b) VALUES (?, ?), (?, 'c')`,
+			Rules: MysqlRuleset,
+			Result: &ast.InsertStmt{
+				Table:   ast.Name("mytable"),
+				Columns: []*ast.Identifier{ast.Name("a"), ast.Name("b")},
+				Values: [][]ast.Expr{
+					{&ast.Param{Index: 1}, &ast.Param{Index: 2}},
+					{&ast.Param{Index: 3}, ast.Lit("'c'")},
+				},
+			}},
+		{Input: `DELETE FROM mytable WHERE team_id = :team AND id > ?2`,
+			Rules: SqliteRuleset,
+			Result: &ast.DeleteStmt{
+				From: ast.Name("mytable"),
+				Where: ast.Binary(
+					ast.Binary(ast.Name("team_id"), ast.EQUAL, &ast.Param{Name: "team"}),
+					ast.AND,
+					ast.Binary(ast.Name("id"), ast.GREATER, &ast.Param{Index: 2}),
+				),
+			}},
+		{Input: `SELECT now()`,
+			Rules:  PostgresRuleset,
+			Result: &ast.SelectStmt{Type: ast.SELECT_ALL, Select: []ast.Expr{ast.Call(ast.Name("now"))}}},
+
+		// sqlite quoting and pattern matching
+		{Input: "SELECT [size], `kind` FROM \"mytable\" WHERE name GLOB 'k*' OR name REGEXP 'g.*'",
+			Rules: SqliteRuleset,
+			Result: &ast.SelectStmt{
+				Type:   ast.SELECT_ALL,
+				Select: []ast.Expr{ast.Quoted("size"), ast.Quoted("kind")},
+				From:   ast.Quoted("mytable"),
+				Where: ast.Binary(
+					ast.Binary(ast.Name("name"), ast.GLOB, ast.Lit("'k*'")),
+					ast.OR,
+					ast.Binary(ast.Name("name"), ast.REGEXP, ast.Lit("'g.*'")),
+				),
+			}},
+		{Input: `SELECT * FROM mytable WHERE a = b < c`, // comparisons bind tighter than equality
+			Rules: SqliteRuleset,
+			Result: &ast.SelectStmt{
+				Type: ast.SELECT_ALL,
+				Star: true,
+				From: ast.Name("mytable"),
+				Where: ast.Binary(
+					ast.Name("a"),
+					ast.EQUAL,
+					ast.Binary(ast.Name("b"), ast.LESS, ast.Name("c")),
+				),
+			}},
+	}
+
+	for _, example := range examples {
+		parser := New([]byte(example.Input), example.Rules)
+		if example.Trace {
+			parser.Trace = os.Stdout
+		}
+		stmt, err := parser.ParseStatement()
+		expect.Nil(t, err, "Error for `"+example.Input+"`")
+		expect.Equal(t, stmt, example.Result, example.Input)
+	}
+
+	// keyword literals and negated operators are parsed in every dialect
+	rulesets := []Ruleset{AnsiRuleset, MysqlRuleset, PostgresRuleset, SqliteRuleset}
+	operators := []struct {
+		Input  string
+		Result ast.Expr
+	}{
+		{Input: `SELECT * FROM mytable WHERE deleted IS NULL AND active = TRUE OR active = FALSE`,
+			Result: ast.Binary(
+				ast.Binary(
+					ast.Binary(ast.Name("deleted"), ast.IS, ast.Lit("NULL")),
+					ast.AND,
+					ast.Binary(ast.Name("active"), ast.EQUAL, ast.Lit("TRUE")),
+				),
+				ast.OR,
+				ast.Binary(ast.Name("active"), ast.EQUAL, ast.Lit("FALSE")),
+			)},
+		{Input: `SELECT * FROM mytable WHERE deleted IS NOT NULL`,
+			Result: ast.Binary(ast.Name("deleted"), ast.IS, ast.Unary(ast.NOT, ast.Lit("NULL")))},
+		{Input: `SELECT * FROM mytable WHERE id NOT IN (1, 2) AND name NOT LIKE 'k%'`,
+			Result: ast.Binary(
+				ast.Unary(ast.NOT, ast.Binary(ast.Name("id"), ast.IN, ast.List(ast.Lit("1"), ast.Lit("2")))),
+				ast.AND,
+				ast.Unary(ast.NOT, ast.Binary(ast.Name("name"), ast.LIKE, ast.Lit("'k%'"))),
+			)},
+		{Input: `SELECT * FROM mytable WHERE size BETWEEN 1 AND 2`,
+			Result: ast.Binary(ast.Name("size"), ast.BETWEEN, ast.Binary(ast.Lit("1"), ast.AND, ast.Lit("2")))},
+		{Input: `SELECT * FROM mytable WHERE size NOT BETWEEN 1 AND 2 AND id = 3`,
+			Result: ast.Binary(
+				ast.Unary(ast.NOT, ast.Binary(ast.Name("size"), ast.BETWEEN, ast.Binary(ast.Lit("1"), ast.AND, ast.Lit("2")))),
+				ast.AND,
+				ast.Binary(ast.Name("id"), ast.EQUAL, ast.Lit("3")),
+			)},
+	}
+
+	for _, rules := range rulesets {
+		for _, example := range operators {
+			parser := New([]byte(example.Input), rules)
+			stmt, err := parser.ParseStatement()
+			expect.Nil(t, err, "Error for `"+example.Input+"`")
+			expect.Equal(t, stmt, &ast.SelectStmt{
+				Type:  ast.SELECT_ALL,
+				Star:  true,
+				From:  ast.Name("mytable"),
+				Where: example.Result,
+			}, example.Input)
+		}
+	}
+
+	errors := []struct {
+		Input string
+		Rules Ruleset
+		Error string
+	}{
+		{Input: `SELECT * FROM mytable WHERE name SIMILAR 'k%'`,
+			Rules: PostgresRuleset,
+			Error: `sql:1:46: expected 'TO' but received 'String'`},
+		{Input: `SELECT * FROM mytable WHERE size BETWEEN 1 OR 2`,
+			Rules: PostgresRuleset,
+			Error: `sql:1:46: expected 'AND' but received 'OR'`},
+		{Input: `SELECT * FROM mytable WHERE size NOT = 1`,
+			Rules: PostgresRuleset,
+			Error: `sql:1:37: statement includes 'NOT', but it is not defined as an operator`},
+		{Input: `SELECT * FROM mytable WHERE id::text = '3'`,
+			Rules: SqliteRuleset,
+			Error: `sql:1:33: statement includes '::', but it is not defined as an operator`},
+		{Input: `SELECT * FROM mytable WHERE id = $1`, // postgres params in mysql
+			Rules: MysqlRuleset,
+			Error: `sql:1:35: cannot parse statement; reached unimplemented expression at '$'`},
+	}
+
+	for _, example := range errors {
+		parser := New([]byte(example.Input), example.Rules)
+		stmt, err := parser.ParseStatement()
+		expect.Nil(t, stmt)
+		if expect.NotNil(t, err, "expected a parsing error") {
+			expect.Equal(t, err.Error(), example.Error)
+		}
+	}
+}
+
+func TestParseStatements(t *testing.T) {
+	script := `
+		-- create some muppets
+		INSERT INTO muppets (name) VALUES ('kermit'), ('gonzo');;
+		UPDATE muppets SET kind = 'frog' WHERE name = 'kermit';
+
+		/* and then remove them */
+		DELETE FROM muppets
+	`
+	parser := New([]byte(script), AnsiRuleset)
+	stmts, err := parser.ParseStatements()
+	expect.Nil(t, err)
+	expect.False(t, parser.Incomplete())
+	expect.Equal(t, stmts, []ast.Stmt{
+		&ast.InsertStmt{
+			Table:   ast.Name("muppets"),
+			Columns: []*ast.Identifier{ast.Name("name")},
+			Values:  [][]ast.Expr{{ast.Lit("'kermit'")}, {ast.Lit("'gonzo'")}},
+		},
+		&ast.UpdateStmt{
+			Table: ast.Name("muppets"),
+			Set:   []*ast.Assignment{ast.Assign(ast.Name("kind"), ast.Lit("'frog'"))},
+			Where: ast.Binary(ast.Name("name"), ast.EQUAL, ast.Lit("'kermit'")),
+		},
+		&ast.DeleteStmt{From: ast.Name("muppets")},
+	})
+
+	// an empty script
+	parser = New([]byte("  -- nothing to see here\n"), AnsiRuleset)
+	stmts, err = parser.ParseStatements()
+	expect.Nil(t, err)
+	expect.Equal(t, len(stmts), 0)
+
+	// positional params are numbered separately in each statement
+	parser = New([]byte(`DELETE FROM a WHERE id = ?; DELETE FROM b WHERE id = ?`), MysqlRuleset)
+	stmts, err = parser.ParseStatements()
+	expect.Nil(t, err)
+	expect.Equal(t, stmts, []ast.Stmt{
+		&ast.DeleteStmt{From: ast.Name("a"), Where: ast.Binary(ast.Name("id"), ast.EQUAL, &ast.Param{Index: 1})},
+		&ast.DeleteStmt{From: ast.Name("b"), Where: ast.Binary(ast.Name("id"), ast.EQUAL, &ast.Param{Index: 1})},
+	})
+
+	// unimplemented clauses are skipped until the semicolon
+	parser = New([]byte(`SELECT * FROM foos PROCEDURE compute(foo); SELECT * FROM bars`), Ruleset{AllowNotImplemented: true})
+	stmts, err = parser.ParseStatements()
+	expect.Nil(t, err)
+	expect.Equal(t, len(stmts), 2)
+	expect.True(t, parser.Incomplete(), "expected the parser to report the skipped clause")
+
+	// the statements before an error are returned
+	parser = New([]byte(`SELECT * FROM foos; SELECT * FROM; SELECT * FROM bars`), AnsiRuleset)
+	stmts, err = parser.ParseStatements()
+	expect.Equal(t, stmts, []ast.Stmt{
+		&ast.SelectStmt{Type: ast.SELECT_ALL, Star: true, From: ast.Name("foos")},
+	})
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.Error(), `sql:1:35: expected 'a table name' but received ';'`)
+	}
+}
+
+func TestParseAll(t *testing.T) {
+	src := "SELECT * FROM foos;\n" +
+		"SELECT * FROM;\n" +
+		"DELETE FROM bars WHERE id = 1e;\n" +
+		"UPDATE bars SET a = 1;\n" +
+		"INSERT INTO bars VALUES (1, 2), (3);\n" +
+		"SELECT * FROM bars`\n"
+	parser := New([]byte(src), AnsiRuleset)
+	stmts, err := parser.ParseAll()
+	expect.Equal(t, stmts, []ast.Stmt{
+		&ast.SelectStmt{Type: ast.SELECT_ALL, Star: true, From: ast.Name("foos")},
+		&ast.UpdateStmt{Table: ast.Name("bars"), Set: []*ast.Assignment{ast.Assign(ast.Name("a"), ast.Lit("1"))}},
+	})
 	if expect.NotNil(t, err) {
-		expect.Equal(t, err.Error(), `sql:1:15: cannot parse statement; reached unimplemented clause at 'mytable'`)
+		errors, ok := err.(ErrorList)
+		if expect.True(t, ok) {
+			var messages []string
+			for _, e := range errors {
+				messages = append(messages, e.Error())
+			}
+			expect.Equal(t, messages, []string{
+				`sql:2:15: expected 'a table name' but received ';'`,
+				`sql:3:29: missing digits after exponent in number`,
+				`sql:5:33: expected 2 values in row but received 1`,
+				"sql:6:19: unexpected character U+0060 '`'",
+			})
+		}
+		expect.Equal(t, err.Error(), `sql:2:15: expected 'a table name' but received ';' (and 3 more errors)`)
+	}
+
+	// without errors, the error is nil (rather than an empty ErrorList)
+	parser = New([]byte(`SELECT * FROM foos; SELECT * FROM bars`), AnsiRuleset)
+	stmts, err = parser.ParseAll()
+	expect.Nil(t, err)
+	expect.Equal(t, len(stmts), 2)
+	expect.Equal(t, ErrorList{}.Error(), "no errors")
+}
+
+func TestParsePositions(t *testing.T) {
+	parser := New([]byte("INSERT INTO mytable (id)\n  VALUES (1), (-2)"), MysqlRuleset)
+	parser.Positions = Positions{}
+	stmt, err := parser.ParseStatement()
+	expect.Nil(t, err)
+
+	insert := stmt.(*ast.InsertStmt)
+	expect.Equal(t, parser.Positions[insert].String(), "sql:1:1")
+	expect.Equal(t, parser.Positions[insert.Table].String(), "sql:1:13")
+	expect.Equal(t, parser.Positions[insert.Columns[0]].String(), "sql:1:22")
+	expect.Equal(t, parser.Positions[insert.Values[0][0]].String(), "sql:2:11")
+	expect.Equal(t, parser.Positions[insert.Values[1][0]].String(), "sql:2:16")
+	expect.Equal(t, parser.Positions[insert.Values[1][0].(*ast.UnaryExpr).Subexpr].String(), "sql:2:17")
+}
+
+func TestParseDelete(t *testing.T) {
+	examples := []struct {
+		Input  string
+		Rules  Ruleset
+		Result ast.Stmt
+		Trace  bool // for debugging
+	}{
+		{Input: `DELETE FROM mytable`, // basics
+			Result: &ast.DeleteStmt{From: ast.Name("mytable")}},
+		{Input: `DELETE FROM "mytable" WHERE id = 3;`, // with WHERE
+			Rules: AnsiRuleset,
+			Result: &ast.DeleteStmt{
+				From:  ast.Quoted("mytable"),
+				Where: ast.Binary(ast.Name("id"), ast.EQUAL, ast.Lit("3")),
+			}},
+		{Input: `DELETE FROM mytable WHERE size > 5 LIMIT 10`, // with LIMIT
+			Rules: AnsiRuleset,
+			Result: &ast.DeleteStmt{
+				From:  ast.Name("mytable"),
+				Where: ast.Binary(ast.Name("size"), ast.GREATER, ast.Lit("5")),
+				Limit: ast.Lit("10"),
+			}},
+	}
+
+	for _, example := range examples {
+		parser := New([]byte(example.Input), example.Rules)
+		if example.Trace {
+			parser.Trace = os.Stdout
+		}
+		stmt, err := parser.ParseStatement()
+		expect.Nil(t, err, "Error for `"+example.Input+"`")
+		expect.Equal(t, stmt, example.Result, example.Input)
+	}
+}
+
+func TestParseDeleteErrors(t *testing.T) {
+	examples := []struct {
+		Input string
+		Error string
+	}{
+		{Input: `DELETE mytable`,
+			Error: `sql:1:15: expected 'FROM' but received 'Identifier'`},
+		{Input: `DELETE FROM`,
+			Error: `sql:1:12: expected 'a table name' but received 'End of statement'`},
+		{Input: `DELETE FROM mytable LIMIT 10 WHERE id = 3`,
+			Error: `sql:1:35: cannot parse statement; reached unimplemented clause at 'WHERE'`},
+	}
+
+	for _, example := range examples {
+		parser := New([]byte(example.Input), Ruleset{})
+		stmt, err := parser.ParseStatement()
+		expect.Nil(t, stmt)
+		if expect.NotNil(t, err, "expected a parsing error") {
+			expect.Equal(t, err.Error(), example.Error)
+		}
 	}
 }
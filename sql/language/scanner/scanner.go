@@ -1,6 +1,7 @@
 package scanner
 
 import (
+	"bytes"
 	"fmt"
 	"unicode/utf8"
 
@@ -30,8 +31,30 @@ type Ruleset struct {
 
 	DollarIsLetter bool
 
-	// CStyleComment bool
-	// CStyleEscapeSeq bool
+	// DollarQuotedStrings controls whether "$$text$$" and "$tag$text$tag$" are
+	// scanned as strings (as in Postgres)
+	DollarQuotedStrings bool
+
+	// DollarParams controls whether "$1", "$2", etc are scanned as PARAM
+	// tokens, where the literal is the parameter's number
+	DollarParams bool
+
+	// QuestionParams controls whether "?" is scanned as a PARAM token with an
+	// empty literal, or with the number as the literal if it is like "?2"
+	QuestionParams bool
+
+	// ColonParams controls whether named parameters like ":id" are scanned as
+	// PARAM tokens, where the literal is the parameter's name
+	ColonParams bool
+
+	// KeepComments controls whether Scan returns "--" and "/* */" comments as
+	// COMMENT tokens.  Otherwise, they are skipped like whitespace.
+	KeepComments bool
+
+	// CStyleEscapeSeq controls whether backslash escapes in strings are
+	// validated, allowing only \' \" \\ \/ \0 \b \f \n \r \t and \uXXXX.
+	// It also disallows control characters other than tab in strings.
+	CStyleEscapeSeq bool
 }
 
 // A Scanner holds the scanner's internal state.
@@ -42,11 +65,12 @@ type Scanner struct {
 	rules Ruleset
 
 	// scanning state
-	char       rune // current character
-	offset     int  // byte offset to current char
-	readOffset int  // reading offset (position after current character)
-	lineOffset int  // current line offset
-	line       int  // current line
+	char       rune           // current character
+	offset     int            // byte offset to current char
+	readOffset int            // reading offset (position after current character)
+	lineOffset int            // current line offset
+	line       int            // current line
+	start      token.Position // position of the last scanned token
 
 	// public state
 	ErrorCount int // number of errors encountered
@@ -91,9 +115,10 @@ func (s *Scanner) Init(src []byte, err ErrorHandler, rules Ruleset) {
 //
 // In all other cases, Scan returns an empty literal string.
 func (s *Scanner) Scan() (pos int, tok token.Token, lit string) {
-	// scanAgain:
+scanAgain:
 	s.skipWhitespace()
 
+	s.start = s.Pos()
 	pos = s.offset
 	ch := s.char
 	switch {
@@ -111,9 +136,6 @@ func (s *Scanner) Scan() (pos int, tok token.Token, lit string) {
 		switch ch {
 		case -1:
 			tok = token.EOS
-		// case ???:
-		// 	s.scanComment()
-		// 	goto scanAgain
 		case '"':
 			if s.rules.DoubleQuoteIsString {
 				tok, lit = s.scanString('"')
@@ -133,19 +155,50 @@ func (s *Scanner) Scan() (pos int, tok token.Token, lit string) {
 		case ';':
 			tok = token.SEMICOLON
 		case ':':
-			tok = token.COLON
+			if s.char == ':' {
+				s.next()
+				tok = token.CONS
+			} else if s.rules.ColonParams && isLetter(s.char) {
+				tok, lit = token.PARAM, s.scanIdentifier()
+			} else {
+				tok = token.COLON
+			}
 		case '$':
-			tok = token.DOLLAR
+			if s.rules.DollarParams && isDigit(s.char) {
+				offset := s.offset
+				s.scanMantissa()
+				tok, lit = token.PARAM, string(s.src[offset:s.offset])
+			} else if s.rules.DollarQuotedStrings && (s.char == '$' || isLetter(s.char)) {
+				tok, lit = s.scanDollarString()
+			} else {
+				tok = token.DOLLAR
+			}
 		case '*':
 			tok = token.ASTERISK
 		case '?':
-			tok = token.QUESTION
+			if s.rules.QuestionParams {
+				offset := s.offset
+				s.scanMantissa()
+				tok, lit = token.PARAM, string(s.src[offset:s.offset])
+			} else {
+				tok = token.QUESTION
+			}
+		case '%':
+			tok = token.PERCENT
 		case '+':
 			tok = token.PLUS
 		case '-':
-			tok = token.MINUS
+			if s.char == '-' {
+				tok, lit = token.COMMENT, s.scanLineComment()
+			} else {
+				tok = token.MINUS
+			}
 		case '/':
-			tok = token.SLASH
+			if s.char == '*' {
+				tok, lit = s.scanBlockComment()
+			} else {
+				tok = token.SLASH
+			}
 		case ',':
 			tok = token.COMMA
 		case '=':
@@ -201,9 +254,19 @@ func (s *Scanner) Scan() (pos int, tok token.Token, lit string) {
 		}
 	}
 
+	if tok == token.COMMENT && !s.rules.KeepComments {
+		goto scanAgain
+	}
+
 	return
 }
 
+// TokenPos returns the position of the beginning of the token most recently
+// returned by Scan.
+func (s *Scanner) TokenPos() token.Position {
+	return s.start
+}
+
 func (s *Scanner) Pos() token.Position {
 	// Get length of current line in UTF-8 characters
 	column := 1 + len(string(s.src[s.lineOffset:s.offset]))
@@ -277,6 +340,38 @@ func (s *Scanner) skipWhitespace() {
 	}
 }
 
+func (s *Scanner) scanLineComment() string {
+	// first dash already consumed
+	offset := s.offset - 1
+	for s.char != '\n' && s.char != '\r' && s.char >= 0 {
+		s.next()
+	}
+
+	return string(s.src[offset:s.offset])
+}
+
+func (s *Scanner) scanBlockComment() (token.Token, string) {
+	// opening slash already consumed
+	offset := s.offset - 1
+	s.next() // eat asterisk
+
+	for {
+		ch := s.char
+		if ch < 0 {
+			s.error(offset, "unterminated comment")
+			return token.INVALID, string(s.src[offset:s.offset])
+		}
+
+		s.next()
+		if ch == '*' && s.char == '/' {
+			s.next()
+			break
+		}
+	}
+
+	return token.COMMENT, string(s.src[offset:s.offset])
+}
+
 func (s *Scanner) scanIdentifier() string {
 	offset := s.offset
 	for isLetter(s.char) || isDigit(s.char) || (s.char == '$' && s.rules.DollarIsLetter) {
@@ -307,6 +402,38 @@ func (s *Scanner) scanQuotedIdentifier(closemark rune) (token.Token, string) {
 	return tok, lit
 }
 
+func (s *Scanner) scanDollarString() (token.Token, string) {
+	// opening dollar already consumed
+	offset := s.offset - 1
+	for isLetter(s.char) || isDigit(s.char) {
+		s.next()
+	}
+	if s.char < 0 {
+		s.error(offset, "unterminated dollar quote")
+		return token.INVALID, string(s.src[offset:s.offset])
+	} else if s.char != '$' {
+		s.error(offset, fmt.Sprintf("unexpected character in dollar quote: %#U", s.char))
+		return token.INVALID, string(s.src[offset:s.offset])
+	}
+	s.next()
+
+	delimiter := s.src[offset:s.offset]
+	for {
+		if s.char < 0 {
+			s.error(offset, "unterminated string")
+			return token.INVALID, string(s.src[offset:s.offset])
+		} else if s.char == '$' && bytes.HasPrefix(s.src[s.offset:], delimiter) {
+			for range delimiter {
+				s.next()
+			}
+			break
+		}
+		s.next()
+	}
+
+	return token.STRING, string(s.src[offset:s.offset])
+}
+
 func (s *Scanner) scanMantissa() {
 	for isDigit(s.char) {
 		s.next()
@@ -351,21 +478,86 @@ func (s *Scanner) scanString(qouteMark rune) (token.Token, string) {
 	offset := s.offset - 1
 	tok := token.STRING
 
+	// only the first error in a string is reported
 	for {
 		ch := s.char
 		if ch == '\n' || ch == '\r' || ch < 0 {
+			if tok != token.INVALID {
+				s.error(offset, "unterminated string")
+			}
 			tok = token.INVALID
-			s.error(offset, "unterminated string")
 			break
-		} else if ch == '\\' {
-			s.next()
 		}
 
 		s.next()
 		if ch == qouteMark {
 			break
+		} else if ch == '\\' {
+			if !s.rules.CStyleEscapeSeq {
+				s.next()
+			} else if !s.scanEscape(qouteMark, tok == token.INVALID) {
+				tok = token.INVALID
+			}
+		} else if s.rules.CStyleEscapeSeq && ch < ' ' && ch != '\t' && tok != token.INVALID {
+			s.error(offset, fmt.Sprintf("unexpected character in string: %U", ch))
+			tok = token.INVALID
 		}
 	}
 
 	return tok, string(s.src[offset:s.offset])
 }
+
+// scanEscape scans the escape sequence after a backslash in a string,
+// reporting an error unless quiet (and returning false) if it is invalid
+func (s *Scanner) scanEscape(quoteMark rune, quiet bool) bool {
+	offset := s.offset
+	fail := func(msg string) bool {
+		if !quiet {
+			s.error(offset, msg)
+		}
+		return false
+	}
+
+	switch s.char {
+	case quoteMark, '\'', '"', '\\', '/', '0', 'b', 'f', 'n', 'r', 't':
+		s.next()
+		return true
+	case 'u':
+		s.next()
+		var code rune
+		for i := 0; i < 4; i++ {
+			ch := s.char
+			if ch < 0 {
+				return fail("unterminated escape sequence")
+			}
+
+			digit := hexValue(ch)
+			if digit < 0 {
+				return fail(fmt.Sprintf("unexpected character in escape sequence: %#U", ch))
+			}
+			code = code*16 + digit
+			s.next()
+		}
+		if !utf8.ValidRune(code) {
+			return fail("escape sequence is invalid unicode code point")
+		}
+		return true
+	case -1:
+		return fail("unterminated escape sequence")
+	default:
+		return fail(fmt.Sprintf(`unknown escape sequence: \%c`, s.char))
+	}
+}
+
+func hexValue(ch rune) rune {
+	switch {
+	case '0' <= ch && ch <= '9':
+		return ch - '0'
+	case 'a' <= ch && ch <= 'f':
+		return ch - 'a' + 10
+	case 'A' <= ch && ch <= 'F':
+		return ch - 'A' + 10
+	default:
+		return -1
+	}
+}
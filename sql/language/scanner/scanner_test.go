@@ -105,17 +105,77 @@ func TestSkipsWhitesace(t *testing.T) {
 	expect.Equal(t, scan.pos, 5)
 	expect.Equal(t, scan.lit, "SELECT")
 
-	// scan, err = scanOnce("\n    --comment\n    SELECT--comment\n")
-	// expect.Nil(t, err)
-	// expect.Equal(t, scan.tok, token.SELECT)
-	// expect.Equal(t, scan.pos, 18)
-	// expect.Equal(t, scan.lit, "SELECT")
-	//
-	// scan, err = scanOnce("\n    --comment\r\n    SELECT--comment\n")
-	// expect.Nil(t, err)
-	// expect.Equal(t, scan.tok, token.SELECT)
-	// expect.Equal(t, scan.pos, 19)
-	// expect.Equal(t, scan.lit, "SELECT")
+	scan, err = scanOnce("\n    --comment\n    SELECT--comment\n")
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.SELECT)
+	expect.Equal(t, scan.pos, 19)
+	expect.Equal(t, scan.lit, "SELECT")
+
+	scan, err = scanOnce("\n    --comment\r\n    SELECT--comment\n")
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.SELECT)
+	expect.Equal(t, scan.pos, 20)
+	expect.Equal(t, scan.lit, "SELECT")
+
+	scan, err = scanOnce("/* a\n * comment */ SELECT")
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.SELECT)
+	expect.Equal(t, scan.pos, 19)
+	expect.Equal(t, scan.lit, "SELECT")
+}
+
+func TestScansComments(t *testing.T) {
+	rules := Ruleset{KeepComments: true}
+	scan, err := scanOnceWith("-- a comment\nSELECT", rules)
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.COMMENT)
+	expect.Equal(t, scan.pos, 0)
+	expect.Equal(t, scan.lit, "-- a comment")
+
+	scan, err = scanOnceWith("/* a\n * comment */ SELECT", rules)
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.COMMENT)
+	expect.Equal(t, scan.pos, 0)
+	expect.Equal(t, scan.lit, "/* a\n * comment */")
+
+	scan, err = scanOnceWith("/**/", rules)
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.COMMENT)
+	expect.Equal(t, scan.lit, "/**/")
+
+	scan, err = scanOnce("  /* no end *")
+	expect.Equal(t, scan.tok, token.INVALID)
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.pos.Offset, 2)
+		expect.Equal(t, err.pos.Column, 3)
+		expect.Equal(t, err.msg, "unterminated comment")
+	}
+
+	// a single dash or slash is still an operator
+	scan, err = scanOnce("- -")
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.MINUS)
+	scan, err = scanOnce("/ *")
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.SLASH)
+}
+
+func TestCommentPositions(t *testing.T) {
+	s := Scanner{}
+	s.Init([]byte("SELECT /* multi\nline */ *\n-- line\nFROM"), nil, Ruleset{KeepComments: true})
+
+	s.Scan()
+	expect.Equal(t, s.TokenPos(), token.Position{"sql", 0, 1, 1})
+	s.Scan()
+	expect.Equal(t, s.TokenPos(), token.Position{"sql", 7, 1, 8})
+	s.Scan()
+	expect.Equal(t, s.TokenPos(), token.Position{"sql", 24, 2, 9})
+	_, tok, _ := s.Scan()
+	expect.Equal(t, tok, token.COMMENT)
+	expect.Equal(t, s.TokenPos(), token.Position{"sql", 26, 3, 1})
+	_, tok, _ = s.Scan()
+	expect.Equal(t, tok, token.FROM)
+	expect.Equal(t, s.TokenPos(), token.Position{"sql", 34, 4, 1})
 }
 
 func TestErrorsRespectWhitespace(t *testing.T) {
@@ -192,6 +252,20 @@ func TestScansStrings(t *testing.T) {
 	expect.Equal(t, scan.pos, 0)
 	expect.Equal(t, scan.lit, `'slashes \\ \/'`)
 
+	escapes := Ruleset{CStyleEscapeSeq: true}
+	scan, err = scanOnceWith(`'escaped \n\r\b\t\f\0 \\ \/ \' \" \u00e9 \u00E9'`, escapes)
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.STRING)
+	expect.Equal(t, scan.lit, `'escaped \n\r\b\t\f\0 \\ \/ \' \" \u00e9 \u00E9'`)
+
+	scan, err = scanOnceWith("'tab\tseparated'", escapes)
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.STRING)
+
+	scan, err = scanOnce(`'unchecked \z \x \u1'`) // without CStyleEscapeSeq
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.STRING)
+
 	scan, err = scanOnceWith(`"simple"`, Ruleset{DoubleQuoteIsString: true})
 	expect.Nil(t, err)
 	expect.Equal(t, scan.tok, token.STRING)
@@ -200,6 +274,8 @@ func TestScansStrings(t *testing.T) {
 }
 
 func TestReportsUsefulStringErrors(t *testing.T) {
+	escapes := Ruleset{CStyleEscapeSeq: true}
+
 	scan, err := scanOnce(`'`)
 	expect.Equal(t, scan.tok, token.INVALID)
 	if expect.NotNil(t, err) {
@@ -218,59 +294,59 @@ func TestReportsUsefulStringErrors(t *testing.T) {
 		expect.Equal(t, err.msg, `unterminated string`)
 	}
 
-	// scan, err = scanOnce("'contains unescaped \u0007 control char'")
-	// expect.Equal(t, scan.tok, token.INVALID)
-	// if expect.NotNil(t, err) {
-	// 	expect.Equal(t, err.pos.Offset, 0)
-	// 	expect.Equal(t, err.pos.Line, 1)
-	// 	expect.Equal(t, err.pos.Column, 1)
-	// 	expect.Equal(t, err.msg, `unexpected character in string: U+0007`)
-	// }
-
-	// scan, err = scanOnce("'null-byte \u0000 in string'")
-	// expect.Equal(t, scan.tok, token.INVALID)
-	// if expect.NotNil(t, err) {
-	// 	expect.Equal(t, err.pos.Offset, 0)
-	// 	expect.Equal(t, err.pos.Line, 1)
-	// 	expect.Equal(t, err.pos.Column, 1)
-	// 	expect.Equal(t, err.msg, `unexpected character in string: U+0000`)
-	// }
-
-	// scan, err = scanOnce(`'\u`)
-	// expect.Equal(t, scan.tok, token.INVALID)
-	// if expect.NotNil(t, err) {
-	// 	expect.Equal(t, err.pos.Offset, 2)
-	// 	expect.Equal(t, err.pos.Line, 1)
-	// 	expect.Equal(t, err.pos.Column, 3)
-	// 	expect.Equal(t, err.msg, `unterminated escape sequence`)
-	// }
-
-	// scan, err = scanOnce(`'\`)
-	// expect.Equal(t, scan.tok, token.INVALID)
-	// if expect.NotNil(t, err) {
-	// 	expect.Equal(t, err.pos.Offset, 2)
-	// 	expect.Equal(t, err.pos.Line, 1)
-	// 	expect.Equal(t, err.pos.Column, 3)
-	// 	expect.Equal(t, err.msg, `unterminated escape sequence`)
-	// }
-
-	// scan, err = scanOnce(`'\m'`)
-	// expect.Equal(t, scan.tok, token.INVALID)
-	// if expect.NotNil(t, err) {
-	// 	expect.Equal(t, err.pos.Offset, 2)
-	// 	expect.Equal(t, err.pos.Line, 1)
-	// 	expect.Equal(t, err.pos.Column, 3)
-	// 	expect.Equal(t, err.msg, `unknown escape sequence`)
-	// }
-
-	// scan, err = scanOnce(`'\uD800'`)
-	// expect.Equal(t, scan.tok, token.INVALID)
-	// if expect.NotNil(t, err) {
-	// 	expect.Equal(t, err.pos.Offset, 2)
-	// 	expect.Equal(t, err.pos.Line, 1)
-	// 	expect.Equal(t, err.pos.Column, 3)
-	// 	expect.Equal(t, err.msg, `escape sequence is invalid unicode code point`)
-	// }
+	scan, err = scanOnceWith("'contains unescaped \u0007 control char'", escapes)
+	expect.Equal(t, scan.tok, token.INVALID)
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.pos.Offset, 0)
+		expect.Equal(t, err.pos.Line, 1)
+		expect.Equal(t, err.pos.Column, 1)
+		expect.Equal(t, err.msg, `unexpected character in string: U+0007`)
+	}
+
+	scan, err = scanOnceWith("'null-byte \u0000 in string'", escapes)
+	expect.Equal(t, scan.tok, token.INVALID)
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.pos.Offset, 0)
+		expect.Equal(t, err.pos.Line, 1)
+		expect.Equal(t, err.pos.Column, 1)
+		expect.Equal(t, err.msg, `unexpected character in string: U+0000`)
+	}
+
+	scan, err = scanOnceWith(`'\u`, escapes)
+	expect.Equal(t, scan.tok, token.INVALID)
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.pos.Offset, 2)
+		expect.Equal(t, err.pos.Line, 1)
+		expect.Equal(t, err.pos.Column, 3)
+		expect.Equal(t, err.msg, `unterminated escape sequence`)
+	}
+
+	scan, err = scanOnceWith(`'\`, escapes)
+	expect.Equal(t, scan.tok, token.INVALID)
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.pos.Offset, 2)
+		expect.Equal(t, err.pos.Line, 1)
+		expect.Equal(t, err.pos.Column, 3)
+		expect.Equal(t, err.msg, `unterminated escape sequence`)
+	}
+
+	scan, err = scanOnceWith(`'\m'`, escapes)
+	expect.Equal(t, scan.tok, token.INVALID)
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.pos.Offset, 2)
+		expect.Equal(t, err.pos.Line, 1)
+		expect.Equal(t, err.pos.Column, 3)
+		expect.Equal(t, err.msg, `unknown escape sequence: \m`)
+	}
+
+	scan, err = scanOnceWith(`'\uD800'`, escapes)
+	expect.Equal(t, scan.tok, token.INVALID)
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.pos.Offset, 2)
+		expect.Equal(t, err.pos.Line, 1)
+		expect.Equal(t, err.pos.Column, 3)
+		expect.Equal(t, err.msg, `escape sequence is invalid unicode code point`)
+	}
 
 	scan, err = scanOnce("'multi\nline'")
 	expect.Equal(t, scan.tok, token.INVALID)
@@ -290,68 +366,148 @@ func TestReportsUsefulStringErrors(t *testing.T) {
 		expect.Equal(t, err.msg, `unterminated string`)
 	}
 
-	// scan, err = scanOnce(`'bad \z esc'`)
-	// expect.Equal(t, scan.tok, token.INVALID)
-	// if expect.NotNil(t, err) {
-	// 	expect.Equal(t, err.pos.Offset, 6)
-	// 	expect.Equal(t, err.pos.Line, 1)
-	// 	expect.Equal(t, err.pos.Column, 7)
-	// 	expect.Equal(t, err.msg, `unexpected character escape sequence: \z`)
-	// }
-
-	// scan, err = scanOnce(`'bad \x esc'`)
-	// expect.Equal(t, scan.tok, token.INVALID)
-	// if expect.NotNil(t, err) {
-	// 	expect.Equal(t, err.pos.Offset, 6)
-	// 	expect.Equal(t, err.pos.Line, 1)
-	// 	expect.Equal(t, err.pos.Column, 7)
-	// 	expect.Equal(t, err.msg, `unexpected character escape sequence: \x`)
-	// }
-
-	// scan, err = scanOnce(`'bad \u1 esc'`)
-	// expect.Equal(t, scan.tok, token.INVALID)
-	// if expect.NotNil(t, err) {
-	// 	expect.Equal(t, err.pos.Offset, 6)
-	// 	expect.Equal(t, err.pos.Line, 1)
-	// 	expect.Equal(t, err.pos.Column, 7)
-	// 	expect.Equal(t, err.msg, `unexpected character in escape sequence: U+0020 ' '`)
-	// }
-
-	// scan, err = scanOnce(`'bad \u0XX1 esc'`)
-	// expect.Equal(t, scan.tok, token.INVALID)
-	// if expect.NotNil(t, err) {
-	// 	expect.Equal(t, err.pos.Offset, 6)
-	// 	expect.Equal(t, err.pos.Line, 1)
-	// 	expect.Equal(t, err.pos.Column, 7)
-	// 	expect.Equal(t, err.msg, `unexpected character in escape sequence: U+0058 'X'`)
-	// }
-
-	// scan, err = scanOnce(`'bad \uXXXX esc'`)
-	// expect.Equal(t, scan.tok, token.INVALID)
-	// if expect.NotNil(t, err) {
-	// 	expect.Equal(t, err.pos.Offset, 6)
-	// 	expect.Equal(t, err.pos.Line, 1)
-	// 	expect.Equal(t, err.pos.Column, 7)
-	// 	expect.Equal(t, err.msg, `unexpected character in escape sequence: U+0058 'X'`)
-	// }
-
-	// scan, err = scanOnce(`'bad \uFXXX esc'`)
-	// expect.Equal(t, scan.tok, token.INVALID)
-	// if expect.NotNil(t, err) {
-	// 	expect.Equal(t, err.pos.Offset, 6)
-	// 	expect.Equal(t, err.pos.Line, 1)
-	// 	expect.Equal(t, err.pos.Column, 7)
-	// 	expect.Equal(t, err.msg, `unexpected character in escape sequence: U+0058 'X'`)
-	// }
-
-	// scan, err = scanOnce(`'bad \uXXXF esc'`)
-	// expect.Equal(t, scan.tok, token.INVALID)
-	// if expect.NotNil(t, err) {
-	// 	expect.Equal(t, err.pos.Offset, 6)
-	// 	expect.Equal(t, err.pos.Line, 1)
-	// 	expect.Equal(t, err.pos.Column, 7)
-	// 	expect.Equal(t, err.msg, `unexpected character in escape sequence: U+0058 'X'`)
-	// }
+	scan, err = scanOnceWith(`'bad \z esc'`, escapes)
+	expect.Equal(t, scan.tok, token.INVALID)
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.pos.Offset, 6)
+		expect.Equal(t, err.pos.Line, 1)
+		expect.Equal(t, err.pos.Column, 7)
+		expect.Equal(t, err.msg, `unknown escape sequence: \z`)
+	}
+
+	scan, err = scanOnceWith(`'bad \x esc'`, escapes)
+	expect.Equal(t, scan.tok, token.INVALID)
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.pos.Offset, 6)
+		expect.Equal(t, err.pos.Line, 1)
+		expect.Equal(t, err.pos.Column, 7)
+		expect.Equal(t, err.msg, `unknown escape sequence: \x`)
+	}
+
+	scan, err = scanOnceWith(`'bad \u1 esc'`, escapes)
+	expect.Equal(t, scan.tok, token.INVALID)
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.pos.Offset, 6)
+		expect.Equal(t, err.pos.Line, 1)
+		expect.Equal(t, err.pos.Column, 7)
+		expect.Equal(t, err.msg, `unexpected character in escape sequence: U+0020 ' '`)
+	}
+
+	scan, err = scanOnceWith(`'bad \u0XX1 esc'`, escapes)
+	expect.Equal(t, scan.tok, token.INVALID)
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.pos.Offset, 6)
+		expect.Equal(t, err.pos.Line, 1)
+		expect.Equal(t, err.pos.Column, 7)
+		expect.Equal(t, err.msg, `unexpected character in escape sequence: U+0058 'X'`)
+	}
+
+	scan, err = scanOnceWith(`'bad \uXXXX esc'`, escapes)
+	expect.Equal(t, scan.tok, token.INVALID)
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.pos.Offset, 6)
+		expect.Equal(t, err.pos.Line, 1)
+		expect.Equal(t, err.pos.Column, 7)
+		expect.Equal(t, err.msg, `unexpected character in escape sequence: U+0058 'X'`)
+	}
+
+	scan, err = scanOnceWith(`'bad \uFXXX esc'`, escapes)
+	expect.Equal(t, scan.tok, token.INVALID)
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.pos.Offset, 6)
+		expect.Equal(t, err.pos.Line, 1)
+		expect.Equal(t, err.pos.Column, 7)
+		expect.Equal(t, err.msg, `unexpected character in escape sequence: U+0058 'X'`)
+	}
+
+	scan, err = scanOnceWith(`'bad \uXXXF esc'`, escapes)
+	expect.Equal(t, scan.tok, token.INVALID)
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.pos.Offset, 6)
+		expect.Equal(t, err.pos.Line, 1)
+		expect.Equal(t, err.pos.Column, 7)
+		expect.Equal(t, err.msg, `unexpected character in escape sequence: U+0058 'X'`)
+	}
+}
+
+func TestScansDollarQuotedStrings(t *testing.T) {
+	rules := Ruleset{DollarQuotedStrings: true}
+	scan, err := scanOnceWith(`$$it's simple$$`, rules)
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.STRING)
+	expect.Equal(t, scan.pos, 0)
+	expect.Equal(t, scan.lit, `$$it's simple$$`)
+
+	scan, err = scanOnceWith("$fn$ multi\n $$line$$ $fn$ trailing", rules)
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.STRING)
+	expect.Equal(t, scan.lit, "$fn$ multi\n $$line$$ $fn$")
+
+	scan, err = scanOnceWith(`$tag$ no end $$`, rules)
+	expect.Equal(t, scan.tok, token.INVALID)
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.pos.Offset, 0)
+		expect.Equal(t, err.msg, `unterminated string`)
+	}
+
+	scan, err = scanOnceWith(`$tag`, rules)
+	expect.Equal(t, scan.tok, token.INVALID)
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.pos.Offset, 0)
+		expect.Equal(t, err.msg, `unterminated dollar quote`)
+	}
+
+	scan, err = scanOnceWith(`$tag-`, rules)
+	expect.Equal(t, scan.tok, token.INVALID)
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.pos.Offset, 0)
+		expect.Equal(t, err.msg, `unexpected character in dollar quote: U+002D '-'`)
+	}
+
+	scan, err = scanOnce(`$$`) // without DollarQuotedStrings
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.DOLLAR)
+}
+
+func TestScansParams(t *testing.T) {
+	scan, err := scanOnceWith(`$12`, Ruleset{DollarParams: true})
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.PARAM)
+	expect.Equal(t, scan.pos, 0)
+	expect.Equal(t, scan.lit, "12")
+
+	scan, err = scanOnce(`$1`) // without DollarParams
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.DOLLAR)
+
+	scan, err = scanOnceWith(`?`, Ruleset{QuestionParams: true})
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.PARAM)
+	expect.Equal(t, scan.pos, 0)
+	expect.Equal(t, scan.lit, "")
+
+	scan, err = scanOnceWith(`?3`, Ruleset{QuestionParams: true})
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.PARAM)
+	expect.Equal(t, scan.lit, "3")
+
+	scan, err = scanOnce(`?`) // without QuestionParams
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.QUESTION)
+
+	scan, err = scanOnceWith(`:team_id`, Ruleset{ColonParams: true})
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.PARAM)
+	expect.Equal(t, scan.pos, 0)
+	expect.Equal(t, scan.lit, "team_id")
+
+	scan, err = scanOnceWith(`::`, Ruleset{ColonParams: true})
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.CONS)
+
+	scan, err = scanOnce(`:team_id`) // without ColonParams
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.COLON)
 }
 
 func TestScansNumbers(t *testing.T) {
@@ -509,6 +665,18 @@ func TestScansPunctuation(t *testing.T) {
 	expect.Equal(t, scan.pos, 0)
 	expect.Equal(t, scan.lit, "")
 
+	scan, err = scanOnce("::")
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.CONS)
+	expect.Equal(t, scan.pos, 0)
+	expect.Equal(t, scan.lit, "")
+
+	scan, err = scanOnce("%")
+	expect.Nil(t, err)
+	expect.Equal(t, scan.tok, token.PERCENT)
+	expect.Equal(t, scan.pos, 0)
+	expect.Equal(t, scan.lit, "")
+
 	scan, err = scanOnce("=")
 	expect.Nil(t, err)
 	expect.Equal(t, scan.tok, token.EQUALS)
@@ -643,3 +811,17 @@ func TestScanPos(t *testing.T) {
 	expect.Equal(t, s.Pos(), token.Position{"sql", 25, 3, 3})
 	expect.Nil(t, err)
 }
+
+func TestScanTokenPos(t *testing.T) {
+	s := Scanner{}
+	s.Init([]byte("INSERT INTO\n  candies"), nil, Ruleset{})
+
+	s.Scan()
+	expect.Equal(t, s.TokenPos(), token.Position{"sql", 0, 1, 1})
+	s.Scan()
+	expect.Equal(t, s.TokenPos(), token.Position{"sql", 7, 1, 8})
+	s.Scan()
+	expect.Equal(t, s.TokenPos(), token.Position{"sql", 14, 2, 3})
+	s.Scan()
+	expect.Equal(t, s.TokenPos(), token.Position{"sql", 21, 2, 10})
+}
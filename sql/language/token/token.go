@@ -55,6 +55,7 @@ const (
 	// Literals
 	STRING
 	NUMBER
+	PARAM
 
 	// Punctuation
 	SEMICOLON
@@ -110,6 +111,7 @@ const (
 	DESC
 	LIMIT
 	OFFSET
+	PROCEDURE
 
 	INSERT
 	INTO
@@ -118,6 +120,8 @@ const (
 	UPDATE
 	SET
 
+	DELETE
+
 	WITH
 	AS
 	ALL
@@ -129,6 +133,11 @@ const (
 	TRUE
 	FALSE
 
+	CASE
+	WHEN
+	THEN
+	ELSE
+
 	_beginKeywordOperators
 
 	AND
@@ -142,6 +151,7 @@ const (
 	ILIKE
 	REGEXP
 	SIMILAR
+	GLOB
 
 	_endKeywordOperators
 
@@ -158,6 +168,7 @@ var tokens = [...]string{
 
 	STRING: "String",
 	NUMBER: "Number",
+	PARAM:  "Parameter",
 
 	SEMICOLON: ";",
 	COLON:     ":",
@@ -204,6 +215,8 @@ var tokens = [...]string{
 	LIMIT:  "LIMIT",
 	OFFSET: "OFFSET",
 
+	PROCEDURE: "PROCEDURE",
+
 	INSERT: "INSERT",
 	INTO:   "INTO",
 	VALUES: "VALUES",
@@ -211,6 +224,8 @@ var tokens = [...]string{
 	UPDATE: "UPDATE",
 	SET:    "SET",
 
+	DELETE: "DELETE",
+
 	WITH:        "WITH",
 	AS:          "AS",
 	ALL:         "ALL",
@@ -222,6 +237,11 @@ var tokens = [...]string{
 	TRUE:  "TRUE",
 	FALSE: "FALSE",
 
+	CASE: "CASE",
+	WHEN: "WHEN",
+	THEN: "THEN",
+	ELSE: "ELSE",
+
 	AND:      "AND",
 	OR:       "OR",
 	IS:       "IS",
@@ -233,6 +253,7 @@ var tokens = [...]string{
 	ILIKE:    "ILIKE",
 	REGEXP:   "REGEXP",
 	SIMILAR:  "SIMILAR",
+	GLOB:     "GLOB",
 
 	_endKeywords: "",
 }
@@ -269,7 +290,7 @@ func Lookup(ident string) Token {
 }
 
 func (tok Token) HasLiteral() bool {
-	return COMMENT <= tok && tok <= NUMBER
+	return COMMENT <= tok && tok <= PARAM
 }
 
 func (tok Token) IsKeyword() bool {
@@ -110,6 +110,7 @@ func TestHasLiteral(t *testing.T) {
 
 	expect.Equal(t, STRING.HasLiteral(), true)
 	expect.Equal(t, NUMBER.HasLiteral(), true)
+	expect.Equal(t, PARAM.HasLiteral(), true)
 
 	expect.Equal(t, SEMICOLON.HasLiteral(), false)
 	expect.Equal(t, COLON.HasLiteral(), false)
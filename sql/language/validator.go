@@ -0,0 +1,357 @@
+package language
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/reflexionhealth/vanilla/sql"
+	"github.com/reflexionhealth/vanilla/sql/language/ast"
+	"github.com/reflexionhealth/vanilla/sql/language/parser"
+	"github.com/reflexionhealth/vanilla/sql/language/token"
+)
+
+// A Diagnostic is a semantic problem found in a statement by a Validator
+type Diagnostic struct {
+	Pos token.Position
+	Msg string
+}
+
+func (d Diagnostic) String() string {
+	return d.Pos.String() + ": " + d.Msg
+}
+
+// A Validator checks that parsed statements are consistent with a schema,
+// reporting unknown tables and columns, ambiguous column names, and
+// comparisons between values of incompatible types.
+//
+// Names are compared case-insensitively unless they are quoted.
+type Validator struct {
+	tables []sql.Table
+}
+
+// NewValidator returns a Validator for statements using the tables
+func NewValidator(tables ...sql.Table) *Validator {
+	return &Validator{tables}
+}
+
+// Validate returns the problems found in a statement.  If positions were
+// recorded while parsing the statement, the diagnostics include the position
+// of the offending part of the statement.
+func (v *Validator) Validate(stmt ast.Stmt, positions parser.Positions) []Diagnostic {
+	c := &checker{validator: v, positions: positions}
+	c.stmt(stmt)
+	return c.diagnostics
+}
+
+// ValidateSource parses each statement in src and validates it, returning a
+// ParseError if the source can't be parsed.
+func (v *Validator) ValidateSource(src []byte, rules parser.Ruleset) ([]Diagnostic, error) {
+	p := parser.New(src, rules)
+	p.Positions = parser.Positions{}
+	stmts, err := p.ParseStatements()
+	if err != nil {
+		return nil, err
+	}
+
+	var diagnostics []Diagnostic
+	for _, stmt := range stmts {
+		diagnostics = append(diagnostics, v.Validate(stmt, p.Positions)...)
+	}
+	return diagnostics, nil
+}
+
+func (v *Validator) lookupTable(name *ast.Identifier) *sql.Table {
+	for i := range v.tables {
+		if nameMatches(name, v.tables[i].Name) {
+			return &v.tables[i]
+		}
+	}
+	return nil
+}
+
+func nameMatches(ident *ast.Identifier, name string) bool {
+	if ident.Quoted {
+		return ident.Name == name
+	}
+	return strings.EqualFold(ident.Name, name)
+}
+
+func findColumn(table *sql.Table, name *ast.Identifier) *sql.Column {
+	for i := range table.Columns {
+		if nameMatches(name, table.Columns[i].Name) {
+			return &table.Columns[i]
+		}
+	}
+	return nil
+}
+
+// valueKind is a broad category of sql types, for finding comparisons which
+// are certainly wrong (without knowing the exact rules of each dialect)
+type valueKind int
+
+const (
+	unknownKind valueKind = iota
+	numberKind
+	textKind
+	booleanKind
+)
+
+var kindNames = [...]string{
+	unknownKind: "unknown",
+	numberKind:  "number",
+	textKind:    "text",
+	booleanKind: "boolean",
+}
+
+var typeKinds = map[string]valueKind{
+	"int": numberKind, "integer": numberKind, "smallint": numberKind, "bigint": numberKind,
+	"tinyint": numberKind, "mediumint": numberKind, "int2": numberKind, "int4": numberKind,
+	"int8": numberKind, "serial": numberKind, "smallserial": numberKind, "bigserial": numberKind,
+	"real": numberKind, "float": numberKind, "float4": numberKind, "float8": numberKind,
+	"double": numberKind, "double precision": numberKind, "numeric": numberKind, "decimal": numberKind,
+
+	"text": textKind, "varchar": textKind, "char": textKind, "character": textKind,
+	"character varying": textKind, "nvarchar": textKind, "nchar": textKind, "citext": textKind,
+	"tinytext": textKind, "mediumtext": textKind, "longtext": textKind,
+
+	"bool": booleanKind, "boolean": booleanKind,
+}
+
+// columnKind categorizes a column's type, like "varchar(255) not null"
+func columnKind(column *sql.Column) valueKind {
+	typ := strings.ToLower(strings.TrimSpace(column.Type))
+	if i := strings.IndexByte(typ, '('); i >= 0 {
+		typ = strings.TrimSpace(typ[:i])
+	}
+	return typeKinds[typ]
+}
+
+func literalKind(lit *ast.Literal) valueKind {
+	if len(lit.Raw) == 0 {
+		return unknownKind
+	}
+
+	switch lit.Raw {
+	case "NULL":
+		return unknownKind
+	case "TRUE", "FALSE":
+		return booleanKind
+	}
+
+	switch lit.Raw[0] {
+	case '$':
+		return textKind // a dollar-quoted string
+	case '\'', '"':
+		// strings containing a number can usually be compared to numbers
+		contents := strings.TrimSpace(lit.Raw[1 : len(lit.Raw)-1])
+		if len(contents) > 0 && strings.Trim(contents, "0123456789.-+e") == "" {
+			return unknownKind
+		}
+		return textKind
+	default:
+		return numberKind
+	}
+}
+
+var comparisons = map[ast.OpType]bool{
+	ast.EQUAL:            true,
+	ast.NOT_EQUAL:        true,
+	ast.LESS:             true,
+	ast.LESS_OR_EQUAL:    true,
+	ast.GREATER:          true,
+	ast.GREATER_OR_EQUAL: true,
+}
+
+// scopeTable is a table which can be referred to by name in an expression
+type scopeTable struct {
+	name  *ast.Identifier // the table's name or alias
+	table *sql.Table      // nil if the table is unknown
+}
+
+type checker struct {
+	validator   *Validator
+	positions   parser.Positions
+	diagnostics []Diagnostic
+	scope       []scopeTable
+}
+
+func (c *checker) report(node interface{}, format string, args ...interface{}) {
+	c.diagnostics = append(c.diagnostics, Diagnostic{c.positions[node], fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) stmt(stmt ast.Stmt) {
+	switch s := stmt.(type) {
+	case *ast.SelectStmt:
+		c.selectStmt(s)
+	case *ast.InsertStmt:
+		table := c.addTable(s.Table, s.Table)
+		for _, column := range s.Columns {
+			c.column(table, column)
+		}
+		for _, row := range s.Values {
+			c.exprs(row)
+		}
+		if s.Select != nil {
+			c.selectStmt(s.Select)
+		}
+	case *ast.UpdateStmt:
+		table := c.addTable(s.Table, s.Table)
+		for _, assign := range s.Set {
+			c.column(table, assign.Column)
+			c.expr(assign.Value)
+		}
+		c.expr(s.Where)
+	case *ast.DeleteStmt:
+		c.addTable(s.From, s.From)
+		c.expr(s.Where)
+		c.expr(s.Limit)
+	}
+}
+
+func (c *checker) selectStmt(s *ast.SelectStmt) {
+	outer := c.scope
+	c.scope = nil
+	c.from(s.From)
+
+	c.exprs(s.Select)
+	c.expr(s.Where)
+	c.exprs(s.GroupBy)
+	c.expr(s.Having)
+	for _, item := range s.OrderBy {
+		c.expr(item.Expr)
+	}
+	c.expr(s.Limit)
+	c.expr(s.Offset)
+	c.scope = outer
+}
+
+func (c *checker) from(table ast.TableExpr) {
+	switch t := table.(type) {
+	case *ast.Identifier:
+		c.addTable(t, t)
+	case *ast.AliasedTable:
+		c.addTable(t.Table, t.Alias)
+	case *ast.JoinExpr:
+		c.from(t.Left)
+		c.from(t.Right)
+		c.expr(t.On)
+		for _, column := range t.Using {
+			c.resolve(nil, column, true)
+		}
+	}
+}
+
+// addTable brings a table into scope with a name, reporting if it doesn't exist
+func (c *checker) addTable(table *ast.Identifier, name *ast.Identifier) *sql.Table {
+	found := c.validator.lookupTable(table)
+	if found == nil {
+		c.report(table, "unknown table %q", table.Name)
+	}
+	c.scope = append(c.scope, scopeTable{name, found})
+	return found
+}
+
+// column checks that a column exists in a table, if the table is known
+func (c *checker) column(table *sql.Table, name *ast.Identifier) {
+	if table != nil && findColumn(table, name) == nil {
+		c.report(name, "unknown column %q in table %q", name.Name, table.Name)
+	}
+}
+
+func (c *checker) exprs(exprs []ast.Expr) {
+	for _, expr := range exprs {
+		c.expr(expr)
+	}
+}
+
+// expr checks an expression, returning the kind of value it has (if known)
+func (c *checker) expr(expr ast.Expr) valueKind {
+	switch e := expr.(type) {
+	case *ast.Identifier:
+		return c.resolve(nil, e, false)
+	case *ast.QualifiedName:
+		return c.resolve(e.Qualifier, e.Name, false)
+	case *ast.Literal:
+		return literalKind(e)
+	case *ast.BinaryExpr:
+		left, right := c.expr(e.Left), c.expr(e.Right)
+		if comparisons[e.Operator] && left != unknownKind && right != unknownKind && left != right {
+			c.report(e, "cannot compare %s with %s", describe(e.Left, left), describe(e.Right, right))
+		}
+	case *ast.UnaryExpr:
+		c.expr(e.Subexpr)
+	case *ast.CallExpr:
+		c.exprs(e.Args)
+	case *ast.ParenExpr:
+		return c.expr(e.Expr)
+	case *ast.ListExpr:
+		c.exprs(e.Exprs)
+	case *ast.CaseExpr:
+		c.expr(e.Operand)
+		for _, when := range e.Whens {
+			c.expr(when.Cond)
+			c.expr(when.Result)
+		}
+		c.expr(e.Else)
+	}
+	return unknownKind
+}
+
+func describe(expr ast.Expr, kind valueKind) string {
+	switch e := expr.(type) {
+	case *ast.Identifier:
+		return fmt.Sprintf("%s column %q", kindNames[kind], e.Name)
+	case *ast.QualifiedName:
+		return fmt.Sprintf("%s column %q", kindNames[kind], e.Qualifier.Name+"."+e.Name.Name)
+	case *ast.Literal:
+		return kindNames[kind] + " " + e.Raw
+	case *ast.ParenExpr:
+		return describe(e.Expr, kind)
+	default:
+		return kindNames[kind]
+	}
+}
+
+// resolve finds the column a name refers to, reporting if it is unknown or
+// ambiguous (unless the column is allowed to be in multiple tables).
+func (c *checker) resolve(qualifier, name *ast.Identifier, allowAmbiguous bool) valueKind {
+	if qualifier != nil {
+		for _, entry := range c.scope {
+			if nameMatches(qualifier, entry.name.Name) {
+				if entry.table == nil {
+					return unknownKind // the table was already reported
+				}
+				column := findColumn(entry.table, name)
+				if column == nil {
+					c.report(name, "unknown column %q in table %q", name.Name, entry.table.Name)
+					return unknownKind
+				}
+				return columnKind(column)
+			}
+		}
+		c.report(qualifier, "unknown table or alias %q", qualifier.Name)
+		return unknownKind
+	}
+
+	var found *sql.Column
+	var tables []string
+	incomplete := false
+	for _, entry := range c.scope {
+		if entry.table == nil {
+			incomplete = true
+		} else if column := findColumn(entry.table, name); column != nil {
+			found = column
+			tables = append(tables, fmt.Sprintf("%q", entry.name.Name))
+		}
+	}
+
+	switch {
+	case len(tables) == 0 && !incomplete:
+		c.report(name, "unknown column %q", name.Name)
+	case len(tables) > 1 && !allowAmbiguous:
+		c.report(name, "ambiguous column %q is in tables %s", name.Name, strings.Join(tables, ", "))
+	case len(tables) == 1:
+		return columnKind(found)
+	}
+	return unknownKind
+}
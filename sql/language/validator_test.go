@@ -0,0 +1,102 @@
+package language
+
+import (
+	"testing"
+
+	"github.com/reflexionhealth/vanilla/expect"
+	"github.com/reflexionhealth/vanilla/sql"
+	"github.com/reflexionhealth/vanilla/sql/language/ast"
+	"github.com/reflexionhealth/vanilla/sql/language/parser"
+)
+
+var validatorTables = []sql.Table{
+	{Name: "users", Columns: []sql.Column{
+		{Name: "id", Type: "integer"},
+		{Name: "team_id", Type: "integer"},
+		{Name: "name", Type: "varchar(255)"},
+		{Name: "admin", Type: "boolean"},
+	}},
+	{Name: "teams", Columns: []sql.Column{
+		{Name: "id", Type: "integer"},
+		{Name: "Name", Type: "text"},
+	}},
+}
+
+func TestValidateSource(t *testing.T) {
+	examples := []struct {
+		Input       string
+		Diagnostics []string
+	}{
+		// valid statements
+		{Input: `SELECT id, name FROM users WHERE team_id = 3 ORDER BY name`},
+		{Input: `SELECT u.name, t.name FROM users AS u JOIN teams t ON u.team_id = t.id WHERE t.id > 2`},
+		{Input: `SELECT * FROM users JOIN teams USING (id) WHERE team_id = '3'`},
+		{Input: `SELECT COUNT(*) FROM users GROUP BY team_id HAVING COUNT(id) > 1`},
+		{Input: `SELECT "Name" FROM teams WHERE NAME = 'muppets'`},
+		{Input: `INSERT INTO users (id, name) VALUES (1, 'kermit'); INSERT INTO teams SELECT id, name FROM users`},
+		{Input: `UPDATE users SET name = 'gonzo' WHERE id = 2; DELETE FROM users WHERE name LIKE 'g%'`},
+		{Input: `SELECT * FROM users WHERE admin = TRUE AND name <> NULL`},
+
+		// unknown tables and columns
+		{Input: `SELECT * FROM muppets WHERE id = 3`,
+			Diagnostics: []string{`sql:1:15: unknown table "muppets"`}},
+		{Input: `SELECT size FROM users WHERE users.kind = 'frog'`,
+			Diagnostics: []string{
+				`sql:1:8: unknown column "size"`,
+				`sql:1:36: unknown column "kind" in table "users"`,
+			}},
+		{Input: `SELECT x.id FROM users u`,
+			Diagnostics: []string{`sql:1:8: unknown table or alias "x"`}},
+		{Input: `SELECT "name" FROM teams`,
+			Diagnostics: []string{`sql:1:8: unknown column "name"`}},
+		{Input: `INSERT INTO users (id, size) VALUES (1, 2)`,
+			Diagnostics: []string{`sql:1:24: unknown column "size" in table "users"`}},
+		{Input: `UPDATE teams SET size = 2 WHERE kind = 3`,
+			Diagnostics: []string{
+				`sql:1:18: unknown column "size" in table "teams"`,
+				`sql:1:33: unknown column "kind"`,
+			}},
+		{Input: `DELETE FROM users WHERE size > 3`,
+			Diagnostics: []string{`sql:1:25: unknown column "size"`}},
+
+		// ambiguous columns
+		{Input: `SELECT id FROM users JOIN teams ON team_id = teams.id`,
+			Diagnostics: []string{`sql:1:8: ambiguous column "id" is in tables "users", "teams"`}},
+
+		// incompatible comparisons
+		{Input: `SELECT * FROM users WHERE id = 'kermit'`,
+			Diagnostics: []string{`sql:1:27: cannot compare number column "id" with text 'kermit'`}},
+		{Input: `SELECT * FROM users u JOIN teams t ON (u.name) = t.id`,
+			Diagnostics: []string{`sql:1:39: cannot compare text column "u.name" with number column "t.id"`}},
+		{Input: `SELECT * FROM users WHERE 3 <> admin`,
+			Diagnostics: []string{`sql:1:27: cannot compare number 3 with boolean column "admin"`}},
+		{Input: `SELECT * FROM users WHERE id = FALSE`,
+			Diagnostics: []string{`sql:1:27: cannot compare number column "id" with boolean FALSE`}},
+	}
+
+	validator := NewValidator(validatorTables...)
+	for _, example := range examples {
+		diagnostics, err := validator.ValidateSource([]byte(example.Input), parser.AnsiRuleset)
+		expect.Nil(t, err, example.Input)
+
+		var messages []string
+		for _, diagnostic := range diagnostics {
+			messages = append(messages, diagnostic.String())
+		}
+		expect.Equal(t, messages, example.Diagnostics, example.Input)
+	}
+}
+
+func TestValidateWithoutPositions(t *testing.T) {
+	stmt := &ast.SelectStmt{Star: true, From: ast.Name("muppets")}
+	diagnostics := NewValidator(validatorTables...).Validate(stmt, nil)
+	expect.Equal(t, diagnostics, []Diagnostic{{Msg: `unknown table "muppets"`}})
+	expect.Equal(t, diagnostics[0].String(), `-: unknown table "muppets"`)
+}
+
+func TestValidateSourceParseError(t *testing.T) {
+	_, err := NewValidator(validatorTables...).ValidateSource([]byte(`SELECT * FROM`), parser.AnsiRuleset)
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.Error(), `sql:1:14: expected 'a table name' but received 'End of statement'`)
+	}
+}
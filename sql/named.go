@@ -0,0 +1,127 @@
+package sql
+
+import (
+	"bytes"
+	conn "database/sql"
+	"fmt"
+)
+
+// Named returns a named arg for conditions which refer to their arguments
+// by name instead of by position:
+//
+//     dialect.Select("*").From("users").Where("team_id = :team OR owner_id = :user",
+//         sql.Named("team", 3), sql.Named("user", 7))
+//
+// Each ":name" in the condition is replaced with a positional placeholder
+// for the statement's Dialect, and the arg is repeated if the name is used
+// more than once.  This works for the Where, WhereOr, Having, and Join
+// methods of each statement, which all build a Raw condition.  The Set method
+// of an UpdateStmt also accepts a named arg, but binds only its value, since
+// there is no sql in which to refer to the name:
+//
+//     user := sql.Named("user", 7)
+//     dialect.Update("teams").Set("updated_by", user).Where("owner_id = :user", user)
+//
+// It is the same as database/sql.Named, which can also be used.
+func Named(name string, value interface{}) conn.NamedArg {
+	return conn.Named(name, value)
+}
+
+// A NamedArgError is thrown while building a condition if its named args
+// don't match the names in the condition, or if named and positional args
+// are mixed in the same condition.
+type NamedArgError struct {
+	Sql     string
+	Problem string
+}
+
+func (e *NamedArgError) Error() string {
+	return fmt.Sprintf("in condition %q %v", e.Sql, e.Problem)
+}
+
+type namedCondition struct {
+	parts []string // the sql before and after each placeholder
+	args  []interface{}
+}
+
+func hasNamedArgs(args []interface{}) bool {
+	for _, arg := range args {
+		if _, ok := arg.(conn.NamedArg); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func newNamedCondition(sql string, args []interface{}) *namedCondition {
+	values := make(map[string]interface{}, len(args))
+	unused := make(map[string]bool, len(args))
+	for _, arg := range args {
+		named, ok := arg.(conn.NamedArg)
+		if !ok {
+			panic(&NamedArgError{sql, "mixes named and positional args"})
+		}
+		values[named.Name] = named.Value
+		unused[named.Name] = true
+	}
+
+	cond := &namedCondition{}
+	start := 0
+	quoted := false
+	for i := 0; i < len(sql); i++ {
+		switch {
+		case sql[i] == '\'':
+			quoted = !quoted
+		case quoted || sql[i] != ':':
+			continue
+		case i+1 < len(sql) && sql[i+1] == ':':
+			i++ // a cast like "x::text", rather than a name
+		case i+1 < len(sql) && isNameStart(sql[i+1]):
+			end := i + 2
+			for end < len(sql) && isNamePart(sql[end]) {
+				end++
+			}
+
+			name := sql[i+1 : end]
+			value, ok := values[name]
+			if !ok {
+				panic(&NamedArgError{sql, "uses :" + name + " but it wasn't provided"})
+			}
+			delete(unused, name)
+
+			cond.parts = append(cond.parts, sql[start:i])
+			cond.args = append(cond.args, value)
+			start = end
+			i = end - 1
+		}
+	}
+	cond.parts = append(cond.parts, sql[start:])
+
+	for _, arg := range args {
+		if name := arg.(conn.NamedArg).Name; unused[name] {
+			panic(&NamedArgError{sql, "was provided :" + name + " but doesn't use it"})
+		}
+	}
+	return cond
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isNamePart(c byte) bool {
+	return isNameStart(c) || ('0' <= c && c <= '9')
+}
+
+func (c *namedCondition) WriteSql(buf *bytes.Buffer, dct *Dialect, argOffset int) {
+	for i, part := range c.parts {
+		if i > 0 {
+			buf.WriteString(dct.Placeholder(argOffset + i))
+		}
+		buf.WriteString(part)
+	}
+}
+
+func (c *namedCondition) Args() []interface{} {
+	return c.args
+}
@@ -0,0 +1,60 @@
+package sql
+
+import (
+	conn "database/sql"
+	"testing"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+func TestNamedArgs(t *testing.T) {
+	postgres := Dialect{IdentOpen: '"', IdentClose: '"', Placeholder: PlaceholderDollar}
+
+	stmt := postgres.Select("*").From("users").
+		WhereCond(Eq("active", true)).
+		Where("(team_id = :team OR owner_id = :user OR invited_by = :user)", Named("user", 7), Named("team", 3)).
+		Where("name::text <> ':user' AND email = :email", conn.Named("email", "a@b.c"))
+	expected := `SELECT * FROM "users" WHERE active = $1 ` +
+		`AND (team_id = $2 OR owner_id = $3 OR invited_by = $4) ` +
+		`AND name::text <> ':user' AND email = $5`
+	expect.Equal(t, stmt.Sql(), expected)
+	expect.Equal(t, stmt.Args(), []interface{}{true, 3, 7, 7, "a@b.c"})
+
+	update := Update("users").Set("active", false).Where("id = :id", Named("id", 5))
+	expect.Equal(t, update.Sql(), `UPDATE "users" SET "active" = ? WHERE id = ?`)
+	expect.Equal(t, update.Args(), []interface{}{false, 5})
+
+	user := Named("user", 7)
+	update = postgres.Update("teams").Set("updated_by", user).Set("name", "muppets").
+		Where("owner_id = :user OR :user = ANY(admins)", user)
+	expect.Equal(t, update.Sql(), `UPDATE "teams" SET "updated_by" = $1, "name" = $2 WHERE owner_id = $3 OR $4 = ANY(admins)`)
+	expect.Equal(t, update.Args(), []interface{}{7, "muppets", 7, 7})
+
+	// colon placeholders are not names
+	oracle := Dialect{IdentOpen: '"', IdentClose: '"', Placeholder: PlaceholderColon}
+	remove := oracle.Delete("users").Where("id = :id", Named("id", 5)).WhereCond(Eq("team_id", 3))
+	expect.Equal(t, remove.Sql(), `DELETE FROM "users" WHERE id = :1 AND team_id = :2`)
+}
+
+func TestNamedArgErrors(t *testing.T) {
+	examples := []struct {
+		Sql     string
+		Args    []interface{}
+		Problem string
+	}{
+		{"id = :id AND team_id = ?", []interface{}{Named("id", 5), 3}, "mixes named and positional args"},
+		{"id = :id", []interface{}{Named("user", 5)}, "uses :id but it wasn't provided"},
+		{"id = :id", []interface{}{Named("id", 5), Named("team", 3)}, "was provided :team but doesn't use it"},
+	}
+	for _, ex := range examples {
+		func() {
+			defer func() {
+				err, ok := recover().(*NamedArgError)
+				expect.True(t, ok)
+				expect.Equal(t, err.Problem, ex.Problem)
+				expect.Equal(t, err.Sql, ex.Sql)
+			}()
+			Raw(ex.Sql, ex.Args...)
+		}()
+	}
+}
@@ -0,0 +1,156 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// A SchemaError describes how a Table differs from the table in a database
+type SchemaError struct {
+	Table        string
+	TableMissing bool     // the table doesn't exist in the database
+	Missing      []string // columns of the Table which aren't in the database
+	Extra        []string // columns in the database which aren't in the Table
+}
+
+func (e *SchemaError) Error() string {
+	if e.TableMissing {
+		return fmt.Sprintf("sql: table %v does not exist", e.Table)
+	}
+
+	var problems []string
+	if len(e.Missing) > 0 {
+		problems = append(problems, "is missing columns "+strings.Join(e.Missing, ", "))
+	}
+	if len(e.Extra) > 0 {
+		problems = append(problems, "has extra columns "+strings.Join(e.Extra, ", "))
+	}
+	return fmt.Sprintf("sql: table %v %v", e.Table, strings.Join(problems, " and "))
+}
+
+// Validate compares the table to the columns of the table with the same
+// name in the current schema of the database's information_schema (see
+// Dialect.CurrentSchema), and returns a SchemaError if they differ.  It is
+// meant to be called when a service starts, to check that the schema matches
+// the code's expectations:
+//
+//     if err := usersTable.Validate(ctx, db, &postgres); err != nil {
+//         log.Fatal(err)
+//     }
+//
+// Only the names of columns are compared, because the types reported by
+// information_schema are often spelled differently than in a CREATE TABLE.
+func (t *Table) Validate(ctx context.Context, db Runner, dct *Dialect) error {
+	schemaErr, err := t.compare(ctx, db, dct)
+	if err != nil {
+		return err
+	} else if schemaErr != nil {
+		return schemaErr
+	}
+	return nil
+}
+
+// A DiffOption changes the statement returned by Table.Diff
+type DiffOption int
+
+const (
+	// DropColumns makes Diff drop the columns in the database which aren't in
+	// the table, which destroys the data in those columns
+	DropColumns DiffOption = iota + 1
+)
+
+// Diff compares the table to the database like Validate, and returns the
+// statement that would make the database match the table: a CREATE TABLE
+// if the table doesn't exist, an ALTER TABLE to add the missing columns, or
+// nil if there is nothing to add.
+//
+// Extra columns in the database are only dropped with the DropColumns option:
+//
+//     stmt, err := usersTable.Diff(ctx, db, &postgres, sql.DropColumns)
+//
+func (t *Table) Diff(ctx context.Context, db Runner, dct *Dialect, opts ...DiffOption) (Sqler, error) {
+	schemaErr, err := t.compare(ctx, db, dct)
+	if err != nil || schemaErr == nil {
+		return nil, err
+	}
+
+	if schemaErr.TableMissing {
+		return t.Create().Dialect(dct), nil
+	}
+
+	dropColumns := false
+	for _, opt := range opts {
+		if opt == DropColumns {
+			dropColumns = true
+		}
+	}
+	if len(schemaErr.Missing) == 0 && !dropColumns {
+		return nil, nil
+	}
+
+	alter := AlterTable(t.Name).Dialect(dct)
+	for _, col := range t.Columns {
+		for _, name := range schemaErr.Missing {
+			if col.Name == name {
+				alter.AddColumn(col)
+			}
+		}
+	}
+	if dropColumns {
+		for _, name := range schemaErr.Extra {
+			alter.DropColumn(name)
+		}
+	}
+	return alter, nil
+}
+
+func (t *Table) compare(ctx context.Context, db Runner, dct *Dialect) (*SchemaError, error) {
+	dct = useDialect(dct)
+	query := "SELECT column_name FROM information_schema.columns WHERE table_schema = " +
+		dct.currentSchema() + " AND table_name = " + dct.Placeholder(1) + " ORDER BY ordinal_position"
+	rows, err := db.QueryContext(ctx, query, t.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	existing := make(map[string]bool)
+	var ordered []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		if !existing[name] {
+			existing[name] = true
+			ordered = append(ordered, name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(ordered) == 0 {
+		return &SchemaError{Table: t.Name, TableMissing: true}, nil
+	}
+
+	schemaErr := &SchemaError{Table: t.Name}
+	expected := make(map[string]bool)
+	for _, col := range t.Columns {
+		expected[col.Name] = true
+		if !existing[col.Name] {
+			schemaErr.Missing = append(schemaErr.Missing, col.Name)
+		}
+	}
+	for _, name := range ordered {
+		if !expected[name] {
+			schemaErr.Extra = append(schemaErr.Extra, name)
+		}
+	}
+
+	if len(schemaErr.Missing) == 0 && len(schemaErr.Extra) == 0 {
+		return nil, nil
+	}
+	return schemaErr, nil
+}
@@ -0,0 +1,82 @@
+package sql
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+func TestTableValidate(t *testing.T) {
+	postgres := Dialect{IdentOpen: '"', IdentClose: '"', Placeholder: PlaceholderDollar}
+	ctx := context.Background()
+	tbl := Table{
+		Name: "testers",
+		Columns: []Column{
+			{"name", "text", []string{"NOT NULL"}},
+			{"experience", "integer", []string{"DEFAULT 0"}},
+			{"pet_name", "text", nil},
+		},
+	}
+
+	rec, db := openRecorder()
+	defer db.Close()
+	rec.results = [][][]driver.Value{{{"name"}, {"experience"}, {"pet_name"}}}
+	expect.Nil(t, tbl.Validate(ctx, db, &postgres))
+	expect.Equal(t, rec.Events(), []string{
+		"SELECT column_name FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = $1 ORDER BY ordinal_position",
+	})
+
+	rec.results = [][][]driver.Value{{{"name"}, {"experience"}, {"pet_name"}}}
+	stmt, err := tbl.Diff(ctx, db, &postgres)
+	expect.Nil(t, err)
+	expect.Nil(t, stmt)
+
+	rec.results = [][][]driver.Value{{{"name"}, {"age"}, {"nickname"}}}
+	err = tbl.Validate(ctx, db, &postgres)
+	expect.NotNil(t, err)
+	expect.Equal(t, err.Error(), "sql: table testers is missing columns experience, pet_name and has extra columns age, nickname")
+	expect.Equal(t, err.(*SchemaError).Missing, []string{"experience", "pet_name"})
+	expect.Equal(t, err.(*SchemaError).Extra, []string{"age", "nickname"})
+
+	rec.results = [][][]driver.Value{{{"name"}, {"age"}, {"nickname"}}}
+	stmt, err = tbl.Diff(ctx, db, &postgres)
+	expect.Nil(t, err)
+	expected := `ALTER TABLE "testers" ADD COLUMN "experience" integer DEFAULT 0, ADD COLUMN "pet_name" text`
+	expect.Equal(t, stmt.Sql(), expected)
+	expect.Equal(t, len(tbl.Columns), 3) // should not modify the table
+
+	rec.results = [][][]driver.Value{{{"name"}, {"age"}, {"nickname"}}}
+	stmt, err = tbl.Diff(ctx, db, &postgres, DropColumns)
+	expect.Nil(t, err)
+	expected = `ALTER TABLE "testers" ADD COLUMN "experience" integer DEFAULT 0, ADD COLUMN "pet_name" text, DROP COLUMN "age", DROP COLUMN "nickname"`
+	expect.Equal(t, stmt.Sql(), expected)
+
+	// extra columns aren't dropped unless asked
+	rec.results = [][][]driver.Value{{{"name"}, {"experience"}, {"pet_name"}, {"age"}}}
+	stmt, err = tbl.Diff(ctx, db, &postgres)
+	expect.Nil(t, err)
+	expect.Nil(t, stmt)
+
+	rec.results = [][][]driver.Value{{{"name"}, {"experience"}, {"pet_name"}, {"age"}}}
+	stmt, err = tbl.Diff(ctx, db, &postgres, DropColumns)
+	expect.Nil(t, err)
+	expect.Equal(t, stmt.Sql(), `ALTER TABLE "testers" DROP COLUMN "age"`)
+
+	mysql := Dialect{IdentOpen: '`', IdentClose: '`', Placeholder: PlaceholderQuestion, CurrentSchema: "DATABASE()"}
+	rec.results = [][][]driver.Value{{{"name"}, {"experience"}, {"pet_name"}}}
+	expect.Nil(t, tbl.Validate(ctx, db, &mysql))
+	events := rec.Events()
+	expect.Equal(t, events[len(events)-1],
+		"SELECT column_name FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? ORDER BY ordinal_position")
+
+	rec.results = [][][]driver.Value{{}}
+	err = tbl.Validate(ctx, db, &postgres)
+	expect.Equal(t, err.Error(), "sql: table testers does not exist")
+
+	rec.results = [][][]driver.Value{{}}
+	stmt, err = tbl.Diff(ctx, db, &postgres)
+	expect.Nil(t, err)
+	expect.Equal(t, stmt.Sql(), `CREATE TABLE "testers" ("name" text NOT NULL, "experience" integer DEFAULT 0, "pet_name" text)`)
+}
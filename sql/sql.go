@@ -13,11 +13,12 @@ package sql
 
 import (
 	"bytes"
+	conn "database/sql"
 	"fmt"
 	"reflect"
 	"strings"
-	"unicode"
-	"unicode/utf8"
+
+	"github.com/reflexionhealth/vanilla/inflect"
 )
 
 type SortOrder bool
@@ -70,10 +71,22 @@ type ValueCountError struct {
 }
 
 func (e *ValueCountError) Error() string {
-	builder := reflect.TypeOf(e.Builder).Elem().Name
+	builder := reflect.TypeOf(e.Builder).Elem().Name()
 	return fmt.Sprintf("in %v.Values(...) expected %v values but received %v", builder, len(e.Columns), len(e.Values))
 }
 
+// A DialectError is thrown while building a statement which uses a feature
+// that isn't supported by the statement's Dialect.
+type DialectError struct {
+	Builder Sqler
+	Feature string
+}
+
+func (e *DialectError) Error() string {
+	builder := reflect.TypeOf(e.Builder).Elem().Name()
+	return fmt.Sprintf("in %v.Sql() the dialect does not support %v", builder, e.Feature)
+}
+
 // CreateTableStmt is an expression builder for statements of the form:
 //
 //   CREATE TABLE table_name ( ... )"
@@ -237,30 +250,168 @@ func (at *AlterTableStmt) Args() []interface{} {
 	return nil
 }
 
+// CreateIndexStmt is an expression builder for statements of the form:
+//
+//   CREATE INDEX index_name ON table_name (columns)
+//
+type CreateIndexStmt struct {
+	dialect     *Dialect
+	name        string
+	table       string
+	columns     []string
+	unique      bool
+	ifNotExists bool
+}
+
+func CreateIndex(name string) *CreateIndexStmt {
+	return &CreateIndexStmt{name: name}
+}
+
+func (ci *CreateIndexStmt) Dialect(dialect *Dialect) *CreateIndexStmt {
+	ci.dialect = dialect
+	return ci
+}
+
+func (ci *CreateIndexStmt) On(table string) *CreateIndexStmt {
+	ci.table = table
+	return ci
+}
+
+func (ci *CreateIndexStmt) Columns(columns ...string) *CreateIndexStmt {
+	ci.columns = append(ci.columns, columns...)
+	return ci
+}
+
+func (ci *CreateIndexStmt) Unique() *CreateIndexStmt {
+	ci.unique = true
+	return ci
+}
+
+func (ci *CreateIndexStmt) IfNotExists() *CreateIndexStmt {
+	ci.ifNotExists = true
+	return ci
+}
+
+func (ci *CreateIndexStmt) Sql() string {
+	dct := useDialect(ci.dialect)
+	qry := bytes.Buffer{}
+	qry.WriteString("CREATE ")
+	if ci.unique {
+		qry.WriteString("UNIQUE ")
+	}
+	qry.WriteString("INDEX ")
+	if ci.ifNotExists {
+		qry.WriteString("IF NOT EXISTS ")
+	}
+	dct.WriteIdentifier(&qry, ci.name)
+	qry.WriteString(" ON ")
+	dct.WriteIdentifier(&qry, ci.table)
+	qry.WriteString(" (")
+	for i, col := range ci.columns {
+		if i > 0 {
+			qry.WriteString(", ")
+		}
+		dct.WriteIdentifier(&qry, col)
+	}
+	qry.WriteString(")")
+	return qry.String()
+}
+
+func (ci *CreateIndexStmt) Args() []interface{} {
+	return nil
+}
+
+// DropIndexStmt is an expression builder for statements of the form:
+//
+//   DROP INDEX index_name
+//
+type DropIndexStmt struct {
+	dialect  *Dialect
+	name     string
+	table    string
+	ifExists bool
+}
+
+func DropIndex(name string) *DropIndexStmt {
+	return &DropIndexStmt{name: name}
+}
+
+func (di *DropIndexStmt) Dialect(dialect *Dialect) *DropIndexStmt {
+	di.dialect = dialect
+	return di
+}
+
+// On sets the index's table, for engines like MySQL that require
+// "DROP INDEX index_name ON table_name".
+func (di *DropIndexStmt) On(table string) *DropIndexStmt {
+	di.table = table
+	return di
+}
+
+func (di *DropIndexStmt) IfExists() *DropIndexStmt {
+	di.ifExists = true
+	return di
+}
+
+func (di *DropIndexStmt) Sql() string {
+	dct := useDialect(di.dialect)
+	qry := bytes.Buffer{}
+	qry.WriteString("DROP INDEX ")
+	if di.ifExists {
+		qry.WriteString("IF EXISTS ")
+	}
+	dct.WriteIdentifier(&qry, di.name)
+	if len(di.table) > 0 {
+		qry.WriteString(" ON ")
+		dct.WriteIdentifier(&qry, di.table)
+	}
+	return qry.String()
+}
+
+func (di *DropIndexStmt) Args() []interface{} {
+	return nil
+}
+
 // SelectStmt is an expression builder for statements of the form:
 //
 //   SELECT columns FROM table ...
 //
 // TODO: Tests for SelectStmt et al.
-// TODO: Having, GroupBy, OrderBy, Limit, Offset
+// TODO: Offset
 type SelectStmt struct {
 	dialect    *Dialect
+	ctes       []cte
+	recursive  bool
 	table      string
+	tableQuery Sqler
 	selection  string
 	columns    []Column
-	conditions []string
-	arguments  []interface{}
+	joins      []join
+	conditions []Condition
+	groupBy    []string
+	having     []Condition
 	orderBy    []string
 	orderDesc  []SortOrder
 	limit      int
 }
 
+type cte struct {
+	name  string
+	query Sqler
+}
+
+type join struct {
+	kind      string
+	table     string
+	condition Condition
+}
+
 func Select(columns string) *SelectStmt {
-	return &SelectStmt{nil, "", columns, nil, nil, nil, nil, nil, 0}
+	return &SelectStmt{selection: columns}
 }
 
 func SelectColumns(columns []Column) *SelectStmt {
-	return &SelectStmt{nil, "", "", columns, nil, nil, nil, nil, 0}
+	return &SelectStmt{columns: columns}
 }
 
 func (ss *SelectStmt) Dialect(dialect *Dialect) *SelectStmt {
@@ -268,6 +419,25 @@ func (ss *SelectStmt) Dialect(dialect *Dialect) *SelectStmt {
 	return ss
 }
 
+// With adds a common table expression to the statement, as in
+// "WITH name AS (query) SELECT ...".  The name may include a column list,
+// eg. "totals(team_id, total)", so it isn't quoted.
+//
+// The query's args are placed before the args of the outer statement.
+// If the query is built by this package, its placeholders are numbered
+// using the outer statement's Dialect.
+func (ss *SelectStmt) With(name string, query Sqler) *SelectStmt {
+	ss.ctes = append(ss.ctes, cte{name, query})
+	return ss
+}
+
+// WithRecursive is like With, but the statement begins with "WITH RECURSIVE"
+// so that queries can reference themselves.
+func (ss *SelectStmt) WithRecursive(name string, query Sqler) *SelectStmt {
+	ss.recursive = true
+	return ss.With(name, query)
+}
+
 func (ss *SelectStmt) From(table string) *SelectStmt {
 	ss.table = table
 	return ss
@@ -278,9 +448,77 @@ func (ss *SelectStmt) FromTable(table Table) *SelectStmt {
 	return ss
 }
 
+// FromQuery selects from the results of a subquery, as in
+// "SELECT ... FROM (query) alias".  The query's args are placed after
+// the args of any With clauses, and before the args of any Joins.
+func (ss *SelectStmt) FromQuery(query Sqler, alias string) *SelectStmt {
+	ss.table = alias
+	ss.tableQuery = query
+	return ss
+}
+
+// Join adds a "JOIN table ON condition" clause to the statement.
+// The args are placeholder values for the condition, and are placed
+// before the args of any Where clauses.
+func (ss *SelectStmt) Join(table string, condition string, args ...interface{}) *SelectStmt {
+	return ss.join("JOIN", table, condition, args)
+}
+
+// InnerJoin adds an "INNER JOIN table ON condition" clause to the statement.
+func (ss *SelectStmt) InnerJoin(table string, condition string, args ...interface{}) *SelectStmt {
+	return ss.join("INNER JOIN", table, condition, args)
+}
+
+// LeftJoin adds a "LEFT JOIN table ON condition" clause to the statement.
+func (ss *SelectStmt) LeftJoin(table string, condition string, args ...interface{}) *SelectStmt {
+	return ss.join("LEFT JOIN", table, condition, args)
+}
+
+// RightJoin adds a "RIGHT JOIN table ON condition" clause to the statement.
+func (ss *SelectStmt) RightJoin(table string, condition string, args ...interface{}) *SelectStmt {
+	return ss.join("RIGHT JOIN", table, condition, args)
+}
+
+func (ss *SelectStmt) join(kind string, table string, condition string, args []interface{}) *SelectStmt {
+	ss.joins = append(ss.joins, join{kind, table, Raw(condition, args...)})
+	return ss
+}
+
+// Where adds a condition to the statement's "WHERE" clause.  Multiple
+// conditions are joined with AND.
 func (ss *SelectStmt) Where(condition string, args ...interface{}) *SelectStmt {
-	ss.conditions = append(ss.conditions, condition)
-	ss.arguments = append(ss.arguments, args...)
+	return ss.WhereCond(Raw(condition, args...))
+}
+
+// WhereOr is like Where, but the condition is joined to the existing
+// conditions with OR, as in "(previous conditions) OR condition".
+func (ss *SelectStmt) WhereOr(condition string, args ...interface{}) *SelectStmt {
+	ss.conditions = orConditions(ss.conditions, Raw(condition, args...))
+	return ss
+}
+
+// WhereCond is like Where, but for a Condition built by Eq, And, etc.
+func (ss *SelectStmt) WhereCond(cond Condition) *SelectStmt {
+	ss.conditions = append(ss.conditions, cond)
+	return ss
+}
+
+// GroupBy adds columns to the statement's "GROUP BY" clause.
+func (ss *SelectStmt) GroupBy(columns ...string) *SelectStmt {
+	ss.groupBy = append(ss.groupBy, columns...)
+	return ss
+}
+
+// Having adds a condition to the statement's "HAVING" clause.  Multiple
+// conditions are joined with AND, and the args are placed after the args
+// of any Where clauses.
+func (ss *SelectStmt) Having(condition string, args ...interface{}) *SelectStmt {
+	return ss.HavingCond(Raw(condition, args...))
+}
+
+// HavingCond is like Having, but for a Condition built by Eq, And, etc.
+func (ss *SelectStmt) HavingCond(cond Condition) *SelectStmt {
+	ss.having = append(ss.having, cond)
 	return ss
 }
 
@@ -296,32 +534,83 @@ func (ss *SelectStmt) Limit(num int) *SelectStmt {
 }
 
 func (ss *SelectStmt) Sql() string {
-	dct := useDialect(ss.dialect)
 	qry := bytes.Buffer{}
+	ss.writeSql(&qry, useDialect(ss.dialect), 0)
+	return qry.String()
+}
+
+func (ss *SelectStmt) writeSql(qry *bytes.Buffer, dct *Dialect, argn int) {
+	if len(ss.ctes) > 0 {
+		if ss.recursive {
+			qry.WriteString("WITH RECURSIVE ")
+		} else {
+			qry.WriteString("WITH ")
+		}
+		for i, cte := range ss.ctes {
+			if i > 0 {
+				qry.WriteString(", ")
+			}
+			qry.WriteString(cte.name)
+			qry.WriteString(" AS (")
+			writeQuery(qry, dct, cte.query, argn)
+			qry.WriteString(")")
+			argn += len(cte.query.Args())
+		}
+		qry.WriteString(" ")
+	}
+
 	qry.WriteString("SELECT ")
 	if len(ss.columns) > 0 {
 		for i, col := range ss.columns {
 			if i > 0 {
 				qry.WriteString(", ")
 			}
-			dct.WriteIdentifier(&qry, col.Name)
+			dct.WriteIdentifier(qry, col.Name)
 		}
 	} else {
 		qry.WriteString(ss.selection)
 	}
 
 	qry.WriteString(" FROM ")
-	dct.WriteIdentifier(&qry, ss.table)
+	if ss.tableQuery != nil {
+		qry.WriteString("(")
+		writeQuery(qry, dct, ss.tableQuery, argn)
+		qry.WriteString(") ")
+		argn += len(ss.tableQuery.Args())
+	}
+	dct.WriteIdentifier(qry, ss.table)
+	for _, jn := range ss.joins {
+		qry.WriteString(" ")
+		qry.WriteString(jn.kind)
+		qry.WriteString(" ")
+		dct.WriteIdentifier(qry, jn.table)
+		qry.WriteString(" ON ")
+		jn.condition.WriteSql(qry, dct, argn)
+		argn += len(jn.condition.Args())
+	}
+
 	if len(ss.conditions) > 0 {
+		where := And(ss.conditions...)
 		qry.WriteString(" WHERE ")
-		for i, cond := range ss.conditions {
+		where.WriteSql(qry, dct, argn)
+		argn += len(where.Args())
+	}
+
+	if len(ss.groupBy) > 0 {
+		qry.WriteString(" GROUP BY ")
+		for i, col := range ss.groupBy {
 			if i > 0 {
-				qry.WriteString(" AND ")
+				qry.WriteString(", ")
 			}
-			qry.WriteString(cond)
+			qry.WriteString(col)
 		}
 	}
 
+	if len(ss.having) > 0 {
+		qry.WriteString(" HAVING ")
+		And(ss.having...).WriteSql(qry, dct, argn)
+	}
+
 	if len(ss.orderBy) > 0 {
 		qry.WriteString(" ORDER BY ")
 		for i, col := range ss.orderBy {
@@ -341,12 +630,21 @@ func (ss *SelectStmt) Sql() string {
 	if ss.limit > 0 {
 		qry.WriteString(fmt.Sprintf(" LIMIT %d", ss.limit))
 	}
-
-	return qry.String()
 }
 
 func (ss *SelectStmt) Args() []interface{} {
-	return ss.arguments
+	var args []interface{}
+	for _, cte := range ss.ctes {
+		args = append(args, cte.query.Args()...)
+	}
+	if ss.tableQuery != nil {
+		args = append(args, ss.tableQuery.Args()...)
+	}
+	for _, jn := range ss.joins {
+		args = append(args, jn.condition.Args()...)
+	}
+	args = append(args, And(ss.conditions...).Args()...)
+	return append(args, And(ss.having...).Args()...)
 }
 
 // InsertStmt is an expression builder for statements of the form:
@@ -360,6 +658,7 @@ type InsertStmt struct {
 	insertion string
 	columns   []Column
 	arguments []interface{}
+	returning []string
 
 	values  int
 	records int
@@ -367,11 +666,25 @@ type InsertStmt struct {
 
 func Insert(columns string) *InsertStmt {
 	values := strings.Count(columns, ",") + 1
-	return &InsertStmt{nil, "", columns, nil, nil, values, 0}
+	return &InsertStmt{insertion: columns, values: values}
 }
 
 func InsertColumns(columns []Column) *InsertStmt {
-	return &InsertStmt{nil, "", "", columns, nil, len(columns), 0}
+	return &InsertStmt{columns: columns, values: len(columns)}
+}
+
+// InsertStruct uses the reflect package to inspect a struct value, and
+// returns an InsertStmt with a column and value for each exported field.
+// It accepts the ColumnsFlag flags to control what fields and names are used.
+//
+// InsertStruct will panic with reflect.ValueError if value isn't a struct.
+func InsertStruct(value interface{}, flags ColumnsFlag) *InsertStmt {
+	names, values := structValues("InsertStruct", value, flags)
+	columns := make([]Column, len(names))
+	for i, name := range names {
+		columns[i] = Column{Name: name}
+	}
+	return InsertColumns(columns).Values(values...)
 }
 
 func (is *InsertStmt) Dialect(dialect *Dialect) *InsertStmt {
@@ -404,6 +717,46 @@ func (is *InsertStmt) Values(args ...interface{}) *InsertStmt {
 	return is
 }
 
+// Returning adds expressions to the statement's "RETURNING" clause.
+// Sql will panic with DialectError if the dialect doesn't support RETURNING.
+func (is *InsertStmt) Returning(columns ...string) *InsertStmt {
+	is.returning = append(is.returning, columns...)
+	return is
+}
+
+// Chunked splits the statement into statements which each have at most
+// maxParams args, so that inserting many rows doesn't exceed the engine's
+// limit for parameters in a single query (eg. 65535 for Postgres).
+//
+// Chunked will panic if a single row has more than maxParams values.
+func (is *InsertStmt) Chunked(maxParams int) []*InsertStmt {
+	rowsPerChunk := 1
+	if is.values > 0 {
+		rowsPerChunk = maxParams / is.values
+	}
+	if rowsPerChunk < 1 {
+		panic(fmt.Errorf("in InsertStmt.Chunked(%v) each row has %v values", maxParams, is.values))
+	}
+	if is.records <= rowsPerChunk {
+		return []*InsertStmt{is}
+	}
+
+	var chunks []*InsertStmt
+	for start := 0; start < is.records; start += rowsPerChunk {
+		records := rowsPerChunk
+		if start+records > is.records {
+			records = is.records - start
+		}
+
+		chunk := *is
+		chunk.arguments = is.arguments[start*is.values : (start+records)*is.values : (start+records)*is.values]
+		chunk.records = records
+		chunk.returning = is.returning[:len(is.returning):len(is.returning)]
+		chunks = append(chunks, &chunk)
+	}
+	return chunks
+}
+
 func (is *InsertStmt) Sql() string {
 	dct := useDialect(is.dialect)
 	qry := bytes.Buffer{}
@@ -441,6 +794,7 @@ func (is *InsertStmt) Sql() string {
 		}
 	}
 
+	writeReturning(&qry, dct, is, is.returning)
 	return qry.String()
 }
 
@@ -454,16 +808,16 @@ func (is *InsertStmt) Args() []interface{} {
 //
 // TODO: Tests for UpdateStmt et al.
 type UpdateStmt struct {
-	dialect         *Dialect
-	table           string
-	columns         []string
-	columnValues    []interface{}
-	conditions      []string
-	conditionValues []interface{}
+	dialect      *Dialect
+	table        string
+	columns      []string
+	columnValues []interface{}
+	conditions   []Condition
+	returning    []string
 }
 
 func Update(name string) *UpdateStmt {
-	return &UpdateStmt{nil, name, nil, nil, nil, nil}
+	return &UpdateStmt{table: name}
 }
 
 func UpdateTable(table Table) *UpdateStmt {
@@ -475,15 +829,54 @@ func (us *UpdateStmt) Dialect(dialect *Dialect) *UpdateStmt {
 	return us
 }
 
+// Set adds "name = value" to the statement's "SET" clause.  The value can be
+// a named arg (see Named), so that the same args can be given to Set and
+// Where, but only its value is used: the placeholder is named by the column.
 func (us *UpdateStmt) Set(name string, value interface{}) *UpdateStmt {
+	if named, ok := value.(conn.NamedArg); ok {
+		value = named.Value
+	}
 	us.columns = append(us.columns, name)
 	us.columnValues = append(us.columnValues, value)
 	return us
 }
 
+// SetStruct uses the reflect package to inspect a struct value, and calls
+// Set with the column name and value of each exported field.  It accepts
+// the ColumnsFlag flags to control what fields and names are used.
+//
+// SetStruct will panic with reflect.ValueError if value isn't a struct.
+func (us *UpdateStmt) SetStruct(value interface{}, flags ColumnsFlag) *UpdateStmt {
+	names, values := structValues("SetStruct", value, flags)
+	for i, name := range names {
+		us.Set(name, values[i])
+	}
+	return us
+}
+
+// Where adds a condition to the statement's "WHERE" clause.  Multiple
+// conditions are joined with AND.
 func (us *UpdateStmt) Where(condition string, args ...interface{}) *UpdateStmt {
-	us.conditions = append(us.conditions, condition)
-	us.conditionValues = append(us.conditionValues, args...)
+	return us.WhereCond(Raw(condition, args...))
+}
+
+// WhereOr is like Where, but the condition is joined to the existing
+// conditions with OR, as in "(previous conditions) OR condition".
+func (us *UpdateStmt) WhereOr(condition string, args ...interface{}) *UpdateStmt {
+	us.conditions = orConditions(us.conditions, Raw(condition, args...))
+	return us
+}
+
+// WhereCond is like Where, but for a Condition built by Eq, And, etc.
+func (us *UpdateStmt) WhereCond(cond Condition) *UpdateStmt {
+	us.conditions = append(us.conditions, cond)
+	return us
+}
+
+// Returning adds expressions to the statement's "RETURNING" clause.
+// Sql will panic with DialectError if the dialect doesn't support RETURNING.
+func (us *UpdateStmt) Returning(columns ...string) *UpdateStmt {
+	us.returning = append(us.returning, columns...)
 	return us
 }
 
@@ -506,20 +899,16 @@ func (us *UpdateStmt) Sql() string {
 	}
 	if len(us.conditions) > 0 {
 		qry.WriteString(" WHERE ")
-
-		for i, cond := range us.conditions {
-			if i > 0 {
-				qry.WriteString(", ")
-			}
-			qry.WriteString(cond)
-		}
-
+		And(us.conditions...).WriteSql(&qry, dct, argn)
 	}
+	writeReturning(&qry, dct, us, us.returning)
 	return qry.String()
 }
 
 func (us *UpdateStmt) Args() []interface{} {
-	return append(us.columnValues, us.conditionValues...)
+	args := make([]interface{}, 0, len(us.columnValues))
+	args = append(args, us.columnValues...)
+	return append(args, And(us.conditions...).Args()...)
 }
 
 // DeleteStmt is an expression builder for statements of the form:
@@ -528,14 +917,14 @@ func (us *UpdateStmt) Args() []interface{} {
 //
 // TODO: Tests for DeleteStmt et al.
 type DeleteStmt struct {
-	dialect         *Dialect
-	table           string
-	conditions      []string
-	conditionValues []interface{}
+	dialect    *Dialect
+	table      string
+	conditions []Condition
+	returning  []string
 }
 
 func Delete(name string) *DeleteStmt {
-	return &DeleteStmt{nil, name, nil, nil}
+	return &DeleteStmt{table: name}
 }
 
 func (ds *DeleteStmt) Dialect(dialect *Dialect) *DeleteStmt {
@@ -548,14 +937,34 @@ func (ds *DeleteStmt) From(table string) *DeleteStmt {
 	return ds
 }
 
+// Where adds a condition to the statement's "WHERE" clause.  Multiple
+// conditions are joined with AND.
 func (ds *DeleteStmt) Where(condition string, args ...interface{}) *DeleteStmt {
-	ds.conditions = append(ds.conditions, condition)
-	ds.conditionValues = append(ds.conditionValues, args...)
+	return ds.WhereCond(Raw(condition, args...))
+}
+
+// WhereOr is like Where, but the condition is joined to the existing
+// conditions with OR, as in "(previous conditions) OR condition".
+func (ds *DeleteStmt) WhereOr(condition string, args ...interface{}) *DeleteStmt {
+	ds.conditions = orConditions(ds.conditions, Raw(condition, args...))
+	return ds
+}
+
+// WhereCond is like Where, but for a Condition built by Eq, And, etc.
+func (ds *DeleteStmt) WhereCond(cond Condition) *DeleteStmt {
+	ds.conditions = append(ds.conditions, cond)
+	return ds
+}
+
+// Returning adds expressions to the statement's "RETURNING" clause.
+// Sql will panic with DialectError if the dialect doesn't support RETURNING.
+func (ds *DeleteStmt) Returning(columns ...string) *DeleteStmt {
+	ds.returning = append(ds.returning, columns...)
 	return ds
 }
 
 func (ds *DeleteStmt) Args() []interface{} {
-	return ds.conditionValues
+	return And(ds.conditions...).Args()
 }
 
 func (ds *DeleteStmt) Sql() string {
@@ -566,20 +975,47 @@ func (ds *DeleteStmt) Sql() string {
 
 	if len(ds.conditions) > 0 {
 		qry.WriteString(" WHERE ")
+		And(ds.conditions...).WriteSql(&qry, dct, 0)
+	}
+	writeReturning(&qry, dct, ds, ds.returning)
+	return qry.String()
+}
 
-		for i, cond := range ds.conditions {
-			if i > 0 {
-				qry.WriteString(", ")
-			}
-			qry.WriteString(cond)
-		}
+// writeQuery writes a query nested in another statement, where the query's
+// first placeholder is number argOffset+1.  Queries which aren't built by
+// this package are written as-is.
+func writeQuery(qry *bytes.Buffer, dct *Dialect, query Sqler, argOffset int) {
+	if nested, ok := query.(interface {
+		writeSql(*bytes.Buffer, *Dialect, int)
+	}); ok {
+		nested.writeSql(qry, dct, argOffset)
+	} else {
+		qry.WriteString(query.Sql())
+	}
+}
 
+func writeReturning(qry *bytes.Buffer, dct *Dialect, builder Sqler, columns []string) {
+	if len(columns) == 0 {
+		return
+	}
+	if !dct.SupportsReturning {
+		panic(&DialectError{builder, "RETURNING"})
+	}
+
+	qry.WriteString(" RETURNING ")
+	for i, col := range columns {
+		if i > 0 {
+			qry.WriteString(", ")
+		}
+		qry.WriteString(col)
 	}
-	return qry.String()
 }
 
-// TODO: Better documentation and tests for InCondition
+// InCondition returns an "IN" condition with optionCount placeholders,
+// where the first placeholder is number argOffset+1
 // e.g. qry.Where(sql.InCondition("thing", len(things), len(qry.Args()), Mysql), things...)
+//
+// Deprecated: Use In with WhereCond, which counts the placeholders itself.
 func InCondition(what string, optionCount int, argOffset int, dct *Dialect) string {
 	dct = useDialect(dct)
 	cond := bytes.Buffer{}
@@ -589,14 +1025,17 @@ func InCondition(what string, optionCount int, argOffset int, dct *Dialect) stri
 		if i > 0 {
 			cond.WriteString(", ")
 		}
-		cond.WriteString(dct.Placeholder(i + argOffset))
+		cond.WriteString(dct.Placeholder(argOffset + i + 1))
 	}
 	cond.WriteString(")")
 	return cond.String()
 }
 
-// TODO: Better documentation and tests for InCondition
+// NotInCondition returns a "NOT IN" condition with optionCount placeholders,
+// where the first placeholder is number argOffset+1
 // e.g. qry.Where(sql.NotInCondition("thing", len(things), len(qry.Args()), Mysql), things...)
+//
+// Deprecated: Use NotIn with WhereCond, which counts the placeholders itself.
 func NotInCondition(what string, optionCount int, argOffset int, dct *Dialect) string {
 	dct = useDialect(dct)
 	cond := bytes.Buffer{}
@@ -606,7 +1045,7 @@ func NotInCondition(what string, optionCount int, argOffset int, dct *Dialect) s
 		if i > 0 {
 			cond.WriteString(", ")
 		}
-		cond.WriteString(dct.Placeholder(i + argOffset))
+		cond.WriteString(dct.Placeholder(argOffset + i + 1))
 	}
 	cond.WriteString(")")
 	return cond.String()
@@ -632,11 +1071,11 @@ const (
 	// It does not try to detect common initialisms
 	ColumnNamesSnakecase
 
-	// ColumnsOnlyExported skips fields that are unexported (first character uppercase)
+	// ColumnsOnlyExported skips fields that are unexported (first character lowercase)
 	ColumnsOnlyExported
 
-	// ColumnsOnlyExported only outputs columns for fields with the "sql" tag
-	//ColumnsOnlyTagged
+	// ColumnsOnlyTagged only outputs columns for fields with the "sql" tag
+	ColumnsOnlyTagged
 )
 
 // Columns uses the reflect package to inspect a struct value and returns
@@ -647,33 +1086,23 @@ const (
 // the outer struct. Currently, if multiple fields with the same name multiple
 // columns with that name will appear multiple times in the response.
 //
+// A field's column name can be set with the "sql" struct tag, which takes
+// precedence over the ColumnNames flags, and fields tagged with "-" are skipped:
+//
+//     type User struct {
+//         ID       int    `sql:"user_id"`
+//         Password string `sql:"-"`
+//     }
+//
 // For a plain []string, see the ColumnNames function
 func Columns(structValue interface{}, flags ColumnsFlag) ([]Column, error) {
-	val := reflect.ValueOf(structValue)
-	typ := val.Type()
-	if typ.Kind() != reflect.Struct {
-		// needless runtime sacrifice to the gods of type safety
-		return nil, &reflect.ValueError{"Columns", typ.Kind()}
-	}
-
 	var columns []Column
-	for i := 0; i < typ.NumField(); i++ {
-		fld := typ.Field(i)
-		if flags&ColumnsOnlyExported != 0 && len(fld.PkgPath) > 0 {
-			continue
-		}
-
-		if fld.Anonymous {
-			cols, err := Columns(val.Field(i).Interface(), flags)
-			if err != nil {
-				return nil, err
-			}
-			columns = append(columns, cols...)
-		} else {
-			columns = append(columns, Column{Name: inflect(fld.Name, flags)})
-		}
+	err := walkColumns("Columns", reflect.ValueOf(structValue), flags, func(name string, _ reflect.Value) {
+		columns = append(columns, Column{Name: name})
+	})
+	if err != nil {
+		return nil, err
 	}
-
 	return columns, nil
 }
 
@@ -685,32 +1114,74 @@ func Columns(structValue interface{}, flags ColumnsFlag) ([]Column, error) {
 // the outer struct. Currently, if multiple fields with the same name multiple
 // columns with that name will appear multiple times in the response.
 func ColumnNames(structValue interface{}, flags ColumnsFlag) ([]string, error) {
-	val := reflect.ValueOf(structValue)
+	var columns []string
+	err := walkColumns("ColumnNames", reflect.ValueOf(structValue), flags, func(name string, _ reflect.Value) {
+		columns = append(columns, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+// structValues returns the column names and values of a struct's fields.
+// Unexported fields are always skipped because their values can't be read,
+// but the exported fields of embedded structs are included.
+func structValues(method string, value interface{}, flags ColumnsFlag) ([]string, []interface{}) {
+	var names []string
+	var values []interface{}
+	err := walkColumns(method, reflect.Indirect(reflect.ValueOf(value)), flags|ColumnsOnlyExported, func(name string, field reflect.Value) {
+		names = append(names, name)
+		values = append(values, field.Interface())
+	})
+	if err != nil {
+		panic(err)
+	}
+	return names, values
+}
+
+// walkColumns calls fn with the name and value of each field in the struct
+// which should be a column, descending into anonymous struct fields.
+func walkColumns(method string, val reflect.Value, flags ColumnsFlag, fn func(string, reflect.Value)) error {
 	typ := val.Type()
 	if typ.Kind() != reflect.Struct {
 		// needless runtime sacrifice to the gods of type safety
-		return nil, &reflect.ValueError{"ColumnNames", typ.Kind()}
+		return &reflect.ValueError{Method: method, Kind: typ.Kind()}
 	}
 
-	var columns []string
 	for i := 0; i < typ.NumField(); i++ {
 		fld := typ.Field(i)
-		if flags&ColumnsOnlyExported != 0 && len(fld.PkgPath) > 0 {
+		tag, tagged := fld.Tag.Lookup("sql")
+		if comma := strings.IndexByte(tag, ','); comma >= 0 {
+			tag = tag[:comma]
+		}
+		if tag == "-" {
 			continue
 		}
 
-		if fld.Anonymous {
-			cols, err := ColumnNames(val.Field(i).Interface(), flags)
-			if err != nil {
-				return nil, err
+		// like encoding/json, an anonymous field with a tagged name is a column
+		if fld.Anonymous && len(tag) == 0 {
+			if err := walkColumns(method, val.Field(i), flags, fn); err != nil {
+				return err
 			}
-			columns = append(columns, cols...)
+			continue
+		}
+
+		if flags&ColumnsOnlyExported != 0 && len(fld.PkgPath) > 0 {
+			continue
+		}
+		if flags&ColumnsOnlyTagged != 0 && !tagged {
+			continue
+		}
+
+		if len(tag) > 0 {
+			fn(tag, val.Field(i))
 		} else {
-			columns = append(columns, inflect(fld.Name, flags))
+			fn(inflectColumn(fld.Name, flags), val.Field(i))
 		}
 	}
 
-	return columns, nil
+	return nil
 }
 
 // ColumnsToNames maps an array of columns to an array of column names
@@ -722,53 +1193,17 @@ func ColumnsToNames(columns []Column) []string {
 	return names
 }
 
-func inflect(input string, flags ColumnsFlag) string {
+func inflectColumn(input string, flags ColumnsFlag) string {
 	switch {
 	case flags&ColumnNamesCamelcase != 0:
-		return camelcase(input)
+		return inflect.Camelcase(input)
 	case flags&ColumnNamesLowercase != 0:
 		return strings.ToLower(input)
 	case flags&ColumnNamesPascalcase != 0:
-		return pascalcase(input)
+		return inflect.Pascalcase(input)
 	case flags&ColumnNamesSnakecase != 0:
-		return snakecase(input)
+		return inflect.Snakecase(input)
 	default:
 		return input
 	}
 }
-
-func camelcase(input string) string {
-	r, size := utf8.DecodeRuneInString(input)
-	if unicode.IsUpper(r) {
-		return string(unicode.ToLower(r)) + input[size:]
-	} else {
-		return input
-	}
-}
-
-func pascalcase(input string) string {
-	r, size := utf8.DecodeRuneInString(input)
-	if unicode.IsLower(r) {
-		return string(unicode.ToUpper(r)) + input[size:]
-	} else {
-		return input
-	}
-}
-
-func snakecase(input string) string {
-	var output bytes.Buffer
-	var runes = []rune(input)
-	for i, char := range runes {
-		if unicode.IsUpper(char) {
-			// NOTE: If this is not the first character AND it is before or after a lowercase character
-			if i > 0 && (unicode.IsLower(runes[i-1]) || (i != len(runes)-1 && unicode.IsLower(runes[i+1]))) {
-				output.WriteRune('_')
-			}
-			output.WriteRune(unicode.ToLower(char))
-		} else {
-			output.WriteRune(char)
-		}
-	}
-
-	return output.String()
-}
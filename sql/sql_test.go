@@ -1,6 +1,7 @@
 package sql
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/reflexionhealth/vanilla/expect"
@@ -50,20 +51,243 @@ func TestAlterTable(t *testing.T) {
 	expect.Equal(t, len(tbl.Alter().Args()), 0)
 }
 
-func TestSnakecase(t *testing.T) {
-	examples := []struct {
-		Input  string
-		Output string
-	}{
-		{Input: "snake_case", Output: "snake_case"}, // NOTE: expected input is camelCase or pascalCase
-		{Input: "camelCase", Output: "camel_case"},
-		{Input: "PascalCase", Output: "pascal_case"},
-		{Input: "exampleID", Output: "example_id"},
-		{Input: "HTTPPost", Output: "http_post"},
-		{Input: "HostURL", Output: "host_url"},
-		{Input: "XMLHttpRequest", Output: "xml_http_request"},
+func TestCreateIndex(t *testing.T) {
+	mysql := Dialect{IdentOpen: '`', IdentClose: '`', Placeholder: PlaceholderQuestion}
+
+	var expected string
+	expected = `CREATE INDEX "testers_name" ON "testers" ("name")`
+	expect.Equal(t, CreateIndex("testers_name").On("testers").Columns("name").Sql(), expected)
+	expected = `CREATE UNIQUE INDEX IF NOT EXISTS "testers_name_pet" ON "testers" ("name", "pet_name")`
+	expect.Equal(t, CreateIndex("testers_name_pet").On("testers").Columns("name", "pet_name").Unique().IfNotExists().Sql(), expected)
+	expected = "CREATE UNIQUE INDEX `testers_name` ON `testers` (`name`)"
+	expect.Equal(t, mysql.CreateIndex("testers_name").On("testers").Columns("name").Unique().Sql(), expected)
+
+	expect.Equal(t, len(CreateIndex("testers_name").Args()), 0)
+}
+
+func TestDropIndex(t *testing.T) {
+	mysql := Dialect{IdentOpen: '`', IdentClose: '`', Placeholder: PlaceholderQuestion}
+
+	expect.Equal(t, DropIndex("testers_name").Sql(), `DROP INDEX "testers_name"`)
+	expect.Equal(t, DropIndex("testers_name").IfExists().Sql(), `DROP INDEX IF EXISTS "testers_name"`)
+	expect.Equal(t, mysql.DropIndex("testers_name").On("testers").Sql(), "DROP INDEX `testers_name` ON `testers`")
+
+	expect.Equal(t, len(DropIndex("testers_name").Args()), 0)
+}
+
+func TestSelectJoin(t *testing.T) {
+	postgres := Dialect{IdentOpen: '"', IdentClose: '"', Placeholder: PlaceholderDollar}
+	mysql := Dialect{IdentOpen: '`', IdentClose: '`', Placeholder: PlaceholderQuestion}
+
+	var expected string
+	expected = `SELECT * FROM "users" JOIN "teams" ON teams.id = users.team_id`
+	expect.Equal(t, Select("*").From("users").Join("teams", "teams.id = users.team_id").Sql(), expected)
+
+	stmt := postgres.Select("users.name, pets.name").From("users").
+		LeftJoin("pets", "pets.owner_id = users.id AND pets.species = $1", "cat").
+		InnerJoin("teams", "teams.id = users.team_id").
+		Where("users.active = $2", true).
+		OrderBy("users.name", ASC)
+	expected = `SELECT users.name, pets.name FROM "users" ` +
+		`LEFT JOIN "pets" ON pets.owner_id = users.id AND pets.species = $1 ` +
+		`INNER JOIN "teams" ON teams.id = users.team_id ` +
+		`WHERE users.active = $2 ORDER BY users.name ASC`
+	expect.Equal(t, stmt.Sql(), expected)
+	expect.Equal(t, stmt.Args(), []interface{}{"cat", true})
+
+	stmt = mysql.Select("*").From("users").RightJoin("teams", "teams.id = users.team_id")
+	expected = "SELECT * FROM `users` RIGHT JOIN `teams` ON teams.id = users.team_id"
+	expect.Equal(t, stmt.Sql(), expected)
+	expect.Equal(t, len(stmt.Args()), 0)
+}
+
+func TestSelectGroupBy(t *testing.T) {
+	postgres := Dialect{IdentOpen: '"', IdentClose: '"', Placeholder: PlaceholderDollar}
+
+	var expected string
+	expected = `SELECT team_id, count(*) FROM "users" GROUP BY team_id`
+	expect.Equal(t, Select("team_id, count(*)").From("users").GroupBy("team_id").Sql(), expected)
+
+	stmt := postgres.Select("team_id, role, count(*)").From("users").
+		Where("active = $1", true).
+		GroupBy("team_id", "role").
+		Having("count(*) > $2", 5).
+		Having("max(age) < $3", 40).
+		OrderBy("team_id", DESC).
+		Limit(10)
+	expected = `SELECT team_id, role, count(*) FROM "users" WHERE active = $1 ` +
+		`GROUP BY team_id, role HAVING count(*) > $2 AND max(age) < $3 ` +
+		`ORDER BY team_id DESC LIMIT 10`
+	expect.Equal(t, stmt.Sql(), expected)
+	expect.Equal(t, stmt.Args(), []interface{}{true, 5, 40})
+}
+
+func TestReturning(t *testing.T) {
+	postgres := Dialect{IdentOpen: '"', IdentClose: '"', Placeholder: PlaceholderDollar, SupportsReturning: true}
+
+	var expected string
+	expected = `INSERT INTO "users" (name, age) VALUES ($1, $2) RETURNING id`
+	expect.Equal(t, postgres.Insert("name, age").Into("users").Values("Ana", 31).Returning("id").Sql(), expected)
+	expected = `UPDATE "users" SET "age" = $1 WHERE id = $2 RETURNING id, updated_at`
+	expect.Equal(t, postgres.Update("users").Set("age", 32).Where("id = $2", 7).Returning("id", "updated_at").Sql(), expected)
+	expected = `DELETE FROM "users" WHERE id = $1 RETURNING *`
+	expect.Equal(t, postgres.Delete("users").Where("id = $1", 7).Returning("*").Sql(), expected)
+
+	defer func() {
+		err, ok := recover().(*DialectError)
+		expect.True(t, ok)
+		expect.Equal(t, err.Feature, "RETURNING")
+		expect.Equal(t, err.Error(), "in DeleteStmt.Sql() the dialect does not support RETURNING")
+	}()
+	Delete("users").Where("id = ?", 7).Returning("id").Sql()
+}
+
+type testAudit struct {
+	CreatedBy string
+	UpdatedBy string
+}
+
+type testPet struct {
+	testAudit
+	PetName  string
+	OwnerID  int
+	internal bool
+}
+
+func TestInsertStruct(t *testing.T) {
+	postgres := Dialect{IdentOpen: '"', IdentClose: '"', Placeholder: PlaceholderDollar}
+	pet := testPet{testAudit{"ana", "bo"}, "Rex", 7, true}
+
+	stmt := postgres.InsertStruct(pet, ColumnNamesSnakecase).Into("pets")
+	expected := `INSERT INTO "pets" ("created_by", "updated_by", "pet_name", "owner_id") VALUES ($1, $2, $3, $4)`
+	expect.Equal(t, stmt.Sql(), expected)
+	expect.Equal(t, stmt.Args(), []interface{}{"ana", "bo", "Rex", 7})
+
+	// pointers to structs are also accepted, and rows can be added
+	stmt = InsertStruct(&pet, ColumnNamesCamelcase).Into("pets").Values("cy", "di", "Fido", 8)
+	expected = `INSERT INTO "pets" ("createdBy", "updatedBy", "petName", "ownerID") VALUES (?, ?, ?, ?), (?, ?, ?, ?)`
+	expect.Equal(t, stmt.Sql(), expected)
+
+	defer func() {
+		_, ok := recover().(*reflect.ValueError)
+		expect.True(t, ok)
+	}()
+	InsertStruct("not a struct", 0)
+}
+
+func TestUpdateSetStruct(t *testing.T) {
+	postgres := Dialect{IdentOpen: '"', IdentClose: '"', Placeholder: PlaceholderDollar}
+	pet := testPet{testAudit{"ana", "bo"}, "Rex", 7, true}
+
+	stmt := postgres.Update("pets").SetStruct(pet, ColumnNamesSnakecase).Where("id = $5", 12)
+	expected := `UPDATE "pets" SET "created_by" = $1, "updated_by" = $2, "pet_name" = $3, "owner_id" = $4 WHERE id = $5`
+	expect.Equal(t, stmt.Sql(), expected)
+	expect.Equal(t, stmt.Args(), []interface{}{"ana", "bo", "Rex", 7, 12})
+}
+
+func TestColumnTags(t *testing.T) {
+	type Base struct {
+		ID int `sql:"user_id"`
 	}
-	for _, ex := range examples {
-		expect.Equal(t, snakecase(ex.Input), ex.Output)
+	type user struct {
+		Base
+		FullName string `sql:"name,omitempty"`
+		Email    string
+		Password string `sql:"-"`
+		Location struct {
+			City string
+		} `sql:"location"`
+		secret string
+	}
+
+	names, err := ColumnNames(user{}, ColumnNamesSnakecase)
+	expect.Nil(t, err)
+	expect.Equal(t, names, []string{"user_id", "name", "email", "location", "secret"})
+
+	names, err = ColumnNames(user{}, ColumnNamesSnakecase|ColumnsOnlyExported)
+	expect.Nil(t, err)
+	expect.Equal(t, names, []string{"user_id", "name", "email", "location"})
+
+	columns, err := Columns(user{}, ColumnsOnlyTagged)
+	expect.Nil(t, err)
+	expect.Equal(t, ColumnsToNames(columns), []string{"user_id", "name", "location"})
+
+	stmt := InsertStruct(user{Base: Base{3}, FullName: "Ana", Email: "ana@example.com"}, ColumnNamesSnakecase).Into("users")
+	expected := `INSERT INTO "users" ("user_id", "name", "email", "location") VALUES (?, ?, ?, ?)`
+	expect.Equal(t, stmt.Sql(), expected)
+	expect.Equal(t, len(stmt.Args()), 4)
+}
+
+func TestInsertChunked(t *testing.T) {
+	postgres := Dialect{IdentOpen: '"', IdentClose: '"', Placeholder: PlaceholderDollar, SupportsReturning: true}
+	stmt := postgres.Insert("name, age").Into("users").Returning("id")
+	for i := 1; i <= 5; i++ {
+		stmt.Values("user", i)
 	}
+
+	chunks := stmt.Chunked(4)
+	expect.Equal(t, len(chunks), 3)
+	expect.Equal(t, chunks[0].Sql(), `INSERT INTO "users" (name, age) VALUES ($1, $2), ($3, $4) RETURNING id`)
+	expect.Equal(t, chunks[0].Args(), []interface{}{"user", 1, "user", 2})
+	expect.Equal(t, chunks[1].Sql(), `INSERT INTO "users" (name, age) VALUES ($1, $2), ($3, $4) RETURNING id`)
+	expect.Equal(t, chunks[1].Args(), []interface{}{"user", 3, "user", 4})
+	expect.Equal(t, chunks[2].Sql(), `INSERT INTO "users" (name, age) VALUES ($1, $2) RETURNING id`)
+	expect.Equal(t, chunks[2].Args(), []interface{}{"user", 5})
+
+	// appending to a chunk doesn't change the other chunks
+	chunks[0].Values("extra", 6)
+	expect.Equal(t, chunks[1].Args(), []interface{}{"user", 3, "user", 4})
+
+	// a statement under the limit isn't split
+	chunks = stmt.Chunked(65535)
+	expect.Equal(t, len(chunks), 1)
+	expect.Equal(t, chunks[0].Args(), stmt.Args())
+
+	defer func() { expect.NotNil(t, recover()) }()
+	stmt.Chunked(1)
+}
+
+func TestInsertValueCount(t *testing.T) {
+	expect.NotPanics(t, func() { Insert("name, age").Into("users").Values("Ana", 31) })
+	expect.PanicsWithError(t, "in InsertStmt.Values(...) expected 2 values but received 3", func() {
+		Insert("name, age").Into("users").Values("Ana", 31, "extra")
+	})
+	expect.PanicsWithError(t, "in InsertStmt.Values(...) expected 2 values but received 1", func() {
+		InsertColumns([]Column{{Name: "name"}, {Name: "age"}}).Into("users").Values("Ana")
+	})
+}
+
+// rawQuery is a Sqler which isn't built by this package
+type rawQuery struct {
+	sql  string
+	args []interface{}
+}
+
+func (q rawQuery) Sql() string         { return q.sql }
+func (q rawQuery) Args() []interface{} { return q.args }
+
+func TestSelectWith(t *testing.T) {
+	postgres := Dialect{IdentOpen: '"', IdentClose: '"', Placeholder: PlaceholderDollar}
+
+	active := Select("id, team_id").From("users").WhereCond(Eq("active", true))
+	totals := Select("team_id, count(*) AS total").From("active_users").GroupBy("team_id").HavingCond(Gt("count(*)", 2))
+	stmt := postgres.Select("*").From("team_totals").
+		With("active_users", active).
+		With("team_totals", totals).
+		WhereCond(Lt("total", 10))
+	expected := `WITH active_users AS (SELECT id, team_id FROM "users" WHERE active = $1), ` +
+		`team_totals AS (SELECT team_id, count(*) AS total FROM "active_users" GROUP BY team_id HAVING count(*) > $2) ` +
+		`SELECT * FROM "team_totals" WHERE total < $3`
+	expect.Equal(t, stmt.Sql(), expected)
+	expect.Equal(t, stmt.Args(), []interface{}{true, 2, 10})
+
+	// the nested query is unchanged
+	expect.Equal(t, active.Sql(), `SELECT id, team_id FROM "users" WHERE active = ?`)
+
+	counter := rawQuery{"SELECT 1 UNION ALL SELECT n + 1 FROM counter WHERE n < $1", []interface{}{5}}
+	stmt = postgres.Select("n").From("counter").WithRecursive("counter(n)", counter).WhereCond(Gt("n", 2))
+	expected = `WITH RECURSIVE counter(n) AS (SELECT 1 UNION ALL SELECT n + 1 FROM counter WHERE n < $1) ` +
+		`SELECT n FROM "counter" WHERE n > $2`
+	expect.Equal(t, stmt.Sql(), expected)
+	expect.Equal(t, stmt.Args(), []interface{}{5, 2})
 }
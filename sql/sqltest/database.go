@@ -0,0 +1,124 @@
+package sqltest
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"strconv"
+	"sync"
+
+	"github.com/reflexionhealth/vanilla/sql/language/parser"
+)
+
+func init() {
+	sql.Register("sqltest", &Driver{AnsiRuleset})
+}
+
+// A Result is a canned response to a statement run on a Database
+type Result struct {
+	Columns []string         // the columns of a query (defaults to the columns selected)
+	Rows    [][]driver.Value // the rows returned by a query
+
+	LastInsertId int64 // the result of an exec
+	RowsAffected int64
+
+	Err error // returned instead of a result, if not nil
+}
+
+// A Database is a fake database which returns canned results.
+// It can be opened with the "sqltest" driver using its Name,
+// and each statement is checked against its Rules.
+//
+//     fake := sqltest.NewDatabase(sqltest.MysqlRuleset)
+//     defer fake.Close()
+//     fake.Respond(sqltest.Result{Rows: [][]driver.Value{{1, "kermit"}}})
+//
+//     db := fake.Open()
+//     row := db.QueryRow("SELECT id, name FROM muppets WHERE id = ?", 1)
+//
+type Database struct {
+	Rules parser.Ruleset
+
+	name    string
+	mutex   sync.Mutex
+	results []Result
+
+	expectations *Expectations // if not nil, statements must be expected
+}
+
+var databases = struct {
+	sync.Mutex
+	count  int
+	byName map[string]*Database
+}{byName: map[string]*Database{}}
+
+// NewDatabase returns an empty database, which is available to the "sqltest"
+// driver until it is closed
+func NewDatabase(rules parser.Ruleset) *Database {
+	databases.Lock()
+	defer databases.Unlock()
+	databases.count += 1
+
+	d := &Database{Rules: rules, name: "sqltest_" + strconv.Itoa(databases.count)}
+	databases.byName[d.name] = d
+	return d
+}
+
+func lookupDatabase(name string) *Database {
+	databases.Lock()
+	defer databases.Unlock()
+	return databases.byName[name]
+}
+
+// Name returns the data source name which opens the database
+func (d *Database) Name() string {
+	return d.name
+}
+
+// Open returns a *sql.DB connected to the database
+func (d *Database) Open() *sql.DB {
+	db, err := sql.Open("sqltest", d.name)
+	if err != nil {
+		panic(err) // the sqltest driver never fails to open
+	}
+	return db
+}
+
+// Close makes the database unavailable to the "sqltest" driver
+func (d *Database) Close() {
+	databases.Lock()
+	delete(databases.byName, d.name)
+	databases.Unlock()
+}
+
+// Respond queues results for the next statements run on the database, in order.
+// Once the results have all been used, queries return no rows and execs
+// affect no rows.
+func (d *Database) Respond(results ...Result) {
+	d.mutex.Lock()
+	d.results = append(d.results, results...)
+	d.mutex.Unlock()
+}
+
+// next returns the result for the next statement
+func (d *Database) next(stmt *Stmt, args []driver.Value) Result {
+	if d.expectations != nil {
+		return d.expectations.match(stmt, args)
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if len(d.results) == 0 {
+		return Result{}
+	}
+	result := d.results[0]
+	d.results = d.results[1:]
+	return result
+}
+
+type execResult struct {
+	lastInsertId int64
+	rowsAffected int64
+}
+
+func (r execResult) LastInsertId() (int64, error) { return r.lastInsertId, nil }
+func (r execResult) RowsAffected() (int64, error) { return r.rowsAffected, nil }
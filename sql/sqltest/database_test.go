@@ -0,0 +1,114 @@
+package sqltest
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+func TestDatabaseResults(t *testing.T) {
+	fake := NewDatabase(MysqlRuleset)
+	defer fake.Close()
+	fake.Respond(
+		Result{Rows: [][]driver.Value{{int64(1), "kermit"}, {int64(2), "gonzo"}}},
+		Result{LastInsertId: 3, RowsAffected: 1},
+		Result{Err: errors.New("connection reset")},
+	)
+
+	db := fake.Open()
+	rows, err := db.Query("SELECT id, name FROM muppets WHERE kind <> ?", "pig")
+	expect.Nil(t, err)
+
+	var names []string
+	for rows.Next() {
+		var id int
+		var name string
+		expect.Nil(t, rows.Scan(&id, &name))
+		names = append(names, name)
+	}
+	expect.Nil(t, rows.Err())
+	expect.Equal(t, names, []string{"kermit", "gonzo"})
+
+	result, err := db.Exec("INSERT INTO muppets (name) VALUES (?)", "fozzie")
+	expect.Nil(t, err)
+	id, err := result.LastInsertId()
+	expect.Nil(t, err)
+	expect.Equal(t, id, int64(3))
+	affected, err := result.RowsAffected()
+	expect.Nil(t, err)
+	expect.Equal(t, affected, int64(1))
+
+	_, err = db.Exec("DELETE FROM muppets")
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.Error(), "connection reset")
+	}
+
+	// after the canned results are used, statements have empty results
+	var count int
+	err = db.QueryRow("SELECT count FROM muppets").Scan(&count)
+	expect.Equal(t, err, sql.ErrNoRows)
+}
+
+func TestDatabaseChecksSyntax(t *testing.T) {
+	fake := NewDatabase(PostgresRuleset)
+	defer fake.Close()
+	fake.Respond(Result{RowsAffected: 1})
+
+	db := fake.Open()
+	_, err := db.Exec("UPDATE `muppets` SET name = $1 WHERE id = 1", "animal")
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.Error(), "sql:1:8: unexpected character U+0060 '`'")
+	}
+
+	// the canned result is still used by the next statement
+	result, err := db.Exec("UPDATE muppets SET name = $1 WHERE id = 1", "animal")
+	expect.Nil(t, err)
+	affected, _ := result.RowsAffected()
+	expect.Equal(t, affected, int64(1))
+}
+
+func TestDatabaseRowsNeedColumns(t *testing.T) {
+	fake := NewDatabase(AnsiRuleset)
+	defer fake.Close()
+	fake.Respond(
+		Result{Rows: [][]driver.Value{{int64(1), "kermit"}}},
+		Result{Columns: []string{"id", "name"}, Rows: [][]driver.Value{{int64(1), "kermit"}}},
+	)
+
+	db := fake.Open()
+	_, err := db.Query("SELECT * FROM muppets")
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.Error(), "sqltest: result row has 2 values but the query has 0 columns")
+	}
+
+	var id int
+	var name string
+	err = db.QueryRow("SELECT * FROM muppets").Scan(&id, &name)
+	expect.Nil(t, err)
+	expect.Equal(t, name, "kermit")
+}
+
+func TestDatabaseTransactions(t *testing.T) {
+	fake := NewDatabase(AnsiRuleset)
+	defer fake.Close()
+
+	db := fake.Open()
+	tx, err := db.Begin()
+	expect.Nil(t, err)
+	_, err = tx.Exec("DELETE FROM muppets")
+	expect.Nil(t, err)
+	expect.Nil(t, tx.Commit())
+}
+
+func TestClosedDatabase(t *testing.T) {
+	fake := NewDatabase(AnsiRuleset)
+	fake.Respond(Result{Err: errors.New("unused")})
+	fake.Close()
+
+	// connections opened after closing use the default rules with no results
+	_, err := fake.Open().Exec("DELETE FROM muppets")
+	expect.Nil(t, err)
+}
@@ -0,0 +1,31 @@
+/*
+Package sqltest provides a database/sql driver for testing code which runs
+sql queries, without connecting to a real database.
+
+The driver doesn't have its own scanner or parser; every query is parsed by
+the sql/language parser using one of its Rulesets, so a query which would be
+rejected by the language parser is an error from the driver as well.
+
+	sqltest.Register("sqltest_mysql", sqltest.MysqlRuleset)
+	db, _ := sql.Open("sqltest_mysql", "")
+	_, err := db.Query("SELECT * FROM")
+	// err: sql:1:14: expected 'a table name' but received 'End of statement'
+
+The package registers a "sqltest" driver using the AnsiRuleset.  To return
+canned results from queries, or to check statements against another dialect,
+create a Database and open it by name:
+
+	fake := sqltest.NewDatabase(sqltest.PostgresRuleset)
+	defer fake.Close()
+	fake.Respond(sqltest.Result{Columns: []string{"count"}, Rows: [][]driver.Value{{int64(3)}}})
+
+	db := fake.Open() // same as sql.Open("sqltest", fake.Name())
+
+To check that code runs particular statements, see Expectations.
+
+The Rulesets in this package are the same as the language parser's builtin
+rulesets, except that they allow syntax which hasn't been implemented by the
+parser yet (skipping to the end of the statement), so that tests aren't
+broken by queries that are valid but not understood.
+*/
+package sqltest
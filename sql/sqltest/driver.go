@@ -4,43 +4,62 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"fmt"
 	"io"
 
 	"github.com/reflexionhealth/vanilla/sql/language/ast"
 	"github.com/reflexionhealth/vanilla/sql/language/parser"
 )
 
-var AnsiRuleset parser.Ruleset
-var MysqlRuleset parser.Ruleset
+// Rulesets for each of the dialects supported by the language parser
+var (
+	AnsiRuleset     = testRules(parser.AnsiRuleset)
+	MysqlRuleset    = testRules(parser.MysqlRuleset)
+	PostgresRuleset = testRules(parser.PostgresRuleset)
+	SqliteRuleset   = testRules(parser.SqliteRuleset)
+)
 
-func init() {
-	AnsiRuleset = parser.AnsiRuleset
-	AnsiRuleset.AllowNotImplemented = true // temporary, maybe
-	MysqlRuleset = parser.MysqlRuleset
-	MysqlRuleset.AllowNotImplemented = true // temporary, maybe
+func testRules(rules parser.Ruleset) parser.Ruleset {
+	rules.AllowNotImplemented = true // temporary, maybe
+	return rules
 }
 
+// Register makes a Driver using the rules available to sql.Open by name
 func Register(name string, rules parser.Ruleset) {
 	sql.Register(name, &Driver{rules})
 }
 
+// A Driver is a database/sql driver which parses queries with the Rules.
+//
+// If the data source name is the Name of a Database, the connection uses the
+// database's rules and results instead.
 type Driver struct {
 	Rules parser.Ruleset
 }
 
 func (d *Driver) Open(name string) (driver.Conn, error) {
-	return &Conn{Rules: d.Rules}, nil
+	db := lookupDatabase(name)
+	if db == nil {
+		db = &Database{Rules: d.Rules}
+	}
+	return &Conn{Rules: db.Rules, db: db}, nil
 }
 
+// A Conn is a connection opened by the Driver
 type Conn struct {
 	Closed bool
 	Rules  parser.Ruleset
+
+	db *Database
 }
 
 func (c *Conn) Prepare(query string) (driver.Stmt, error) {
 	prep := parser.New([]byte(query), c.Rules)
 	stmt, err := prep.ParseStatement()
-	return &Stmt{Ast: stmt}, err
+	if err != nil {
+		return nil, err
+	}
+	return &Stmt{Sql: query, Ast: stmt, db: c.db, incomplete: prep.Incomplete()}, nil
 }
 
 func (c *Conn) Close() error {
@@ -50,12 +69,33 @@ func (c *Conn) Close() error {
 }
 
 func (c *Conn) Begin() (driver.Tx, error) {
-	return nil, errors.New("TODO: Implement Conn.Begin() for testing of transactions")
+	return &Tx{}, nil
+}
+
+// A Tx is a transaction, which doesn't do anything when it ends
+type Tx struct {
+	Committed  bool
+	RolledBack bool
+}
+
+func (tx *Tx) Commit() error {
+	tx.Committed = true
+	return nil
 }
 
+func (tx *Tx) Rollback() error {
+	tx.RolledBack = true
+	return nil
+}
+
+// A Stmt is a query which was parsed by the connection
 type Stmt struct {
 	Closed bool
+	Sql    string
 	Ast    ast.Stmt
+
+	db         *Database
+	incomplete bool // the ast is missing unimplemented syntax
 }
 
 func (s *Stmt) Close() error {
@@ -68,7 +108,11 @@ func (s *Stmt) NumInput() int {
 }
 
 func (s *Stmt) Exec(args []driver.Value) (driver.Result, error) {
-	return nil, errors.New("TODO: Implement Stmt.Exec() for testing of INSERTs, UPDATEs")
+	result := s.db.next(s, args)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+	return execResult{result.LastInsertId, result.RowsAffected}, nil
 }
 
 func (s *Stmt) Query(args []driver.Value) (driver.Rows, error) {
@@ -86,9 +130,22 @@ func (s *Stmt) Query(args []driver.Value) (driver.Rows, error) {
 		}
 	}
 
-	return &Rows{columns: columns}, nil
+	result := s.db.next(s, args)
+	if result.Err != nil {
+		return nil, result.Err
+	}
+	if result.Columns != nil {
+		columns = result.Columns
+	}
+	for _, row := range result.Rows {
+		if len(row) != len(columns) {
+			return nil, fmt.Errorf("sqltest: result row has %d values but the query has %d columns", len(row), len(columns))
+		}
+	}
+	return &Rows{columns: columns, rows: result.Rows}, nil
 }
 
+// Rows are the results of a query
 type Rows struct {
 	Closed  bool
 	Scanned int // count of scanned rows
@@ -7,10 +7,6 @@ import (
 	"github.com/reflexionhealth/vanilla/expect"
 )
 
-func init() {
-	sql.Register("sqltest", &Driver{})
-}
-
 func TestDriverUsage(t *testing.T) {
 	db, err := sql.Open("sqltest", "")
 	expect.Nil(t, err)
@@ -37,3 +33,18 @@ func TestSqlParseError(t *testing.T) {
 	expect.NotNil(t, err)
 	expect.Equal(t, err.Error(), "sql:1:14: expected 'a table name' but received 'End of statement'")
 }
+
+func TestDialectRulesets(t *testing.T) {
+	Register("sqltest_postgres", PostgresRuleset)
+	db, err := sql.Open("sqltest_postgres", "")
+	expect.Nil(t, err)
+
+	rows, err := db.Query("SELECT * FROM examples WHERE id = $1 FOR UPDATE", 3)
+	expect.Nil(t, err)
+	expect.Nil(t, rows.Close())
+
+	_, err = db.Query("SELECT * FROM `examples`")
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.Error(), "sql:1:15: unexpected character U+0060 '`'")
+	}
+}
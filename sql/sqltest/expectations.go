@@ -0,0 +1,240 @@
+package sqltest
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/reflexionhealth/vanilla/sql/language/ast"
+	"github.com/reflexionhealth/vanilla/sql/language/parser"
+	"github.com/reflexionhealth/vanilla/sql/language/scanner"
+	"github.com/reflexionhealth/vanilla/sql/language/token"
+)
+
+// Expectations is a fake database which only accepts the statements that a
+// test expects, and reports the expected statements which weren't run.
+//
+// Statements are matched by their parsed ast, so they can differ in spacing,
+// comments, the case of keywords, and the case or quoting of names (except
+// that quoted names with different cases don't match).  If either statement
+// includes syntax which the parser skips because it isn't implemented yet,
+// the statements must have the same tokens instead, since their asts are
+// missing the skipped part of the statement.
+//
+//     expected := sqltest.NewExpectations(sqltest.MysqlRuleset)
+//     defer expected.Close()
+//     expected.Expect("SELECT id FROM muppets WHERE name = ?").
+//         WithArgs("kermit").
+//         WillReturn(sqltest.Result{Rows: [][]driver.Value{{int64(1)}}})
+//
+//     runCodeUnderTest(expected.Open())
+//     expected.Verify(t)
+//
+type Expectations struct {
+	db *Database
+
+	mutex      sync.Mutex
+	expected   []*Expectation
+	unexpected []string
+}
+
+// An Expectation is a statement expected by Expectations
+type Expectation struct {
+	query      string
+	stmt       ast.Stmt
+	incomplete bool
+	args       []driver.Value
+	result     Result
+
+	checkArgs bool
+	met       bool
+}
+
+// NewExpectations returns Expectations for statements using the rules
+func NewExpectations(rules parser.Ruleset) *Expectations {
+	e := &Expectations{db: NewDatabase(rules)}
+	e.db.expectations = e
+	return e
+}
+
+// Open returns a *sql.DB connected to the fake database
+func (e *Expectations) Open() *sql.DB {
+	return e.db.Open()
+}
+
+// Close makes the fake database unavailable to the "sqltest" driver
+func (e *Expectations) Close() {
+	e.db.Close()
+}
+
+// Expect adds an expected statement, which must be run once.
+// It panics if the query can't be parsed.
+func (e *Expectations) Expect(query string) *Expectation {
+	prep := parser.New([]byte(query), e.db.Rules)
+	stmt, err := prep.ParseStatement()
+	if err != nil {
+		panic(fmt.Sprintf("sqltest: cannot parse expected statement %q: %v", query, err))
+	}
+
+	x := &Expectation{query: query, stmt: stmt, incomplete: prep.Incomplete()}
+	e.mutex.Lock()
+	e.expected = append(e.expected, x)
+	e.mutex.Unlock()
+	return x
+}
+
+// WithArgs requires the statement's arguments to be equal to args.
+// The arguments are compared after database/sql converts them to driver
+// values, so an int argument is expected as an int64.
+func (x *Expectation) WithArgs(args ...driver.Value) *Expectation {
+	x.args = args
+	x.checkArgs = true
+	return x
+}
+
+// WillReturn sets the result of the statement
+func (x *Expectation) WillReturn(result Result) *Expectation {
+	x.result = result
+	return x
+}
+
+func (x *Expectation) matches(stmt *Stmt, args []driver.Value, rules scanner.Ruleset) bool {
+	if !sameNode(reflect.ValueOf(x.stmt), reflect.ValueOf(stmt.Ast)) {
+		return false
+	}
+	if (x.incomplete || stmt.incomplete) &&
+		!reflect.DeepEqual(normalizedTokens(x.query, rules), normalizedTokens(stmt.Sql, rules)) {
+		return false
+	}
+	return !x.checkArgs || (len(x.args) == 0 && len(args) == 0) || reflect.DeepEqual(x.args, args)
+}
+
+// Verify reports an error if an expected statement wasn't run, or if a
+// statement was run that wasn't expected
+func (e *Expectations) Verify(t *testing.T) {
+	if err := e.Check(); err != nil {
+		t.Error(err)
+	}
+}
+
+// Check returns an error listing the expected statements which weren't run
+// and the statements which were run but weren't expected, if there are any
+func (e *Expectations) Check() error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	var problems []string
+	for _, x := range e.expected {
+		if !x.met {
+			problems = append(problems, "\n  expected statement was not run: "+x.query)
+		}
+	}
+	for _, query := range e.unexpected {
+		problems = append(problems, "\n  unexpected statement: "+query)
+	}
+	if len(problems) > 0 {
+		return errors.New("sqltest: expectations were not met:" + strings.Join(problems, ""))
+	}
+	return nil
+}
+
+// match returns the result of the first unmet expectation matching stmt
+func (e *Expectations) match(stmt *Stmt, args []driver.Value) Result {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	for _, x := range e.expected {
+		if !x.met && x.matches(stmt, args, e.db.Rules.ScanRules) {
+			x.met = true
+			return x.result
+		}
+	}
+
+	e.unexpected = append(e.unexpected, stmt.Sql)
+	return Result{Err: fmt.Errorf("sqltest: unexpected statement: %s", stmt.Sql)}
+}
+
+var identifierType = reflect.TypeOf(&ast.Identifier{})
+
+// sameNode compares two ast values like reflect.DeepEqual, except that
+// identifiers are compared by normalizedName and nil slices equal empty slices
+func sameNode(a, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		if a.Type() == identifierType {
+			return normalizedName(a.Interface().(*ast.Identifier)) == normalizedName(b.Interface().(*ast.Identifier))
+		}
+		return sameNode(a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if !sameNode(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !sameNode(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a.Interface() == b.Interface()
+	}
+}
+
+// normalizedName is the name of an identifier, in lowercase unless it is quoted
+func normalizedName(ident *ast.Identifier) string {
+	if ident.Quoted {
+		return ident.Name
+	}
+	return strings.ToLower(ident.Name)
+}
+
+// normalizedTokens scans a statement into tokens which are compared when the
+// ast of a statement is incomplete, ignoring comments, the case of keywords,
+// and the case of unquoted names
+func normalizedTokens(query string, rules scanner.Ruleset) []string {
+	var s scanner.Scanner
+	s.Init([]byte(query), nil, rules)
+
+	var tokens []string
+	for {
+		_, tok, lit := s.Scan()
+		switch {
+		case tok == token.EOS:
+			if n := len(tokens); n > 0 && tokens[n-1] == token.SEMICOLON.String() {
+				tokens = tokens[:n-1]
+			}
+			return tokens
+		case tok == token.COMMENT:
+			continue
+		case tok == token.IDENT:
+			// like normalizedName, quoted and unquoted names are the same
+			tokens = append(tokens, token.QUOTED_IDENT.String()+" "+strings.ToLower(lit))
+		case tok.HasLiteral():
+			tokens = append(tokens, tok.String()+" "+lit)
+		default:
+			tokens = append(tokens, tok.String())
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package sqltest
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+func TestExpectations(t *testing.T) {
+	expected := NewExpectations(MysqlRuleset)
+	defer expected.Close()
+	expected.Expect("SELECT id, name FROM muppets WHERE kind = ?").
+		WithArgs("frog").
+		WillReturn(Result{Rows: [][]driver.Value{{int64(1), "kermit"}}})
+	expected.Expect("update muppets set kind = 'bear' where id = ?").
+		WillReturn(Result{RowsAffected: 1})
+
+	db := expected.Open()
+
+	// statements match regardless of spacing, comments, case, and quoting
+	result, err := db.Exec("UPDATE `muppets`\n  SET KIND = 'bear' -- wocka wocka\n  WHERE ID = ?", 3)
+	expect.Nil(t, err)
+	affected, _ := result.RowsAffected()
+	expect.Equal(t, affected, int64(1))
+
+	var id int
+	var name string
+	err = db.QueryRow("SELECT id, name FROM muppets WHERE kind = ?", "frog").Scan(&id, &name)
+	expect.Nil(t, err)
+	expect.Equal(t, name, "kermit")
+
+	expected.Verify(t)
+}
+
+func TestExpectationsNotMet(t *testing.T) {
+	expected := NewExpectations(MysqlRuleset)
+	defer expected.Close()
+	expected.Expect("SELECT id FROM muppets WHERE kind = ?").WithArgs("frog")
+	expected.Expect("DELETE FROM muppets WHERE id = 3")
+	expected.Expect("DELETE FROM muppets WHERE id = 3")
+
+	db := expected.Open()
+	_, err := db.Exec("DELETE FROM muppets WHERE id = 3")
+	expect.Nil(t, err)
+
+	// statements with different args, values, or quoted names don't match
+	_, err = db.Query("SELECT id FROM muppets WHERE kind = ?", "pig")
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.Error(), "sqltest: unexpected statement: SELECT id FROM muppets WHERE kind = ?")
+	}
+	_, err = db.Exec("DELETE FROM muppets WHERE id = 4")
+	expect.NotNil(t, err)
+	_, err = db.Exec("DELETE FROM `Muppets` WHERE id = 3")
+	expect.NotNil(t, err)
+
+	err = expected.Check()
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.Error(), "sqltest: expectations were not met:\n"+
+			"  expected statement was not run: SELECT id FROM muppets WHERE kind = ?\n"+
+			"  expected statement was not run: DELETE FROM muppets WHERE id = 3\n"+
+			"  unexpected statement: SELECT id FROM muppets WHERE kind = ?\n"+
+			"  unexpected statement: DELETE FROM muppets WHERE id = 4\n"+
+			"  unexpected statement: DELETE FROM `Muppets` WHERE id = 3")
+	}
+}
+
+func TestExpectInvalidStatement(t *testing.T) {
+	defer func() {
+		expect.Equal(t, recover(), `sqltest: cannot parse expected statement "SELECT * FROM": `+
+			`sql:1:14: expected 'a table name' but received 'End of statement'`)
+	}()
+
+	expected := NewExpectations(AnsiRuleset)
+	defer expected.Close()
+	expected.Expect("SELECT * FROM")
+}
+
+func TestExpectIncompleteStatement(t *testing.T) {
+	expected := NewExpectations(MysqlRuleset)
+	defer expected.Close()
+	expected.Expect("INSERT INTO muppets (name) VALUES ('kermit') ON DUPLICATE KEY UPDATE name = 'kermit'")
+	expected.Expect("INSERT INTO muppets (name) VALUES ('gonzo')")
+
+	db := expected.Open()
+
+	// the ON DUPLICATE KEY clause isn't parsed, so the rest of the statement
+	// must be the same (except for spacing, comments, case, and quoting)
+	_, err := db.Exec("INSERT INTO muppets (name) VALUES ('kermit') ON DUPLICATE KEY UPDATE name = 'gonzo'")
+	expect.NotNil(t, err)
+	_, err = db.Exec("INSERT INTO muppets (name) VALUES ('gonzo') ON DUPLICATE KEY UPDATE name = 'gonzo'")
+	expect.NotNil(t, err)
+	_, err = db.Exec("insert into `muppets` (NAME) values ('kermit') -- upsert\n  on duplicate key update name = 'kermit';")
+	expect.Nil(t, err)
+	_, err = db.Exec("INSERT INTO muppets (name) VALUES ('gonzo')")
+	expect.Nil(t, err)
+
+	err = expected.Check()
+	if expect.NotNil(t, err) {
+		expect.Equal(t, err.Error(), "sqltest: expectations were not met:\n"+
+			"  unexpected statement: INSERT INTO muppets (name) VALUES ('kermit') ON DUPLICATE KEY UPDATE name = 'gonzo'\n"+
+			"  unexpected statement: INSERT INTO muppets (name) VALUES ('gonzo') ON DUPLICATE KEY UPDATE name = 'gonzo'")
+	}
+}
@@ -0,0 +1,108 @@
+package sql
+
+import (
+	"context"
+	conn "database/sql"
+	"errors"
+	"time"
+)
+
+// A TxBeginner can begin a transaction, such as a *database/sql.DB or *database/sql.Conn
+type TxBeginner interface {
+	BeginTx(ctx context.Context, opts *conn.TxOptions) (*conn.Tx, error)
+}
+
+// WithTx runs fn in a transaction, which is committed if fn returns nil
+// and rolled back if fn returns an error or panics.
+//
+//     err := sql.WithTx(ctx, db, func(tx *conn.Tx) error {
+//         _, err := tx.ExecContext(ctx, stmt.Sql(), stmt.Args()...)
+//         return err
+//     })
+//
+// To retry transactions that fail because of concurrent updates, see RetryPolicy.
+func WithTx(ctx context.Context, db TxBeginner, fn func(tx *conn.Tx) error) error {
+	return RetryPolicy{}.WithTx(ctx, db, fn)
+}
+
+// A RetryPolicy controls how WithTx retries a transaction which fails with
+// a serialization failure, which is expected when using the SERIALIZABLE
+// isolation level.
+//
+//     policy := sql.RetryPolicy{Attempts: 3, Backoff: 10 * time.Millisecond}
+//     err := policy.WithTx(ctx, db, func(tx *conn.Tx) error { ... })
+//
+type RetryPolicy struct {
+	// Attempts is the maximum number of times to run the transaction.
+	// If it is zero, the transaction is only tried once.
+	Attempts int
+
+	// Backoff is the delay before the first retry, which doubles for each
+	// following retry.
+	Backoff time.Duration
+
+	// Options are passed to BeginTx for each attempt
+	Options *conn.TxOptions
+
+	// Retryable reports whether a failed transaction should be retried.
+	// If it is nil, IsSerializationFailure is used.
+	Retryable func(error) bool
+}
+
+// WithTx is like the WithTx function, but retries failed transactions
+// according to the policy.  Because fn may be called more than once,
+// it should not have side effects outside of the transaction.
+func (p RetryPolicy) WithTx(ctx context.Context, db TxBeginner, fn func(tx *conn.Tx) error) error {
+	retryable := p.Retryable
+	if retryable == nil {
+		retryable = IsSerializationFailure
+	}
+
+	backoff := p.Backoff
+	for attempt := 1; ; attempt++ {
+		err := runTx(ctx, db, p.Options, fn)
+		if err == nil || attempt >= p.Attempts || !retryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+			backoff *= 2
+		}
+	}
+}
+
+func runTx(ctx context.Context, db TxBeginner, opts *conn.TxOptions, fn func(tx *conn.Tx) error) error {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// IsSerializationFailure reports whether err is a serialization failure or
+// deadlock, as reported by drivers which provide a "SQLState() string" method
+// on their errors (eg. SQLSTATE 40001 and 40P01 in Postgres).
+func IsSerializationFailure(err error) bool {
+	var state interface{ SQLState() string }
+	if !errors.As(err, &state) {
+		return false
+	}
+
+	code := state.SQLState()
+	return code == "40001" || code == "40P01"
+}
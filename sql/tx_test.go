@@ -0,0 +1,101 @@
+package sql
+
+import (
+	"context"
+	conn "database/sql"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/expect"
+)
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "sql state " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+func TestWithTx(t *testing.T) {
+	rec, db := openRecorder()
+	defer db.Close()
+	ctx := context.Background()
+
+	err := WithTx(ctx, db, func(tx *conn.Tx) error {
+		_, err := tx.ExecContext(ctx, "DELETE FROM users")
+		return err
+	})
+	expect.Nil(t, err)
+	expect.Equal(t, rec.Events(), []string{"BEGIN", "DELETE FROM users", "COMMIT"})
+
+	rec, db = openRecorder()
+	failed := errors.New("failed")
+	err = WithTx(ctx, db, func(tx *conn.Tx) error { return failed })
+	expect.Equal(t, err, failed)
+	expect.Equal(t, rec.Events(), []string{"BEGIN", "ROLLBACK"})
+
+	rec, db = openRecorder()
+	func() {
+		defer func() { expect.Equal(t, recover(), "oops") }()
+		WithTx(ctx, db, func(tx *conn.Tx) error { panic("oops") })
+	}()
+	expect.Equal(t, rec.Events(), []string{"BEGIN", "ROLLBACK"})
+
+	// without a retry policy, serialization failures are not retried
+	rec, db = openRecorder()
+	rec.commitErr = []error{sqlStateError("40001")}
+	err = WithTx(ctx, db, func(tx *conn.Tx) error { return nil })
+	expect.Equal(t, err, sqlStateError("40001"))
+	expect.Equal(t, rec.Events(), []string{"BEGIN", "COMMIT failed"})
+}
+
+func TestRetryPolicy(t *testing.T) {
+	ctx := context.Background()
+	policy := RetryPolicy{Attempts: 3, Backoff: time.Millisecond}
+
+	rec, db := openRecorder()
+	rec.commitErr = []error{sqlStateError("40001"), sqlStateError("40P01")}
+	calls := 0
+	err := policy.WithTx(ctx, db, func(tx *conn.Tx) error { calls++; return nil })
+	expect.Nil(t, err)
+	expect.Equal(t, calls, 3)
+	expect.Equal(t, rec.Events(), []string{"BEGIN", "COMMIT failed", "BEGIN", "COMMIT failed", "BEGIN", "COMMIT"})
+
+	// stops after the maximum attempts
+	rec, db = openRecorder()
+	rec.commitErr = []error{sqlStateError("40001"), sqlStateError("40001"), sqlStateError("40001")}
+	calls = 0
+	err = policy.WithTx(ctx, db, func(tx *conn.Tx) error { calls++; return nil })
+	expect.Equal(t, err, sqlStateError("40001"))
+	expect.Equal(t, calls, 3)
+
+	// other errors aren't retried
+	rec, db = openRecorder()
+	calls = 0
+	err = policy.WithTx(ctx, db, func(tx *conn.Tx) error { calls++; return sqlStateError("23505") })
+	expect.Equal(t, err, sqlStateError("23505"))
+	expect.Equal(t, calls, 1)
+
+	// custom retryable errors
+	rec, db = openRecorder()
+	calls = 0
+	policy.Retryable = func(err error) bool { return err.Error() == "busy" }
+	err = policy.WithTx(ctx, db, func(tx *conn.Tx) error {
+		if calls++; calls < 2 {
+			return errors.New("busy")
+		}
+		return nil
+	})
+	expect.Nil(t, err)
+	expect.Equal(t, calls, 2)
+	expect.Equal(t, rec.Events(), []string{"BEGIN", "ROLLBACK", "BEGIN", "COMMIT"})
+}
+
+func TestIsSerializationFailure(t *testing.T) {
+	expect.True(t, IsSerializationFailure(sqlStateError("40001")))
+	expect.True(t, IsSerializationFailure(sqlStateError("40P01")))
+	expect.True(t, IsSerializationFailure(fmt.Errorf("in transaction: %w", sqlStateError("40001"))))
+	expect.False(t, IsSerializationFailure(sqlStateError("23505")))
+	expect.False(t, IsSerializationFailure(errors.New("40001")))
+	expect.False(t, IsSerializationFailure(nil))
+}
@@ -0,0 +1,67 @@
+package uuid
+
+import (
+	"io"
+	"sync"
+)
+
+// NewBatch returns n random generated (version 4) UUIDs.
+// The entropy for the whole batch is read at once, which is faster than
+// calling NewV4 in a loop when generating many IDs (eg. in bulk imports).
+func NewBatch(n int) []UUID {
+	buf := make([]byte, 16*n)
+	safeRandom(buf)
+
+	uuids := make([]UUID, n)
+	for i := range uuids {
+		copy(uuids[i][:], buf[16*i:])
+		uuids[i].SetVersion(4)
+		uuids[i].SetVariant()
+	}
+	return uuids
+}
+
+// Pool generates random (version 4) UUIDs from a buffer of entropy,
+// which is refilled from the Source (usually crypto/rand) once exhausted.  A Pool is safe for
+// concurrent use.
+//
+// Buffered entropy is held in memory until it is used, so a Pool should not
+// be used for UUIDs which must be unguessable (eg. session tokens) by
+// an attacker who can read process memory.
+type Pool struct {
+	mutex  sync.Mutex
+	reader io.Reader
+	buf    []byte
+	off    int
+}
+
+// NewPool returns a Pool which buffers entropy for size UUIDs at a time.
+func NewPool(size int) *Pool {
+	if size < 1 {
+		size = 1
+	}
+	buf := make([]byte, 16*size)
+	return &Pool{reader: sourceReader{}, buf: buf, off: len(buf)}
+}
+
+// NewV4 returns random generated UUID.
+func (p *Pool) NewV4() UUID {
+	u := UUID{}
+
+	p.mutex.Lock()
+	if p.off == len(p.buf) {
+		if _, err := io.ReadFull(p.reader, p.buf); err != nil {
+			p.mutex.Unlock()
+			panic(err)
+		}
+		p.off = 0
+	}
+	copy(u[:], p.buf[p.off:])
+	p.off += 16
+	p.mutex.Unlock()
+
+	u.SetVersion(4)
+	u.SetVariant()
+
+	return u
+}
@@ -0,0 +1,98 @@
+package uuid
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestNewBatch(t *testing.T) {
+	uuids := NewBatch(1000)
+	if len(uuids) != 1000 {
+		t.Fatalf("NewBatch generated %d UUIDs, expected 1000", len(uuids))
+	}
+
+	seen := make(map[UUID]bool)
+	for _, u := range uuids {
+		if u.Version() != 4 || u.Variant() != VariantRFC4122 {
+			t.Errorf("NewBatch generated UUID with incorrect version or variant: %s", u)
+		}
+		if seen[u] {
+			t.Errorf("NewBatch generated duplicate UUID: %s", u)
+		}
+		seen[u] = true
+	}
+
+	if len(NewBatch(0)) != 0 {
+		t.Errorf("NewBatch(0) should generate no UUIDs")
+	}
+}
+
+func TestPool(t *testing.T) {
+	entropy := bytes.Repeat([]byte{0xff}, 16*3)
+	entropy = append(entropy, bytes.Repeat([]byte{0x00}, 16*3)...)
+
+	p := NewPool(3)
+	p.reader = bytes.NewReader(entropy)
+	for i := 0; i < 6; i++ {
+		u := p.NewV4()
+		if u.Version() != 4 || u.Variant() != VariantRFC4122 {
+			t.Errorf("Pool generated UUID with incorrect version or variant: %s", u)
+		}
+		if i < 3 && u.String() != "ffffffff-ffff-4fff-bfff-ffffffffffff" {
+			t.Errorf("Pool generated UUID from incorrect entropy: %s", u)
+		} else if i >= 3 && u.String() != "00000000-0000-4000-8000-000000000000" {
+			t.Errorf("Pool generated UUID from incorrect entropy: %s", u)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Pool should panic when entropy is exhausted")
+		}
+	}()
+	p.NewV4()
+}
+
+func TestPoolConcurrent(t *testing.T) {
+	p := NewPool(16)
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	seen := make(map[UUID]bool)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				u := p.NewV4()
+				mutex.Lock()
+				if seen[u] {
+					t.Errorf("Pool generated duplicate UUID: %s", u)
+				}
+				seen[u] = true
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkNewV4(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		NewV4()
+	}
+}
+
+func BenchmarkNewBatch(b *testing.B) {
+	for i := 0; i < b.N; i += 1000 {
+		NewBatch(1000)
+	}
+}
+
+func BenchmarkPool(b *testing.B) {
+	p := NewPool(1000)
+	for i := 0; i < b.N; i++ {
+		p.NewV4()
+	}
+}
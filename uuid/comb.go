@@ -0,0 +1,64 @@
+package uuid
+
+import "time"
+
+// Comb generates "COMB" UUIDs: version 4 UUIDs whose first 48 bits are a
+// timestamp instead of random data, so that IDs generated later sort after
+// earlier IDs and new rows are inserted near each other in a B-tree index.
+//
+// Prefer NewV7 where it can be used; Comb is for databases and consumers
+// which expect a version 4 UUID.
+//
+// The timestamp counts ticks of Precision since the Unix epoch, and wraps
+// after 2^48 ticks (about 8900 years for milliseconds, but only 9 years for
+// microseconds).  UUIDs within a tick are not ordered.  The remaining 74
+// bits are random, so the chance of a collision is only significant for
+// more than about 2^37 UUIDs generated within the same tick.
+//
+// The zero value is ready to use, with a precision of one millisecond and
+// the time read from the current Source (see SetSource).
+type Comb struct {
+	Precision time.Duration
+
+	now func() time.Time
+}
+
+// NewComb returns a Comb with the given timestamp precision.
+// If precision is zero, the precision is one millisecond.
+func NewComb(precision time.Duration) *Comb {
+	if precision <= 0 {
+		precision = time.Millisecond
+	}
+	return &Comb{Precision: precision}
+}
+
+// New returns a COMB UUID for the current time.
+func (c *Comb) New() UUID {
+	u := UUID{}
+	safeRandom(u[6:])
+
+	now, precision := c.now, c.Precision
+	if now == nil {
+		now = sourceNow
+	}
+	if precision <= 0 {
+		precision = time.Millisecond
+	}
+
+	ticks := uint64(now().UnixNano() / int64(precision))
+	u[0] = byte(ticks >> 40)
+	u[1] = byte(ticks >> 32)
+	u[2] = byte(ticks >> 24)
+	u[3] = byte(ticks >> 16)
+	u[4] = byte(ticks >> 8)
+	u[5] = byte(ticks)
+
+	u.SetVersion(4)
+	u.SetVariant()
+
+	return u
+}
+
+func sourceNow() time.Time {
+	return currentSource().Now()
+}
@@ -0,0 +1,79 @@
+package uuid
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/clock"
+)
+
+func TestComb(t *testing.T) {
+	now := time.Unix(1476400000, 0)
+	c := NewComb(0)
+	c.now = func() time.Time { return now }
+
+	if c.Precision != time.Millisecond {
+		t.Errorf("Comb has incorrect default precision: %s", c.Precision)
+	}
+
+	u := c.New()
+	if u.Version() != 4 {
+		t.Errorf("COMB UUID generated with incorrect version: %d", u.Version())
+	}
+	if u.Variant() != VariantRFC4122 {
+		t.Errorf("COMB UUID generated with incorrect variant: %d", u.Variant())
+	}
+	ticks := uint64(1476400000000)
+	expected := []byte{byte(ticks >> 40), byte(ticks >> 32), byte(ticks >> 24), byte(ticks >> 16), byte(ticks >> 8), byte(ticks)}
+	if !bytes.Equal(u[:6], expected) {
+		t.Errorf("COMB UUID generated with incorrect timestamp: %x, expected %x", u[:6], expected)
+	}
+
+	prev := u
+	for i := 0; i < 100; i++ {
+		now = now.Add(time.Millisecond)
+		u := c.New()
+		if bytes.Compare(prev[:], u[:]) >= 0 {
+			t.Errorf("COMB UUID generated out of order: %s after %s", u, prev)
+		}
+		prev = u
+	}
+}
+
+func TestCombZeroValue(t *testing.T) {
+	clock.Freeze(t, time.Unix(1476400000, 0))
+
+	var c Comb
+	u := c.New()
+	ticks := uint64(1476400000000)
+	expected := []byte{byte(ticks >> 40), byte(ticks >> 32), byte(ticks >> 24), byte(ticks >> 16), byte(ticks >> 8), byte(ticks)}
+	if !bytes.Equal(u[:6], expected) {
+		t.Errorf("COMB UUID from the zero value has incorrect timestamp: %x, expected %x", u[:6], expected)
+	}
+	if u.Version() != 4 {
+		t.Errorf("COMB UUID from the zero value has incorrect version: %d", u.Version())
+	}
+}
+
+func TestCombPrecision(t *testing.T) {
+	now := time.Unix(1476400000, 0)
+	c := NewComb(time.Second)
+	c.now = func() time.Time { return now }
+
+	u1 := c.New()
+	now = now.Add(500 * time.Millisecond)
+	u2 := c.New()
+	if !bytes.Equal(u1[:6], u2[:6]) {
+		t.Errorf("COMB UUIDs in the same tick should have the same prefix: %s and %s", u1, u2)
+	}
+	if bytes.Equal(u1[6:], u2[6:]) {
+		t.Errorf("COMB UUIDs should have random suffixes: %s and %s", u1, u2)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	u3 := c.New()
+	if bytes.Compare(u2[:6], u3[:6]) >= 0 {
+		t.Errorf("COMB UUIDs in the next tick should sort after: %s and %s", u2, u3)
+	}
+}
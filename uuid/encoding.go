@@ -0,0 +1,94 @@
+package uuid
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// The Bitcoin base58 alphabet, which omits 0, O, I, and l
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// Length of a UUID encoded by Base58 or Base64URL
+const (
+	base58Length = 22
+	base64Length = 22
+)
+
+var base58Index [256]byte
+
+func init() {
+	for i := range base58Index {
+		base58Index[i] = 0xff
+	}
+	for i := 0; i < len(base58Alphabet); i++ {
+		base58Index[base58Alphabet[i]] = byte(i)
+	}
+}
+
+// Base58 returns the UUID as a 22 character base58 string, for use in short
+// URLs.  The string is padded with leading "1"s (zeros) to a fixed length,
+// so encoded UUIDs sort in the same order as their bytes.
+func (u UUID) Base58() string {
+	// NOTE: Repeatedly divide the 128-bit number by 58, collecting remainders
+	num := u
+	buf := make([]byte, base58Length)
+	for i := base58Length - 1; i >= 0; i-- {
+		remainder := 0
+		for j := range num {
+			acc := remainder<<8 | int(num[j])
+			num[j] = byte(acc / 58)
+			remainder = acc % 58
+		}
+		buf[i] = base58Alphabet[remainder]
+	}
+	return string(buf)
+}
+
+// FromBase58 returns the UUID encoded by Base58.
+func FromBase58(input string) (u UUID, err error) {
+	if len(input) != base58Length {
+		err = fmt.Errorf("uuid: base58 UUID must be %d characters long, got %d", base58Length, len(input))
+		return
+	}
+
+	for i := 0; i < len(input); i++ {
+		digit := base58Index[input[i]]
+		if digit == 0xff {
+			err = fmt.Errorf("uuid: invalid base58 character %q", input[i])
+			return
+		}
+
+		// NOTE: Multiply the 128-bit number by 58 and add the digit
+		carry := int(digit)
+		for j := len(u) - 1; j >= 0; j-- {
+			acc := int(u[j])*58 + carry
+			u[j] = byte(acc)
+			carry = acc >> 8
+		}
+		if carry != 0 {
+			err = fmt.Errorf("uuid: base58 UUID is out of range: %s", input)
+			return Nil, err
+		}
+	}
+	return
+}
+
+// Base64URL returns the UUID as a 22 character unpadded base64 string,
+// using the URL and filename safe alphabet.
+func (u UUID) Base64URL() string {
+	return base64.RawURLEncoding.EncodeToString(u[:])
+}
+
+// FromBase64URL returns the UUID encoded by Base64URL.
+func FromBase64URL(input string) (u UUID, err error) {
+	if len(input) != base64Length {
+		err = fmt.Errorf("uuid: base64 UUID must be %d characters long, got %d", base64Length, len(input))
+		return
+	}
+
+	_, err = base64.RawURLEncoding.Strict().Decode(u[:], []byte(input))
+	if err != nil {
+		return Nil, fmt.Errorf("uuid: invalid base64 UUID: %s", input)
+	}
+	return
+}
@@ -0,0 +1,112 @@
+package uuid
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+)
+
+func TestBase58(t *testing.T) {
+	examples := []struct {
+		UUID   UUID
+		Base58 string
+	}{
+		{Nil, "1111111111111111111111"},
+		{UUID{15: 1}, "1111111111111111111112"},
+		{UUID{15: 58}, "1111111111111111111121"},
+		{NamespaceDNS, "EJ34kCVxxF9jHMKD4EgrAK"},
+		{UUID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, "YcVfxkQb6JRzqk5kF2tNLv"},
+	}
+
+	for _, ex := range examples {
+		if s := ex.UUID.Base58(); s != ex.Base58 {
+			t.Errorf("Incorrect base58 encoding of %s: %s, expected %s", ex.UUID, s, ex.Base58)
+		}
+
+		u, err := FromBase58(ex.Base58)
+		if err != nil {
+			t.Errorf("Error parsing base58 UUID %s: %s", ex.Base58, err)
+		}
+		if !Equal(u, ex.UUID) {
+			t.Errorf("Incorrect base58 decoding of %s: %s, expected %s", ex.Base58, u, ex.UUID)
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		u1 := NewV4()
+		u2, err := FromBase58(u1.Base58())
+		if err != nil || !Equal(u1, u2) {
+			t.Errorf("Base58 of %s did not round trip: %s, %v", u1, u2, err)
+		}
+	}
+}
+
+func TestBase58Sorted(t *testing.T) {
+	uuids := make([]UUID, 100)
+	encoded := make([]string, 100)
+	for i := range uuids {
+		uuids[i] = NewV4()
+	}
+	sort.Slice(uuids, func(i, j int) bool { return bytes.Compare(uuids[i][:], uuids[j][:]) < 0 })
+	for i := range uuids {
+		encoded[i] = uuids[i].Base58()
+	}
+	if !sort.StringsAreSorted(encoded) {
+		t.Errorf("Base58 encoding does not preserve UUID order")
+	}
+}
+
+func TestFromBase58Invalid(t *testing.T) {
+	examples := []string{
+		"",
+		"111111111111111111111",
+		"11111111111111111111111",
+		"111111111111111111111O",
+		"111111111111111111111-",
+		"zzzzzzzzzzzzzzzzzzzzzz",
+	}
+
+	for _, ex := range examples {
+		if _, err := FromBase58(ex); err == nil {
+			t.Errorf("Should return error parsing invalid base58 UUID %q", ex)
+		}
+	}
+}
+
+func TestBase64URL(t *testing.T) {
+	if s := NamespaceDNS.Base64URL(); s != "a6e4EJ2tEdGAtADAT9QwyA" {
+		t.Errorf("Incorrect base64 encoding of %s: %s", NamespaceDNS, s)
+	}
+
+	u, err := FromBase64URL("a6e4EJ2tEdGAtADAT9QwyA")
+	if err != nil {
+		t.Errorf("Error parsing base64 UUID: %s", err)
+	}
+	if !Equal(u, NamespaceDNS) {
+		t.Errorf("Incorrect base64 decoding: %s, expected %s", u, NamespaceDNS)
+	}
+
+	for i := 0; i < 100; i++ {
+		u1 := NewV4()
+		u2, err := FromBase64URL(u1.Base64URL())
+		if err != nil || !Equal(u1, u2) {
+			t.Errorf("Base64URL of %s did not round trip: %s, %v", u1, u2, err)
+		}
+	}
+}
+
+func TestFromBase64URLInvalid(t *testing.T) {
+	examples := []string{
+		"",
+		"a6e4EJ2tEdGAtADAT9Qwy",
+		"a6e4EJ2tEdGAtADAT9QwyA==",
+		"a6e4EJ2tEdGAtADAT9Qwy+",
+		"a6e4EJ2tEdGAtADAT9QwyB",
+	}
+
+	for _, ex := range examples {
+		if _, err := FromBase64URL(ex); err == nil {
+			t.Errorf("Should return error parsing invalid base64 UUID %q", ex)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package uuid
+
+import "fmt"
+
+// Deterministic IDs can be derived from external identifiers by hashing them
+// in a namespace; the same namespace and name always produce the same UUID.
+func ExampleNewV5() {
+	fmt.Println(NewV5(NamespaceDNS, "python.org"))
+	fmt.Println(NewV5(NamespaceURL, "https://www.python.org/"))
+	fmt.Println(NewV3(NamespaceDNS, "python.org"))
+	// Output:
+	// 886313e1-3b8a-5372-9b90-0c9aee199e5d
+	// 5406f80d-92e9-51cd-a176-77445955e733
+	// 6fa459ea-ee8a-3ca4-894e-db77e160355e
+}
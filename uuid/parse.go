@@ -0,0 +1,105 @@
+package uuid
+
+import (
+	"encoding/hex"
+	"errors"
+)
+
+// Lookup table from a hex digit to its value, or 0xff for non-hex characters.
+// NOTE: This is built by a var initializer (instead of an init func) because
+// the Namespace UUIDs are parsed during package initialization.
+var hexValues = buildHexValues()
+
+func buildHexValues() (values [256]byte) {
+	for i := range values {
+		values[i] = 0xff
+	}
+	for i := byte(0); i < 10; i++ {
+		values['0'+i] = i
+	}
+	for i := byte(0); i < 6; i++ {
+		values['a'+i] = 10 + i
+		values['A'+i] = 10 + i
+	}
+	return values
+}
+
+// The offset of each byte of a UUID in its canonical string form
+var hexOffsets = [16]int{0, 2, 4, 6, 9, 11, 14, 16, 19, 21, 24, 26, 28, 30, 32, 34}
+
+// Parse returns the UUID parsed from text, in any of the forms accepted by
+// UnmarshalText.  It does not allocate unless the text is invalid.
+func Parse(text []byte) (UUID, error) {
+	u, problem := parse(text)
+	if len(problem) > 0 {
+		return Nil, errors.New("uuid: " + problem + ": " + string(text))
+	}
+	return u, nil
+}
+
+// Must returns u, but panics if err is not nil.
+// It simplifies safe intialization of global variables, eg.
+//
+//     var NamespaceApp = uuid.Must(uuid.FromString("..."))
+//
+func Must(u UUID, err error) UUID {
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// IsValid returns true if s is a UUID in a form accepted by FromString.
+func IsValid(s string) bool {
+	_, problem := parse(s)
+	return len(problem) == 0
+}
+
+// AppendText appends the canonical string representation of the UUID to b.
+// It implements the encoding.TextAppender interface.
+func (u UUID) AppendText(b []byte) ([]byte, error) {
+	var buf [36]byte
+	u.encodeCanonical(&buf)
+	return append(b, buf[:]...), nil
+}
+
+func (u UUID) encodeCanonical(buf *[36]byte) {
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = dash
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = dash
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = dash
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = dash
+	hex.Encode(buf[24:], u[10:])
+}
+
+// parse decodes a canonical, braced, or URN form UUID from text.
+// If the text is invalid, it returns a description of the problem instead.
+func parse[T string | []byte](text T) (u UUID, problem string) {
+	switch {
+	case len(text) < 36:
+		return Nil, "UUID string too short"
+	case len(text) == 36:
+		// canonical
+	case len(text) == 38 && text[0] == '{' && text[37] == '}':
+		text = text[1:37]
+	case len(text) == 45 && string(text[:9]) == string(urnPrefix):
+		text = text[9:]
+	default:
+		return Nil, "invalid string format"
+	}
+
+	if text[8] != dash || text[13] != dash || text[18] != dash || text[23] != dash {
+		return Nil, "invalid string format"
+	}
+	for i, offset := range hexOffsets {
+		hi, lo := hexValues[text[offset]], hexValues[text[offset+1]]
+		if hi == 0xff || lo == 0xff {
+			return Nil, "invalid hex digit"
+		}
+		u[i] = hi<<4 | lo
+	}
+	return u, ""
+}
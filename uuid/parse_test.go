@@ -0,0 +1,122 @@
+package uuid
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	examples := []string{
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		"6BA7B810-9DAD-11D1-80B4-00C04FD430C8",
+		"{6ba7b810-9dad-11d1-80b4-00c04fd430c8}",
+		"urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+	}
+
+	for _, ex := range examples {
+		u, err := Parse([]byte(ex))
+		if err != nil {
+			t.Errorf("Error parsing UUID %s: %s", ex, err)
+		}
+		if !Equal(u, NamespaceDNS) {
+			t.Errorf("UUIDs should be equal: %s and %s", u, NamespaceDNS)
+		}
+		if !IsValid(ex) {
+			t.Errorf("UUID should be valid: %s", ex)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c8=",
+		"{6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		"urn:uuid:{6ba7b810-9dad-11d1-80b4-00c04fd430c8}",
+		"6ba7b810+9dad+11d1+80b4+00c04fd430c8",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430cg",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430-c",
+	}
+
+	for _, ex := range invalid {
+		if _, err := Parse([]byte(ex)); err == nil {
+			t.Errorf("Should return error trying to parse invalid string, passed %s", ex)
+		}
+		if IsValid(ex) {
+			t.Errorf("UUID should not be valid: %s", ex)
+		}
+	}
+
+	_, err := Parse([]byte("6ba7b810-9dad-11d1-80b4-00c04fd430c"))
+	if err == nil || err.Error() != "uuid: UUID string too short: 6ba7b810-9dad-11d1-80b4-00c04fd430c" {
+		t.Errorf("Incorrect error for short string: %v", err)
+	}
+}
+
+func TestMust(t *testing.T) {
+	if u := Must(FromString("6ba7b810-9dad-11d1-80b4-00c04fd430c8")); !Equal(u, NamespaceDNS) {
+		t.Errorf("UUIDs should be equal: %s and %s", u, NamespaceDNS)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Must should panic on error")
+		}
+	}()
+	Must(FromString("bogus"))
+}
+
+func TestAppendText(t *testing.T) {
+	b, err := NamespaceDNS.AppendText([]byte("id="))
+	if err != nil {
+		t.Errorf("Error appending UUID: %s", err)
+	}
+	if !bytes.Equal(b, []byte("id=6ba7b810-9dad-11d1-80b4-00c04fd430c8")) {
+		t.Errorf("Incorrect appended UUID: %s", b)
+	}
+}
+
+func TestParseAllocs(t *testing.T) {
+	text := []byte("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	buf := make([]byte, 0, 36)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		Parse(text)
+		FromString("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+		IsValid("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+		IsValid("bogus")
+		NamespaceDNS.AppendText(buf)
+	})
+	if allocs > 0 {
+		t.Errorf("Parsing allocated %v times, expected none", allocs)
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	b.ReportAllocs()
+	text := []byte("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	for i := 0; i < b.N; i++ {
+		Parse(text)
+	}
+}
+
+func BenchmarkFromString(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		FromString("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	}
+}
+
+func BenchmarkString(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = NamespaceDNS.String()
+	}
+}
+
+func BenchmarkAppendText(b *testing.B) {
+	b.ReportAllocs()
+	buf := make([]byte, 0, 36)
+	for i := 0; i < b.N; i++ {
+		NamespaceDNS.AppendText(buf)
+	}
+}
@@ -0,0 +1,140 @@
+package uuid
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	mrand "math/rand"
+	"sync"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/clock"
+)
+
+// A Source provides the random data and the time used to generate UUIDs.
+type Source interface {
+	// Read fills b with random data
+	Read(b []byte) (n int, err error)
+	// Now returns the current time
+	Now() time.Time
+}
+
+// DefaultSource reads random data from crypto/rand, and time from clock.Default.
+var DefaultSource Source = defaultSource{}
+
+type defaultSource struct{}
+
+func (defaultSource) Read(b []byte) (int, error) { return rand.Read(b) }
+func (defaultSource) Now() time.Time             { return clock.UTC() }
+
+var (
+	sourceMutex sync.RWMutex
+	source      = DefaultSource
+)
+
+func currentSource() Source {
+	sourceMutex.RLock()
+	defer sourceMutex.RUnlock()
+	return source
+}
+
+// sourceReader is an io.Reader which reads from the current Source
+type sourceReader struct{}
+
+func (sourceReader) Read(b []byte) (int, error) { return currentSource().Read(b) }
+
+// SetSource replaces the Source used to generate UUIDs until restore is
+// called, so that tests of code which generates UUIDs are reproducible.
+//
+//     t.Cleanup(uuid.SetSource(uuid.NewSequenceSource()))
+//
+// The state used to keep version 1 and 7 UUIDs ordered is also reset, and then
+// restored by restore.  Tests which call SetSource must not run in parallel
+// with other tests that generate UUIDs.
+func SetSource(src Source) (restore func()) {
+	// NOTE: Lock in the same order as the generators (which read the source
+	// while holding their storage locks).
+	storageMutex.Lock()
+	v7Mutex.Lock()
+	sourceMutex.Lock()
+	prevSource := source
+	prevOnce, prevLastTime, prevClockSeq, prevAddr := storageOnce, lastTime, clockSequence, hardwareAddr
+	prevLastMilli, prevCounter := v7LastMilli, v7Counter
+
+	source = src
+	storageOnce, lastTime = new(sync.Once), 0
+	v7LastMilli, v7Counter = 0, 0
+	sourceMutex.Unlock()
+	v7Mutex.Unlock()
+	storageMutex.Unlock()
+
+	return func() {
+		storageMutex.Lock()
+		v7Mutex.Lock()
+		sourceMutex.Lock()
+		source = prevSource
+		storageOnce, lastTime, clockSequence, hardwareAddr = prevOnce, prevLastTime, prevClockSeq, prevAddr
+		v7LastMilli, v7Counter = prevLastMilli, prevCounter
+		sourceMutex.Unlock()
+		v7Mutex.Unlock()
+		storageMutex.Unlock()
+	}
+}
+
+// MockSource is a deterministic Source for tests.  Its random data is either
+// pseudo-random from a seed (see NewSeededSource) or a counter (see
+// NewSequenceSource), and its time is read from a clock.Source.
+type MockSource struct {
+	// Clock is the clock used for the time of version 1 and 7 UUIDs.
+	// If nil, clock.Default is used (which can be frozen with clock.Freeze).
+	Clock *clock.Source
+
+	mutex   sync.Mutex
+	rand    *mrand.Rand
+	counter uint64
+}
+
+// NewSeededSource returns a MockSource which generates pseudo-random data
+// from the seed, so the same UUIDs are generated each time a test is run.
+func NewSeededSource(seed int64) *MockSource {
+	return &MockSource{rand: mrand.New(mrand.NewSource(seed))}
+}
+
+// NewSequenceSource returns a MockSource where each read fills the buffer
+// with zeros, except for a counter which starts at 1 in the last 8 bytes.
+// The random part of each UUID is then easily recognizable, eg.
+//
+//     uuid.NewV4() // 00000000-0000-4000-8000-000000000001
+//     uuid.NewV4() // 00000000-0000-4000-8000-000000000002
+//
+func NewSequenceSource() *MockSource {
+	return &MockSource{}
+}
+
+func (s *MockSource) Read(b []byte) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.rand != nil {
+		return s.rand.Read(b)
+	}
+
+	for i := range b {
+		b[i] = 0
+	}
+	s.counter++
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], s.counter)
+	if len(b) >= 8 {
+		copy(b[len(b)-8:], counter[:])
+	} else {
+		copy(b, counter[8-len(b):])
+	}
+	return len(b), nil
+}
+
+func (s *MockSource) Now() time.Time {
+	if s.Clock != nil {
+		return s.Clock.UTC()
+	}
+	return clock.UTC()
+}
@@ -0,0 +1,76 @@
+package uuid
+
+import (
+	"testing"
+	"time"
+
+	"github.com/reflexionhealth/vanilla/clock"
+)
+
+func TestSequenceSource(t *testing.T) {
+	now := time.Date(2016, time.October, 14, 12, 0, 0, 0, time.UTC)
+	src := NewSequenceSource()
+	src.Clock = &clock.Source{Now: now, Frozen: true}
+
+	t.Run("generate", func(t *testing.T) {
+		t.Cleanup(SetSource(src))
+
+		expected := []string{
+			"00000000-0000-4000-8000-000000000001",
+			"00000000-0000-4000-8000-000000000002",
+		}
+		for _, s := range expected {
+			if u := NewV4(); u.String() != s {
+				t.Errorf("Incorrect UUIDv4 from sequence source: %s, expected %s", u, s)
+			}
+		}
+
+		if u := NewBatch(2); u[0].String() != "00000000-0000-4000-8000-000000000000" || u[1].String() != "00000000-0000-4000-8000-000000000003" {
+			t.Errorf("Incorrect UUIDv4 batch from sequence source: %s", u)
+		}
+
+		u := NewV7()
+		if tm, _ := u.Time(); !tm.Equal(now) {
+			t.Errorf("Incorrect time for UUIDv7 from mock source: %s, expected %s", tm, now)
+		}
+		if u.String() != "0157c310-2e00-7000-8000-000000000004" {
+			t.Errorf("Incorrect UUIDv7 from sequence source: %s", u)
+		}
+
+		u = NewV1()
+		if tm, _ := u.Time(); !tm.Equal(now) {
+			t.Errorf("Incorrect time for UUIDv1 from mock source: %s, expected %s", tm, now)
+		}
+	})
+
+	// the default source is restored after the test
+	if u := NewV4(); u.String() == "00000000-0000-4000-8000-000000000006" {
+		t.Errorf("Default source was not restored after the test")
+	}
+	if u := NewV7(); u.String()[:13] == "0157c310-2e00" {
+		t.Errorf("Default source was not restored after the test")
+	}
+}
+
+func TestSeededSource(t *testing.T) {
+	generate := func() []UUID {
+		var uuids []UUID
+		t.Run("generate", func(t *testing.T) {
+			src := NewSeededSource(42)
+			src.Clock = &clock.Source{Now: time.Unix(1476446400, 0), Frozen: true}
+			t.Cleanup(SetSource(src))
+			uuids = []UUID{NewV4(), NewV7(), NewV7(), NewV1(), NewComb(0).New()}
+		})
+		return uuids
+	}
+
+	first, second := generate(), generate()
+	for i := range first {
+		if !Equal(first[i], second[i]) {
+			t.Errorf("Seeded source generated different UUIDs: %s and %s", first[i], second[i])
+		}
+	}
+	if Equal(first[1], first[2]) {
+		t.Errorf("Seeded source generated duplicate UUIDv7: %s", first[1])
+	}
+}
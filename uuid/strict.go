@@ -0,0 +1,65 @@
+package uuid
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// StrictOptions relax the checks made by ParseStrict.
+type StrictOptions struct {
+	// AllowUppercase accepts upper (or mixed) case hex digits
+	AllowUppercase bool
+	// AllowBraces accepts the "{6ba7b810-9dad-11d1-80b4-00c04fd430c8}" form
+	AllowBraces bool
+	// AllowURN accepts the "urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8" form
+	AllowURN bool
+	// AllowNil accepts the Nil UUID, which has no version or variant
+	AllowNil bool
+}
+
+// ParseStrict parses a UUID in the canonical form returned by String, ie.
+// lower case "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx", and checks that it has
+// the RFC 4122 variant and a known version (1 through 8).
+//
+// Use FromString to parse legacy data more leniently.
+func ParseStrict(input string) (UUID, error) {
+	return StrictOptions{}.Parse(input)
+}
+
+// Parse is like ParseStrict, but accepts the forms allowed by the options.
+func (opts StrictOptions) Parse(input string) (UUID, error) {
+	text := []byte(input)
+	if bytes.HasPrefix(text, urnPrefix) {
+		if !opts.AllowURN {
+			return Nil, fmt.Errorf("uuid: URN form is not allowed: %s", input)
+		}
+		text = text[len(urnPrefix):]
+	} else if len(text) > 0 && text[0] == '{' {
+		if !opts.AllowBraces || text[len(text)-1] != '}' {
+			return Nil, fmt.Errorf("uuid: braced form is not allowed: %s", input)
+		}
+		text = text[1 : len(text)-1]
+	}
+
+	if len(text) != 36 {
+		return Nil, fmt.Errorf("uuid: UUID string must be 36 characters long: %s", input)
+	}
+	if !opts.AllowUppercase && bytes.ContainsAny(text, "ABCDEF") {
+		return Nil, fmt.Errorf("uuid: upper case hex digits are not allowed: %s", input)
+	}
+
+	u, problem := parse(text)
+	if len(problem) > 0 {
+		return Nil, errors.New("uuid: " + problem + ": " + input)
+	}
+
+	if Equal(u, Nil) && opts.AllowNil {
+		return u, nil
+	} else if u.Variant() != VariantRFC4122 {
+		return Nil, fmt.Errorf("uuid: UUID does not have the RFC 4122 variant: %s", input)
+	} else if v := u.Version(); v < 1 || v > 8 {
+		return Nil, fmt.Errorf("uuid: UUID has unknown version %d: %s", v, input)
+	}
+	return u, nil
+}
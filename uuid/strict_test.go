@@ -0,0 +1,82 @@
+package uuid
+
+import "testing"
+
+func TestParseStrict(t *testing.T) {
+	u, err := ParseStrict("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	if err != nil {
+		t.Errorf("Error parsing canonical UUID: %s", err)
+	}
+	if !Equal(u, NamespaceDNS) {
+		t.Errorf("UUIDs should be equal: %s and %s", u, NamespaceDNS)
+	}
+
+	v4 := NewV4()
+	if u, err := ParseStrict(v4.String()); err != nil || !Equal(u, v4) {
+		t.Errorf("Error parsing generated UUID %s: %s, %v", v4, u, err)
+	}
+
+	invalid := []string{
+		"",
+		"6ba7b8109dad11d180b400c04fd430c8",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c8a",
+		"6ba7b810+9dad-11d1-80b4-00c04fd430c8",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430cz",
+		"6BA7B810-9DAD-11D1-80B4-00C04FD430C8",
+		"6ba7B810-9dad-11d1-80b4-00c04fd430c8",
+		"{6ba7b810-9dad-11d1-80b4-00c04fd430c8}",
+		"urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		"00000000-0000-0000-0000-000000000000",
+		"6ba7b810-9dad-11d1-00b4-00c04fd430c8", // NCS variant
+		"6ba7b810-9dad-11d1-c0b4-00c04fd430c8", // Microsoft variant
+		"6ba7b810-9dad-01d1-80b4-00c04fd430c8", // version 0
+		"6ba7b810-9dad-91d1-80b4-00c04fd430c8", // version 9
+	}
+
+	for _, s := range invalid {
+		if _, err := ParseStrict(s); err == nil {
+			t.Errorf("Should return error parsing invalid UUID %q", s)
+		}
+	}
+}
+
+func TestParseStrictOptions(t *testing.T) {
+	examples := []struct {
+		Options StrictOptions
+		String  string
+		UUID    UUID
+	}{
+		{StrictOptions{AllowUppercase: true}, "6BA7B810-9DAD-11D1-80B4-00C04FD430C8", NamespaceDNS},
+		{StrictOptions{AllowUppercase: true}, "6ba7B810-9dad-11d1-80b4-00c04fd430c8", NamespaceDNS},
+		{StrictOptions{AllowBraces: true}, "{6ba7b810-9dad-11d1-80b4-00c04fd430c8}", NamespaceDNS},
+		{StrictOptions{AllowURN: true}, "urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8", NamespaceDNS},
+		{StrictOptions{AllowNil: true}, "00000000-0000-0000-0000-000000000000", Nil},
+	}
+
+	for _, ex := range examples {
+		u, err := ex.Options.Parse(ex.String)
+		if err != nil {
+			t.Errorf("Error parsing %q with %+v: %s", ex.String, ex.Options, err)
+		}
+		if !Equal(u, ex.UUID) {
+			t.Errorf("Incorrect UUID parsing %q with %+v: %s", ex.String, ex.Options, u)
+		}
+	}
+
+	invalid := []struct {
+		Options StrictOptions
+		String  string
+	}{
+		{StrictOptions{AllowBraces: true}, "{6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+		{StrictOptions{AllowBraces: true}, "urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+		{StrictOptions{AllowURN: true}, "{6ba7b810-9dad-11d1-80b4-00c04fd430c8}"},
+		{StrictOptions{AllowNil: true}, "00000000-0000-0000-0000-000000000001"},
+	}
+
+	for _, ex := range invalid {
+		if _, err := ex.Options.Parse(ex.String); err == nil {
+			t.Errorf("Should return error parsing %q with %+v", ex.String, ex.Options)
+		}
+	}
+}
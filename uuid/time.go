@@ -0,0 +1,44 @@
+package uuid
+
+import (
+	"bytes"
+	"encoding/binary"
+	"time"
+)
+
+// Time returns the time a version 1 or version 7 UUID was generated.
+// It returns false for other versions, which don't contain a timestamp.
+func (u UUID) Time() (time.Time, bool) {
+	switch u.Version() {
+	case 1:
+		timestamp := uint64(binary.BigEndian.Uint16(u[6:])&0x0fff)<<48 |
+			uint64(binary.BigEndian.Uint16(u[4:]))<<32 |
+			uint64(binary.BigEndian.Uint32(u[0:]))
+		nanos := (int64(timestamp) - epochStart) * 100
+		return time.Unix(0, nanos).UTC(), true
+	case 7:
+		milli := uint64(u[0])<<40 | uint64(u[1])<<32 | uint64(binary.BigEndian.Uint32(u[2:]))
+		return time.Unix(0, int64(milli)*int64(time.Millisecond)).UTC(), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// Compare returns -1, 0, or +1 if u1 is ordered before, equal to, or after u2.
+//
+// If both UUIDs are time-based (see Time), they are ordered chronologically;
+// otherwise, or if the times are equal, they are ordered by their bytes.
+// Version 7 and COMB UUIDs are always ordered chronologically by their bytes,
+// but version 1 UUIDs are not.
+func Compare(u1 UUID, u2 UUID) int {
+	t1, ok1 := u1.Time()
+	t2, ok2 := u2.Time()
+	if ok1 && ok2 {
+		if t1.Before(t2) {
+			return -1
+		} else if t1.After(t2) {
+			return 1
+		}
+	}
+	return bytes.Compare(u1[:], u2[:])
+}
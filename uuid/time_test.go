@@ -0,0 +1,67 @@
+package uuid
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestTime(t *testing.T) {
+	// NOTE: the namespace UUIDs from RFC 4122 are version 1 UUIDs
+	u := NamespaceDNS
+	tm, ok := u.Time()
+	expected := time.Date(1998, time.February, 4, 22, 13, 53, 151182400, time.UTC)
+	if !ok || !tm.Equal(expected) {
+		t.Errorf("Incorrect time for UUIDv1 %s: %s, %v; expected %s", u, tm, ok, expected)
+	}
+
+	// From RFC 9562, Appendix A.6 (Tuesday, February 22, 2022 2:22:22.00 PM GMT-05:00)
+	u, _ = FromString("017f22e2-79b0-7cc3-98c4-dc0c0c07398f")
+	tm, ok = u.Time()
+	expected = time.Date(2022, time.February, 22, 19, 22, 22, 0, time.UTC)
+	if !ok || !tm.Equal(expected) {
+		t.Errorf("Incorrect time for UUIDv7 %s: %s, %v; expected %s", u, tm, ok, expected)
+	}
+
+	before := time.Now().Add(-time.Second)
+	for _, u := range []UUID{NewV1(), NewV7()} {
+		tm, ok := u.Time()
+		if !ok || tm.Before(before) || tm.After(time.Now()) {
+			t.Errorf("Incorrect time for generated UUIDv%d %s: %s", u.Version(), u, tm)
+		}
+	}
+
+	for _, u := range []UUID{Nil, NewV3(NamespaceDNS, "python.org"), NewV4(), NewV5(NamespaceDNS, "python.org")} {
+		if _, ok := u.Time(); ok {
+			t.Errorf("UUIDv%d should not have a time: %s", u.Version(), u)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	oldFunc := epochFunc
+	defer func() { epochFunc = oldFunc }()
+
+	// NOTE: the low bits of the timestamp are first in a UUIDv1
+	timestamp := uint64(epochStart|0xffffffff) - 1
+	epochFunc = func() uint64 { timestamp++; return timestamp }
+
+	uuids := []UUID{NewV1(), NewV1(), NewV1()}
+	if bytes.Compare(uuids[0][:], uuids[1][:]) < 0 {
+		t.Fatalf("UUIDv1 bytes should not be ordered across the low timestamp boundary: %s", uuids)
+	}
+	sorted := sort.SliceIsSorted(uuids, func(i, j int) bool { return Compare(uuids[i], uuids[j]) < 0 })
+	if !sorted {
+		t.Errorf("UUIDv1 should be ordered chronologically: %s", uuids)
+	}
+	if Equal(uuids[0], uuids[1]) || Compare(uuids[1], uuids[1]) != 0 {
+		t.Errorf("UUIDv1 should only compare equal to itself")
+	}
+
+	a, _ := FromString("00000000-0000-4000-8000-000000000001")
+	b, _ := FromString("00000000-0000-4000-8000-000000000002")
+	if Compare(a, b) != -1 || Compare(b, a) != 1 || Compare(a, a) != 0 {
+		t.Errorf("UUIDv4 should be ordered by bytes")
+	}
+}
@@ -20,20 +20,20 @@
 // WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
 
 // Package uuid provides implementation of Universally Unique Identifier (UUID).
-// Supported versions are 1, 3, 4 and 5 (as specified in RFC 4122) and
-// version 2 (as specified in DCE 1.1).
+// Supported versions are 1, 3, 4 and 5 (as specified in RFC 4122),
+// version 2 (as specified in DCE 1.1), and version 7 (as specified in RFC 9562).
 package uuid
 
 import (
 	"bytes"
 	"crypto/md5"
-	"crypto/rand"
 	"crypto/sha1"
 	"database/sql/driver"
 	"encoding/binary"
-	"encoding/hex"
+	"errors"
 	"fmt"
 	"hash"
+	"io"
 	"net"
 	"os"
 	"sync"
@@ -65,7 +65,7 @@ const dash byte = '-'
 // UUID v1/v2 storage.
 var (
 	storageMutex  sync.Mutex
-	storageOnce   sync.Once
+	storageOnce   = new(sync.Once)
 	epochFunc     = unixTimeFunc
 	clockSequence uint16
 	lastTime      uint64
@@ -74,12 +74,17 @@ var (
 	posixGID      = uint32(os.Getgid())
 )
 
-// String parse helpers.
+// UUID v7 storage.
 var (
-	urnPrefix  = []byte("urn:uuid:")
-	byteGroups = []int{8, 4, 4, 4, 12}
+	v7Mutex     sync.Mutex
+	v7TimeFunc  = unixMilliFunc
+	v7LastMilli uint64
+	v7Counter   uint16
 )
 
+// String parse helpers.
+var urnPrefix = []byte("urn:uuid:")
+
 func initClockSequence() {
 	buf := make([]byte, 2)
 	safeRandom(buf)
@@ -111,7 +116,7 @@ func initStorage() {
 }
 
 func safeRandom(dest []byte) {
-	if _, err := rand.Read(dest); err != nil {
+	if _, err := io.ReadFull(sourceReader{}, dest); err != nil {
 		panic(err)
 	}
 }
@@ -120,7 +125,13 @@ func safeRandom(dest []byte) {
 // UUID epoch (October 15, 1582) and current time.
 // This is default epoch calculation function.
 func unixTimeFunc() uint64 {
-	return epochStart + uint64(time.Now().UnixNano()/100)
+	return epochStart + uint64(currentSource().Now().UnixNano()/100)
+}
+
+// Returns the current Unix time in milliseconds.
+// This is the default timestamp function for UUID v7.
+func unixMilliFunc() uint64 {
+	return uint64(currentSource().Now().UnixNano() / int64(time.Millisecond))
 }
 
 // UUID representation compliant with specification
@@ -188,19 +199,9 @@ func (u UUID) Bytes() []byte {
 // Returns canonical string representation of UUID:
 // xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx.
 func (u UUID) String() string {
-	buf := make([]byte, 36)
-
-	hex.Encode(buf[0:8], u[0:4])
-	buf[8] = dash
-	hex.Encode(buf[9:13], u[4:6])
-	buf[13] = dash
-	hex.Encode(buf[14:18], u[6:8])
-	buf[18] = dash
-	hex.Encode(buf[19:23], u[8:10])
-	buf[23] = dash
-	hex.Encode(buf[24:], u[10:])
-
-	return string(buf)
+	var buf [36]byte
+	u.encodeCanonical(&buf)
+	return string(buf[:])
 }
 
 // SetVersion sets version bits.
@@ -226,52 +227,7 @@ func (u UUID) MarshalText() (text []byte, err error) {
 // "{6ba7b810-9dad-11d1-80b4-00c04fd430c8}",
 // "urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8"
 func (u *UUID) UnmarshalText(text []byte) (err error) {
-	if len(text) < 32 {
-		err = fmt.Errorf("uuid: UUID string too short: %s", text)
-		return
-	}
-
-	t := text[:]
-	braced := false
-
-	if bytes.Equal(t[:9], urnPrefix) {
-		t = t[9:]
-	} else if t[0] == '{' {
-		braced = true
-		t = t[1:]
-	}
-
-	b := u[:]
-
-	for i, byteGroup := range byteGroups {
-		if i > 0 {
-			if t[0] != '-' {
-				err = fmt.Errorf("uuid: invalid string format")
-				return
-			}
-			t = t[1:]
-		}
-
-		if len(t) < byteGroup {
-			err = fmt.Errorf("uuid: UUID string too short: %s", text)
-			return
-		}
-
-		if i == 4 && len(t) > byteGroup &&
-			((braced && t[byteGroup] != '}') || len(t[byteGroup:]) > 1 || !braced) {
-			err = fmt.Errorf("uuid: UUID string too long: %s", text)
-			return
-		}
-
-		_, err = hex.Decode(b[:byteGroup/2], t[:byteGroup])
-		if err != nil {
-			return
-		}
-
-		t = t[byteGroup:]
-		b = b[byteGroup/2:]
-	}
-
+	*u, err = Parse(text)
 	return
 }
 
@@ -336,8 +292,11 @@ func FromBytesOrNil(input []byte) UUID {
 // FromString returns UUID parsed from string input.
 // Input is expected in a form accepted by UnmarshalText.
 func FromString(input string) (u UUID, err error) {
-	err = u.UnmarshalText([]byte(input))
-	return
+	u, problem := parse(input)
+	if len(problem) > 0 {
+		return Nil, errors.New("uuid: " + problem + ": " + input)
+	}
+	return u, nil
 }
 
 // FromStringOrNil returns UUID parsed from string input.
@@ -442,6 +401,50 @@ func NewV5(ns UUID, name string) UUID {
 	return u
 }
 
+// NewV7 returns a time-ordered UUID based on the current Unix time in
+// milliseconds and random data.
+//
+// UUIDs generated by the same process are strictly increasing: within a millisecond
+// the 12 bits after the timestamp are used as a counter (RFC 9562, Section 6.2,
+// Method 1), which starts from a random value in the lower half of its range.
+// If the counter overflows, or the clock moves backwards, the timestamp of
+// the previous UUID is advanced instead.
+func NewV7() UUID {
+	u := UUID{}
+	safeRandom(u[6:])
+
+	milli, counter := getV7Storage(binary.BigEndian.Uint16(u[6:]) & 0x07ff)
+	u[0] = byte(milli >> 40)
+	u[1] = byte(milli >> 32)
+	binary.BigEndian.PutUint32(u[2:], uint32(milli))
+	binary.BigEndian.PutUint16(u[6:], counter)
+
+	u.SetVersion(7)
+	u.SetVariant()
+
+	return u
+}
+
+// Returns the timestamp and counter for the next UUID v7.
+// A new millisecond starts the counter at seed.
+func getV7Storage(seed uint16) (uint64, uint16) {
+	v7Mutex.Lock()
+	defer v7Mutex.Unlock()
+
+	milli := v7TimeFunc()
+	if milli > v7LastMilli {
+		v7LastMilli = milli
+		v7Counter = seed
+	} else if v7Counter < 0x0fff {
+		v7Counter++
+	} else {
+		v7LastMilli++
+		v7Counter = seed
+	}
+
+	return v7LastMilli, v7Counter
+}
+
 // Returns UUID based on hashing of namespace UUID and name.
 func newFromHash(h hash.Hash, ns UUID, name string) UUID {
 	u := UUID{}
@@ -23,6 +23,7 @@ package uuid
 
 import (
 	"bytes"
+	"encoding/binary"
 	"testing"
 )
 
@@ -582,3 +583,54 @@ func TestNewV5(t *testing.T) {
 		t.Errorf("UUIDv3 generated same UUIDs for sane names in different namespaces: %s and %s", u1, u4)
 	}
 }
+
+func TestNewV7(t *testing.T) {
+	u := NewV7()
+
+	if u.Version() != 7 {
+		t.Errorf("UUIDv7 generated with incorrect version: %d", u.Version())
+	}
+
+	if u.Variant() != VariantRFC4122 {
+		t.Errorf("UUIDv7 generated with incorrect variant: %d", u.Variant())
+	}
+
+	milli := uint64(u[0])<<40 | uint64(u[1])<<32 | uint64(binary.BigEndian.Uint32(u[2:]))
+	now := unixMilliFunc()
+	if milli > now || now-milli > 1000 {
+		t.Errorf("UUIDv7 generated with incorrect timestamp: %d, expected about %d", milli, now)
+	}
+}
+
+func TestNewV7Monotonic(t *testing.T) {
+	oldFunc, oldMilli := v7TimeFunc, v7LastMilli
+	defer func() { v7TimeFunc, v7LastMilli = oldFunc, oldMilli }()
+	v7LastMilli = 0
+
+	// NOTE: the clock is stopped, and then moves backwards
+	milli := uint64(1476400000000)
+	v7TimeFunc = func() uint64 { return milli }
+
+	prev := NewV7()
+	for i := 0; i < 10000; i++ {
+		if i == 5000 {
+			milli -= 10
+		}
+
+		u := NewV7()
+		if bytes.Compare(prev[:], u[:]) >= 0 {
+			t.Fatalf("UUIDv7 generated out of order: %s after %s", u, prev)
+		}
+		if u.Version() != 7 || u.Variant() != VariantRFC4122 {
+			t.Fatalf("UUIDv7 generated with incorrect version or variant: %s", u)
+		}
+		prev = u
+	}
+
+	// a new millisecond restarts the counter
+	milli += 1000
+	u := NewV7()
+	if binary.BigEndian.Uint16(u[6:])&0x0fff >= 0x0800 {
+		t.Errorf("UUIDv7 counter was not reset in a new millisecond: %s", u)
+	}
+}